@@ -0,0 +1,240 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultAskTimeout is how long Ask waits for a reply when the context has no deadline.
+const DefaultAskTimeout = 30 * time.Second
+
+// replySource delivers replies addressed to this process.
+type replySource interface {
+	// Address is the reply address dispatchers publish to.
+	Address() string
+
+	// Replies returns the channel of received replies.
+	Replies() <-chan []byte
+}
+
+// Inbox receives replies for commands sent with Ask and hands each one to the
+// waiting caller, correlated by command ID. Replies for unknown commands, e.g.
+// ones that arrive after Ask gave up, are dropped.
+type Inbox struct {
+	source replySource
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	waiters map[string]chan Reply
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// InboxOption configures an Inbox.
+type InboxOption func(*Inbox)
+
+// WithInboxLogger configures structured logging for the inbox.
+// Use slog.New(slog.NewTextHandler(io.Discard, nil)) to disable logging.
+func WithInboxLogger(logger *slog.Logger) InboxOption {
+	return func(i *Inbox) {
+		if logger != nil {
+			i.logger = logger
+		}
+	}
+}
+
+// NewInbox creates an inbox reading replies from source, such as a ChannelBus or
+// an integration/bus/redisstream reply inbox. Call Start() or Run() to begin
+// receiving, and pass the inbox to senders with WithReplyInbox.
+//
+// Example:
+//
+//	inbox := command.NewInbox(bus)
+//	sender := command.NewSender(bus, command.WithReplyInbox(inbox))
+//	g.Go(inbox.Run(ctx))
+func NewInbox(source replySource, opts ...InboxOption) *Inbox {
+	i := &Inbox{
+		source:  source,
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		waiters: make(map[string]chan Reply),
+	}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i
+}
+
+// Address returns the reply address of the inbox.
+func (i *Inbox) Address() string {
+	return i.source.Address()
+}
+
+// Start receives replies until the context is cancelled or the source is closed.
+// This is a blocking operation; use Run() for the errgroup pattern.
+func (i *Inbox) Start(ctx context.Context) error {
+	i.mu.Lock()
+	if i.cancel != nil {
+		i.mu.Unlock()
+		return ErrInboxAlreadyStarted
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	i.cancel = cancel
+	i.done = make(chan struct{})
+	done := i.done
+	i.mu.Unlock()
+
+	defer close(done)
+	defer cancel()
+
+	i.logger.InfoContext(ctx, "reply inbox started", slog.String("address", i.Address()))
+
+	replies := i.source.Replies()
+	for {
+		select {
+		case <-ctx.Done():
+			i.logger.Info("reply inbox stopping")
+			return ctx.Err()
+		case data, ok := <-replies:
+			if !ok {
+				i.logger.Info("reply source closed")
+				return nil
+			}
+			i.deliver(ctx, data)
+		}
+	}
+}
+
+// Stop stops receiving replies and waits for Start to return.
+func (i *Inbox) Stop() error {
+	i.mu.Lock()
+	if i.cancel == nil {
+		i.mu.Unlock()
+		return ErrInboxNotStarted
+	}
+	cancel, done := i.cancel, i.done
+	i.cancel = nil
+	i.mu.Unlock()
+
+	cancel()
+	<-done
+	return nil
+}
+
+// Run provides errgroup compatibility for coordinated lifecycle management.
+func (i *Inbox) Run(ctx context.Context) func() error {
+	return func() error {
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- i.Start(ctx)
+		}()
+
+		select {
+		case <-ctx.Done():
+			if err := i.Stop(); err != nil {
+				i.logger.Error("reply inbox shutdown failed", slog.String("error", err.Error()))
+			}
+			<-errCh
+			return nil
+		case err := <-errCh:
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (i *Inbox) deliver(ctx context.Context, data []byte) {
+	var reply Reply
+	if err := json.Unmarshal(data, &reply); err != nil {
+		i.logger.ErrorContext(ctx, "failed to unmarshal reply", slog.String("error", err.Error()))
+		return
+	}
+
+	i.mu.Lock()
+	ch, ok := i.waiters[reply.CommandID]
+	delete(i.waiters, reply.CommandID)
+	i.mu.Unlock()
+
+	if !ok {
+		i.logger.DebugContext(ctx, "dropped reply without a waiting caller",
+			slog.String("command_id", reply.CommandID))
+		return
+	}
+	ch <- reply
+}
+
+// wait registers a caller for the reply to the command.
+// The returned function unregisters it.
+func (i *Inbox) wait(commandID string) (<-chan Reply, func()) {
+	ch := make(chan Reply, 1)
+
+	i.mu.Lock()
+	i.waiters[commandID] = ch
+	i.mu.Unlock()
+
+	return ch, func() {
+		i.mu.Lock()
+		delete(i.waiters, commandID)
+		i.mu.Unlock()
+	}
+}
+
+// Ask sends req as a command and waits for the result of its handler, registered
+// with NewReplyHandlerFunc or Replying. The sender needs an inbox (WithReplyInbox)
+// and the dispatcher a reply publisher (WithReplyPublisher).
+//
+// Ask waits until the context is done, or for the sender's ask timeout
+// (DefaultAskTimeout) if the context has no deadline, and then returns an error
+// wrapping ErrReplyTimeout. A handler error is returned wrapping ErrCommandFailed.
+//
+// Example:
+//
+//	balance, err := command.Ask[GetBalance, Balance](ctx, sender, GetBalance{AccountID: id})
+func Ask[Req, Resp any](ctx context.Context, sender *Sender, req Req) (Resp, error) {
+	var resp Resp
+
+	if sender.inbox == nil {
+		return resp, ErrNoReplyInbox
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sender.askTimeout)
+		defer cancel()
+	}
+
+	command := NewCommand(req)
+	command.ReplyTo = sender.inbox.Address()
+
+	// Register before publishing, so a fast reply is not dropped
+	replies, unregister := sender.inbox.wait(command.ID)
+	defer unregister()
+
+	if err := sender.publish(ctx, command); err != nil {
+		return resp, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return resp, fmt.Errorf("%w: command %s: %w", ErrReplyTimeout, command.ID, ctx.Err())
+	case reply := <-replies:
+		if reply.Error != "" {
+			return resp, fmt.Errorf("%w: %s", ErrCommandFailed, reply.Error)
+		}
+		if len(reply.Payload) > 0 {
+			if err := json.Unmarshal(reply.Payload, &resp); err != nil {
+				return resp, fmt.Errorf("failed to unmarshal reply to command %s: %w", command.ID, err)
+			}
+		}
+		return resp, nil
+	}
+}
@@ -0,0 +1,177 @@
+package command_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/command"
+)
+
+type GetBalance struct {
+	AccountID string
+}
+
+type Balance struct {
+	AccountID string
+	Amount    int
+}
+
+type Unhandled struct{}
+
+// startAsking runs a dispatcher and an inbox on one channel bus until the test ends.
+func startAsking(t *testing.T, handlers ...command.Handler) *command.Sender {
+	t.Helper()
+
+	bus := command.NewChannelBus()
+	dispatcher := command.NewDispatcher(
+		command.WithCommandSource(bus),
+		command.WithReplyPublisher(bus),
+		command.WithHandler(handlers...),
+	)
+	inbox := command.NewInbox(bus)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{}, 2)
+	go func() { _ = dispatcher.Start(ctx); done <- struct{}{} }()
+	go func() { _ = inbox.Start(ctx); done <- struct{}{} }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		<-done
+	})
+
+	return command.NewSender(bus, command.WithReplyInbox(inbox), command.WithAskTimeout(time.Second))
+}
+
+func TestAsk(t *testing.T) {
+	t.Parallel()
+
+	getBalance := command.NewReplyHandlerFunc(func(ctx context.Context, q GetBalance) (Balance, error) {
+		if q.AccountID == "" {
+			return Balance{}, errors.New("account id is required")
+		}
+		return Balance{AccountID: q.AccountID, Amount: 42}, nil
+	})
+
+	t.Run("returns typed reply", func(t *testing.T) {
+		t.Parallel()
+
+		sender := startAsking(t, getBalance)
+
+		balance, err := command.Ask[GetBalance, Balance](context.Background(), sender, GetBalance{AccountID: "acc-1"})
+		require.NoError(t, err)
+		assert.Equal(t, Balance{AccountID: "acc-1", Amount: 42}, balance)
+	})
+
+	t.Run("correlates concurrent asks", func(t *testing.T) {
+		t.Parallel()
+
+		sender := startAsking(t, getBalance)
+
+		ids := []string{"a", "b", "c", "d", "e"}
+		errs := make(chan error, len(ids))
+		for _, id := range ids {
+			go func() {
+				balance, err := command.Ask[GetBalance, Balance](context.Background(), sender, GetBalance{AccountID: id})
+				if err == nil && balance.AccountID != id {
+					err = errors.New("reply for another command: " + balance.AccountID)
+				}
+				errs <- err
+			}()
+		}
+		for range ids {
+			assert.NoError(t, <-errs)
+		}
+	})
+
+	t.Run("returns handler error", func(t *testing.T) {
+		t.Parallel()
+
+		sender := startAsking(t, getBalance)
+
+		_, err := command.Ask[GetBalance, Balance](context.Background(), sender, GetBalance{})
+		assert.ErrorIs(t, err, command.ErrCommandFailed)
+		assert.ErrorContains(t, err, "account id is required")
+	})
+
+	t.Run("fails fast without a handler", func(t *testing.T) {
+		t.Parallel()
+
+		sender := startAsking(t, getBalance)
+
+		_, err := command.Ask[Unhandled, struct{}](context.Background(), sender, Unhandled{})
+		assert.ErrorIs(t, err, command.ErrCommandFailed)
+		assert.ErrorContains(t, err, command.ErrNoHandler.Error())
+	})
+
+	t.Run("times out without a reply", func(t *testing.T) {
+		t.Parallel()
+
+		sender := startAsking(t, command.NewHandlerFunc(func(ctx context.Context, q GetBalance) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := command.Ask[GetBalance, Balance](ctx, sender, GetBalance{AccountID: "acc-1"})
+		assert.ErrorIs(t, err, command.ErrReplyTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("requires a reply inbox", func(t *testing.T) {
+		t.Parallel()
+
+		sender := command.NewSender(command.NewChannelBus())
+
+		_, err := command.Ask[GetBalance, Balance](context.Background(), sender, GetBalance{})
+		assert.ErrorIs(t, err, command.ErrNoReplyInbox)
+	})
+}
+
+func TestDispatcher_RepliesWithoutInbox(t *testing.T) {
+	t.Parallel()
+
+	// Replies fill the buffer because no inbox drains it, as after the inbox stopped
+	bus := command.NewChannelBus(command.WithBufferSize(2))
+	dispatcher := command.NewDispatcher(
+		command.WithCommandSource(bus),
+		command.WithReplyPublisher(bus),
+		command.WithShutdownTimeout(time.Second),
+		command.WithHandler(command.NewReplyHandlerFunc(func(ctx context.Context, q GetBalance) (Balance, error) {
+			return Balance{AccountID: q.AccountID}, nil
+		})),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = dispatcher.Start(ctx) }()
+
+	sender := command.NewSender(bus)
+	for range 5 {
+		cmd := command.NewCommand(GetBalance{AccountID: "acc-1"})
+		cmd.ReplyTo = command.ChannelReplyAddress
+		require.NoError(t, sender.SendCommand(ctx, cmd))
+	}
+
+	require.Eventually(t, func() bool {
+		return dispatcher.Stats().CommandsProcessed == 5
+	}, time.Second, 5*time.Millisecond, "handlers return although replies are dropped")
+
+	require.NoError(t, dispatcher.Stop())
+
+	closed := make(chan error, 1)
+	go func() { closed <- bus.Close() }()
+	select {
+	case err := <-closed:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("bus close deadlocked on a pending reply")
+	}
+}
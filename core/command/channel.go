@@ -10,6 +10,10 @@ import (
 const (
 	// DefaultChannelBufferSize is the default buffer size for the in-memory channel bus.
 	DefaultChannelBufferSize = 100
+
+	// ChannelReplyAddress is the reply address of a ChannelBus. All replies
+	// published to a channel bus go to its single inbox.
+	ChannelReplyAddress = "channel"
 )
 
 // ChannelBus implements both commandBus and commandSource interfaces using Go channels.
@@ -18,6 +22,9 @@ const (
 // ChannelBus is thread-safe and can handle concurrent publishers.
 // It uses a buffered channel to prevent blocking publishers when the dispatcher is slow.
 //
+// It also carries replies for Ask: it is the dispatcher's ReplyPublisher and the
+// source of an Inbox at the same time.
+//
 // Example:
 //
 //	bus := command.NewChannelBus(
@@ -32,10 +39,11 @@ const (
 //	    command.WithHandler(handler),
 //	)
 type ChannelBus struct {
-	ch     chan []byte
-	logger *slog.Logger
-	mu     sync.RWMutex
-	closed bool
+	ch      chan []byte
+	replies chan []byte
+	logger  *slog.Logger
+	mu      sync.RWMutex
+	closed  bool
 }
 
 // ChannelBusOption configures a ChannelBus.
@@ -48,6 +56,7 @@ func WithBufferSize(size int) ChannelBusOption {
 	return func(b *ChannelBus) {
 		if size > 0 {
 			b.ch = make(chan []byte, size)
+			b.replies = make(chan []byte, size)
 		}
 	}
 }
@@ -73,9 +82,10 @@ func WithChannelLogger(logger *slog.Logger) ChannelBusOption {
 //	defer bus.Close()
 func NewChannelBus(opts ...ChannelBusOption) *ChannelBus {
 	b := &ChannelBus{
-		ch:     make(chan []byte, DefaultChannelBufferSize),
-		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
-		closed: false,
+		ch:      make(chan []byte, DefaultChannelBufferSize),
+		replies: make(chan []byte, DefaultChannelBufferSize),
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed:  false,
 	}
 
 	for _, opt := range opts {
@@ -112,6 +122,40 @@ func (b *ChannelBus) Commands() <-chan []byte {
 	return b.ch
 }
 
+// PublishReply implements ReplyPublisher. The address is ignored: a channel bus
+// has a single inbox.
+//
+// It never blocks: when the reply buffer is full, typically because the Inbox
+// has stopped or was never started, the reply is dropped with
+// ErrReplyBufferFull. Nobody is waiting for a reply no inbox reads.
+func (b *ChannelBus) PublishReply(ctx context.Context, _ string, data []byte) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return ErrChannelBusClosed
+	}
+
+	select {
+	case b.replies <- data:
+		return nil
+	default:
+		b.logger.WarnContext(ctx, "reply buffer is full, dropping reply",
+			slog.Int("buffer_size", cap(b.replies)))
+		return ErrReplyBufferFull
+	}
+}
+
+// Address returns ChannelReplyAddress, the reply address of an Inbox reading from the bus.
+func (b *ChannelBus) Address() string {
+	return ChannelReplyAddress
+}
+
+// Replies returns a read-only channel that emits reply data for an Inbox.
+func (b *ChannelBus) Replies() <-chan []byte {
+	return b.replies
+}
+
 // Close gracefully shuts down the channel bus by closing the underlying channel.
 // After Close is called, Publish will return an error.
 // This should be called to signal the dispatcher that no more commands will be published.
@@ -125,6 +169,7 @@ func (b *ChannelBus) Close() error {
 
 	b.closed = true
 	close(b.ch)
+	close(b.replies)
 	b.logger.Info("channel bus closed")
 	return nil
 }
@@ -214,6 +214,34 @@ func TestChannelBusCommands(t *testing.T) {
 	})
 }
 
+func TestChannelBusPublishReply(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drops replies when the buffer is full", func(t *testing.T) {
+		t.Parallel()
+
+		bus := command.NewChannelBus(command.WithBufferSize(1))
+		ctx := context.Background()
+
+		require.NoError(t, bus.PublishReply(ctx, command.ChannelReplyAddress, []byte(`{"command_id":"1"}`)))
+		err := bus.PublishReply(ctx, command.ChannelReplyAddress, []byte(`{"command_id":"2"}`))
+		require.ErrorIs(t, err, command.ErrReplyBufferFull)
+
+		// Close does not wait for a blocked reply
+		require.NoError(t, bus.Close())
+	})
+
+	t.Run("returns error after close", func(t *testing.T) {
+		t.Parallel()
+
+		bus := command.NewChannelBus()
+		require.NoError(t, bus.Close())
+
+		err := bus.PublishReply(context.Background(), command.ChannelReplyAddress, []byte(`{}`))
+		assert.ErrorIs(t, err, command.ErrChannelBusClosed)
+	})
+}
+
 func TestChannelBusClose(t *testing.T) {
 	t.Parallel()
 
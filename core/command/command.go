@@ -12,6 +12,9 @@ type Command struct {
 	Name      string    `json:"name"`
 	Payload   any       `json:"payload"`
 	CreatedAt time.Time `json:"created_at"`
+
+	// ReplyTo is the address of the inbox awaiting the handler's result, set by Ask.
+	ReplyTo string `json:"reply_to,omitempty"`
 }
 
 // NewCommand creates a new Command with auto-generated ID and timestamp.
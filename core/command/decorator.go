@@ -2,6 +2,11 @@ package command
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
 	"time"
 )
 
@@ -66,3 +71,198 @@ func WithTimeout[T any](timeout time.Duration) Decorator[T] {
 		}
 	}
 }
+
+// RetryPolicy configures WithRetry. Zero fields take the defaults of DefaultRetryPolicy,
+// except JitterFactor: zero jitter is allowed for deterministic delays.
+type RetryPolicy struct {
+	MaxAttempts     int           // Attempts including the first one
+	InitialInterval time.Duration // Delay before the first retry
+	MaxInterval     time.Duration // Upper bound of the delay
+	Multiplier      float64       // Growth factor of the delay per retry
+	JitterFactor    float64       // Random spread of each delay, from 0 to 1
+
+	// Retryable reports whether a failed attempt should be retried.
+	// Nil retries every error except ErrCircuitOpen.
+	Retryable func(error) bool
+}
+
+// DefaultRetryPolicy returns a policy of 3 attempts with exponential backoff
+// from 100ms to 10s and 20% jitter.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:     3,
+		InitialInterval: 100 * time.Millisecond,
+		MaxInterval:     10 * time.Second,
+		Multiplier:      2,
+		JitterFactor:    0.2,
+	}
+}
+
+// Backoff returns the delay before the given retry, starting at 1:
+// min(InitialInterval * Multiplier^(retry-1), MaxInterval), spread by JitterFactor.
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	if retry <= 0 {
+		return 0
+	}
+
+	defaults := DefaultRetryPolicy()
+	initial := p.InitialInterval
+	if initial <= 0 {
+		initial = defaults.InitialInterval
+	}
+	maxInterval := p.MaxInterval
+	if maxInterval <= 0 {
+		maxInterval = defaults.MaxInterval
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = defaults.Multiplier
+	}
+
+	interval := math.Min(float64(initial)*math.Pow(multiplier, float64(retry-1)), float64(maxInterval))
+	if jitter := math.Min(p.JitterFactor, 1); jitter > 0 {
+		interval *= 1 + (rand.Float64()*2-1)*jitter
+	}
+
+	return time.Duration(interval)
+}
+
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts <= 0 {
+		return DefaultRetryPolicy().MaxAttempts
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return !errors.Is(err, ErrCircuitOpen)
+}
+
+// WithRetry creates a decorator that retries a failed handler with backoff.
+// Waiting stops as soon as the context is cancelled, returning the last error.
+// Place it inside WithTimeout to bound every attempt, or outside to bound them all.
+//
+// Example:
+//
+//	handler := command.NewHandlerFunc(
+//	    command.ApplyDecorators(
+//	        chargeCard,
+//	        command.WithRetry[ChargeCard](command.RetryPolicy{
+//	            MaxAttempts: 5,
+//	            Retryable:   func(err error) bool { return !errors.Is(err, ErrCardDeclined) },
+//	        }),
+//	    ),
+//	)
+func WithRetry[T any](policy RetryPolicy) Decorator[T] {
+	return func(next HandlerFunc[T]) HandlerFunc[T] {
+		return func(ctx context.Context, payload T) error {
+			for attempt := 1; ; attempt++ {
+				err := next(ctx, payload)
+				if err == nil || attempt >= policy.maxAttempts() || !policy.retryable(err) || ctx.Err() != nil {
+					return err
+				}
+
+				timer := time.NewTimer(policy.Backoff(attempt))
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return err
+				case <-timer.C:
+				}
+			}
+		}
+	}
+}
+
+// WithDeadLetter creates a decorator that hands a command whose handler failed to
+// the sink and reports it as handled, so it is acknowledged instead of redelivered.
+// Place it outside WithRetry so only commands that exhausted their retries reach it.
+//
+// Failures caused by shutdown (a cancelled context) and ErrCircuitOpen are not
+// permanent and pass through. If the sink fails, both errors are returned.
+//
+// Example:
+//
+//	sink := command.NewQueueDeadLetterSink(dlq, "")
+//	handler := command.NewHandlerFunc(
+//	    command.ApplyDecorators(
+//	        chargeCard,
+//	        command.WithDeadLetter[ChargeCard](sink),
+//	        command.WithRetry[ChargeCard](command.DefaultRetryPolicy()),
+//	    ),
+//	)
+func WithDeadLetter[T any](sink DeadLetterSink) Decorator[T] {
+	return func(next HandlerFunc[T]) HandlerFunc[T] {
+		return func(ctx context.Context, payload T) error {
+			err := next(ctx, payload)
+			if err == nil || errors.Is(err, ErrCircuitOpen) || errors.Is(ctx.Err(), context.Canceled) {
+				return err
+			}
+
+			letter := DeadLetter{
+				ID:       CommandID(ctx),
+				Name:     CommandName(ctx),
+				Error:    err.Error(),
+				FailedAt: time.Now(),
+			}
+			if letter.Name == "" {
+				letter.Name = getCommandName(payload)
+			}
+			if data, marshalErr := json.Marshal(payload); marshalErr == nil {
+				letter.Payload = data
+			}
+
+			// The handler's deadline may be what failed it; storing must not inherit it
+			if sinkErr := sink.Store(context.WithoutCancel(ctx), letter); sinkErr != nil {
+				return errors.Join(err, fmt.Errorf("failed to dead-letter command: %w", sinkErr))
+			}
+			return nil
+		}
+	}
+}
+
+// CircuitBreaker tracks handler failures and rejects calls while too many of them
+// fail in a row. Satisfied by *webhook.CircuitBreaker from pkg/webhook.
+type CircuitBreaker interface {
+	Allow() bool
+	RecordSuccess()
+	RecordFailure()
+}
+
+// WithCircuitBreaker creates a decorator that returns ErrCircuitOpen without calling
+// the handler while the breaker is open, e.g. while a downstream service is down.
+// Share one breaker between the handlers that depend on the same service.
+// Cancellation by shutdown is not recorded as a failure.
+//
+// Example:
+//
+//	breaker := webhook.NewCircuitBreaker(5, 2, 30*time.Second)
+//	handler := command.NewHandlerFunc(
+//	    command.ApplyDecorators(
+//	        chargeCard,
+//	        command.WithDeadLetter[ChargeCard](sink),
+//	        command.WithRetry[ChargeCard](command.DefaultRetryPolicy()),
+//	        command.WithCircuitBreaker[ChargeCard](breaker),
+//	    ),
+//	)
+func WithCircuitBreaker[T any](cb CircuitBreaker) Decorator[T] {
+	return func(next HandlerFunc[T]) HandlerFunc[T] {
+		return func(ctx context.Context, payload T) error {
+			if !cb.Allow() {
+				return ErrCircuitOpen
+			}
+
+			err := next(ctx, payload)
+			switch {
+			case err == nil:
+				cb.RecordSuccess()
+			case !errors.Is(ctx.Err(), context.Canceled):
+				cb.RecordFailure()
+			}
+			return err
+		}
+	}
+}
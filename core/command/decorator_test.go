@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/dmitrymomot/foundation/core/command"
+	"github.com/dmitrymomot/foundation/core/queue"
+	"github.com/dmitrymomot/foundation/pkg/webhook"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -284,3 +286,183 @@ func TestDecoratorComposition(t *testing.T) {
 		assert.Equal(t, []string{"logging-start", "handler", "logging-end"}, executionLog)
 	})
 }
+
+func TestWithRetry(t *testing.T) {
+	t.Parallel()
+
+	errTransient := errors.New("transient")
+	fastPolicy := command.RetryPolicy{MaxAttempts: 3, InitialInterval: time.Millisecond, MaxInterval: time.Millisecond}
+
+	t.Run("retries until success", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		handler := command.ApplyDecorators(func(ctx context.Context, cmd DecoratorTestCommand) error {
+			calls++
+			if calls < 3 {
+				return errTransient
+			}
+			return nil
+		}, command.WithRetry[DecoratorTestCommand](fastPolicy))
+
+		require.NoError(t, handler(context.Background(), DecoratorTestCommand{}))
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("returns last error after max attempts", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		handler := command.ApplyDecorators(func(ctx context.Context, cmd DecoratorTestCommand) error {
+			calls++
+			return errTransient
+		}, command.WithRetry[DecoratorTestCommand](fastPolicy))
+
+		assert.ErrorIs(t, handler(context.Background(), DecoratorTestCommand{}), errTransient)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("skips errors that are not retryable", func(t *testing.T) {
+		t.Parallel()
+
+		errPermanent := errors.New("permanent")
+		policy := fastPolicy
+		policy.Retryable = func(err error) bool { return !errors.Is(err, errPermanent) }
+
+		var calls int
+		handler := command.ApplyDecorators(func(ctx context.Context, cmd DecoratorTestCommand) error {
+			calls++
+			return errPermanent
+		}, command.WithRetry[DecoratorTestCommand](policy))
+
+		assert.ErrorIs(t, handler(context.Background(), DecoratorTestCommand{}), errPermanent)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("stops waiting when context is cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		var calls int
+		handler := command.ApplyDecorators(func(ctx context.Context, cmd DecoratorTestCommand) error {
+			calls++
+			return errTransient
+		}, command.WithRetry[DecoratorTestCommand](command.RetryPolicy{MaxAttempts: 10, InitialInterval: time.Hour}))
+
+		start := time.Now()
+		assert.ErrorIs(t, handler(ctx, DecoratorTestCommand{}), errTransient)
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+
+	policy := command.RetryPolicy{InitialInterval: 100 * time.Millisecond, MaxInterval: time.Second, Multiplier: 2}
+	assert.Equal(t, time.Duration(0), policy.Backoff(0))
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1))
+	assert.Equal(t, 400*time.Millisecond, policy.Backoff(3))
+	assert.Equal(t, time.Second, policy.Backoff(10))
+
+	policy.JitterFactor = 0.5
+	for range 100 {
+		delay := policy.Backoff(1)
+		assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+		assert.LessOrEqual(t, delay, 150*time.Millisecond)
+	}
+}
+
+func TestWithDeadLetter(t *testing.T) {
+	t.Parallel()
+
+	errFailed := errors.New("card declined")
+	failing := func(ctx context.Context, cmd DecoratorTestCommand) error { return errFailed }
+
+	t.Run("stores failed command and reports it handled", func(t *testing.T) {
+		t.Parallel()
+
+		sink := command.NewMemoryDeadLetterSink()
+		handler := command.ApplyDecorators(failing, command.WithDeadLetter[DecoratorTestCommand](sink))
+
+		ctx := command.WithCommandMeta(context.Background(), command.NewCommand(DecoratorTestCommand{Value: "x"}))
+		require.NoError(t, handler(ctx, DecoratorTestCommand{Value: "x"}))
+
+		letters := sink.List()
+		require.Len(t, letters, 1)
+		assert.Equal(t, command.CommandID(ctx), letters[0].ID)
+		assert.Equal(t, "DecoratorTestCommand", letters[0].Name)
+		assert.JSONEq(t, `{"Value":"x"}`, string(letters[0].Payload))
+		assert.Equal(t, "card declined", letters[0].Error)
+	})
+
+	t.Run("returns both errors when the sink fails", func(t *testing.T) {
+		t.Parallel()
+
+		errSink := errors.New("sink down")
+		sink := command.DeadLetterSinkFunc(func(ctx context.Context, letter command.DeadLetter) error { return errSink })
+		handler := command.ApplyDecorators(failing, command.WithDeadLetter[DecoratorTestCommand](sink))
+
+		err := handler(context.Background(), DecoratorTestCommand{})
+		assert.ErrorIs(t, err, errFailed)
+		assert.ErrorIs(t, err, errSink)
+	})
+
+	t.Run("passes through shutdown and open circuit", func(t *testing.T) {
+		t.Parallel()
+
+		sink := command.NewMemoryDeadLetterSink()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		handler := command.ApplyDecorators(failing, command.WithDeadLetter[DecoratorTestCommand](sink))
+		assert.ErrorIs(t, handler(ctx, DecoratorTestCommand{}), errFailed)
+
+		handler = command.ApplyDecorators(func(ctx context.Context, cmd DecoratorTestCommand) error {
+			return command.ErrCircuitOpen
+		}, command.WithDeadLetter[DecoratorTestCommand](sink))
+		assert.ErrorIs(t, handler(context.Background(), DecoratorTestCommand{}), command.ErrCircuitOpen)
+
+		assert.Empty(t, sink.List())
+	})
+
+	t.Run("queue sink stores entry in the dead letter queue", func(t *testing.T) {
+		t.Parallel()
+
+		storage := queue.NewMemoryStorage()
+		dlq, err := queue.NewDeadLetterQueue(storage)
+		require.NoError(t, err)
+
+		handler := command.ApplyDecorators(failing,
+			command.WithDeadLetter[DecoratorTestCommand](command.NewQueueDeadLetterSink(dlq, "")))
+		require.NoError(t, handler(context.Background(), DecoratorTestCommand{Value: "x"}))
+
+		entries, err := dlq.List(context.Background(), queue.DLQFilter{Queue: command.DefaultDeadLetterQueue})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "DecoratorTestCommand", entries[0].TaskName)
+		assert.Equal(t, "card declined", entries[0].Error)
+		assert.JSONEq(t, `{"Value":"x"}`, string(entries[0].Payload))
+	})
+}
+
+func TestWithCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	breaker := webhook.NewCircuitBreaker(2, 1, time.Hour)
+
+	var calls int
+	handler := command.ApplyDecorators(func(ctx context.Context, cmd DecoratorTestCommand) error {
+		calls++
+		return errors.New("downstream unavailable")
+	}, command.WithCircuitBreaker[DecoratorTestCommand](breaker))
+
+	for range 2 {
+		assert.Error(t, handler(context.Background(), DecoratorTestCommand{}))
+	}
+	assert.ErrorIs(t, handler(context.Background(), DecoratorTestCommand{}), command.ErrCircuitOpen)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, webhook.CircuitOpen, breaker.State())
+}
@@ -21,6 +21,9 @@ const (
 
 	// DefaultStuckThreshold is the default number of active commands that indicates a stuck dispatcher.
 	DefaultStuckThreshold = 1000
+
+	// DefaultReplyTimeout is the default time allowed for publishing a command reply.
+	DefaultReplyTimeout = 5 * time.Second
 )
 
 // Dispatcher manages command handlers and coordinates command processing.
@@ -28,9 +31,11 @@ type Dispatcher struct {
 	handlers        map[string]Handler
 	commandBus      commandSource
 	fallbackHandler func(context.Context, Command) error
+	replyPublisher  ReplyPublisher
 	mu              sync.RWMutex
 
 	shutdownTimeout       time.Duration
+	replyTimeout          time.Duration
 	maxConcurrentHandlers int
 	handlerSemaphore      chan struct{}
 	staleThreshold        time.Duration
@@ -52,6 +57,15 @@ type commandSource interface {
 	Commands() <-chan []byte
 }
 
+// Acknowledger is implemented by command sources that redeliver commands until
+// they are acknowledged, such as durable message brokers. The dispatcher
+// acknowledges a command once its handler succeeds. Commands that cannot be
+// decoded or have no handler are acknowledged right away, so they are not
+// redelivered forever; failed and panicked commands are left for redelivery.
+type Acknowledger interface {
+	Ack(ctx context.Context, data []byte) error
+}
+
 // DispatcherStats provides observability metrics for monitoring and debugging.
 type DispatcherStats struct {
 	CommandsProcessed int64
@@ -73,6 +87,7 @@ func NewDispatcher(opts ...DispatcherOption) *Dispatcher {
 	d := &Dispatcher{
 		handlers:              make(map[string]Handler),
 		shutdownTimeout:       DefaultShutdownTimeout,
+		replyTimeout:          DefaultReplyTimeout,
 		maxConcurrentHandlers: 0, // 0 means unlimited
 		staleThreshold:        DefaultStaleThreshold,
 		stuckThreshold:        DefaultStuckThreshold,
@@ -137,16 +152,20 @@ func (d *Dispatcher) Start(ctx context.Context) error {
 			if err := json.Unmarshal(data, &command); err != nil {
 				d.logger.ErrorContext(dispCtx, "failed to unmarshal command",
 					slog.String("error", err.Error()))
+				d.ack(dispCtx, data)
 				continue
 			}
 
-			if err := d.processHandler(dispCtx, command); err != nil {
+			if err := d.processHandler(dispCtx, command, data); err != nil {
 				if !errors.Is(err, ErrNoHandler) {
 					d.logger.ErrorContext(dispCtx, "failed to process command",
 						slog.String("command_id", command.ID),
 						slog.String("command_name", command.Name),
 						slog.String("error", err.Error()))
 				}
+				// Fail the asking caller fast instead of letting it time out
+				d.reply(dispCtx, command, nil, err)
+				d.ack(dispCtx, data)
 			}
 		}
 	}
@@ -236,7 +255,61 @@ func (d *Dispatcher) releaseSemaphore() {
 	}
 }
 
-func (d *Dispatcher) processHandler(ctx context.Context, command Command) error {
+// ack acknowledges the command data if the source needs acknowledgements.
+// It runs after the dispatcher context may have been cancelled, so a command
+// handled during shutdown is still acknowledged.
+func (d *Dispatcher) ack(ctx context.Context, data []byte) {
+	acker, ok := d.commandBus.(Acknowledger)
+	if !ok {
+		return
+	}
+	if err := acker.Ack(context.WithoutCancel(ctx), data); err != nil {
+		d.logger.ErrorContext(ctx, "failed to acknowledge command",
+			slog.String("error", err.Error()))
+	}
+}
+
+// reply publishes the handler's result to the inbox of a command sent with Ask.
+// Commands sent without Ask have no reply address and get no reply.
+func (d *Dispatcher) reply(ctx context.Context, command Command, payload any, handlerErr error) {
+	if command.ReplyTo == "" {
+		return
+	}
+	if d.replyPublisher == nil {
+		d.logger.WarnContext(ctx, "command expects a reply but no reply publisher is configured",
+			slog.String("command_id", command.ID),
+			slog.String("command_name", command.Name))
+		return
+	}
+
+	reply := Reply{CommandID: command.ID, CreatedAt: time.Now()}
+	if handlerErr != nil {
+		reply.Error = handlerErr.Error()
+	} else if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			reply.Error = fmt.Sprintf("failed to marshal reply: %v", err)
+		} else {
+			reply.Payload = data
+		}
+	}
+
+	data, err := json.Marshal(reply)
+	if err == nil {
+		// The reply outlives shutdown, but not a reply transport nobody drains
+		replyCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), d.replyTimeout)
+		err = d.replyPublisher.PublishReply(replyCtx, command.ReplyTo, data)
+		cancel()
+	}
+	if err != nil {
+		d.logger.ErrorContext(ctx, "failed to publish command reply",
+			slog.String("command_id", command.ID),
+			slog.String("command_name", command.Name),
+			slog.String("error", err.Error()))
+	}
+}
+
+func (d *Dispatcher) processHandler(ctx context.Context, command Command, data []byte) error {
 	d.mu.RLock()
 	handler, exists := d.handlers[command.Name]
 	fallback := d.fallbackHandler
@@ -283,6 +356,7 @@ func (d *Dispatcher) processHandler(ctx context.Context, command Command) error
 						slog.String("command_id", command.ID),
 						slog.String("command_name", command.Name),
 						slog.Duration("duration", time.Since(start)))
+					d.ack(handlerCtx, data)
 				}
 
 				d.lastActivityAt.Store(time.Now().UnixNano())
@@ -302,6 +376,11 @@ func (d *Dispatcher) processHandler(ctx context.Context, command Command) error
 
 		handlerCtx := WithStartProcessingTime(WithCommandMeta(ctx, command), time.Now())
 
+		slot := &replySlot{}
+		if command.ReplyTo != "" {
+			handlerCtx = withReplySlot(handlerCtx, slot)
+		}
+
 		if !d.acquireSemaphore(handlerCtx) {
 			return
 		}
@@ -315,12 +394,16 @@ func (d *Dispatcher) processHandler(ctx context.Context, command Command) error
 					slog.String("command_name", command.Name),
 					slog.String("handler", handler.CommandName()),
 					slog.Any("panic", r))
+				d.reply(handlerCtx, command, nil, fmt.Errorf("command handler panicked: %v", r))
 			}
 		}()
 
 		start := time.Now()
 
-		if err := handler.Handle(handlerCtx, command.Payload); err != nil {
+		err := handler.Handle(handlerCtx, command.Payload)
+		d.reply(handlerCtx, command, slot.payload, err)
+
+		if err != nil {
 			d.commandsFailed.Add(1)
 			d.logger.ErrorContext(handlerCtx, "command handler failed",
 				slog.String("command_id", command.ID),
@@ -335,6 +418,7 @@ func (d *Dispatcher) processHandler(ctx context.Context, command Command) error
 				slog.String("command_name", command.Name),
 				slog.String("handler", handler.CommandName()),
 				slog.Duration("duration", time.Since(start)))
+			d.ack(handlerCtx, data)
 		}
 
 		d.lastActivityAt.Store(time.Now().UnixNano())
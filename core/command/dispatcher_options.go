@@ -129,3 +129,32 @@ func WithFallbackHandler(fn func(context.Context, Command) error) DispatcherOpti
 		}
 	}
 }
+
+// WithReplyPublisher sets where the dispatcher publishes the results of commands
+// sent with Ask. Without it, such commands are handled but never answered.
+//
+// Example:
+//
+//	dispatcher := command.NewDispatcher(
+//	    command.WithCommandSource(bus),
+//	    command.WithReplyPublisher(bus),
+//	    command.WithHandler(command.NewReplyHandlerFunc(getBalance)),
+//	)
+func WithReplyPublisher(publisher ReplyPublisher) DispatcherOption {
+	return func(d *Dispatcher) {
+		if publisher != nil {
+			d.replyPublisher = publisher
+		}
+	}
+}
+
+// WithReplyTimeout sets how long publishing a reply may take before it is
+// dropped and logged. The asking sender then times out as if no reply came.
+// Default is DefaultReplyTimeout.
+func WithReplyTimeout(d time.Duration) DispatcherOption {
+	return func(disp *Dispatcher) {
+		if d > 0 {
+			disp.replyTimeout = d
+		}
+	}
+}
@@ -1064,3 +1064,62 @@ func TestDispatcherContextPropagation(t *testing.T) {
 		cancel()
 	})
 }
+
+// ackingBus records the acknowledged commands of a ChannelBus.
+type ackingBus struct {
+	*command.ChannelBus
+	mu    sync.Mutex
+	acked [][]byte
+}
+
+func (b *ackingBus) Ack(_ context.Context, data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.acked = append(b.acked, data)
+	return nil
+}
+
+func (b *ackingBus) ackCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.acked)
+}
+
+func TestDispatcherAcknowledgement(t *testing.T) {
+	t.Parallel()
+
+	bus := &ackingBus{ChannelBus: command.NewChannelBus()}
+	defer bus.Close()
+
+	var handled atomic.Int32
+	dispatcher := command.NewDispatcher(
+		command.WithCommandSource(bus),
+		command.WithHandler(command.NewHandlerFunc(func(ctx context.Context, cmd DispatcherTestCommand) error {
+			handled.Add(1)
+			if cmd.Value == "fail" {
+				return errors.New("handler failed")
+			}
+			return nil
+		})),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = dispatcher.Start(ctx) }()
+
+	sender := command.NewSender(bus)
+	require.NoError(t, sender.Send(ctx, DispatcherTestCommand{Value: "ok"}))
+	require.NoError(t, sender.Send(ctx, DispatcherTestCommand{Value: "fail"}))
+	require.NoError(t, sender.Send(ctx, AnotherCommand{Data: "no handler"}))
+	require.NoError(t, bus.Publish(ctx, []byte("not json")))
+
+	require.Eventually(t, func() bool { return handled.Load() == 2 && bus.ackCount() == 3 }, time.Second, 5*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	require.Len(t, bus.acked, 3, "the failed command is left for redelivery")
+	for _, data := range bus.acked {
+		assert.NotContains(t, string(data), `"fail"`)
+	}
+}
@@ -129,10 +129,98 @@
 //			handleCreateUser,
 //			LoggingDecorator[CreateUser],
 //			MetricsDecorator[CreateUser],
-//			RetryDecorator[CreateUser],
+//			command.WithRetry[CreateUser](command.DefaultRetryPolicy()),
 //		),
 //	)
 //
+// # Retries, Dead Letters and Circuit Breaking
+//
+// A failed command is logged and dropped unless the bus redelivers it. Three
+// built-in decorators make failures recoverable:
+//
+//   - WithRetry retries with exponential backoff and jitter (RetryPolicy), and stops
+//     waiting when the context is cancelled
+//   - WithDeadLetter hands a command that still fails to a DeadLetterSink and reports
+//     it handled: NewMemoryDeadLetterSink, NewLogDeadLetterSink, or
+//     NewQueueDeadLetterSink for the dead letter queue of core/queue
+//   - WithCircuitBreaker fails fast with ErrCircuitOpen while a downstream service is
+//     down, using a breaker such as *webhook.CircuitBreaker from pkg/webhook
+//
+// Order them dead letter first, circuit breaker last, so each attempt is counted by
+// the breaker and only commands that exhausted their retries are dead-lettered:
+//
+//	handler := command.NewHandlerFunc(
+//		command.ApplyDecorators(
+//			handleChargeCard,
+//			command.WithDeadLetter[ChargeCard](command.NewQueueDeadLetterSink(dlq, "")),
+//			command.WithRetry[ChargeCard](command.DefaultRetryPolicy()),
+//			command.WithCircuitBreaker[ChargeCard](webhook.NewCircuitBreaker(5, 2, 30*time.Second)),
+//		),
+//	)
+//
+// ErrCircuitOpen is neither retried nor dead-lettered; it reaches the dispatcher so a
+// redelivering bus can try again later.
+//
+// # Idempotency
+//
+// Durable buses redeliver commands, so handlers must tolerate seeing one twice.
+// WithIdempotency skips commands a handler has already processed, keyed by
+// CommandID(ctx) and a handler name, and records each success in a ProcessedStore:
+//
+//	handler := command.NewHandlerFunc(
+//		command.ApplyDecorators(
+//			handleChargeCard,
+//			command.WithIdempotency[ChargeCard](store, "billing.charge_card"),
+//		),
+//	)
+//
+// NewMemoryProcessedStore serves tests and single processes;
+// integration/idempotency/redisstore and integration/idempotency/pgstore serve
+// distributed deployments. The PostgreSQL store runs the check, the handler and the
+// record in one transaction (see pg.WithTx), so they commit together.
+//
+// # Request/Reply
+//
+// Send is fire-and-forget. Ask sends a command and waits for its handler's typed
+// result, correlated by command ID, so the dispatcher can also serve queries and
+// synchronous steps across processes:
+//
+//	// Handler side: return a result and publish replies
+//	dispatcher := command.NewDispatcher(
+//		command.WithCommandSource(bus),
+//		command.WithReplyPublisher(bus),
+//		command.WithHandler(command.NewReplyHandlerFunc(
+//			func(ctx context.Context, q GetBalance) (Balance, error) {
+//				return accounts.Balance(ctx, q.AccountID)
+//			},
+//		)),
+//	)
+//
+//	// Asking side: receive replies in an inbox
+//	inbox := command.NewInbox(bus)
+//	sender := command.NewSender(bus, command.WithReplyInbox(inbox))
+//	g.Go(inbox.Run(ctx))
+//
+//	balance, err := command.Ask[GetBalance, Balance](ctx, sender, GetBalance{AccountID: id})
+//
+// Ask waits until the context deadline, or WithAskTimeout (DefaultAskTimeout)
+// without one, and returns ErrReplyTimeout when no reply arrives. Handler errors,
+// panics and commands without a handler come back as ErrCommandFailed. Decorate
+// reply handlers by adapting them with Replying:
+//
+//	command.NewHandlerFunc(command.ApplyDecorators(
+//		command.Replying(getBalance),
+//		command.WithTimeout[GetBalance](time.Second),
+//	))
+//
+// ChannelBus carries replies within a process. integration/bus/redisstream carries
+// them between processes: the Bus publishes replies, and a ReplyInbox reads them
+// from a stream owned by the asking process.
+//
+// Publishing a reply is bounded by WithReplyTimeout (DefaultReplyTimeout), and
+// ChannelBus drops replies with ErrReplyBufferFull when no inbox drains them, so
+// a stopped inbox never blocks handlers or dispatcher shutdown.
+//
 // # Dispatcher Lifecycle
 //
 // The dispatcher supports three lifecycle patterns:
@@ -274,25 +362,33 @@
 //		Commands() <-chan []byte
 //	}
 //
-//	// Example: Redis Streams implementation
-//	type RedisCommandBus struct {
-//		client *redis.Client
-//		stream string
+//	// Optional: for sources that redeliver commands until acknowledged
+//	type Acknowledger interface {
+//		Ack(ctx context.Context, data []byte) error
 //	}
 //
-//	func (r *RedisCommandBus) Publish(ctx context.Context, data []byte) error {
-//		return r.client.XAdd(ctx, &redis.XAddArgs{
-//			Stream: r.stream,
-//			Values: map[string]interface{}{"data": data},
-//		}).Err()
-//	}
+// The dispatcher calls Ack with the received data once the handler succeeds.
+// Commands whose handler fails or panics are not acknowledged, so the source
+// redelivers them.
+//
+// integration/bus/redisstream provides a durable bus on Redis Streams with
+// consumer groups, so the dispatcher can run in a separate process from the
+// senders without losing commands on a crash:
 //
-//	func (r *RedisCommandBus) Commands() <-chan []byte {
-//		ch := make(chan []byte)
-//		go r.consumeStream(ch)
-//		return ch
+//	bus, err := redisstream.New(redisClient, "commands", redisstream.WithGroup("dispatchers"))
+//	if err != nil {
+//		return err
 //	}
 //
+//	sender := command.NewSender(bus) // in the HTTP server
+//
+//	dispatcher := command.NewDispatcher( // in the worker process
+//		command.WithCommandSource(bus),
+//		command.WithHandler(handler),
+//	)
+//	g.Go(bus.Run(ctx))
+//	g.Go(dispatcher.Run(ctx))
+//
 // # Error Handling
 //
 // The package defines these error types:
@@ -32,4 +32,25 @@ var (
 
 	// ErrDispatcherStuck is returned when the dispatcher has too many active commands.
 	ErrDispatcherStuck = errors.New("dispatcher may be stuck - too many active commands")
+
+	// ErrCircuitOpen is returned by WithCircuitBreaker while the circuit breaker rejects calls.
+	ErrCircuitOpen = errors.New("circuit breaker is open")
+
+	// ErrNoReplyInbox is returned by Ask when the sender has no reply inbox.
+	ErrNoReplyInbox = errors.New("sender has no reply inbox")
+
+	// ErrReplyTimeout is returned by Ask when no reply arrives in time.
+	ErrReplyTimeout = errors.New("timed out waiting for command reply")
+
+	// ErrCommandFailed is returned by Ask when the command handler failed.
+	ErrCommandFailed = errors.New("command handler failed")
+
+	// ErrInboxAlreadyStarted is returned when attempting to start an already running inbox.
+	ErrInboxAlreadyStarted = errors.New("inbox already started")
+
+	// ErrReplyBufferFull is returned when a channel bus drops a reply because no inbox drains its buffer.
+	ErrReplyBufferFull = errors.New("reply buffer is full")
+
+	// ErrInboxNotStarted is returned when attempting to stop an inbox that hasn't been started.
+	ErrInboxNotStarted = errors.New("inbox not started")
 )
@@ -0,0 +1,118 @@
+package command_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/command"
+)
+
+// txStore records the transactions the decorator runs the handler in.
+type txStore struct {
+	*command.MemoryProcessedStore
+	runs int
+}
+
+func (s *txStore) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	s.runs++
+	return fn(ctx)
+}
+
+func TestWithIdempotency(t *testing.T) {
+	t.Parallel()
+
+	newCtx := func() context.Context {
+		return command.WithCommandMeta(context.Background(), command.NewCommand(DecoratorTestCommand{}))
+	}
+
+	t.Run("skips processed commands", func(t *testing.T) {
+		t.Parallel()
+
+		store := command.NewMemoryProcessedStore(0)
+		var calls int
+		handler := command.ApplyDecorators(func(ctx context.Context, cmd DecoratorTestCommand) error {
+			calls++
+			return nil
+		}, command.WithIdempotency[DecoratorTestCommand](store, "test.handler"))
+
+		ctx := newCtx()
+		require.NoError(t, handler(ctx, DecoratorTestCommand{}))
+		require.NoError(t, handler(ctx, DecoratorTestCommand{}))
+		assert.Equal(t, 1, calls)
+
+		require.NoError(t, handler(newCtx(), DecoratorTestCommand{}))
+		assert.Equal(t, 2, calls, "another command runs")
+
+		processed, err := store.IsProcessed(ctx, "other.handler", command.CommandID(ctx))
+		require.NoError(t, err)
+		assert.False(t, processed, "records are per handler")
+	})
+
+	t.Run("does not record failures", func(t *testing.T) {
+		t.Parallel()
+
+		store := command.NewMemoryProcessedStore(0)
+		var calls int
+		handler := command.ApplyDecorators(func(ctx context.Context, cmd DecoratorTestCommand) error {
+			calls++
+			if calls == 1 {
+				return errors.New("failed")
+			}
+			return nil
+		}, command.WithIdempotency[DecoratorTestCommand](store, "test.handler"))
+
+		ctx := newCtx()
+		assert.Error(t, handler(ctx, DecoratorTestCommand{}))
+		require.NoError(t, handler(ctx, DecoratorTestCommand{}))
+		require.NoError(t, handler(ctx, DecoratorTestCommand{}))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("handles commands without an id every time", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+		handler := command.ApplyDecorators(func(ctx context.Context, cmd DecoratorTestCommand) error {
+			calls++
+			return nil
+		}, command.WithIdempotency[DecoratorTestCommand](command.NewMemoryProcessedStore(0), "test.handler"))
+
+		require.NoError(t, handler(context.Background(), DecoratorTestCommand{}))
+		require.NoError(t, handler(context.Background(), DecoratorTestCommand{}))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("runs in the store transaction", func(t *testing.T) {
+		t.Parallel()
+
+		store := &txStore{MemoryProcessedStore: command.NewMemoryProcessedStore(0)}
+		handler := command.ApplyDecorators(func(ctx context.Context, cmd DecoratorTestCommand) error {
+			return nil
+		}, command.WithIdempotency[DecoratorTestCommand](store, "test.handler"))
+
+		require.NoError(t, handler(newCtx(), DecoratorTestCommand{}))
+		assert.Equal(t, 1, store.runs)
+	})
+}
+
+func TestMemoryProcessedStore_TTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := command.NewMemoryProcessedStore(20 * time.Millisecond)
+	require.NoError(t, store.MarkProcessed(ctx, "h", "1"))
+
+	processed, err := store.IsProcessed(ctx, "h", "1")
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	assert.Eventually(t, func() bool {
+		processed, err := store.IsProcessed(ctx, "h", "1")
+		return err == nil && !processed
+	}, time.Second, 5*time.Millisecond)
+}
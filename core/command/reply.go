@@ -0,0 +1,73 @@
+package command
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Reply carries the result of a command sent with Ask back to the asking inbox.
+type Reply struct {
+	CommandID string          `json:"command_id"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// ReplyPublisher delivers replies to the inbox at address. Configure it on the
+// dispatcher with WithReplyPublisher. Implemented by ChannelBus and
+// integration/bus/redisstream.
+type ReplyPublisher interface {
+	PublishReply(ctx context.Context, address string, data []byte) error
+}
+
+// ReplyHandlerFunc is a type-safe function signature for commands that return a result.
+type ReplyHandlerFunc[Req, Resp any] func(context.Context, Req) (Resp, error)
+
+// Replying adapts a reply handler function to a HandlerFunc, so it can be
+// decorated like any other handler. The dispatcher sends the result to the
+// asking inbox; commands sent without Ask discard it.
+//
+// Example:
+//
+//	handler := command.NewHandlerFunc(
+//	    command.ApplyDecorators(
+//	        command.Replying(getBalance),
+//	        command.WithTimeout[GetBalance](time.Second),
+//	    ),
+//	)
+func Replying[Req, Resp any](fn ReplyHandlerFunc[Req, Resp]) HandlerFunc[Req] {
+	return func(ctx context.Context, req Req) error {
+		resp, err := fn(ctx, req)
+		if err != nil {
+			return err
+		}
+		if slot, ok := ctx.Value(replySlotCtx{}).(*replySlot); ok {
+			slot.payload = resp
+		}
+		return nil
+	}
+}
+
+// NewReplyHandlerFunc creates a handler for commands that return a result.
+// The command name is derived from Req, like NewHandlerFunc.
+//
+// Example:
+//
+//	handler := command.NewReplyHandlerFunc(func(ctx context.Context, q GetBalance) (Balance, error) {
+//	    return accounts.Balance(ctx, q.AccountID)
+//	})
+func NewReplyHandlerFunc[Req, Resp any](fn ReplyHandlerFunc[Req, Resp]) Handler {
+	return NewHandlerFunc(Replying(fn))
+}
+
+type replySlotCtx struct{}
+
+// replySlot receives the result of a Replying handler for the dispatcher.
+type replySlot struct {
+	payload any
+}
+
+func withReplySlot(ctx context.Context, slot *replySlot) context.Context {
+	return context.WithValue(ctx, replySlotCtx{}, slot)
+}
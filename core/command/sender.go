@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"io"
 	"log/slog"
+	"time"
 )
 
 // commandBus represents a message bus that can publish commands.
@@ -14,8 +15,10 @@ type commandBus interface {
 
 // Sender publishes commands to a command bus.
 type Sender struct {
-	bus    commandBus
-	logger *slog.Logger
+	bus        commandBus
+	inbox      *Inbox
+	askTimeout time.Duration
+	logger     *slog.Logger
 }
 
 // SenderOption configures a Sender.
@@ -31,6 +34,25 @@ func WithSenderLogger(logger *slog.Logger) SenderOption {
 	}
 }
 
+// WithReplyInbox sets the inbox that receives replies for commands sent with Ask.
+func WithReplyInbox(inbox *Inbox) SenderOption {
+	return func(s *Sender) {
+		if inbox != nil {
+			s.inbox = inbox
+		}
+	}
+}
+
+// WithAskTimeout sets how long Ask waits for a reply when the context has no
+// deadline. Default is DefaultAskTimeout.
+func WithAskTimeout(timeout time.Duration) SenderOption {
+	return func(s *Sender) {
+		if timeout > 0 {
+			s.askTimeout = timeout
+		}
+	}
+}
+
 // NewSender creates a new command sender with the given command bus.
 //
 // Example:
@@ -39,8 +61,9 @@ func WithSenderLogger(logger *slog.Logger) SenderOption {
 //	err := sender.Send(ctx, CreateUser{UserID: "123", Email: "user@example.com"})
 func NewSender(bus commandBus, opts ...SenderOption) *Sender {
 	s := &Sender{
-		bus:    bus,
-		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		bus:        bus,
+		askTimeout: DefaultAskTimeout,
+		logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
 	}
 
 	for _, opt := range opts {
@@ -54,8 +77,17 @@ func NewSender(bus commandBus, opts ...SenderOption) *Sender {
 // The command name is automatically derived from the payload type.
 // The Command is marshaled to JSON before publishing.
 func (s *Sender) Send(ctx context.Context, payload any) error {
-	command := NewCommand(payload)
+	return s.publish(ctx, NewCommand(payload))
+}
+
+// SendCommand publishes a prepared command as is, keeping its ID and name, such
+// as a command built earlier with NewCommand and stored to be sent later.
+// Resending a command under the same ID lets idempotent handlers skip it.
+func (s *Sender) SendCommand(ctx context.Context, command Command) error {
+	return s.publish(ctx, command)
+}
 
+func (s *Sender) publish(ctx context.Context, command Command) error {
 	data, err := json.Marshal(command)
 	if err != nil {
 		s.logger.ErrorContext(ctx, "failed to marshal command",
@@ -140,6 +140,27 @@ func TestSenderSend(t *testing.T) {
 	})
 }
 
+func TestSenderSendCommand(t *testing.T) {
+	t.Parallel()
+
+	var capturedData []byte
+	mockBus := &MockCommandBus{}
+	mockBus.On("Publish", mock.Anything, mock.MatchedBy(func(data []byte) bool {
+		capturedData = data
+		return true
+	})).Return(nil)
+
+	sender := command.NewSender(mockBus)
+	prepared := command.NewCommand(SendTestCommand{ID: "789"})
+
+	require.NoError(t, sender.SendCommand(context.Background(), prepared))
+
+	var cmd command.Command
+	require.NoError(t, json.Unmarshal(capturedData, &cmd))
+	assert.Equal(t, prepared.ID, cmd.ID)
+	assert.Equal(t, "SendTestCommand", cmd.Name)
+}
+
 func TestSenderWithRealBus(t *testing.T) {
 	t.Parallel()
 
@@ -37,6 +37,27 @@ func EventName(ctx context.Context) string {
 	return ""
 }
 
+type eventVersionCtx struct{}
+
+// WithEventVersion attaches an event schema version to the context.
+func WithEventVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, eventVersionCtx{}, version)
+}
+
+// EventVersion extracts the event schema version from the context.
+// Returns 0 if not present.
+func EventVersion(ctx context.Context) int {
+	version, _ := eventVersion(ctx)
+	return version
+}
+
+// eventVersion extracts the event schema version and reports whether the
+// context carries one at all.
+func eventVersion(ctx context.Context) (int, bool) {
+	version, ok := ctx.Value(eventVersionCtx{}).(int)
+	return version, ok
+}
+
 type eventTimeCtx struct{}
 
 // WithEventTime attaches the event creation time to the context.
@@ -53,10 +74,11 @@ func EventTime(ctx context.Context) time.Time {
 	return time.Time{}
 }
 
-// WithEventMeta attaches all event metadata (ID, Name, CreatedAt) to the context.
+// WithEventMeta attaches all event metadata (ID, Name, Version, CreatedAt) to the context.
 func WithEventMeta(ctx context.Context, event Event) context.Context {
 	ctx = WithEventID(ctx, event.ID)
 	ctx = WithEventName(ctx, event.Name)
+	ctx = WithEventVersion(ctx, event.Version)
 	ctx = WithEventTime(ctx, event.CreatedAt)
 	return ctx
 }
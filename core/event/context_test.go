@@ -132,6 +132,7 @@ func TestWithEventMeta(t *testing.T) {
 		testEvent := event.Event{
 			ID:        "evt_meta_123",
 			Name:      "TestEvent",
+			Version:   2,
 			CreatedAt: time.Date(2025, 10, 2, 14, 30, 0, 0, time.UTC),
 			Payload:   "test payload",
 		}
@@ -140,6 +141,7 @@ func TestWithEventMeta(t *testing.T) {
 
 		assert.Equal(t, testEvent.ID, event.EventID(ctx))
 		assert.Equal(t, testEvent.Name, event.EventName(ctx))
+		assert.Equal(t, testEvent.Version, event.EventVersion(ctx))
 		assert.Equal(t, testEvent.CreatedAt, event.EventTime(ctx))
 	})
 
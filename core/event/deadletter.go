@@ -0,0 +1,111 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+// DefaultDeadLetterQueue is the queue name under which NewQueueDeadLetterSink stores events.
+const DefaultDeadLetterQueue = "events"
+
+// DeadLetter is an event whose handler failed permanently.
+type DeadLetter struct {
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	Payload  json.RawMessage `json:"payload,omitempty"`
+	Error    string          `json:"error"`
+	FailedAt time.Time       `json:"failed_at"`
+}
+
+// DeadLetterSink stores events handed over by WithDeadLetter.
+type DeadLetterSink interface {
+	Store(ctx context.Context, letter DeadLetter) error
+}
+
+// DeadLetterSinkFunc adapts a function to DeadLetterSink.
+type DeadLetterSinkFunc func(ctx context.Context, letter DeadLetter) error
+
+// Store calls f(ctx, letter).
+func (f DeadLetterSinkFunc) Store(ctx context.Context, letter DeadLetter) error {
+	return f(ctx, letter)
+}
+
+// MemoryDeadLetterSink keeps dead letters in memory, for tests and development.
+// Safe for concurrent use.
+type MemoryDeadLetterSink struct {
+	mu      sync.Mutex
+	letters []DeadLetter
+}
+
+// NewMemoryDeadLetterSink creates an empty in-memory sink.
+func NewMemoryDeadLetterSink() *MemoryDeadLetterSink {
+	return &MemoryDeadLetterSink{}
+}
+
+// Store appends the dead letter.
+func (s *MemoryDeadLetterSink) Store(_ context.Context, letter DeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.letters = append(s.letters, letter)
+	return nil
+}
+
+// List returns the stored dead letters, oldest first.
+func (s *MemoryDeadLetterSink) List() []DeadLetter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.letters)
+}
+
+// NewLogDeadLetterSink creates a sink that only logs dead letters at error level,
+// with the payload, so they can be recovered from the logs.
+// A nil logger uses slog.Default().
+func NewLogDeadLetterSink(logger *slog.Logger) DeadLetterSink {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return DeadLetterSinkFunc(func(ctx context.Context, letter DeadLetter) error {
+		logger.ErrorContext(ctx, "event dead-lettered",
+			slog.String("event_id", letter.ID),
+			slog.String("event_name", letter.Name),
+			slog.String("payload", string(letter.Payload)),
+			slog.String("error", letter.Error))
+		return nil
+	})
+}
+
+// NewQueueDeadLetterSink creates a sink that stores dead letters in the dead letter
+// queue of core/queue under queueName (DefaultDeadLetterQueue if empty), where the
+// queue admin lists them next to failed tasks. The entry's task name is the event
+// name and its payload the event payload, so requeueing it enqueues a task for a
+// queue handler of the event type.
+func NewQueueDeadLetterSink(dlq *queue.DeadLetterQueue, queueName string) DeadLetterSink {
+	if queueName == "" {
+		queueName = DefaultDeadLetterQueue
+	}
+
+	return DeadLetterSinkFunc(func(ctx context.Context, letter DeadLetter) error {
+		// Event IDs are UUIDs; keeping them links the entry to the event in logs
+		taskID, _ := uuid.Parse(letter.ID)
+
+		return dlq.Add(ctx, &queue.TasksDlq{
+			TaskID:   taskID,
+			Queue:    queueName,
+			TaskName: letter.Name,
+			Payload:  letter.Payload,
+			Error:    letter.Error,
+			FailedAt: letter.FailedAt,
+		})
+	})
+}
@@ -0,0 +1,120 @@
+package event_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/event"
+	"github.com/dmitrymomot/foundation/pkg/webhook"
+)
+
+type ReceiptRequested struct {
+	OrderID string
+}
+
+func TestWithRetry(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+	handler := event.ApplyDecorators(func(ctx context.Context, evt ReceiptRequested) error {
+		calls++
+		if calls < 3 {
+			return errors.New("smtp unavailable")
+		}
+		return nil
+	}, event.WithRetry[ReceiptRequested](event.RetryPolicy{MaxAttempts: 3, InitialInterval: time.Millisecond}))
+
+	require.NoError(t, handler(context.Background(), ReceiptRequested{}))
+	assert.Equal(t, 3, calls)
+}
+
+func TestWithFilter(t *testing.T) {
+	t.Parallel()
+
+	var handled []string
+	handler := event.ApplyDecorators(func(ctx context.Context, evt ReceiptRequested) error {
+		handled = append(handled, evt.OrderID)
+		return nil
+	}, event.WithFilter(func(evt ReceiptRequested) bool { return evt.OrderID != "internal" }))
+
+	require.NoError(t, handler(context.Background(), ReceiptRequested{OrderID: "order-1"}))
+	require.NoError(t, handler(context.Background(), ReceiptRequested{OrderID: "internal"}))
+	assert.Equal(t, []string{"order-1"}, handled)
+}
+
+func TestWithDeadLetter(t *testing.T) {
+	t.Parallel()
+
+	sink := event.NewMemoryDeadLetterSink()
+	breaker := webhook.NewCircuitBreaker(1, 1, time.Hour)
+
+	var calls int
+	handler := event.ApplyDecorators(func(ctx context.Context, evt ReceiptRequested) error {
+		calls++
+		return errors.New("smtp unavailable")
+	},
+		event.WithDeadLetter[ReceiptRequested](sink),
+		event.WithRetry[ReceiptRequested](event.RetryPolicy{MaxAttempts: 3, InitialInterval: time.Millisecond}),
+		event.WithCircuitBreaker[ReceiptRequested](breaker),
+	)
+
+	evt := event.NewEvent(ReceiptRequested{OrderID: "42"})
+	ctx := event.WithEventMeta(context.Background(), evt)
+
+	// The first attempt opens the circuit, which ends the retries without dead-lettering
+	assert.ErrorIs(t, handler(ctx, ReceiptRequested{OrderID: "42"}), event.ErrCircuitOpen)
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, sink.List())
+
+	// A closed circuit lets the retries run out, and the event is dead-lettered
+	breaker.Reset()
+	handler = event.ApplyDecorators(func(ctx context.Context, evt ReceiptRequested) error {
+		return errors.New("smtp unavailable")
+	},
+		event.WithDeadLetter[ReceiptRequested](sink),
+		event.WithRetry[ReceiptRequested](event.RetryPolicy{MaxAttempts: 2, InitialInterval: time.Millisecond}),
+	)
+	require.NoError(t, handler(ctx, ReceiptRequested{OrderID: "42"}))
+
+	letters := sink.List()
+	require.Len(t, letters, 1)
+	assert.Equal(t, evt.ID, letters[0].ID)
+	assert.Equal(t, "ReceiptRequested", letters[0].Name)
+	assert.JSONEq(t, `{"OrderID":"42"}`, string(letters[0].Payload))
+	assert.Equal(t, "smtp unavailable", letters[0].Error)
+}
+
+func TestWithIdempotency(t *testing.T) {
+	t.Parallel()
+
+	store := event.NewMemoryProcessedStore(time.Hour)
+	var receipts, invoices int
+	sendReceipt := event.ApplyDecorators(func(ctx context.Context, evt ReceiptRequested) error {
+		receipts++
+		return nil
+	}, event.WithIdempotency[ReceiptRequested](store, "emails.send_receipt"))
+	createInvoice := event.ApplyDecorators(func(ctx context.Context, evt ReceiptRequested) error {
+		invoices++
+		if invoices == 1 {
+			return errors.New("database unavailable")
+		}
+		return nil
+	}, event.WithIdempotency[ReceiptRequested](store, "billing.create_invoice"))
+
+	ctx := event.WithEventMeta(context.Background(), event.NewEvent(ReceiptRequested{OrderID: "42"}))
+
+	// The event is redelivered because one of its handlers failed; the other one skips it
+	for range 2 {
+		require.NoError(t, sendReceipt(ctx, ReceiptRequested{OrderID: "42"}))
+		_ = createInvoice(ctx, ReceiptRequested{OrderID: "42"})
+	}
+	require.NoError(t, createInvoice(ctx, ReceiptRequested{OrderID: "42"}))
+
+	assert.Equal(t, 1, receipts)
+	assert.Equal(t, 2, invoices)
+}
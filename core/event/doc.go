@@ -4,7 +4,7 @@
 //
 // # Core Components
 //
-// Event represents a domain event with metadata (ID, Name, Payload, CreatedAt).
+// Event represents a domain event with metadata (ID, Name, Version, Payload, CreatedAt).
 // Events are automatically assigned UUIDs and timestamps upon creation.
 //
 // Handler processes events through a type-safe interface. Handlers can be created
@@ -105,6 +105,40 @@
 //		event.WithHandler(emailHandler, analyticsHandler),
 //	)
 //
+// # Topics and Wildcard Subscriptions
+//
+// Event names can be hierarchical topics separated by dots, such as
+// "billing.invoice.paid" (see Register). A handler whose event name contains
+// wildcard tokens subscribes to every matching event: "*" matches one token and
+// a trailing ">" matches one or more tokens; a ">" anywhere else makes the
+// pattern match nothing. Exact and wildcard handlers of an event all run; the
+// fallback handler runs only when none matches.
+//
+//	// Every two-token *.deleted event, whatever its payload type
+//	audit := event.NewHandler("*.deleted", func(ctx context.Context, payload json.RawMessage) error {
+//		return auditLog.Record(ctx, event.EventName(ctx), payload)
+//	})
+//
+//	// Every billing event
+//	billing := event.NewHandler("billing.>", func(ctx context.Context, payload map[string]any) error {
+//		return metrics.Increment(ctx, event.EventName(ctx))
+//	})
+//
+//	processor := event.NewProcessor(
+//		event.WithEventSource(bus),
+//		event.WithHandler(audit, billing),
+//	)
+//
+// WithFilter narrows a handler to the events whose decoded payload satisfies a
+// predicate; other events are skipped:
+//
+//	handler := event.NewHandlerFunc(
+//		event.ApplyDecorators(
+//			notifyFinance,
+//			event.WithFilter(func(evt InvoicePaid) bool { return evt.Amount >= 10_000 }),
+//		),
+//	)
+//
 // # Handler Decorators
 //
 // Apply cross-cutting concerns using decorators. Decorators execute in order (first = outermost):
@@ -144,6 +178,48 @@
 //		),
 //	)
 //
+// # Retries, Dead Letters and Circuit Breaking
+//
+// A failed handler is logged and the event is gone unless the bus redelivers it.
+// WithRetry retries with exponential backoff and jitter (RetryPolicy). WithDeadLetter
+// hands an event that still fails to a DeadLetterSink (NewMemoryDeadLetterSink,
+// NewLogDeadLetterSink, or NewQueueDeadLetterSink for the dead letter queue of
+// core/queue) and reports it handled. WithCircuitBreaker fails fast with
+// ErrCircuitOpen while a downstream service is down, using a breaker such as
+// *webhook.CircuitBreaker from pkg/webhook:
+//
+//	handler := event.NewHandlerFunc(
+//		event.ApplyDecorators(
+//			sendReceipt,
+//			event.WithDeadLetter[OrderPlaced](event.NewQueueDeadLetterSink(dlq, "")),
+//			event.WithRetry[OrderPlaced](event.DefaultRetryPolicy()),
+//			event.WithCircuitBreaker[OrderPlaced](webhook.NewCircuitBreaker(5, 2, 30*time.Second)),
+//		),
+//	)
+//
+// Each decorator wraps a single handler, so only the failing handler of an event is
+// retried or dead-lettered. ErrCircuitOpen is neither retried nor dead-lettered.
+//
+// # Idempotency
+//
+// Durable buses redeliver events, and an event with one failed handler is delivered
+// to all of its handlers again. WithIdempotency skips events a handler has already
+// processed, keyed by EventID(ctx) and a handler name, and records each success in a
+// ProcessedStore:
+//
+//	handler := event.NewHandlerFunc(
+//		event.ApplyDecorators(
+//			sendReceipt,
+//			event.WithIdempotency[OrderPlaced](store, "emails.send_receipt"),
+//		),
+//	)
+//
+// Give every handler of an event its own name. NewMemoryProcessedStore serves tests
+// and single processes; integration/idempotency/redisstore and
+// integration/idempotency/pgstore serve distributed deployments. The PostgreSQL
+// store runs the check, the handler and the record in one transaction (see
+// pg.WithTx), so they commit together.
+//
 // # Context Metadata
 //
 // Event metadata is automatically attached to handler contexts for observability:
@@ -151,12 +227,14 @@
 //	handler := event.NewHandlerFunc(func(ctx context.Context, evt UserCreated) error {
 //		eventID := event.EventID(ctx)
 //		eventName := event.EventName(ctx)
+//		eventVersion := event.EventVersion(ctx)
 //		eventTime := event.EventTime(ctx)
 //		processingStart := event.StartProcessingTime(ctx)
 //
 //		logger.Info("processing event",
 //			"event_id", eventID,
 //			"event_name", eventName,
+//			"event_version", eventVersion,
 //			"created_at", eventTime,
 //			"processing_started_at", processingStart)
 //
@@ -239,7 +317,7 @@
 //
 // # Event Name Resolution
 //
-// Event names are automatically derived from payload types using reflection.
+// Event names are derived from payload types using reflection unless registered.
 // For struct types, the type name is used directly (e.g., "UserCreated").
 // For other types, Go's type string representation is used.
 //
@@ -250,6 +328,38 @@
 //		return processUser(ctx, evt)
 //	})
 //
+// # Schema Versioning and Upcasting
+//
+// Derived names break consumers of persisted or in-flight events when a struct is
+// renamed. Register gives a type an explicit name and schema version, used by
+// NewEvent, Publisher and NewHandlerFunc; register types before creating handlers,
+// typically in init:
+//
+//	func init() {
+//		event.Register[UserCreated]("users.user_created", 2)
+//	}
+//
+// When the payload changes shape, bump the version and register an upcaster per
+// version step. Handlers created with NewHandlerFunc or NewHandler run the chain
+// on JSON payloads published with an older version (see EventVersion) before
+// decoding them into the current struct:
+//
+//	// Version 1 had a single FullName field
+//	event.RegisterUpcaster("users.user_created", 1, func(payload json.RawMessage) (json.RawMessage, error) {
+//		var v1 struct {
+//			UserID   string
+//			FullName string
+//		}
+//		if err := json.Unmarshal(payload, &v1); err != nil {
+//			return nil, err
+//		}
+//		first, last, _ := strings.Cut(v1.FullName, " ")
+//		return json.Marshal(UserCreated{UserID: v1.UserID, FirstName: first, LastName: last})
+//	})
+//
+// Events without a version, such as those published before versioning, are
+// version 1. Upcast runs the chain on any payload, e.g. for stored events.
+//
 // # Thread Safety
 //
 // All components are thread-safe and designed for concurrent use:
@@ -275,5 +385,24 @@
 //	}
 //
 // The processor expects events as JSON-marshaled Event structs with ID, Name,
-// Payload, and CreatedAt fields.
+// Payload, and CreatedAt fields, and an optional Version.
+//
+// Sources that redeliver unacknowledged events implement Acknowledger. The
+// processor calls Ack with the received data once every handler of the event
+// has succeeded, and leaves events with a failed handler for redelivery:
+//
+//	type Acknowledger interface {
+//		Ack(ctx context.Context, data []byte) error
+//	}
+//
+// integration/bus/redisstream provides a durable bus on Redis Streams, so the
+// processor can run in a separate process from the publishers. Each consumer
+// group receives every event; give every service its own group.
+//
+// integration/bus/pgoutbox provides a transactional outbox on PostgreSQL: the
+// publisher stores events in the transaction carried by the context, and a relay
+// forwards them to the real bus once the transaction commits.
+//
+// core/eventstore persists events in streams for event sourcing and publishes
+// appended events to an event bus, so processors handle them like any other event.
 package event
@@ -29,4 +29,7 @@ var (
 
 	// ErrProcessorStuck is returned when the processor has too many active events.
 	ErrProcessorStuck = errors.New("processor may be stuck - too many active events")
+
+	// ErrCircuitOpen is returned by WithCircuitBreaker while the circuit breaker rejects calls.
+	ErrCircuitOpen = errors.New("circuit breaker is open")
 )
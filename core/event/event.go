@@ -10,12 +10,14 @@ import (
 type Event struct {
 	ID        string    `json:"id"`
 	Name      string    `json:"name"`
+	Version   int       `json:"version,omitempty"`
 	Payload   any       `json:"payload"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
 // NewEvent creates a new Event with auto-generated ID and timestamp.
-// The event name is automatically derived from the payload type using reflection.
+// The event name and version are those registered for the payload type with
+// Register; unregistered types get the type name and version 1.
 //
 // Example:
 //
@@ -26,12 +28,14 @@ type Event struct {
 //
 //	event := event.NewEvent(UserCreated{UserID: "123", Email: "user@example.com"})
 //	// event.Name will be "UserCreated"
+//	// event.Version will be 1
 //	// event.ID will be a UUID
 //	// event.CreatedAt will be time.Now()
 func NewEvent(payload any) Event {
 	return Event{
 		ID:        uuid.New().String(),
 		Name:      getEventName(payload),
+		Version:   getEventVersion(payload),
 		Payload:   payload,
 		CreatedAt: time.Now(),
 	}
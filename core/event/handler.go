@@ -18,7 +18,10 @@ type Handler interface {
 }
 
 // NewHandler creates a new handler with a manually specified event name.
-// Use this when you need explicit control over the event name.
+// Use this when you need explicit control over the event name, or to subscribe
+// to a topic pattern such as "billing.>" (see MatchTopic). Handlers of events of
+// different types take a payload of json.RawMessage or map[string]any, and
+// read the event name with EventName.
 //
 // Example:
 //
@@ -26,6 +29,10 @@ type Handler interface {
 //	    evt := payload.(UserCreated)
 //	    return processEvent(ctx, evt)
 //	})
+//
+//	audit := event.NewHandler("*.deleted", func(ctx context.Context, payload json.RawMessage) error {
+//	    return auditLog.Record(ctx, event.EventName(ctx), payload)
+//	})
 func NewHandler[T any](eventName string, fn HandlerFunc[T]) Handler {
 	return &handlerFuncWrapper[T]{
 		name: eventName,
@@ -34,7 +41,8 @@ func NewHandler[T any](eventName string, fn HandlerFunc[T]) Handler {
 }
 
 // NewHandlerFunc creates a new type-safe handler from a function.
-// The event name is automatically derived from the type parameter using reflection.
+// The event name is the one registered for the type parameter with Register,
+// or its type name.
 //
 // Example:
 //
@@ -60,7 +68,24 @@ func (h *handlerFuncWrapper[T]) EventName() string {
 	return h.name
 }
 
+// Handle upcasts JSON payloads published with an older version (see EventVersion)
+// to the current one, then decodes them into T. Without a version in the context,
+// as when Handle is called directly, the payload is taken to be current.
 func (h *handlerFuncWrapper[T]) Handle(ctx context.Context, payload any) error {
+	if version, ok := eventVersion(ctx); ok {
+		// Topic handlers receive events of other names than their pattern
+		name := EventName(ctx)
+		if name == "" {
+			name = h.name
+		}
+
+		upcasted, err := upcastPayload(name, version, payload)
+		if err != nil {
+			return err
+		}
+		payload = upcasted
+	}
+
 	typed, err := unmarshalPayload[T](payload)
 	if err != nil {
 		return err
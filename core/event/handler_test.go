@@ -290,6 +290,23 @@ func TestHandler_WithJSONPayload(t *testing.T) {
 	assert.Equal(t, original.Items, capturedPayload.Items)
 }
 
+// TestHandler_WithRawMessagePayload verifies handler decodes json.RawMessage payloads
+func TestHandler_WithRawMessagePayload(t *testing.T) {
+	t.Parallel()
+
+	var capturedPayload UserCreated
+	handler := event.NewHandlerFunc(func(ctx context.Context, evt UserCreated) error {
+		capturedPayload = evt
+		return nil
+	})
+
+	err := handler.Handle(context.Background(), json.RawMessage(`{"UserID":"user-1","Email":"user@example.com"}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", capturedPayload.UserID)
+	assert.Equal(t, "user@example.com", capturedPayload.Email)
+}
+
 // TestHandler_TypeMismatch verifies error on wrong payload type
 func TestHandler_TypeMismatch(t *testing.T) {
 	t.Parallel()
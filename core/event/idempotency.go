@@ -0,0 +1,132 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ProcessedStore records which handlers have processed which events, so a
+// redelivered event is not handled twice. Implementations: MemoryProcessedStore,
+// integration/idempotency/redisstore and integration/idempotency/pgstore.
+type ProcessedStore interface {
+	// IsProcessed reports whether the handler has processed the message.
+	IsProcessed(ctx context.Context, handler, messageID string) (bool, error)
+
+	// MarkProcessed records that the handler has processed the message.
+	MarkProcessed(ctx context.Context, handler, messageID string) error
+}
+
+// txRunner is implemented by stores that can run the handler in one transaction
+// with the record of its success, such as integration/idempotency/pgstore.
+type txRunner interface {
+	RunInTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// WithIdempotency creates a decorator that skips events the handler has already
+// processed, keyed by EventID(ctx) and handlerName, and records each success in
+// the store. Events without an ID in the context are handled every time.
+//
+// With a store that supports transactions (integration/idempotency/pgstore), the
+// check, the handler and the record run in the transaction carried by the context
+// (pg.WithTx), or in a new one: the handler's writes and the record commit together,
+// and concurrent deliveries of the same event wait for each other. With other
+// stores, a crash between the handler and the record leads to one more delivery.
+//
+// Example:
+//
+//	handler := event.NewHandlerFunc(
+//	    event.ApplyDecorators(
+//	        sendReceipt,
+//	        event.WithIdempotency[OrderPlaced](store, "emails.send_receipt"),
+//	    ),
+//	)
+func WithIdempotency[T any](store ProcessedStore, handlerName string) Decorator[T] {
+	return func(next HandlerFunc[T]) HandlerFunc[T] {
+		handle := func(ctx context.Context, payload T) error {
+			id := EventID(ctx)
+			if id == "" {
+				return next(ctx, payload)
+			}
+
+			processed, err := store.IsProcessed(ctx, handlerName, id)
+			if err != nil {
+				return fmt.Errorf("failed to check if event %s was processed: %w", id, err)
+			}
+			if processed {
+				return nil
+			}
+
+			if err := next(ctx, payload); err != nil {
+				return err
+			}
+
+			if err := store.MarkProcessed(ctx, handlerName, id); err != nil {
+				return fmt.Errorf("failed to mark event %s processed: %w", id, err)
+			}
+			return nil
+		}
+
+		runner, ok := store.(txRunner)
+		if !ok {
+			return handle
+		}
+		return func(ctx context.Context, payload T) error {
+			return runner.RunInTx(ctx, func(ctx context.Context) error {
+				return handle(ctx, payload)
+			})
+		}
+	}
+}
+
+// MemoryProcessedStore keeps processed events in memory, for tests and
+// single-process deployments. Safe for concurrent use.
+type MemoryProcessedStore struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	processed map[processedKey]time.Time
+}
+
+type processedKey struct {
+	handler   string
+	messageID string
+}
+
+// NewMemoryProcessedStore creates an in-memory store that forgets records after ttl.
+// A ttl of zero keeps them forever.
+func NewMemoryProcessedStore(ttl time.Duration) *MemoryProcessedStore {
+	return &MemoryProcessedStore{
+		ttl:       ttl,
+		processed: make(map[processedKey]time.Time),
+	}
+}
+
+// IsProcessed reports whether the handler has processed the message within the ttl.
+func (s *MemoryProcessedStore) IsProcessed(_ context.Context, handler, messageID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	at, ok := s.processed[processedKey{handler, messageID}]
+	return ok && !s.expired(at, time.Now()), nil
+}
+
+// MarkProcessed records the message and drops expired records.
+func (s *MemoryProcessedStore) MarkProcessed(_ context.Context, handler, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, at := range s.processed {
+		if s.expired(at, now) {
+			delete(s.processed, key)
+		}
+	}
+	s.processed[processedKey{handler, messageID}] = now
+
+	return nil
+}
+
+func (s *MemoryProcessedStore) expired(at, now time.Time) bool {
+	return s.ttl > 0 && now.Sub(at) >= s.ttl
+}
@@ -26,6 +26,7 @@ const (
 // Processor manages event handlers and coordinates event processing.
 type Processor struct {
 	handlers        map[string][]Handler
+	topicHandlers   []Handler // handlers subscribed to wildcard patterns
 	eventBus        eventSource
 	fallbackHandler func(context.Context, Event) error
 	mu              sync.RWMutex
@@ -52,6 +53,16 @@ type eventSource interface {
 	Events() <-chan []byte
 }
 
+// Acknowledger is implemented by event sources that redeliver events until they
+// are acknowledged, such as durable message brokers. The processor acknowledges
+// an event once all of its handlers succeed. Events that cannot be decoded or
+// have no handlers are acknowledged right away, so they are not redelivered
+// forever; an event with a failed or panicked handler is left for redelivery,
+// and is then delivered to all of its handlers again.
+type Acknowledger interface {
+	Ack(ctx context.Context, data []byte) error
+}
+
 // ProcessorStats provides observability metrics for monitoring and debugging.
 type ProcessorStats struct {
 	EventsProcessed int64
@@ -99,7 +110,7 @@ func (p *Processor) Start(ctx context.Context) error {
 	defer p.running.Store(false)
 
 	p.mu.RLock()
-	hasHandlers := len(p.handlers) > 0 || p.fallbackHandler != nil
+	hasHandlers := len(p.handlers) > 0 || len(p.topicHandlers) > 0 || p.fallbackHandler != nil
 	p.mu.RUnlock()
 
 	if p.eventBus == nil {
@@ -118,7 +129,8 @@ func (p *Processor) Start(ctx context.Context) error {
 	defer close(done)
 
 	p.logger.InfoContext(procCtx, "event processor started",
-		slog.Int("handler_count", len(p.handlers)))
+		slog.Int("handler_count", len(p.handlers)),
+		slog.Int("topic_handler_count", len(p.topicHandlers)))
 
 	events := p.eventBus.Events()
 
@@ -137,16 +149,18 @@ func (p *Processor) Start(ctx context.Context) error {
 			if err := json.Unmarshal(data, &event); err != nil {
 				p.logger.ErrorContext(procCtx, "failed to unmarshal event",
 					slog.String("error", err.Error()))
+				p.ack(procCtx, data)
 				continue
 			}
 
-			if err := p.processHandlers(procCtx, event); err != nil {
+			if err := p.processHandlers(procCtx, event, data); err != nil {
 				if !errors.Is(err, ErrNoHandlers) {
 					p.logger.ErrorContext(procCtx, "failed to process event",
 						slog.String("event_id", event.ID),
 						slog.String("event_name", event.Name),
 						slog.String("error", err.Error()))
 				}
+				p.ack(procCtx, data)
 			}
 		}
 	}
@@ -237,13 +251,33 @@ func (p *Processor) releaseSemaphore() {
 	}
 }
 
-func (p *Processor) processHandlers(ctx context.Context, event Event) error {
+// ack acknowledges the event data if the source needs acknowledgements.
+// It runs after the processor context may have been cancelled, so an event
+// handled during shutdown is still acknowledged.
+func (p *Processor) ack(ctx context.Context, data []byte) {
+	acker, ok := p.eventBus.(Acknowledger)
+	if !ok {
+		return
+	}
+	if err := acker.Ack(context.WithoutCancel(ctx), data); err != nil {
+		p.logger.ErrorContext(ctx, "failed to acknowledge event",
+			slog.String("error", err.Error()))
+	}
+}
+
+func (p *Processor) processHandlers(ctx context.Context, event Event, data []byte) error {
 	p.mu.RLock()
-	handlers, exists := p.handlers[event.Name]
+	handlers := p.handlers[event.Name]
+	for _, h := range p.topicHandlers {
+		if MatchTopic(h.EventName(), event.Name) {
+			// Capped so appending copies instead of writing into the registered slice
+			handlers = append(handlers[:len(handlers):len(handlers)], h)
+		}
+	}
 	fallback := p.fallbackHandler
 	p.mu.RUnlock()
 
-	if !exists || len(handlers) == 0 {
+	if len(handlers) == 0 {
 		if fallback != nil {
 			p.wg.Add(1)
 			p.activeEvents.Add(1)
@@ -284,6 +318,7 @@ func (p *Processor) processHandlers(ctx context.Context, event Event) error {
 						slog.String("event_id", event.ID),
 						slog.String("event_name", event.Name),
 						slog.Duration("duration", time.Since(start)))
+					p.ack(handlerCtx, data)
 				}
 
 				p.lastActivityAt.Store(time.Now().UnixNano())
@@ -294,6 +329,21 @@ func (p *Processor) processHandlers(ctx context.Context, event Event) error {
 		return ErrNoHandlers
 	}
 
+	// The last handler to succeed acknowledges the event, unless another one failed
+	var (
+		remaining atomic.Int32
+		failed    atomic.Bool
+	)
+	remaining.Store(int32(len(handlers)))
+	finish := func(ctx context.Context, ok bool) {
+		if !ok {
+			failed.Store(true)
+		}
+		if remaining.Add(-1) == 0 && !failed.Load() {
+			p.ack(ctx, data)
+		}
+	}
+
 	for _, h := range handlers {
 		p.wg.Add(1)
 		p.activeEvents.Add(1)
@@ -305,12 +355,14 @@ func (p *Processor) processHandlers(ctx context.Context, event Event) error {
 			handlerCtx := WithStartProcessingTime(WithEventMeta(ctx, event), time.Now())
 
 			if !p.acquireSemaphore(handlerCtx) {
+				finish(handlerCtx, false)
 				return
 			}
 			defer p.releaseSemaphore()
 
 			defer func() {
 				if r := recover(); r != nil {
+					finish(handlerCtx, false)
 					p.eventsFailed.Add(1)
 					p.logger.ErrorContext(handlerCtx, "event handler panicked",
 						slog.String("event_id", event.ID),
@@ -323,6 +375,7 @@ func (p *Processor) processHandlers(ctx context.Context, event Event) error {
 			start := time.Now()
 
 			if err := handler.Handle(handlerCtx, event.Payload); err != nil {
+				finish(handlerCtx, false)
 				p.eventsFailed.Add(1)
 				p.logger.ErrorContext(handlerCtx, "event handler failed",
 					slog.String("event_id", event.ID),
@@ -337,6 +390,7 @@ func (p *Processor) processHandlers(ctx context.Context, event Event) error {
 					slog.String("event_name", event.Name),
 					slog.String("handler", handler.EventName()),
 					slog.Duration("duration", time.Since(start)))
+				finish(handlerCtx, true)
 			}
 
 			p.lastActivityAt.Store(time.Now().UnixNano())
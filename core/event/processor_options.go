@@ -11,17 +11,24 @@ type ProcessorOption func(*Processor)
 
 // WithHandler registers one or more handlers with the processor.
 // Multiple handlers can be registered for the same event type.
+// Handlers whose event name contains wildcard tokens subscribe to every
+// matching event (see MatchTopic).
 //
 // Example:
 //
 //	processor := event.NewProcessor(
 //	    event.WithHandler(handler1),
 //	    event.WithHandler(handler2, handler3),
+//	    event.WithHandler(event.NewHandler("billing.>", auditBilling)),
 //	)
 func WithHandler(handlers ...Handler) ProcessorOption {
 	return func(p *Processor) {
 		for _, h := range handlers {
 			eventName := h.EventName()
+			if isTopicPattern(eventName) {
+				p.topicHandlers = append(p.topicHandlers, h)
+				continue
+			}
 			p.handlers[eventName] = append(p.handlers[eventName], h)
 		}
 	}
@@ -1707,3 +1707,73 @@ func TestProcessor_FallbackHandlerPanic(t *testing.T) {
 
 	require.NoError(t, processor.Stop())
 }
+
+// ackingBus records the acknowledged events of a ChannelBus.
+type ackingBus struct {
+	*event.ChannelBus
+	mu    sync.Mutex
+	acked [][]byte
+}
+
+func (b *ackingBus) Ack(_ context.Context, data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.acked = append(b.acked, data)
+	return nil
+}
+
+func (b *ackingBus) ackCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.acked)
+}
+
+func TestProcessor_Acknowledgement(t *testing.T) {
+	t.Parallel()
+
+	type OrderPlaced struct {
+		Fail bool `json:"fail"`
+	}
+	type OrderShipped struct{}
+
+	bus := &ackingBus{ChannelBus: event.NewChannelBus()}
+	defer bus.Close()
+
+	var handled atomic.Int32
+	succeeding := event.NewHandlerFunc(func(ctx context.Context, e OrderPlaced) error {
+		handled.Add(1)
+		return nil
+	})
+	failing := event.NewHandlerFunc(func(ctx context.Context, e OrderPlaced) error {
+		handled.Add(1)
+		if e.Fail {
+			return errors.New("handler failed")
+		}
+		return nil
+	})
+
+	processor := event.NewProcessor(
+		event.WithEventSource(bus),
+		event.WithHandler(succeeding, failing),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = processor.Start(ctx) }()
+
+	publisher := event.NewPublisher(bus)
+	require.NoError(t, publisher.Publish(ctx, OrderPlaced{}))
+	require.NoError(t, publisher.Publish(ctx, OrderPlaced{Fail: true}))
+	require.NoError(t, publisher.Publish(ctx, OrderShipped{}))
+	require.NoError(t, bus.Publish(ctx, []byte("not json")))
+
+	require.Eventually(t, func() bool { return handled.Load() == 4 && bus.ackCount() == 3 }, time.Second, 5*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	require.Len(t, bus.acked, 3, "an event with a failed handler is left for redelivery")
+	for _, data := range bus.acked {
+		assert.NotContains(t, string(data), `"fail":true`)
+	}
+}
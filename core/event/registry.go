@@ -0,0 +1,155 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Upcaster transforms the JSON payload of one version of an event into the next version.
+type Upcaster func(payload json.RawMessage) (json.RawMessage, error)
+
+type registration struct {
+	name    string
+	version int
+}
+
+var (
+	registryMu    sync.RWMutex
+	registrations = map[reflect.Type]registration{}
+	upcasters     = map[string]map[int]Upcaster{}
+)
+
+// Register gives events of type T an explicit name and schema version, used by
+// NewEvent and NewHandlerFunc instead of the Go type name. The name survives
+// renaming the struct; bump the version when the payload changes shape, and
+// register upcasters from older versions with RegisterUpcaster.
+//
+// Register event types before creating handlers and publishing, typically in init.
+// An empty name keeps the type name; versions start at 1.
+//
+// Example:
+//
+//	func init() {
+//	    event.Register[UserCreated]("users.user_created", 2)
+//	    event.RegisterUpcaster("users.user_created", 1, splitFullName)
+//	}
+func Register[T any](name string, version int) {
+	t := baseType(reflect.TypeFor[T]())
+	if name == "" {
+		name = t.Name()
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registrations[t] = registration{name: name, version: max(version, 1)}
+}
+
+// RegisterUpcaster registers an upcaster that transforms payloads of the named
+// event from fromVersion to fromVersion+1. Handlers run the chain of upcasters
+// from the version an event was published with, so register one per version step.
+//
+// Example:
+//
+//	event.RegisterUpcaster("users.user_created", 1, func(payload json.RawMessage) (json.RawMessage, error) {
+//	    var v1 struct{ FullName string }
+//	    if err := json.Unmarshal(payload, &v1); err != nil {
+//	        return nil, err
+//	    }
+//	    first, last, _ := strings.Cut(v1.FullName, " ")
+//	    return json.Marshal(UserCreated{FirstName: first, LastName: last})
+//	})
+func RegisterUpcaster(name string, fromVersion int, fn Upcaster) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if upcasters[name] == nil {
+		upcasters[name] = map[int]Upcaster{}
+	}
+	upcasters[name][max(fromVersion, 1)] = fn
+}
+
+// NameOf returns the name of events of type T: the registered name, or the type name.
+func NameOf[T any]() string {
+	return typeName(reflect.TypeFor[T]())
+}
+
+// VersionOf returns the schema version of events of type T: the registered version, or 1.
+func VersionOf[T any]() int {
+	return typeVersion(reflect.TypeFor[T]())
+}
+
+// Upcast runs the upcasters registered for the named event on a payload of the
+// given version, and returns the payload and version they produce. Payloads
+// without upcasters for their version are returned unchanged. Events published
+// without a version are version 1.
+func Upcast(name string, version int, payload json.RawMessage) (json.RawMessage, int, error) {
+	version = max(version, 1)
+
+	for {
+		fn, ok := upcasterFor(name, version)
+		if !ok {
+			return payload, version, nil
+		}
+
+		upcasted, err := fn(payload)
+		if err != nil {
+			return nil, version, fmt.Errorf("failed to upcast event %s from version %d: %w", name, version, err)
+		}
+		payload = upcasted
+		version++
+	}
+}
+
+// upcasterFor returns the upcaster registered for the named event from the given version.
+// Each step is looked up under the lock, since RegisterUpcaster may extend the chain concurrently.
+func upcasterFor(name string, version int) (Upcaster, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	fn, ok := upcasters[name][version]
+	return fn, ok
+}
+
+// hasUpcasters reports whether any upcaster is registered for the named event.
+func hasUpcasters(name string) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return len(upcasters[name]) > 0
+}
+
+// typeName returns the registered name of the event type, or its type name.
+func typeName(t reflect.Type) string {
+	t = baseType(t)
+
+	registryMu.RLock()
+	reg, ok := registrations[t]
+	registryMu.RUnlock()
+
+	if ok {
+		return reg.name
+	}
+	return t.Name()
+}
+
+// typeVersion returns the registered version of the event type, or 1.
+func typeVersion(t reflect.Type) int {
+	t = baseType(t)
+
+	registryMu.RLock()
+	reg, ok := registrations[t]
+	registryMu.RUnlock()
+
+	if ok {
+		return reg.version
+	}
+	return 1
+}
+
+// baseType unwraps pointer types.
+func baseType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
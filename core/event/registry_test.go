@@ -0,0 +1,233 @@
+package event_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/event"
+)
+
+// Each test registers its own types and names: the registry is shared by the package.
+
+type CustomerRegistered struct {
+	CustomerID string
+}
+
+type CustomerRenamed struct {
+	FirstName string
+	LastName  string
+}
+
+type CustomerArchived struct {
+	CustomerID string
+	Reason     string
+}
+
+type CustomerVerified struct {
+	CustomerID string
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	event.Register[CustomerRegistered]("customers.registered", 3)
+
+	evt := event.NewEvent(CustomerRegistered{CustomerID: "c-1"})
+	assert.Equal(t, "customers.registered", evt.Name)
+	assert.Equal(t, 3, evt.Version)
+
+	evt = event.NewEvent(&CustomerRegistered{CustomerID: "c-1"})
+	assert.Equal(t, "customers.registered", evt.Name)
+
+	handler := event.NewHandlerFunc(func(ctx context.Context, evt CustomerRegistered) error { return nil })
+	assert.Equal(t, "customers.registered", handler.EventName())
+
+	assert.Equal(t, "customers.registered", event.NameOf[CustomerRegistered]())
+	assert.Equal(t, 3, event.VersionOf[*CustomerRegistered]())
+}
+
+func TestRegister_Defaults(t *testing.T) {
+	t.Parallel()
+
+	event.Register[CustomerVerified]("", 0)
+	assert.Equal(t, "CustomerVerified", event.NameOf[CustomerVerified]())
+	assert.Equal(t, 1, event.VersionOf[CustomerVerified]())
+
+	// Unregistered types keep the type name and version 1
+	evt := event.NewEvent(UserCreated{})
+	assert.Equal(t, "UserCreated", evt.Name)
+	assert.Equal(t, 1, evt.Version)
+}
+
+func TestUpcast(t *testing.T) {
+	t.Parallel()
+
+	const name = "customers.renamed"
+	event.Register[CustomerRenamed](name, 3)
+	// v1 {"Name": "Ada Lovelace"} -> v2 {"FullName": "Ada Lovelace"} -> v3 {"FirstName", "LastName"}
+	event.RegisterUpcaster(name, 1, func(payload json.RawMessage) (json.RawMessage, error) {
+		var v1 struct{ Name string }
+		if err := json.Unmarshal(payload, &v1); err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]string{"FullName": v1.Name})
+	})
+	event.RegisterUpcaster(name, 2, func(payload json.RawMessage) (json.RawMessage, error) {
+		var v2 struct{ FullName string }
+		if err := json.Unmarshal(payload, &v2); err != nil {
+			return nil, err
+		}
+		first, last, _ := strings.Cut(v2.FullName, " ")
+		return json.Marshal(CustomerRenamed{FirstName: first, LastName: last})
+	})
+
+	t.Run("chain", func(t *testing.T) {
+		t.Parallel()
+
+		payload, version, err := event.Upcast(name, 1, json.RawMessage(`{"Name":"Ada Lovelace"}`))
+		require.NoError(t, err)
+		assert.Equal(t, 3, version)
+		assert.JSONEq(t, `{"FirstName":"Ada","LastName":"Lovelace"}`, string(payload))
+
+		// Events without a version are version 1
+		_, version, err = event.Upcast(name, 0, json.RawMessage(`{"Name":"Ada Lovelace"}`))
+		require.NoError(t, err)
+		assert.Equal(t, 3, version)
+
+		// Current payloads are unchanged
+		current := json.RawMessage(`{"FirstName":"Ada","LastName":"Lovelace"}`)
+		payload, version, err = event.Upcast(name, 3, current)
+		require.NoError(t, err)
+		assert.Equal(t, 3, version)
+		assert.Equal(t, current, payload)
+	})
+
+	t.Run("handler decodes older versions", func(t *testing.T) {
+		t.Parallel()
+
+		var got CustomerRenamed
+		handler := event.NewHandlerFunc(func(ctx context.Context, evt CustomerRenamed) error {
+			got = evt
+			return nil
+		})
+
+		ctx := event.WithEventVersion(context.Background(), 2)
+		require.NoError(t, handler.Handle(ctx, map[string]any{"FullName": "Grace Hopper"}))
+		assert.Equal(t, CustomerRenamed{FirstName: "Grace", LastName: "Hopper"}, got)
+
+		// Typed payloads are current
+		require.NoError(t, handler.Handle(ctx, CustomerRenamed{FirstName: "Alan"}))
+		assert.Equal(t, CustomerRenamed{FirstName: "Alan"}, got)
+	})
+
+	t.Run("handler without version in context", func(t *testing.T) {
+		t.Parallel()
+
+		var got CustomerRenamed
+		handler := event.NewHandlerFunc(func(ctx context.Context, evt CustomerRenamed) error {
+			got = evt
+			return nil
+		})
+
+		// Called directly, the payload is current and must not be upcasted
+		current := []byte(`{"FirstName":"Ada","LastName":"Lovelace"}`)
+		require.NoError(t, handler.Handle(context.Background(), current))
+		assert.Equal(t, CustomerRenamed{FirstName: "Ada", LastName: "Lovelace"}, got)
+
+		// A received event without a version is version 1
+		ctx := event.WithEventVersion(context.Background(), 0)
+		require.NoError(t, handler.Handle(ctx, []byte(`{"Name":"Grace Hopper"}`)))
+		assert.Equal(t, CustomerRenamed{FirstName: "Grace", LastName: "Hopper"}, got)
+	})
+
+	t.Run("processor delivers older versions upcasted", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		bus := event.NewChannelBus()
+		defer bus.Close()
+
+		received := make(chan CustomerRenamed, 1)
+		processor := event.NewProcessor(
+			event.WithEventSource(bus),
+			event.WithHandler(event.NewHandlerFunc(func(ctx context.Context, evt CustomerRenamed) error {
+				received <- evt
+				return nil
+			})),
+		)
+		go func() { _ = processor.Start(ctx) }()
+
+		// As published by a service still on version 1
+		data, err := json.Marshal(event.Event{
+			ID:        "evt-1",
+			Name:      name,
+			Version:   1,
+			Payload:   map[string]string{"Name": "Ada Lovelace"},
+			CreatedAt: time.Now(),
+		})
+		require.NoError(t, err)
+		require.NoError(t, bus.Publish(ctx, data))
+
+		select {
+		case evt := <-received:
+			assert.Equal(t, CustomerRenamed{FirstName: "Ada", LastName: "Lovelace"}, evt)
+		case <-time.After(time.Second):
+			t.Fatal("event was not handled")
+		}
+	})
+}
+
+func TestUpcast_Error(t *testing.T) {
+	t.Parallel()
+
+	const name = "customers.archived"
+	event.Register[CustomerArchived](name, 2)
+	event.RegisterUpcaster(name, 1, func(json.RawMessage) (json.RawMessage, error) {
+		return nil, errors.New("reason is required")
+	})
+
+	handler := event.NewHandlerFunc(func(ctx context.Context, evt CustomerArchived) error {
+		t.Fatal("handler must not run")
+		return nil
+	})
+
+	err := handler.Handle(event.WithEventVersion(context.Background(), 1), []byte(`{"CustomerID":"c-1"}`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to upcast event customers.archived from version 1")
+}
+
+func TestUpcast_ConcurrentRegistration(t *testing.T) {
+	t.Parallel()
+
+	const name = "customers.concurrent"
+	identity := func(payload json.RawMessage) (json.RawMessage, error) { return payload, nil }
+	event.RegisterUpcaster(name, 1, identity)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for version := 2; version <= 100; version++ {
+			event.RegisterUpcaster(name, version, identity)
+		}
+	}()
+
+	// Upcasting while the chain grows must not race with registration
+	for range 100 {
+		_, version, err := event.Upcast(name, 1, json.RawMessage(`{}`))
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, version, 2)
+	}
+	<-done
+
+	_, version, err := event.Upcast(name, 1, json.RawMessage(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, 101, version)
+}
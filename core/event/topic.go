@@ -0,0 +1,58 @@
+package event
+
+import "strings"
+
+const (
+	// TopicSeparator separates the tokens of hierarchical event names, such as "billing.invoice.paid".
+	TopicSeparator = "."
+
+	// TopicWildcard matches exactly one token of an event name: "billing.*.paid".
+	TopicWildcard = "*"
+
+	// TopicTailWildcard, as the last token, matches one or more trailing tokens: "billing.>".
+	TopicTailWildcard = ">"
+)
+
+// MatchTopic reports whether the event name matches the subscription pattern.
+// Names and patterns are split into tokens by TopicSeparator; TopicWildcard
+// matches any single token and a trailing TopicTailWildcard matches the rest of
+// the name, which must have at least one more token. Other tokens match exactly.
+// A pattern with TopicTailWildcard in any other position is invalid and matches
+// no name.
+//
+// Example:
+//
+//	event.MatchTopic("billing.*", "billing.invoice")           // true
+//	event.MatchTopic("billing.*", "billing.invoice.paid")      // false
+//	event.MatchTopic("billing.>", "billing.invoice.paid")      // true
+//	event.MatchTopic("*.deleted", "user.deleted")              // true
+//	event.MatchTopic("billing.*.paid", "billing.invoice.paid") // true
+//	event.MatchTopic("billing.>.paid", "billing.>.paid")       // false
+func MatchTopic(pattern, name string) bool {
+	patternTokens := strings.Split(pattern, TopicSeparator)
+	nameTokens := strings.Split(name, TopicSeparator)
+
+	for i, token := range patternTokens {
+		if token == TopicTailWildcard {
+			return i == len(patternTokens)-1 && len(nameTokens) > i
+		}
+		if i >= len(nameTokens) {
+			return false
+		}
+		if token != TopicWildcard && token != nameTokens[i] {
+			return false
+		}
+	}
+
+	return len(nameTokens) == len(patternTokens)
+}
+
+// isTopicPattern reports whether the subscription contains wildcard tokens.
+func isTopicPattern(subscription string) bool {
+	for token := range strings.SplitSeq(subscription, TopicSeparator) {
+		if token == TopicWildcard || token == TopicTailWildcard {
+			return true
+		}
+	}
+	return false
+}
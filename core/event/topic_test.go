@@ -0,0 +1,142 @@
+package event_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/event"
+)
+
+func TestMatchTopic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"billing.invoice.paid", "billing.invoice.paid", true},
+		{"billing.invoice.paid", "billing.invoice.sent", false},
+		{"billing.*", "billing.invoice", true},
+		{"billing.*", "billing.invoice.paid", false},
+		{"billing.*", "billing", false},
+		{"billing.*.paid", "billing.invoice.paid", true},
+		{"billing.*.paid", "billing.invoice.sent", false},
+		{"*.deleted", "user.deleted", true},
+		{"*.deleted", "billing.invoice.deleted", false},
+		{"*.*.deleted", "billing.invoice.deleted", true},
+		{"billing.>", "billing.invoice", true},
+		{"billing.>", "billing.invoice.paid", true},
+		{"billing.>", "billing", false},
+		{"billing.>", "shipping.label.created", false},
+		{">", "UserCreated", true},
+		{"billing.>.paid", "billing.>.paid", false},
+		{"billing.>.paid", "billing.invoice.paid", false},
+		{">.paid", "billing.paid", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, event.MatchTopic(tt.pattern, tt.name), "MatchTopic(%q, %q)", tt.pattern, tt.name)
+	}
+}
+
+func TestProcessor_TopicHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := event.NewChannelBus()
+	defer bus.Close()
+
+	var (
+		mu        sync.Mutex
+		audited   []string
+		billing   []string
+		exact     int
+		fallbacks int
+	)
+	record := func(list *[]string, name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		*list = append(*list, name)
+	}
+
+	processor := event.NewProcessor(
+		event.WithEventSource(bus),
+		event.WithHandler(
+			event.NewHandler("*.deleted", func(ctx context.Context, payload json.RawMessage) error {
+				record(&audited, event.EventName(ctx))
+				return nil
+			}),
+			event.NewHandler("billing.>", func(ctx context.Context, payload map[string]any) error {
+				record(&billing, event.EventName(ctx))
+				return nil
+			}),
+			event.NewHandler("user.deleted", func(ctx context.Context, payload map[string]any) error {
+				mu.Lock()
+				defer mu.Unlock()
+				exact++
+				return nil
+			}),
+		),
+		event.WithFallbackHandler(func(ctx context.Context, evt event.Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+			fallbacks++
+			return nil
+		}),
+	)
+	go func() { _ = processor.Start(ctx) }()
+
+	publish := func(name string) {
+		data, err := json.Marshal(event.Event{ID: name, Name: name, Payload: map[string]any{}, CreatedAt: time.Now()})
+		require.NoError(t, err)
+		require.NoError(t, bus.Publish(ctx, data))
+	}
+	publish("user.deleted")
+	publish("invoice.deleted")
+	publish("user.created")
+	publish("billing.invoice.paid")
+
+	require.Eventually(t, func() bool {
+		return processor.Stats().EventsProcessed == 5
+	}, time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.ElementsMatch(t, []string{"user.deleted", "invoice.deleted"}, audited)
+	assert.Equal(t, []string{"billing.invoice.paid"}, billing)
+	assert.Equal(t, 1, exact)
+	assert.Equal(t, 1, fallbacks, "only user.created has no handler")
+}
+
+func TestProcessor_OnlyTopicHandlers(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bus := event.NewChannelBus()
+	defer bus.Close()
+
+	processor := event.NewProcessor(
+		event.WithEventSource(bus),
+		event.WithHandler(event.NewHandler(">", func(ctx context.Context, payload map[string]any) error {
+			return nil
+		})),
+	)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- processor.Start(ctx) }()
+
+	require.Eventually(t, func() bool { return processor.Stats().IsRunning }, time.Second, 10*time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-errCh, context.Canceled)
+}
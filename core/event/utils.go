@@ -6,23 +6,55 @@ import (
 	"reflect"
 )
 
-// getEventName extracts the type name from an event value, unwrapping any pointer types.
+// getEventName returns the event name for a payload: the name registered with
+// Register, or the type name, unwrapping any pointer types.
 //
-// DESIGN DECISION: Returns only the bare type name without package path (e.g., "UserCreated").
-// This is intentional for simplicity in micro-SaaS applications where package namespacing
-// is not typically needed. Users must ensure unique event type names across their codebase
-// to avoid handler collisions. This trade-off favors simplicity over package isolation.
+// DESIGN DECISION: Unregistered types use only the bare type name without package
+// path (e.g., "UserCreated"). This is intentional for simplicity in micro-SaaS
+// applications where package namespacing is not typically needed. Users must ensure
+// unique event type names across their codebase to avoid handler collisions, or
+// register explicit names. This trade-off favors simplicity over package isolation.
 //
 // Example: Both users.UserCreated and billing.UserCreated would resolve to "UserCreated"
-// and trigger the same handlers. Use distinct type names if this is not desired.
+// and trigger the same handlers. Use distinct type names or Register if this is not desired.
 func getEventName(v any) string {
-	t := reflect.TypeOf(v)
+	return typeName(reflect.TypeOf(v))
+}
+
+// getEventVersion returns the schema version registered for a payload type, or 1.
+func getEventVersion(v any) int {
+	return typeVersion(reflect.TypeOf(v))
+}
+
+// upcastPayload runs the upcasters registered for the named event on a raw or
+// decoded JSON payload of the given version. Typed payloads are current and
+// returned unchanged.
+func upcastPayload(name string, version int, payload any) (any, error) {
+	if !hasUpcasters(name) {
+		return payload, nil
+	}
 
-	for t.Kind() == reflect.Pointer {
-		t = t.Elem()
+	var data json.RawMessage
+	switch p := payload.(type) {
+	case json.RawMessage:
+		data = p
+	case []byte:
+		data = p
+	case map[string]any:
+		encoded, err := json.Marshal(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal map payload: %w", err)
+		}
+		data = encoded
+	default:
+		return payload, nil
 	}
 
-	return t.Name()
+	upcasted, _, err := Upcast(name, version, data)
+	if err != nil {
+		return nil, err
+	}
+	return upcasted, nil
 }
 
 func unmarshalPayload[T any](payload any) (T, error) {
@@ -33,6 +65,11 @@ func unmarshalPayload[T any](payload any) (T, error) {
 		return v, nil
 	}
 
+	// Handle json.RawMessage (raw JSON, e.g. from core/eventstore records)
+	if data, ok := payload.(json.RawMessage); ok {
+		payload = []byte(data)
+	}
+
 	// Handle []byte (raw JSON)
 	if data, ok := payload.([]byte); ok {
 		var evt T
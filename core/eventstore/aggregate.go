@@ -0,0 +1,170 @@
+package eventstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/dmitrymomot/foundation/core/event"
+)
+
+// readBatchSize is the number of events Load reads from the store at a time.
+const readBatchSize = 500
+
+// Applier applies one type of event to aggregate state of type S. Create it with On.
+type Applier[S any] struct {
+	name  string
+	apply func(state *S, payload any) error
+}
+
+// On creates an applier of events of type E, named as event.NameOf[E] returns.
+// Stored events of older schema versions are upcast with the upcasters
+// registered in core/event before they are decoded into E.
+//
+// Example:
+//
+//	eventstore.On(func(account *Account, evt MoneyDeposited) {
+//	    account.Balance += evt.Amount
+//	})
+func On[S, E any](fn func(state *S, evt E)) Applier[S] {
+	return Applier[S]{
+		name: event.NameOf[E](),
+		apply: func(state *S, payload any) error {
+			switch p := payload.(type) {
+			case E:
+				fn(state, p)
+			case *E:
+				fn(state, *p)
+			case json.RawMessage:
+				var evt E
+				if err := json.Unmarshal(p, &evt); err != nil {
+					return fmt.Errorf("failed to unmarshal event: %w", err)
+				}
+				fn(state, evt)
+			default:
+				return fmt.Errorf("unexpected payload type: %T", payload)
+			}
+			return nil
+		},
+	}
+}
+
+// Aggregate is an event-sourced entity: its state is the result of applying the
+// events of its stream in order. Decide on a change against State, then Raise the
+// event that records it; Save appends the raised events to the store.
+// An Aggregate is not safe for concurrent use.
+type Aggregate[S any] struct {
+	id       string
+	version  int64
+	state    S
+	appliers map[string]Applier[S]
+	changes  []event.Event
+}
+
+// NewAggregate creates an aggregate with no events and the zero state,
+// stored in the stream with the given ID.
+func NewAggregate[S any](id string, appliers ...Applier[S]) *Aggregate[S] {
+	a := &Aggregate[S]{
+		id:       id,
+		appliers: make(map[string]Applier[S], len(appliers)),
+	}
+	for _, applier := range appliers {
+		a.appliers[applier.name] = applier
+	}
+	return a
+}
+
+// ID returns the ID of the aggregate's stream.
+func (a *Aggregate[S]) ID() string {
+	return a.id
+}
+
+// Version returns the stream version the aggregate was loaded or last saved at.
+// Zero means the aggregate has never been saved.
+func (a *Aggregate[S]) Version() int64 {
+	return a.version
+}
+
+// State returns the current state, including raised events that are not saved yet.
+func (a *Aggregate[S]) State() S {
+	return a.state
+}
+
+// Changes returns the events raised since the aggregate was loaded or last saved.
+func (a *Aggregate[S]) Changes() []event.Event {
+	return a.changes
+}
+
+// Raise applies the event to the state and records it for Save.
+// It returns ErrUnknownEvent if the aggregate has no applier for the event.
+func (a *Aggregate[S]) Raise(payload any) error {
+	evt := event.NewEvent(payload)
+
+	applier, ok := a.appliers[evt.Name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownEvent, evt.Name)
+	}
+	if err := applier.apply(&a.state, payload); err != nil {
+		return fmt.Errorf("failed to apply event %s: %w", evt.Name, err)
+	}
+
+	a.changes = append(a.changes, evt)
+	return nil
+}
+
+// Load rehydrates an aggregate from the events of its stream. Events without an
+// applier are skipped. An aggregate with no events is new: its Version is zero.
+//
+// Example:
+//
+//	account, err := eventstore.Load(ctx, store, "account-42", accountAppliers...)
+//	if account.State().Balance < amount {
+//	    return ErrInsufficientFunds
+//	}
+//	account.Raise(MoneyWithdrawn{Amount: amount})
+//	_, err = eventstore.Save(ctx, store, account)
+func Load[S any](ctx context.Context, store EventStore, id string, appliers ...Applier[S]) (*Aggregate[S], error) {
+	a := NewAggregate(id, appliers...)
+
+	for {
+		records, err := store.ReadStream(ctx, id, a.version+1, readBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stream %s: %w", id, err)
+		}
+
+		for _, record := range records {
+			if applier, ok := a.appliers[record.Name]; ok {
+				payload, _, err := event.Upcast(record.Name, record.Version, record.Payload)
+				if err != nil {
+					return nil, fmt.Errorf("failed to upcast event %s of stream %s: %w", record.ID, id, err)
+				}
+				if err := applier.apply(&a.state, payload); err != nil {
+					return nil, fmt.Errorf("failed to apply event %s of stream %s: %w", record.ID, id, err)
+				}
+			}
+			a.version = record.StreamVersion
+		}
+
+		if len(records) < readBatchSize {
+			return a, nil
+		}
+	}
+}
+
+// Save appends the raised events to the aggregate's stream, expecting the stream
+// at the version the aggregate was loaded at. It returns ErrConcurrencyConflict
+// when another writer appended in between; load the aggregate again and retry.
+func Save[S any](ctx context.Context, store EventStore, a *Aggregate[S]) ([]Record, error) {
+	if len(a.changes) == 0 {
+		return nil, nil
+	}
+
+	records, err := store.Append(ctx, a.id, a.version, a.changes...)
+	if len(records) > 0 {
+		// A PublishingStore returns the stored records with a publishing error
+		a.version = records[len(records)-1].StreamVersion
+		a.changes = nil
+	}
+
+	return records, err
+}
@@ -0,0 +1,177 @@
+package eventstore_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/event"
+	"github.com/dmitrymomot/foundation/core/eventstore"
+)
+
+type Account struct {
+	Balance        int
+	OverdraftCents int
+	Closed         bool
+}
+
+type MoneyDeposited struct {
+	Amount int
+}
+
+type MoneyWithdrawn struct {
+	Amount int
+}
+
+type AccountClosed struct{}
+
+// OverdraftLimitSet v1 carried the limit in whole units as "Limit"
+type OverdraftLimitSet struct {
+	LimitCents int
+}
+
+func init() {
+	event.Register[OverdraftLimitSet]("accounts.overdraft_limit_set", 2)
+	event.RegisterUpcaster("accounts.overdraft_limit_set", 1, func(payload json.RawMessage) (json.RawMessage, error) {
+		var v1 struct{ Limit int }
+		if err := json.Unmarshal(payload, &v1); err != nil {
+			return nil, err
+		}
+		return json.Marshal(OverdraftLimitSet{LimitCents: v1.Limit * 100})
+	})
+}
+
+var accountAppliers = []eventstore.Applier[Account]{
+	eventstore.On(func(a *Account, evt MoneyDeposited) { a.Balance += evt.Amount }),
+	eventstore.On(func(a *Account, evt MoneyWithdrawn) { a.Balance -= evt.Amount }),
+}
+
+func TestAggregate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("raise, save and load", func(t *testing.T) {
+		t.Parallel()
+		store := eventstore.NewMemoryStore()
+
+		account := eventstore.NewAggregate("account-1", accountAppliers...)
+		require.NoError(t, account.Raise(MoneyDeposited{Amount: 100}))
+		require.NoError(t, account.Raise(&MoneyWithdrawn{Amount: 30}))
+		assert.Equal(t, 70, account.State().Balance)
+		assert.Len(t, account.Changes(), 2)
+		assert.Zero(t, account.Version())
+
+		records, err := eventstore.Save(ctx, store, account)
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, int64(2), account.Version())
+		assert.Empty(t, account.Changes())
+
+		loaded, err := eventstore.Load(ctx, store, "account-1", accountAppliers...)
+		require.NoError(t, err)
+		assert.Equal(t, 70, loaded.State().Balance)
+		assert.Equal(t, int64(2), loaded.Version())
+		assert.Equal(t, "account-1", loaded.ID())
+
+		// Nothing raised, nothing appended
+		records, err = eventstore.Save(ctx, store, loaded)
+		require.NoError(t, err)
+		assert.Empty(t, records)
+	})
+
+	t.Run("new aggregate", func(t *testing.T) {
+		t.Parallel()
+
+		account, err := eventstore.Load(ctx, eventstore.NewMemoryStore(), "account-1", accountAppliers...)
+		require.NoError(t, err)
+		assert.Zero(t, account.Version())
+		assert.Equal(t, Account{}, account.State())
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		t.Parallel()
+
+		account := eventstore.NewAggregate("account-1", accountAppliers...)
+		require.ErrorIs(t, account.Raise(AccountClosed{}), eventstore.ErrUnknownEvent)
+		assert.Empty(t, account.Changes())
+	})
+
+	t.Run("events without applier are skipped on load", func(t *testing.T) {
+		t.Parallel()
+		store := eventstore.NewMemoryStore()
+
+		account := eventstore.NewAggregate("account-1", append(accountAppliers,
+			eventstore.On(func(a *Account, _ AccountClosed) { a.Closed = true }))...)
+		require.NoError(t, account.Raise(MoneyDeposited{Amount: 5}))
+		require.NoError(t, account.Raise(AccountClosed{}))
+		_, err := eventstore.Save(ctx, store, account)
+		require.NoError(t, err)
+
+		loaded, err := eventstore.Load(ctx, store, "account-1", accountAppliers...)
+		require.NoError(t, err)
+		assert.Equal(t, Account{Balance: 5}, loaded.State())
+		assert.Equal(t, int64(2), loaded.Version())
+	})
+
+	t.Run("older event versions are upcast on load", func(t *testing.T) {
+		t.Parallel()
+		store := eventstore.NewMemoryStore()
+
+		_, err := store.Append(ctx, "account-1", eventstore.NoStream, event.Event{
+			Name:    "accounts.overdraft_limit_set",
+			Version: 1,
+			Payload: json.RawMessage(`{"Limit":50}`),
+		})
+		require.NoError(t, err)
+
+		account, err := eventstore.Load(ctx, store, "account-1", append(accountAppliers,
+			eventstore.On(func(a *Account, evt OverdraftLimitSet) { a.OverdraftCents = evt.LimitCents }))...)
+		require.NoError(t, err)
+		assert.Equal(t, 5000, account.State().OverdraftCents)
+
+		// New events are stored with the registered name and version
+		require.NoError(t, account.Raise(OverdraftLimitSet{LimitCents: 100}))
+		records, err := eventstore.Save(ctx, store, account)
+		require.NoError(t, err)
+		assert.Equal(t, "accounts.overdraft_limit_set", records[0].Name)
+		assert.Equal(t, 2, records[0].Version)
+	})
+
+	t.Run("concurrent writers conflict", func(t *testing.T) {
+		t.Parallel()
+		store := eventstore.NewMemoryStore()
+
+		first, err := eventstore.Load(ctx, store, "account-1", accountAppliers...)
+		require.NoError(t, err)
+		second, err := eventstore.Load(ctx, store, "account-1", accountAppliers...)
+		require.NoError(t, err)
+
+		require.NoError(t, first.Raise(MoneyDeposited{Amount: 10}))
+		_, err = eventstore.Save(ctx, store, first)
+		require.NoError(t, err)
+
+		require.NoError(t, second.Raise(MoneyDeposited{Amount: 20}))
+		_, err = eventstore.Save(ctx, store, second)
+		require.ErrorIs(t, err, eventstore.ErrConcurrencyConflict)
+	})
+
+	t.Run("load reads long streams in batches", func(t *testing.T) {
+		t.Parallel()
+		store := eventstore.NewMemoryStore()
+
+		account := eventstore.NewAggregate("account-1", accountAppliers...)
+		for range 1200 {
+			require.NoError(t, account.Raise(MoneyDeposited{Amount: 1}))
+		}
+		_, err := eventstore.Save(ctx, store, account)
+		require.NoError(t, err)
+
+		loaded, err := eventstore.Load(ctx, store, "account-1", accountAppliers...)
+		require.NoError(t, err)
+		assert.Equal(t, 1200, loaded.State().Balance)
+		assert.Equal(t, int64(1200), loaded.Version())
+	})
+}
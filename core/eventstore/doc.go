@@ -0,0 +1,116 @@
+// Package eventstore provides an append-only event store with optimistic
+// concurrency and event-sourced aggregates on top of core/event.
+//
+// Events are stored in streams, one per aggregate or entity, and numbered twice:
+// by their version within the stream and by their global position across all
+// streams. Appends name the stream version they expect, so two writers that
+// loaded the same state cannot both append.
+//
+// # Core Components
+//
+// EventStore appends to streams, reads a stream forward or backward, and reads all
+// events by global position. MemoryStore keeps events in memory for tests and
+// development; integration/eventstore/pgstore stores them in PostgreSQL.
+//
+// Record is a stored event: an event.Event with its stream, stream version and
+// global position, and the payload as raw JSON of the schema version it was
+// stored with.
+//
+// Aggregate rehydrates typed state from the events of its stream through
+// appliers created with On, and collects new events raised against that state.
+// Load and Save read and append them.
+//
+// PublishingStore publishes appended events to an event bus, so event.Processor
+// handlers react to them.
+//
+// # Basic Usage
+//
+//	type Account struct {
+//		Balance int
+//	}
+//
+//	type MoneyDeposited struct{ Amount int }
+//	type MoneyWithdrawn struct{ Amount int }
+//
+//	var accountAppliers = []eventstore.Applier[Account]{
+//		eventstore.On(func(a *Account, evt MoneyDeposited) { a.Balance += evt.Amount }),
+//		eventstore.On(func(a *Account, evt MoneyWithdrawn) { a.Balance -= evt.Amount }),
+//	}
+//
+//	func Withdraw(ctx context.Context, store eventstore.EventStore, id string, amount int) error {
+//		account, err := eventstore.Load(ctx, store, id, accountAppliers...)
+//		if err != nil {
+//			return err
+//		}
+//		if account.State().Balance < amount {
+//			return ErrInsufficientFunds
+//		}
+//		if err := account.Raise(MoneyWithdrawn{Amount: amount}); err != nil {
+//			return err
+//		}
+//		_, err = eventstore.Save(ctx, store, account)
+//		return err
+//	}
+//
+// # Optimistic Concurrency
+//
+// Append fails with ErrConcurrencyConflict unless the stream is at the expected
+// version: the version of its last event, or NoStream for a new stream. AnyVersion
+// skips the check. Save expects the version the aggregate was loaded at; on a
+// conflict, load the aggregate again and repeat the decision:
+//
+//	for {
+//		err := Withdraw(ctx, store, id, amount)
+//		if !errors.Is(err, eventstore.ErrConcurrencyConflict) {
+//			return err
+//		}
+//	}
+//
+// # Event Versions
+//
+// Events are stored under the name and schema version registered in core/event
+// (event.Register), and are never rewritten. Load upcasts stored events of older
+// versions with the upcasters registered in core/event before applying them, as
+// event handlers do for published events.
+//
+// # Reading Events
+//
+//	// Whole stream, oldest first
+//	records, err := store.ReadStream(ctx, "account-42", 0, 0)
+//
+//	// Last ten events of the stream, newest first
+//	records, err = store.ReadStreamBackward(ctx, "account-42", 0, 10)
+//
+//	// All streams, in pages by global position
+//	records, err = store.ReadAll(ctx, lastPosition+1, 500)
+//
+// # Publishing to Event Handlers
+//
+// Record.Event turns a stored event into an event.Event whose raw JSON payload
+// event handlers decode into their payload type. NewPublishingStore publishes
+// every appended event to a bus an event.Processor consumes:
+//
+//	bus := event.NewChannelBus()
+//	store, err := eventstore.NewPublishingStore(eventstore.NewMemoryStore(), bus)
+//
+//	processor := event.NewProcessor(
+//		event.WithEventSource(bus),
+//		event.WithHandler(event.NewHandlerFunc(func(ctx context.Context, evt MoneyWithdrawn) error {
+//			return balances.Update(ctx, event.EventID(ctx), evt)
+//		})),
+//	)
+//
+// Events are published after they are stored; a failed publish returns
+// ErrPublishFailed with the stored records. To publish exactly the events that
+// commit, use integration/eventstore/pgstore with the transactional outbox of
+// integration/bus/pgoutbox in one transaction.
+//
+// To build or rebuild read models from stored history, replay ReadAll through
+// event handlers with core/projection.
+//
+// # Custom Event Stores
+//
+// Implement EventStore for other databases. NewRecord encodes an event into a
+// record at a given stream version and CheckVersion performs the expected version
+// check, so implementations only assign global positions and keep appends atomic.
+package eventstore
@@ -0,0 +1,27 @@
+package eventstore
+
+import "errors"
+
+var (
+	// ErrConcurrencyConflict is returned when a stream is not at the version an append expects.
+	ErrConcurrencyConflict = errors.New("stream version conflict")
+
+	// ErrStreamIDEmpty is returned when appending to a stream without an ID.
+	ErrStreamIDEmpty = errors.New("stream id cannot be empty")
+
+	// ErrNoEvents is returned when appending no events.
+	ErrNoEvents = errors.New("no events to append")
+
+	// ErrStoreNil is returned when the event store is nil.
+	ErrStoreNil = errors.New("event store cannot be nil")
+
+	// ErrBusNil is returned when the event bus is nil.
+	ErrBusNil = errors.New("event bus cannot be nil")
+
+	// ErrPublishFailed is returned when appended events could not be published.
+	// The events are stored.
+	ErrPublishFailed = errors.New("failed to publish appended events")
+
+	// ErrUnknownEvent is returned when an aggregate raises an event it has no applier for.
+	ErrUnknownEvent = errors.New("no applier registered for event")
+)
@@ -0,0 +1,117 @@
+package eventstore
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/dmitrymomot/foundation/core/event"
+)
+
+// MemoryStore keeps event streams in memory, for tests and development.
+// Safe for concurrent use.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	all     []Record
+	streams map[string][]int // stream ID to indexes into all
+}
+
+// NewMemoryStore creates an empty in-memory event store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{streams: make(map[string][]int)}
+}
+
+// Append adds events to the end of the stream.
+func (s *MemoryStore) Append(_ context.Context, streamID string, expectedVersion int64, events ...event.Event) ([]Record, error) {
+	if streamID == "" {
+		return nil, ErrStreamIDEmpty
+	}
+	if len(events) == 0 {
+		return nil, ErrNoEvents
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	version := int64(len(s.streams[streamID]))
+	if err := CheckVersion(streamID, version, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(events))
+	for i, evt := range events {
+		record, err := NewRecord(streamID, version+int64(i)+1, evt)
+		if err != nil {
+			return nil, err
+		}
+		record.Position = int64(len(s.all) + i + 1)
+		records = append(records, record)
+	}
+
+	for _, record := range records {
+		s.streams[streamID] = append(s.streams[streamID], len(s.all))
+		s.all = append(s.all, record)
+	}
+
+	return records, nil
+}
+
+// ReadStream returns events of the stream from fromVersion onwards, oldest first.
+func (s *MemoryStore) ReadStream(_ context.Context, streamID string, fromVersion int64, limit int) ([]Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	indexes := s.streams[streamID]
+	start := max(fromVersion, 1) - 1
+	if start >= int64(len(indexes)) {
+		return []Record{}, nil
+	}
+
+	return s.collect(indexes[start:], limit), nil
+}
+
+// ReadStreamBackward returns events of the stream from fromVersion back to the first one, newest first.
+func (s *MemoryStore) ReadStreamBackward(_ context.Context, streamID string, fromVersion int64, limit int) ([]Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	indexes := s.streams[streamID]
+	end := int64(len(indexes))
+	if fromVersion > 0 && fromVersion < end {
+		end = fromVersion
+	}
+
+	backward := slices.Clone(indexes[:end])
+	slices.Reverse(backward)
+	return s.collect(backward, limit), nil
+}
+
+// ReadAll returns events of all streams from fromPosition onwards, in the order they were appended.
+func (s *MemoryStore) ReadAll(_ context.Context, fromPosition int64, limit int) ([]Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	start := max(fromPosition, 1) - 1
+	if start >= int64(len(s.all)) {
+		return []Record{}, nil
+	}
+
+	records := s.all[start:]
+	if limit > 0 && limit < len(records) {
+		records = records[:limit]
+	}
+	return slices.Clone(records), nil
+}
+
+// collect returns the records at the indexes, up to limit of them.
+func (s *MemoryStore) collect(indexes []int, limit int) []Record {
+	if limit > 0 && limit < len(indexes) {
+		indexes = indexes[:limit]
+	}
+
+	records := make([]Record, 0, len(indexes))
+	for _, i := range indexes {
+		records = append(records, s.all[i])
+	}
+	return records
+}
@@ -0,0 +1,175 @@
+package eventstore_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/event"
+	"github.com/dmitrymomot/foundation/core/eventstore"
+)
+
+func versions(records []eventstore.Record) []int64 {
+	out := make([]int64, 0, len(records))
+	for _, r := range records {
+		out = append(out, r.StreamVersion)
+	}
+	return out
+}
+
+func TestMemoryStore_Append(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("assigns versions and positions", func(t *testing.T) {
+		t.Parallel()
+		store := eventstore.NewMemoryStore()
+
+		records, err := store.Append(ctx, "account-1", eventstore.NoStream,
+			event.NewEvent(MoneyDeposited{Amount: 10}),
+			event.NewEvent(MoneyWithdrawn{Amount: 3}))
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+
+		assert.Equal(t, "account-1", records[0].StreamID)
+		assert.Equal(t, "MoneyDeposited", records[0].Name)
+		assert.JSONEq(t, `{"Amount":10}`, string(records[0].Payload))
+		assert.Equal(t, []int64{1, 2}, versions(records))
+
+		records, err = store.Append(ctx, "account-2", eventstore.NoStream, event.NewEvent(MoneyDeposited{Amount: 5}))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), records[0].StreamVersion)
+		assert.Equal(t, int64(3), records[0].Position)
+	})
+
+	t.Run("expected version", func(t *testing.T) {
+		t.Parallel()
+		store := eventstore.NewMemoryStore()
+
+		_, err := store.Append(ctx, "account-1", eventstore.NoStream, event.NewEvent(MoneyDeposited{Amount: 10}))
+		require.NoError(t, err)
+
+		_, err = store.Append(ctx, "account-1", eventstore.NoStream, event.NewEvent(MoneyDeposited{Amount: 10}))
+		require.ErrorIs(t, err, eventstore.ErrConcurrencyConflict)
+
+		_, err = store.Append(ctx, "account-1", 2, event.NewEvent(MoneyDeposited{Amount: 10}))
+		require.ErrorIs(t, err, eventstore.ErrConcurrencyConflict)
+
+		_, err = store.Append(ctx, "account-1", 1, event.NewEvent(MoneyDeposited{Amount: 10}))
+		require.NoError(t, err)
+
+		records, err := store.Append(ctx, "account-1", eventstore.AnyVersion, event.NewEvent(MoneyDeposited{Amount: 10}))
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), records[0].StreamVersion)
+	})
+
+	t.Run("invalid appends", func(t *testing.T) {
+		t.Parallel()
+		store := eventstore.NewMemoryStore()
+
+		_, err := store.Append(ctx, "", eventstore.AnyVersion, event.NewEvent(MoneyDeposited{}))
+		require.ErrorIs(t, err, eventstore.ErrStreamIDEmpty)
+
+		_, err = store.Append(ctx, "account-1", eventstore.AnyVersion)
+		require.ErrorIs(t, err, eventstore.ErrNoEvents)
+
+		// A failing event appends none of the batch
+		_, err = store.Append(ctx, "account-1", eventstore.AnyVersion,
+			event.NewEvent(MoneyDeposited{}),
+			event.Event{Name: "Broken", Payload: json.RawMessage(`{`)})
+		require.Error(t, err)
+
+		records, err := store.ReadAll(ctx, 0, 0)
+		require.NoError(t, err)
+		assert.Empty(t, records)
+	})
+
+	t.Run("concurrent appends conflict", func(t *testing.T) {
+		t.Parallel()
+		store := eventstore.NewMemoryStore()
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		var appended int
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := store.Append(ctx, "account-1", eventstore.NoStream, event.NewEvent(MoneyDeposited{})); err == nil {
+					mu.Lock()
+					appended++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, 1, appended)
+	})
+}
+
+func TestMemoryStore_Read(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := eventstore.NewMemoryStore()
+	for i := range 5 {
+		_, err := store.Append(ctx, "account-1", eventstore.AnyVersion, event.NewEvent(MoneyDeposited{Amount: i}))
+		require.NoError(t, err)
+		_, err = store.Append(ctx, "account-2", eventstore.AnyVersion, event.NewEvent(MoneyDeposited{Amount: i}))
+		require.NoError(t, err)
+	}
+
+	t.Run("stream forward", func(t *testing.T) {
+		t.Parallel()
+
+		records, err := store.ReadStream(ctx, "account-1", 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1, 2, 3, 4, 5}, versions(records))
+
+		records, err = store.ReadStream(ctx, "account-1", 3, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3, 4}, versions(records))
+
+		records, err = store.ReadStream(ctx, "account-1", 6, 0)
+		require.NoError(t, err)
+		assert.Empty(t, records)
+
+		records, err = store.ReadStream(ctx, "missing", 0, 0)
+		require.NoError(t, err)
+		assert.Empty(t, records)
+	})
+
+	t.Run("stream backward", func(t *testing.T) {
+		t.Parallel()
+
+		records, err := store.ReadStreamBackward(ctx, "account-2", 0, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{5, 4}, versions(records))
+
+		records, err = store.ReadStreamBackward(ctx, "account-2", 3, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []int64{3, 2, 1}, versions(records))
+	})
+
+	t.Run("all", func(t *testing.T) {
+		t.Parallel()
+
+		records, err := store.ReadAll(ctx, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, records, 10)
+		for i, record := range records {
+			assert.Equal(t, int64(i+1), record.Position)
+		}
+
+		records, err = store.ReadAll(ctx, 9, 5)
+		require.NoError(t, err)
+		require.Len(t, records, 2)
+		assert.Equal(t, "account-1", records[0].StreamID)
+		assert.Equal(t, "account-2", records[1].StreamID)
+	})
+}
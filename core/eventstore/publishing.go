@@ -0,0 +1,104 @@
+package eventstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/dmitrymomot/foundation/core/event"
+)
+
+// eventBus represents a message bus that can publish events, such as
+// event.ChannelBus, integration/bus/redisstream or integration/bus/pgoutbox.
+type eventBus interface {
+	Publish(ctx context.Context, data []byte) error
+}
+
+// PublishingStore is an EventStore that publishes appended events to an event
+// bus, so event.Processor handlers react to them as to any other event.
+// Reads go straight to the underlying store.
+type PublishingStore struct {
+	EventStore
+	bus    eventBus
+	logger *slog.Logger
+}
+
+// PublishingOption configures a PublishingStore.
+type PublishingOption func(*PublishingStore)
+
+// WithPublishingLogger configures structured logging for publishing operations.
+func WithPublishingLogger(logger *slog.Logger) PublishingOption {
+	return func(s *PublishingStore) {
+		if logger != nil {
+			s.logger = logger
+		}
+	}
+}
+
+// NewPublishingStore wraps the store to publish every appended event to the bus.
+//
+// Events are published after they are appended. Use the transactional outbox of
+// integration/bus/pgoutbox as the bus with integration/eventstore/pgstore, in one
+// transaction carried by the context, to publish exactly the events that commit.
+//
+// Example:
+//
+//	store, err := eventstore.NewPublishingStore(eventstore.NewMemoryStore(), bus)
+//	processor := event.NewProcessor(
+//	    event.WithEventSource(bus),
+//	    event.WithHandler(event.NewHandlerFunc(updateBalanceView)),
+//	)
+func NewPublishingStore(store EventStore, bus eventBus, opts ...PublishingOption) (*PublishingStore, error) {
+	if store == nil {
+		return nil, ErrStoreNil
+	}
+	if bus == nil {
+		return nil, ErrBusNil
+	}
+
+	s := &PublishingStore{
+		EventStore: store,
+		bus:        bus,
+		logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+// Append appends the events and publishes them in order. When publishing fails,
+// it returns the stored records together with an error wrapping ErrPublishFailed;
+// the events that follow the failed one are not published.
+func (s *PublishingStore) Append(ctx context.Context, streamID string, expectedVersion int64, events ...event.Event) ([]Record, error) {
+	records, err := s.EventStore.Append(ctx, streamID, expectedVersion, events...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		data, err := json.Marshal(record.Event())
+		if err == nil {
+			err = s.bus.Publish(ctx, data)
+		}
+		if err != nil {
+			s.logger.ErrorContext(ctx, "failed to publish appended event",
+				slog.String("event_id", record.ID),
+				slog.String("event_name", record.Name),
+				slog.String("stream_id", record.StreamID),
+				slog.String("error", err.Error()))
+			return records, fmt.Errorf("%w: event %s: %w", ErrPublishFailed, record.ID, err)
+		}
+
+		s.logger.DebugContext(ctx, "appended event published",
+			slog.String("event_id", record.ID),
+			slog.String("event_name", record.Name),
+			slog.String("stream_id", record.StreamID))
+	}
+
+	return records, nil
+}
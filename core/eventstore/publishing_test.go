@@ -0,0 +1,100 @@
+package eventstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/event"
+	"github.com/dmitrymomot/foundation/core/eventstore"
+)
+
+type failingBus struct{}
+
+func (failingBus) Publish(context.Context, []byte) error {
+	return errors.New("bus unavailable")
+}
+
+func TestNewPublishingStore(t *testing.T) {
+	t.Parallel()
+
+	_, err := eventstore.NewPublishingStore(nil, event.NewChannelBus())
+	require.ErrorIs(t, err, eventstore.ErrStoreNil)
+
+	_, err = eventstore.NewPublishingStore(eventstore.NewMemoryStore(), nil)
+	require.ErrorIs(t, err, eventstore.ErrBusNil)
+}
+
+func TestPublishingStore_Append(t *testing.T) {
+	t.Parallel()
+
+	t.Run("processor handles appended events", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		bus := event.NewChannelBus()
+		defer bus.Close()
+
+		store, err := eventstore.NewPublishingStore(eventstore.NewMemoryStore(), bus)
+		require.NoError(t, err)
+
+		received := make(chan MoneyDeposited, 1)
+		processor := event.NewProcessor(
+			event.WithEventSource(bus),
+			event.WithHandler(event.NewHandlerFunc(func(ctx context.Context, evt MoneyDeposited) error {
+				received <- evt
+				return nil
+			})),
+		)
+		go func() { _ = processor.Start(ctx) }()
+
+		account := eventstore.NewAggregate("account-1", accountAppliers...)
+		require.NoError(t, account.Raise(MoneyDeposited{Amount: 42}))
+		_, err = eventstore.Save(ctx, store, account)
+		require.NoError(t, err)
+
+		select {
+		case evt := <-received:
+			assert.Equal(t, 42, evt.Amount)
+		case <-time.After(time.Second):
+			t.Fatal("appended event was not handled")
+		}
+
+		records, err := store.ReadStream(ctx, "account-1", 0, 0)
+		require.NoError(t, err)
+		assert.Len(t, records, 1)
+	})
+
+	t.Run("publish failure keeps stored events", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		store, err := eventstore.NewPublishingStore(eventstore.NewMemoryStore(), failingBus{})
+		require.NoError(t, err)
+
+		records, err := store.Append(ctx, "account-1", eventstore.NoStream, event.NewEvent(MoneyDeposited{Amount: 1}))
+		require.ErrorIs(t, err, eventstore.ErrPublishFailed)
+		require.Len(t, records, 1)
+
+		stored, err := store.ReadStream(ctx, "account-1", 0, 0)
+		require.NoError(t, err)
+		assert.Len(t, stored, 1)
+	})
+
+	t.Run("conflicts are not published", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+
+		store, err := eventstore.NewPublishingStore(eventstore.NewMemoryStore(), failingBus{})
+		require.NoError(t, err)
+
+		_, err = store.Append(ctx, "account-1", 3, event.NewEvent(MoneyDeposited{Amount: 1}))
+		require.ErrorIs(t, err, eventstore.ErrConcurrencyConflict)
+		assert.NotErrorIs(t, err, eventstore.ErrPublishFailed)
+	})
+}
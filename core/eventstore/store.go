@@ -0,0 +1,126 @@
+package eventstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dmitrymomot/foundation/core/event"
+)
+
+const (
+	// AnyVersion appends to a stream whatever its current version.
+	AnyVersion int64 = -1
+
+	// NoStream appends only to a stream that has no events yet.
+	NoStream int64 = 0
+)
+
+// EventStore is an append-only store of event streams with optimistic concurrency.
+// Implementations: MemoryStore and integration/eventstore/pgstore.
+type EventStore interface {
+	// Append adds events to the end of the stream and returns them as stored.
+	// It fails with ErrConcurrencyConflict unless the stream is at expectedVersion
+	// (the version of its last event, NoStream for a new stream) or expectedVersion
+	// is AnyVersion. Either all events are appended or none.
+	Append(ctx context.Context, streamID string, expectedVersion int64, events ...event.Event) ([]Record, error)
+
+	// ReadStream returns events of the stream from fromVersion onwards, oldest first.
+	// A limit of zero or less returns all of them. A missing stream has no events.
+	ReadStream(ctx context.Context, streamID string, fromVersion int64, limit int) ([]Record, error)
+
+	// ReadStreamBackward returns events of the stream from fromVersion back to the
+	// first one, newest first. A fromVersion of zero or less starts at the last event.
+	ReadStreamBackward(ctx context.Context, streamID string, fromVersion int64, limit int) ([]Record, error)
+
+	// ReadAll returns events of all streams from the global position fromPosition
+	// onwards, in the order they were appended.
+	ReadAll(ctx context.Context, fromPosition int64, limit int) ([]Record, error)
+}
+
+// Record is an event as stored in an event store.
+type Record struct {
+	ID            string          `json:"id"`
+	StreamID      string          `json:"stream_id"`
+	StreamVersion int64           `json:"stream_version"` // 1-based position in the stream
+	Position      int64           `json:"position"`       // 1-based position across all streams
+	Name          string          `json:"name"`
+	Version       int             `json:"version,omitempty"` // schema version of the payload
+	Payload       json.RawMessage `json:"payload"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// Event returns the record as an event.Event with the raw JSON payload, which
+// event.Handler decodes into its payload type.
+func (r Record) Event() event.Event {
+	return event.Event{
+		ID:        r.ID,
+		Name:      r.Name,
+		Version:   r.Version,
+		Payload:   r.Payload,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+// NewRecord turns an event into the record at streamVersion of the stream, for
+// EventStore implementations. It encodes the payload as JSON, keeping []byte and
+// json.RawMessage payloads as they are, and fills in a missing ID, name, version
+// or creation time. The store assigns the global position.
+func NewRecord(streamID string, streamVersion int64, evt event.Event) (Record, error) {
+	if evt.ID == "" {
+		evt.ID = uuid.New().String()
+	}
+	if evt.Name == "" || evt.Version == 0 {
+		derived := event.NewEvent(evt.Payload)
+		if evt.Name == "" {
+			evt.Name = derived.Name
+		}
+		if evt.Version == 0 {
+			evt.Version = derived.Version
+		}
+	}
+	if evt.CreatedAt.IsZero() {
+		evt.CreatedAt = time.Now()
+	}
+
+	var payload json.RawMessage
+	switch p := evt.Payload.(type) {
+	case json.RawMessage:
+		payload = slices.Clone(p)
+	case []byte:
+		payload = slices.Clone(p)
+	default:
+		data, err := json.Marshal(p)
+		if err != nil {
+			return Record{}, fmt.Errorf("failed to marshal event %s: %w", evt.Name, err)
+		}
+		payload = data
+	}
+	if !json.Valid(payload) {
+		return Record{}, fmt.Errorf("event %s payload is not valid JSON", evt.Name)
+	}
+
+	return Record{
+		ID:            evt.ID,
+		StreamID:      streamID,
+		StreamVersion: streamVersion,
+		Name:          evt.Name,
+		Version:       evt.Version,
+		Payload:       payload,
+		CreatedAt:     evt.CreatedAt,
+	}, nil
+}
+
+// CheckVersion returns ErrConcurrencyConflict unless a stream at version accepts
+// an append expecting expectedVersion, for EventStore implementations.
+func CheckVersion(streamID string, version, expectedVersion int64) error {
+	if expectedVersion == AnyVersion || expectedVersion == version {
+		return nil
+	}
+	return fmt.Errorf("%w: stream %s is at version %d, expected %d",
+		ErrConcurrencyConflict, streamID, version, expectedVersion)
+}
@@ -0,0 +1,65 @@
+package projection
+
+import (
+	"context"
+	"sync"
+)
+
+// CheckpointStore keeps the position of the last event each projection has
+// handled, so replays resume where they stopped.
+//
+// A store that also implements RunInTx(ctx, fn) runs the handlers of each
+// batch and the checkpoint update in one transaction, and one that implements
+// Lock(ctx, name) as well locks the checkpoint in that transaction before the
+// batch is read. Handlers writing read models through the transaction in ctx
+// then project every event exactly once, even with replicas replaying the same
+// projection. Without both, delivery is at least once.
+type CheckpointStore interface {
+	// Load returns the checkpoint of the projection, or 0 if it has none.
+	Load(ctx context.Context, name string) (int64, error)
+	// Save stores the checkpoint of the projection.
+	Save(ctx context.Context, name string, position int64) error
+}
+
+// txRunner is implemented by checkpoint stores that can save a checkpoint in
+// the transaction of the handlers that advanced it.
+type txRunner interface {
+	RunInTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// checkpointLocker is implemented by checkpoint stores that can lock a
+// checkpoint until the transaction in ctx ends.
+type checkpointLocker interface {
+	// Lock waits for the checkpoint of the projection, holds it until the
+	// transaction in ctx ends, and returns it.
+	Lock(ctx context.Context, name string) (int64, error)
+}
+
+// MemoryCheckpointStore keeps checkpoints in memory, for tests and development.
+// Checkpoints are lost on restart, so projections replay from the beginning.
+// It neither runs transactions nor locks checkpoints, so replay each projection
+// from one replayer at a time. Safe for concurrent use.
+type MemoryCheckpointStore struct {
+	mu          sync.RWMutex
+	checkpoints map[string]int64
+}
+
+// NewMemoryCheckpointStore creates an empty in-memory checkpoint store.
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: make(map[string]int64)}
+}
+
+// Load returns the checkpoint of the projection, or 0 if it has none.
+func (s *MemoryCheckpointStore) Load(ctx context.Context, name string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.checkpoints[name], nil
+}
+
+// Save stores the checkpoint of the projection.
+func (s *MemoryCheckpointStore) Save(ctx context.Context, name string, position int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[name] = position
+	return nil
+}
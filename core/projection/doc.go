@@ -0,0 +1,114 @@
+// Package projection builds and rebuilds read models from historical events.
+//
+// A projection is a set of event.Handler values that turn events into a read
+// model, such as a table of account balances. Replayer feeds the events of a
+// Source through those handlers in position order and checkpoints its progress,
+// so a replay that stops resumes where it left off. Runner keeps a projection
+// current for the lifetime of the process with the same checkpoints.
+//
+// # Core Components
+//
+// Source reads events by global position. Every eventstore.EventStore is a
+// Source, including the PostgreSQL store of integration/eventstore/pgstore;
+// SourceFunc adapts any other table.
+//
+// CheckpointStore keeps the position of the last event each projection has
+// handled. MemoryCheckpointStore is for tests and development;
+// integration/eventstore/pgstore provides a PostgreSQL CheckpointStore.
+//
+// Replayer replays the events after the checkpoint until the source has no more
+// and returns a ReplayResult. Runner repeats that on a poll interval with the
+// Start/Stop/Run/Stats/Healthcheck lifecycle of event.Processor.
+//
+// # Basic Usage
+//
+//	replayer, err := projection.NewReplayer("account-balances", store,
+//		projection.WithHandlers(
+//			event.NewHandlerFunc(func(ctx context.Context, evt MoneyDeposited) error {
+//				return balances.Add(ctx, event.EventID(ctx), evt.AccountID, evt.Amount)
+//			}),
+//			event.NewHandlerFunc(func(ctx context.Context, evt MoneyWithdrawn) error {
+//				return balances.Add(ctx, event.EventID(ctx), evt.AccountID, -evt.Amount)
+//			}),
+//		),
+//		projection.WithCheckpointStore(checkpoints),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//
+//	result, err := replayer.Replay(ctx)
+//
+// Handlers are the same event.Handler values an event.Processor runs, matched
+// by exact name or topic pattern, and receive the event metadata (event.EventID,
+// event.EventName, event.EventTime) in their context. Registered upcasters bring
+// old payload versions up to date. Events no handler subscribes to are skipped.
+//
+// # Rebuilding a Projection
+//
+// Clear the read model, move the checkpoint back with Reset and replay:
+//
+//	if err := balances.Truncate(ctx); err != nil {
+//		return err
+//	}
+//	if err := replayer.Reset(ctx); err != nil {
+//		return err
+//	}
+//	result, err := replayer.Replay(ctx)
+//
+// To rebuild without downtime, replay into a new read model under a new
+// projection name and switch readers over once it has caught up.
+//
+// # Checkpoints and Failures
+//
+// The checkpoint is saved after every batch (WithBatchSize, default 500). A
+// handler error or panic stops the replay with ErrHandlerFailed, and the
+// checkpoint stays at the last event projected before it, so the next replay
+// retries the failed event. Handlers may see an event again after a crash and
+// should be idempotent, for example by keying writes on event.EventID.
+//
+// A CheckpointStore with a RunInTx(ctx, fn) method runs each batch's handlers
+// and its checkpoint update in one transaction, and a failed batch is retried
+// whole. One that also has a Lock(ctx, name) method, such as the PostgreSQL
+// one, locks the checkpoint in that transaction and reads the batch after the
+// checkpoint it holds, so replicas replaying the same projection take turns
+// instead of projecting the same batch. Handlers that write the read model
+// through the transaction in ctx then project every event exactly once. With
+// any other store, delivery is at least once.
+//
+// # Throttling and Dry Runs
+//
+// WithRateLimit caps how many events per second reach the handlers, so a
+// rebuild does not starve the database serving the application. WithDryRun
+// reads and matches events without calling handlers or saving checkpoints; the
+// ReplayResult reports how many events would be handled and skipped:
+//
+//	dry, _ := projection.NewReplayer("account-balances", store,
+//		projection.WithHandlers(handlers...),
+//		projection.WithCheckpointStore(checkpoints),
+//		projection.WithDryRun(),
+//	)
+//	result, err := dry.Replay(ctx)
+//	log.Printf("would project %d of %d events", result.Handled, result.Events)
+//
+// # Long-Running Projections
+//
+// Runner catches up from the checkpoint, then polls for new events:
+//
+//	runner, err := projection.NewRunner(replayer,
+//		projection.WithPollInterval(500*time.Millisecond),
+//	)
+//
+//	g, ctx := errgroup.WithContext(ctx)
+//	g.Go(runner.Run(ctx))
+//
+// A failed round is logged and retried from the checkpoint after the poll
+// interval. Healthcheck reports ErrRunnerNotRunning while stopped and
+// ErrProjectionFailing until a round succeeds again; Stats exposes the
+// checkpoint, processed events and the last error.
+//
+// Replays of one Replayer are serialized, so calling Replay while its Runner is
+// running is safe. Runners in several processes may share a projection when
+// its CheckpointStore locks checkpoints; otherwise run it in one process at a
+// time, or give each process its own checkpoint name and read model.
+package projection
@@ -0,0 +1,35 @@
+package projection
+
+import "errors"
+
+var (
+	// ErrNameEmpty is returned when a replayer is created without a projection name.
+	ErrNameEmpty = errors.New("projection name cannot be empty")
+
+	// ErrSourceNil is returned when the historical event source is nil.
+	ErrSourceNil = errors.New("event source cannot be nil")
+
+	// ErrNoHandlers is returned when a replayer is created without handlers.
+	ErrNoHandlers = errors.New("no event handlers configured")
+
+	// ErrHandlerFailed is returned when a handler fails to project an event.
+	// The checkpoint stays at the last event projected before it.
+	ErrHandlerFailed = errors.New("projection handler failed")
+
+	// ErrReplayerNil is returned when a runner is created without a replayer.
+	ErrReplayerNil = errors.New("replayer cannot be nil")
+
+	// ErrDryRunRunner is returned when a runner is created with a dry-run replayer.
+	ErrDryRunRunner = errors.New("projection runner cannot use a dry-run replayer")
+
+	// ErrRunnerAlreadyStarted is returned when starting a runner that is already running.
+	ErrRunnerAlreadyStarted = errors.New("projection runner already started")
+
+	// ErrRunnerNotStarted is returned when stopping a runner that is not running.
+	ErrRunnerNotStarted = errors.New("projection runner not started")
+
+	// Health check errors
+	ErrHealthcheckFailed = errors.New("healthcheck failed")
+	ErrRunnerNotRunning  = errors.New("projection runner is not running")
+	ErrProjectionFailing = errors.New("projection is failing")
+)
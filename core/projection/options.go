@@ -0,0 +1,106 @@
+package projection
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/dmitrymomot/foundation/core/event"
+)
+
+// ReplayOption configures a Replayer.
+type ReplayOption func(*Replayer)
+
+// WithHandlers adds the handlers that build the projection. Events are matched
+// by handler name the way event.Processor matches them, including topic
+// patterns such as "billing.*". Events no handler matches are skipped.
+func WithHandlers(handlers ...event.Handler) ReplayOption {
+	return func(r *Replayer) {
+		for _, h := range handlers {
+			if h != nil {
+				r.handlers = append(r.handlers, h)
+			}
+		}
+	}
+}
+
+// WithCheckpointStore sets where the replay position is kept.
+// Default is a MemoryCheckpointStore, which does not survive restarts.
+func WithCheckpointStore(store CheckpointStore) ReplayOption {
+	return func(r *Replayer) {
+		if store != nil {
+			r.checkpoints = store
+		}
+	}
+}
+
+// WithBatchSize sets how many events are read from the source at once.
+// The checkpoint is saved after each batch. Default is 500.
+func WithBatchSize(size int) ReplayOption {
+	return func(r *Replayer) {
+		if size > 0 {
+			r.batchSize = size
+		}
+	}
+}
+
+// WithRateLimit caps how many events per second are passed to handlers, to
+// keep a rebuild from starving the database the read models live in.
+// Skipped events are not counted. Default is unlimited.
+func WithRateLimit(eventsPerSecond int) ReplayOption {
+	return func(r *Replayer) {
+		if eventsPerSecond > 0 {
+			r.interval = time.Second / time.Duration(eventsPerSecond)
+		}
+	}
+}
+
+// WithDryRun reads and matches events without calling handlers or saving
+// checkpoints. The result reports what a real replay would do.
+func WithDryRun() ReplayOption {
+	return func(r *Replayer) {
+		r.dryRun = true
+	}
+}
+
+// WithLogger sets the logger for replay progress and failures.
+// Use slog.New(slog.NewTextHandler(io.Discard, nil)) to disable logging.
+func WithLogger(logger *slog.Logger) ReplayOption {
+	return func(r *Replayer) {
+		if logger != nil {
+			r.logger = logger
+		}
+	}
+}
+
+// RunnerOption configures a Runner.
+type RunnerOption func(*Runner)
+
+// WithPollInterval sets how long the runner waits for new events after it has
+// caught up, and before retrying a failed batch. Default is 1 second.
+func WithPollInterval(interval time.Duration) RunnerOption {
+	return func(r *Runner) {
+		if interval > 0 {
+			r.pollInterval = interval
+		}
+	}
+}
+
+// WithShutdownTimeout sets the maximum time to wait for the current batch
+// during shutdown. Default is 30 seconds.
+func WithShutdownTimeout(timeout time.Duration) RunnerOption {
+	return func(r *Runner) {
+		if timeout > 0 {
+			r.shutdownTimeout = timeout
+		}
+	}
+}
+
+// WithRunnerLogger sets the logger for the runner lifecycle.
+// Use slog.New(slog.NewTextHandler(io.Discard, nil)) to disable logging.
+func WithRunnerLogger(logger *slog.Logger) RunnerOption {
+	return func(r *Runner) {
+		if logger != nil {
+			r.logger = logger
+		}
+	}
+}
@@ -0,0 +1,365 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/dmitrymomot/foundation/core/event"
+	"github.com/dmitrymomot/foundation/core/eventstore"
+)
+
+// Source reads historical events in the order of their global positions.
+// Every eventstore.EventStore satisfies it, including the PostgreSQL store of
+// integration/eventstore/pgstore; wrap any other table with SourceFunc.
+type Source interface {
+	// ReadAll returns up to limit events starting at fromPosition (inclusive),
+	// ordered by position.
+	ReadAll(ctx context.Context, fromPosition int64, limit int) ([]eventstore.Record, error)
+}
+
+// SourceFunc adapts a function to the Source interface.
+type SourceFunc func(ctx context.Context, fromPosition int64, limit int) ([]eventstore.Record, error)
+
+// ReadAll calls f(ctx, fromPosition, limit).
+func (f SourceFunc) ReadAll(ctx context.Context, fromPosition int64, limit int) ([]eventstore.Record, error) {
+	return f(ctx, fromPosition, limit)
+}
+
+// ReplayResult summarizes a replay.
+type ReplayResult struct {
+	Events   int64         // Events read from the source and projected or skipped
+	Handled  int64         // Events passed to at least one handler (that would be, in a dry run)
+	Skipped  int64         // Events no handler subscribes to
+	Position int64         // Position of the last event read; the saved checkpoint unless DryRun
+	Duration time.Duration // Time spent replaying
+	DryRun   bool          // Whether handlers and checkpoints were left untouched
+}
+
+// Replayer feeds historical events from a Source through the handlers of one
+// projection, in position order, and checkpoints its progress so an
+// interrupted replay resumes where it stopped.
+type Replayer struct {
+	name        string
+	source      Source
+	handlers    []event.Handler
+	checkpoints CheckpointStore
+
+	// Configuration
+	batchSize int
+	interval  time.Duration
+	dryRun    bool
+	logger    *slog.Logger
+
+	// mu serializes replays of the projection and guards next
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewReplayer creates a replayer of the named projection. The name keys its
+// checkpoint, so it must be stable across deployments.
+func NewReplayer(name string, source Source, opts ...ReplayOption) (*Replayer, error) {
+	if name == "" {
+		return nil, ErrNameEmpty
+	}
+	if source == nil {
+		return nil, ErrSourceNil
+	}
+
+	r := &Replayer{
+		name:        name,
+		source:      source,
+		checkpoints: NewMemoryCheckpointStore(),
+		batchSize:   500,
+		logger:      slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if len(r.handlers) == 0 {
+		return nil, ErrNoHandlers
+	}
+
+	return r, nil
+}
+
+// Name returns the projection name.
+func (r *Replayer) Name() string {
+	return r.name
+}
+
+// Position returns the checkpoint of the projection: the position of the last
+// event it has handled, or 0 before the first replay.
+func (r *Replayer) Position(ctx context.Context) (int64, error) {
+	position, err := r.checkpoints.Load(ctx, r.name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load checkpoint of projection %s: %w", r.name, err)
+	}
+	return position, nil
+}
+
+// Reset moves the checkpoint back to the beginning, so the next replay rebuilds
+// the projection from the first event. Clear the read model before replaying.
+// A dry-run replayer leaves the checkpoint untouched.
+func (r *Replayer) Reset(ctx context.Context) error {
+	if r.dryRun {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.checkpoints.Save(ctx, r.name, 0); err != nil {
+		return fmt.Errorf("failed to reset checkpoint of projection %s: %w", r.name, err)
+	}
+	return nil
+}
+
+// Replay feeds the events after the checkpoint through the handlers until the
+// source has no more, saving the checkpoint after every batch.
+//
+// A handler error stops the replay with ErrHandlerFailed; the checkpoint stays
+// at the last event projected before it, so the next replay retries that event.
+// The result is returned on error too and covers the progress made.
+func (r *Replayer) Replay(ctx context.Context) (ReplayResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	start := time.Now()
+	result := ReplayResult{DryRun: r.dryRun}
+
+	position, err := r.Position(ctx)
+	if err != nil {
+		return result, err
+	}
+	result.Position = position
+
+	r.logger.DebugContext(ctx, "projection replay started",
+		slog.String("projection", r.name),
+		slog.Int64("from_position", position),
+		slog.Bool("dry_run", r.dryRun))
+
+	for {
+		batch, err := r.replayBatch(ctx, result.Position)
+		result.Events += batch.events
+		result.Handled += batch.handled
+		result.Skipped += batch.skipped
+		result.Position = batch.position
+		result.Duration = time.Since(start)
+
+		if err != nil {
+			if ctx.Err() == nil {
+				r.logger.ErrorContext(ctx, "projection replay failed",
+					slog.String("projection", r.name),
+					slog.Int64("position", result.Position),
+					slog.String("error", err.Error()))
+			}
+			return result, err
+		}
+		if batch.read < r.batchSize {
+			break
+		}
+	}
+
+	level := slog.LevelDebug
+	if result.Events > 0 {
+		level = slog.LevelInfo
+	}
+	r.logger.Log(ctx, level, "projection replay completed",
+		slog.String("projection", r.name),
+		slog.Int64("position", result.Position),
+		slog.Int64("events", result.Events),
+		slog.Int64("handled", result.Handled),
+		slog.Int64("skipped", result.Skipped),
+		slog.Duration("duration", result.Duration),
+		slog.Bool("dry_run", r.dryRun))
+
+	return result, nil
+}
+
+// batchResult is the outcome of one batch.
+type batchResult struct {
+	read     int   // Events read from the source
+	events   int64 // Events projected or skipped
+	handled  int64
+	skipped  int64
+	position int64 // Position of the last projected or skipped event
+}
+
+// replayBatch projects the batch of events after the position and saves the checkpoint.
+func (r *Replayer) replayBatch(ctx context.Context, after int64) (batchResult, error) {
+	if tx, ok := r.checkpoints.(txRunner); ok && !r.dryRun {
+		return r.replayBatchInTx(ctx, tx, after)
+	}
+
+	records, err := r.read(ctx, after)
+	if err != nil {
+		return batchResult{position: after}, err
+	}
+
+	res := batchResult{read: len(records), position: after}
+	if len(records) == 0 {
+		return res, nil
+	}
+
+	if r.dryRun {
+		err := r.project(ctx, records, &res)
+		return res, err
+	}
+
+	err = r.project(ctx, records, &res)
+	if res.position > after {
+		// Progress made before a failure or shutdown is kept
+		if saveErr := r.save(context.WithoutCancel(ctx), res.position); saveErr != nil {
+			return res, errors.Join(err, saveErr)
+		}
+	}
+	return res, err
+}
+
+// replayBatchInTx reads and projects the batch and saves the checkpoint in one
+// transaction, so a failed batch is retried whole. A store that locks
+// checkpoints is locked first, and the batch starts at the checkpoint read
+// under the lock: another replica may have moved it past after.
+func (r *Replayer) replayBatchInTx(ctx context.Context, tx txRunner, after int64) (batchResult, error) {
+	var committed batchResult
+	err := tx.RunInTx(ctx, func(ctx context.Context) error {
+		from := after
+		if locker, ok := r.checkpoints.(checkpointLocker); ok {
+			position, err := locker.Lock(ctx, r.name)
+			if err != nil {
+				return fmt.Errorf("failed to lock checkpoint of projection %s: %w", r.name, err)
+			}
+			from = position
+		}
+
+		records, err := r.read(ctx, from)
+		if err != nil {
+			return err
+		}
+
+		committed = batchResult{read: len(records), position: from}
+		if len(records) == 0 {
+			return nil
+		}
+		if err := r.project(ctx, records, &committed); err != nil {
+			return err
+		}
+		return r.save(ctx, committed.position)
+	})
+	if err != nil {
+		return batchResult{position: after}, err
+	}
+	return committed, nil
+}
+
+// read returns the batch of events after the position.
+func (r *Replayer) read(ctx context.Context, after int64) ([]eventstore.Record, error) {
+	records, err := r.source.ReadAll(ctx, after+1, r.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events after position %d: %w", after, err)
+	}
+	return records, nil
+}
+
+// project passes the records to their handlers in order, stopping at the first failure.
+func (r *Replayer) project(ctx context.Context, records []eventstore.Record, res *batchResult) error {
+	for _, record := range records {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		handlers := r.handlersFor(record.Name)
+		if len(handlers) == 0 {
+			res.skipped++
+		} else {
+			if !r.dryRun {
+				if err := r.throttle(ctx); err != nil {
+					return err
+				}
+				if err := handle(ctx, record, handlers); err != nil {
+					return err
+				}
+			}
+			res.handled++
+		}
+
+		res.events++
+		res.position = record.Position
+	}
+	return nil
+}
+
+// handlersFor returns the handlers subscribed to the event name, by exact name
+// or topic pattern.
+func (r *Replayer) handlersFor(name string) []event.Handler {
+	var handlers []event.Handler
+	for _, h := range r.handlers {
+		if event.MatchTopic(h.EventName(), name) {
+			handlers = append(handlers, h)
+		}
+	}
+	return handlers
+}
+
+// throttle waits until the rate limit allows handling the next event.
+func (r *Replayer) throttle(ctx context.Context) error {
+	if r.interval == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	if wait := r.next.Sub(now); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		now = r.next
+	}
+	r.next = now.Add(r.interval)
+
+	return nil
+}
+
+// save stores the checkpoint of the projection.
+func (r *Replayer) save(ctx context.Context, position int64) error {
+	if err := r.checkpoints.Save(ctx, r.name, position); err != nil {
+		return fmt.Errorf("failed to save checkpoint of projection %s at position %d: %w", r.name, position, err)
+	}
+	return nil
+}
+
+// handle passes the event to each handler with the metadata event.Processor
+// puts in the context.
+func handle(ctx context.Context, record eventstore.Record, handlers []event.Handler) error {
+	evt := record.Event()
+	ctx = event.WithStartProcessingTime(event.WithEventMeta(ctx, evt), time.Now())
+
+	for _, h := range handlers {
+		if err := safeHandle(ctx, h, evt.Payload); err != nil {
+			return fmt.Errorf("%w: %s on event %s at position %d: %w",
+				ErrHandlerFailed, h.EventName(), record.Name, record.Position, err)
+		}
+	}
+	return nil
+}
+
+// safeHandle turns a handler panic into an error, so it fails the replay
+// instead of the process.
+func safeHandle(ctx context.Context, h event.Handler, payload any) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("handler panicked: %v", p)
+		}
+	}()
+	return h.Handle(ctx, payload)
+}
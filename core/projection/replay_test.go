@@ -0,0 +1,474 @@
+package projection_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/event"
+	"github.com/dmitrymomot/foundation/core/eventstore"
+	"github.com/dmitrymomot/foundation/core/projection"
+)
+
+type ProductAdded struct {
+	SKU   string
+	Price int
+}
+
+type ProductRemoved struct {
+	SKU string
+}
+
+type StockCounted struct {
+	SKU      string
+	Quantity int
+}
+
+// catalog is a read model of the products currently on sale.
+type catalog struct {
+	mu       sync.Mutex
+	products map[string]int
+	calls    int
+}
+
+func newCatalog() *catalog {
+	return &catalog{products: make(map[string]int)}
+}
+
+func (c *catalog) handlers() []event.Handler {
+	return []event.Handler{
+		event.NewHandlerFunc(func(ctx context.Context, e ProductAdded) error {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.calls++
+			c.products[e.SKU] = e.Price
+			return nil
+		}),
+		event.NewHandlerFunc(func(ctx context.Context, e ProductRemoved) error {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.calls++
+			delete(c.products, e.SKU)
+			return nil
+		}),
+	}
+}
+
+func (c *catalog) snapshot() (map[string]int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	products := make(map[string]int, len(c.products))
+	for sku, price := range c.products {
+		products[sku] = price
+	}
+	return products, c.calls
+}
+
+func appendEvents(t *testing.T, store eventstore.EventStore, streamID string, payloads ...any) {
+	t.Helper()
+	events := make([]event.Event, 0, len(payloads))
+	for _, p := range payloads {
+		events = append(events, event.NewEvent(p))
+	}
+	_, err := store.Append(context.Background(), streamID, eventstore.AnyVersion, events...)
+	require.NoError(t, err)
+}
+
+func seedCatalog(t *testing.T) *eventstore.MemoryStore {
+	t.Helper()
+	store := eventstore.NewMemoryStore()
+	appendEvents(t, store, "product-a", ProductAdded{SKU: "a", Price: 10}, StockCounted{SKU: "a", Quantity: 3})
+	appendEvents(t, store, "product-b", ProductAdded{SKU: "b", Price: 20})
+	appendEvents(t, store, "product-a", ProductRemoved{SKU: "a"})
+	appendEvents(t, store, "product-c", ProductAdded{SKU: "c", Price: 30})
+	return store
+}
+
+func TestNewReplayer(t *testing.T) {
+	t.Parallel()
+
+	source := eventstore.NewMemoryStore()
+	handlers := projection.WithHandlers(newCatalog().handlers()...)
+
+	_, err := projection.NewReplayer("", source, handlers)
+	require.ErrorIs(t, err, projection.ErrNameEmpty)
+
+	_, err = projection.NewReplayer("catalog", nil, handlers)
+	require.ErrorIs(t, err, projection.ErrSourceNil)
+
+	_, err = projection.NewReplayer("catalog", source)
+	require.ErrorIs(t, err, projection.ErrNoHandlers)
+
+	replayer, err := projection.NewReplayer("catalog", source, handlers)
+	require.NoError(t, err)
+	assert.Equal(t, "catalog", replayer.Name())
+}
+
+func TestReplayer_Replay(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("projects history and resumes from the checkpoint", func(t *testing.T) {
+		t.Parallel()
+		store := seedCatalog(t)
+		view := newCatalog()
+		checkpoints := projection.NewMemoryCheckpointStore()
+
+		replayer, err := projection.NewReplayer("catalog", store,
+			projection.WithHandlers(view.handlers()...),
+			projection.WithCheckpointStore(checkpoints),
+			projection.WithBatchSize(2))
+		require.NoError(t, err)
+
+		result, err := replayer.Replay(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), result.Events)
+		assert.Equal(t, int64(4), result.Handled)
+		assert.Equal(t, int64(1), result.Skipped, "StockCounted has no handler")
+		assert.Equal(t, int64(5), result.Position)
+		assert.False(t, result.DryRun)
+
+		products, _ := view.snapshot()
+		assert.Equal(t, map[string]int{"b": 20, "c": 30}, products)
+
+		position, err := checkpoints.Load(ctx, "catalog")
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), position)
+
+		// Only events appended after the checkpoint are replayed
+		appendEvents(t, store, "product-d", ProductAdded{SKU: "d", Price: 40})
+		result, err = replayer.Replay(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.Events)
+		assert.Equal(t, int64(6), result.Position)
+
+		products, calls := view.snapshot()
+		assert.Equal(t, map[string]int{"b": 20, "c": 30, "d": 40}, products)
+		assert.Equal(t, 5, calls)
+	})
+
+	t.Run("stops at a failing handler and retries the event", func(t *testing.T) {
+		t.Parallel()
+		store := seedCatalog(t)
+		view := newCatalog()
+
+		failing := true
+		gate := event.NewHandlerFunc(func(ctx context.Context, e ProductRemoved) error {
+			if failing {
+				return errors.New("read model unavailable")
+			}
+			return nil
+		})
+
+		replayer, err := projection.NewReplayer("catalog", store,
+			projection.WithHandlers(gate),
+			projection.WithHandlers(view.handlers()...))
+		require.NoError(t, err)
+
+		result, err := replayer.Replay(ctx)
+		require.ErrorIs(t, err, projection.ErrHandlerFailed)
+		assert.Equal(t, int64(3), result.Position, "checkpoint stays before the failed event")
+
+		position, err := replayer.Position(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), position)
+
+		failing = false
+		result, err = replayer.Replay(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.Events)
+
+		products, calls := view.snapshot()
+		assert.Equal(t, map[string]int{"b": 20, "c": 30}, products)
+		assert.Equal(t, 4, calls, "each event is projected once")
+	})
+
+	t.Run("recovers handler panics", func(t *testing.T) {
+		t.Parallel()
+		store := seedCatalog(t)
+
+		replayer, err := projection.NewReplayer("catalog", store,
+			projection.WithHandlers(event.NewHandlerFunc(func(ctx context.Context, e ProductAdded) error {
+				panic("boom")
+			})))
+		require.NoError(t, err)
+
+		result, err := replayer.Replay(ctx)
+		require.ErrorIs(t, err, projection.ErrHandlerFailed)
+		assert.Equal(t, int64(0), result.Position)
+	})
+
+	t.Run("matches topic patterns and sets event metadata", func(t *testing.T) {
+		t.Parallel()
+		store := eventstore.NewMemoryStore()
+		for _, name := range []string{"catalog.product.added", "billing.invoice.paid", "catalog.product.removed"} {
+			evt := event.NewEvent(ProductAdded{SKU: name})
+			evt.Name = name
+			_, err := store.Append(ctx, "mixed", eventstore.AnyVersion, evt)
+			require.NoError(t, err)
+		}
+
+		var names []string
+		replayer, err := projection.NewReplayer("catalog-topics", store,
+			projection.WithHandlers(event.NewHandler("catalog.>", func(ctx context.Context, e ProductAdded) error {
+				assert.Equal(t, e.SKU, event.EventName(ctx))
+				assert.NotEmpty(t, event.EventID(ctx))
+				names = append(names, e.SKU)
+				return nil
+			})))
+		require.NoError(t, err)
+
+		result, err := replayer.Replay(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), result.Handled)
+		assert.Equal(t, int64(1), result.Skipped)
+		assert.Equal(t, []string{"catalog.product.added", "catalog.product.removed"}, names)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		t.Parallel()
+		store := seedCatalog(t)
+		view := newCatalog()
+		checkpoints := projection.NewMemoryCheckpointStore()
+
+		replayer, err := projection.NewReplayer("catalog", store,
+			projection.WithHandlers(view.handlers()...),
+			projection.WithCheckpointStore(checkpoints),
+			projection.WithBatchSize(2),
+			projection.WithDryRun())
+		require.NoError(t, err)
+
+		result, err := replayer.Replay(ctx)
+		require.NoError(t, err)
+		assert.True(t, result.DryRun)
+		assert.Equal(t, int64(5), result.Events)
+		assert.Equal(t, int64(4), result.Handled)
+		assert.Equal(t, int64(5), result.Position)
+
+		_, calls := view.snapshot()
+		assert.Zero(t, calls, "handlers are not called")
+
+		position, err := checkpoints.Load(ctx, "catalog")
+		require.NoError(t, err)
+		assert.Zero(t, position, "checkpoint is not saved")
+	})
+
+	t.Run("rate limit", func(t *testing.T) {
+		t.Parallel()
+		store := seedCatalog(t)
+
+		replayer, err := projection.NewReplayer("catalog", store,
+			projection.WithHandlers(newCatalog().handlers()...),
+			projection.WithRateLimit(50))
+		require.NoError(t, err)
+
+		start := time.Now()
+		result, err := replayer.Replay(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), result.Handled)
+		assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond, "4 events at 50/s take at least 3 intervals")
+	})
+
+	t.Run("stops on context cancellation and keeps progress", func(t *testing.T) {
+		t.Parallel()
+		store := seedCatalog(t)
+
+		ctx, cancel := context.WithCancel(ctx)
+		replayer, err := projection.NewReplayer("catalog", store,
+			projection.WithHandlers(event.NewHandlerFunc(func(ctx context.Context, e ProductAdded) error {
+				if e.SKU == "b" {
+					cancel()
+				}
+				return nil
+			})))
+		require.NoError(t, err)
+
+		_, err = replayer.Replay(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+
+		position, err := replayer.Position(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), position)
+	})
+}
+
+func TestReplayer_Reset(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := seedCatalog(t)
+	view := newCatalog()
+
+	replayer, err := projection.NewReplayer("catalog", store, projection.WithHandlers(view.handlers()...))
+	require.NoError(t, err)
+
+	_, err = replayer.Replay(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, replayer.Reset(ctx))
+	position, err := replayer.Position(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, position)
+
+	result, err := replayer.Replay(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), result.Events, "the projection is rebuilt from the first event")
+}
+
+// txCheckpoints is a checkpoint store that runs batches in a fake transaction,
+// staging checkpoints until the transaction commits.
+type txCheckpoints struct {
+	*projection.MemoryCheckpointStore
+	mu  sync.Mutex
+	txs int
+}
+
+type txKey struct{}
+
+func (s *txCheckpoints) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	s.mu.Lock()
+	s.txs++
+	s.mu.Unlock()
+	return fn(context.WithValue(ctx, txKey{}, true))
+}
+
+func (s *txCheckpoints) Save(ctx context.Context, name string, position int64) error {
+	if ctx.Value(txKey{}) == nil {
+		return errors.New("checkpoint saved outside the transaction")
+	}
+	return s.MemoryCheckpointStore.Save(ctx, name, position)
+}
+
+func TestReplayer_TransactionalCheckpoints(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := seedCatalog(t)
+	checkpoints := &txCheckpoints{MemoryCheckpointStore: projection.NewMemoryCheckpointStore()}
+
+	replayer, err := projection.NewReplayer("catalog", store,
+		projection.WithCheckpointStore(checkpoints),
+		projection.WithBatchSize(2),
+		projection.WithHandlers(event.NewHandlerFunc(func(ctx context.Context, e ProductAdded) error {
+			assert.NotNil(t, ctx.Value(txKey{}), "handlers run in the checkpoint transaction")
+			return nil
+		})))
+	require.NoError(t, err)
+
+	result, err := replayer.Replay(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), result.Position)
+	assert.Equal(t, 3, checkpoints.txs, "one transaction per batch")
+
+	position, err := replayer.Position(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), position)
+}
+
+// lockingCheckpoints is a transactional checkpoint store that also locks
+// checkpoints until the transaction ends, as the PostgreSQL store does.
+type lockingCheckpoints struct {
+	*txCheckpoints
+	lock sync.Mutex
+}
+
+type lockKey struct{}
+
+func (s *lockingCheckpoints) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	var locked bool
+	defer func() {
+		if locked {
+			s.lock.Unlock()
+		}
+	}()
+	return s.txCheckpoints.RunInTx(context.WithValue(ctx, lockKey{}, &locked), fn)
+}
+
+func (s *lockingCheckpoints) Lock(ctx context.Context, name string) (int64, error) {
+	locked, ok := ctx.Value(lockKey{}).(*bool)
+	if !ok {
+		return 0, errors.New("checkpoint locked outside the transaction")
+	}
+	s.lock.Lock()
+	*locked = true
+	return s.Load(ctx, name)
+}
+
+func TestReplayer_ConcurrentReplicas(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	store := seedCatalog(t)
+	checkpoints := &lockingCheckpoints{
+		txCheckpoints: &txCheckpoints{MemoryCheckpointStore: projection.NewMemoryCheckpointStore()},
+	}
+
+	var mu sync.Mutex
+	projected := make(map[string]int)
+	handler := event.NewHandlerFunc(func(ctx context.Context, e ProductAdded) error {
+		time.Sleep(5 * time.Millisecond) // Widen the window for replicas to overlap
+		mu.Lock()
+		projected[e.SKU]++
+		mu.Unlock()
+		return nil
+	})
+
+	// Each replica has its own replayer, as separate processes would
+	replicas := make([]*projection.Replayer, 2)
+	for i := range replicas {
+		replayer, err := projection.NewReplayer("catalog", store,
+			projection.WithCheckpointStore(checkpoints),
+			projection.WithBatchSize(2),
+			projection.WithHandlers(handler))
+		require.NoError(t, err)
+		replicas[i] = replayer
+	}
+
+	results := make([]projection.ReplayResult, len(replicas))
+	var wg sync.WaitGroup
+	for i, replayer := range replicas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := replayer.Replay(ctx)
+			assert.NoError(t, err)
+			results[i] = result
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, map[string]int{"a": 1, "b": 1, "c": 1}, projected, "each event is projected once")
+	assert.Equal(t, int64(5), results[0].Events+results[1].Events)
+	for _, result := range results {
+		assert.Equal(t, int64(5), result.Position, "every replica ends at the shared checkpoint")
+	}
+
+	position, err := checkpoints.Load(ctx, "catalog")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), position)
+}
+
+func TestSourceFunc(t *testing.T) {
+	t.Parallel()
+
+	var from int64
+	source := projection.SourceFunc(func(ctx context.Context, fromPosition int64, limit int) ([]eventstore.Record, error) {
+		from = fromPosition
+		return nil, nil
+	})
+
+	checkpoints := projection.NewMemoryCheckpointStore()
+	require.NoError(t, checkpoints.Save(context.Background(), "catalog", 41))
+
+	replayer, err := projection.NewReplayer("catalog", source,
+		projection.WithHandlers(newCatalog().handlers()...),
+		projection.WithCheckpointStore(checkpoints))
+	require.NoError(t, err)
+
+	result, err := replayer.Replay(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), from, "reads after the checkpoint")
+	assert.Equal(t, int64(41), result.Position)
+}
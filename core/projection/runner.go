@@ -0,0 +1,237 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RunnerStats provides observability metrics for monitoring and debugging
+type RunnerStats struct {
+	EventsProcessed int64     // Events projected or skipped since the runner was created
+	ReplaysFailed   int64     // Failed catch-up rounds, each retried after the poll interval
+	Position        int64     // Checkpoint after the last catch-up round
+	IsRunning       bool      // Whether the runner is running
+	LastActivityAt  time.Time // Timestamp of the last round that processed events (zero if never)
+	LastError       error     // Error of the last round, nil once a round succeeds
+}
+
+// Runner keeps a projection up to date for the lifetime of the process. It
+// replays the events after the checkpoint, waits for the poll interval once it
+// has caught up, and repeats. A failed round is logged and retried from the
+// checkpoint after the poll interval; Healthcheck reports the projection as
+// failing until a round succeeds.
+type Runner struct {
+	replayer *Replayer
+
+	// Configuration
+	pollInterval    time.Duration
+	shutdownTimeout time.Duration
+	logger          *slog.Logger
+
+	// State management
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+	running atomic.Bool
+
+	// Observability metrics
+	processed      atomic.Int64
+	failed         atomic.Int64
+	position       atomic.Int64
+	lastActivityAt atomic.Int64
+	lastErr        atomic.Pointer[error]
+}
+
+// NewRunner creates a runner of the replayer's projection.
+// Call Start() or Run() to begin projecting.
+func NewRunner(replayer *Replayer, opts ...RunnerOption) (*Runner, error) {
+	if replayer == nil {
+		return nil, ErrReplayerNil
+	}
+	if replayer.dryRun {
+		return nil, ErrDryRunRunner
+	}
+
+	r := &Runner{
+		replayer:        replayer,
+		pollInterval:    time.Second,
+		shutdownTimeout: 30 * time.Second,
+		logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r, nil
+}
+
+// Start projects events until the context is cancelled. This is a blocking
+// operation; use Run() for the errgroup pattern or call this in a goroutine.
+func (r *Runner) Start(ctx context.Context) error {
+	r.mu.Lock()
+	if r.cancel != nil {
+		r.mu.Unlock()
+		return ErrRunnerAlreadyStarted
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	done := r.done
+	r.mu.Unlock()
+
+	defer close(done)
+	defer cancel()
+
+	r.running.Store(true)
+	defer r.running.Store(false)
+
+	r.logger.InfoContext(ctx, "projection runner started",
+		slog.String("projection", r.replayer.name),
+		slog.Duration("poll_interval", r.pollInterval))
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.catchUp(ctx)
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("projection runner stopping",
+				slog.String("projection", r.replayer.name))
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop gracefully shuts down the runner with a timeout.
+// Returns an error if the shutdown timeout is exceeded.
+func (r *Runner) Stop() error {
+	r.mu.Lock()
+	if r.cancel == nil {
+		r.mu.Unlock()
+		return ErrRunnerNotStarted
+	}
+	cancel, done := r.cancel, r.done
+	r.cancel = nil
+	r.mu.Unlock()
+
+	cancel()
+
+	r.logger.Info("projection runner stopping, waiting for the current batch to complete",
+		slog.String("projection", r.replayer.name),
+		slog.Duration("timeout", r.shutdownTimeout))
+
+	select {
+	case <-done:
+		r.logger.Info("projection runner stopped cleanly",
+			slog.String("projection", r.replayer.name))
+		return nil
+	case <-time.After(r.shutdownTimeout):
+		r.logger.Warn("projection runner shutdown timeout exceeded",
+			slog.String("projection", r.replayer.name),
+			slog.Duration("timeout", r.shutdownTimeout))
+		return fmt.Errorf("shutdown timeout exceeded after %s", r.shutdownTimeout)
+	}
+}
+
+// Run provides errgroup compatibility for coordinated lifecycle management.
+// Returns a function that starts the runner, monitors context cancellation,
+// and performs graceful shutdown when the context is cancelled.
+func (r *Runner) Run(ctx context.Context) func() error {
+	return func() error {
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- r.Start(ctx)
+		}()
+
+		select {
+		case <-ctx.Done():
+			// Context cancelled - perform graceful shutdown
+			if err := r.Stop(); err != nil {
+				r.logger.Error("graceful shutdown failed", slog.String("error", err.Error()))
+			}
+			<-errCh // Wait for Start() to exit
+			return nil
+		case err := <-errCh:
+			// Start() returned - check if it's a normal shutdown
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Stats returns current runner statistics for observability and monitoring.
+func (r *Runner) Stats() RunnerStats {
+	lastActivity := r.lastActivityAt.Load()
+	var lastActivityTime time.Time
+	if lastActivity > 0 {
+		lastActivityTime = time.Unix(0, lastActivity)
+	}
+
+	var lastErr error
+	if err := r.lastErr.Load(); err != nil {
+		lastErr = *err
+	}
+
+	return RunnerStats{
+		EventsProcessed: r.processed.Load(),
+		ReplaysFailed:   r.failed.Load(),
+		Position:        r.position.Load(),
+		IsRunning:       r.running.Load(),
+		LastActivityAt:  lastActivityTime,
+		LastError:       lastErr,
+	}
+}
+
+// Healthcheck validates that the runner is operational.
+// Returns nil if healthy, or an error describing the health issue.
+// Checks for:
+// - Runner running status
+// - Failing projection (the last catch-up round failed)
+func (r *Runner) Healthcheck(ctx context.Context) error {
+	stats := r.Stats()
+
+	if !stats.IsRunning {
+		return errors.Join(ErrHealthcheckFailed, ErrRunnerNotRunning)
+	}
+
+	if stats.LastError != nil {
+		return errors.Join(ErrHealthcheckFailed,
+			fmt.Errorf("%w at position %d: %w", ErrProjectionFailing, stats.Position, stats.LastError))
+	}
+
+	return nil
+}
+
+// catchUp replays the events after the checkpoint and records the outcome.
+func (r *Runner) catchUp(ctx context.Context) {
+	result, err := r.replayer.Replay(ctx)
+
+	r.position.Store(result.Position)
+	if result.Events > 0 {
+		r.processed.Add(result.Events)
+		r.lastActivityAt.Store(time.Now().UnixNano())
+	}
+
+	if err != nil {
+		// Shutdown interrupted the round; the checkpoint covers what was projected
+		if ctx.Err() != nil {
+			return
+		}
+		r.failed.Add(1)
+		r.lastErr.Store(&err)
+		return
+	}
+	r.lastErr.Store(nil)
+}
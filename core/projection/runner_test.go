@@ -0,0 +1,114 @@
+package projection_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/event"
+	"github.com/dmitrymomot/foundation/core/eventstore"
+	"github.com/dmitrymomot/foundation/core/projection"
+)
+
+func TestNewRunner(t *testing.T) {
+	t.Parallel()
+
+	_, err := projection.NewRunner(nil)
+	require.ErrorIs(t, err, projection.ErrReplayerNil)
+
+	replayer, err := projection.NewReplayer("catalog", eventstore.NewMemoryStore(),
+		projection.WithHandlers(newCatalog().handlers()...),
+		projection.WithDryRun())
+	require.NoError(t, err)
+
+	_, err = projection.NewRunner(replayer)
+	require.ErrorIs(t, err, projection.ErrDryRunRunner)
+}
+
+func TestRunner_Lifecycle(t *testing.T) {
+	t.Parallel()
+	store := seedCatalog(t)
+	view := newCatalog()
+
+	replayer, err := projection.NewReplayer("catalog", store, projection.WithHandlers(view.handlers()...))
+	require.NoError(t, err)
+	runner, err := projection.NewRunner(replayer, projection.WithPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+
+	require.ErrorIs(t, runner.Stop(), projection.ErrRunnerNotStarted)
+	require.ErrorIs(t, runner.Healthcheck(context.Background()), projection.ErrRunnerNotRunning)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = runner.Start(ctx) }()
+
+	require.Eventually(t, func() bool {
+		return runner.Stats().Position == 5
+	}, time.Second, 5*time.Millisecond, "catches up with history")
+	require.ErrorIs(t, runner.Start(ctx), projection.ErrRunnerAlreadyStarted)
+	require.NoError(t, runner.Healthcheck(ctx))
+
+	// New events are projected on the next poll
+	appendEvents(t, store, "product-d", ProductAdded{SKU: "d", Price: 40})
+	require.Eventually(t, func() bool {
+		products, _ := view.snapshot()
+		return products["d"] == 40
+	}, time.Second, 5*time.Millisecond)
+
+	stats := runner.Stats()
+	assert.True(t, stats.IsRunning)
+	assert.Equal(t, int64(6), stats.EventsProcessed)
+	assert.Equal(t, int64(6), stats.Position)
+	assert.False(t, stats.LastActivityAt.IsZero())
+	assert.NoError(t, stats.LastError)
+
+	require.NoError(t, runner.Stop())
+	assert.False(t, runner.Stats().IsRunning)
+}
+
+func TestRunner_FailingProjection(t *testing.T) {
+	t.Parallel()
+	store := seedCatalog(t)
+
+	var failing atomic.Bool
+	failing.Store(true)
+	replayer, err := projection.NewReplayer("catalog", store,
+		projection.WithHandlers(event.NewHandlerFunc(func(ctx context.Context, e ProductRemoved) error {
+			if failing.Load() {
+				return errors.New("read model unavailable")
+			}
+			return nil
+		})))
+	require.NoError(t, err)
+	runner, err := projection.NewRunner(replayer, projection.WithPollInterval(10*time.Millisecond))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- runner.Run(ctx)() }()
+
+	require.Eventually(t, func() bool {
+		return runner.Stats().ReplaysFailed >= 2
+	}, time.Second, 5*time.Millisecond, "failed rounds are retried")
+
+	err = runner.Healthcheck(ctx)
+	require.ErrorIs(t, err, projection.ErrHealthcheckFailed)
+	require.ErrorIs(t, err, projection.ErrProjectionFailing)
+	require.ErrorIs(t, err, projection.ErrHandlerFailed)
+	assert.Equal(t, int64(3), runner.Stats().Position)
+
+	failing.Store(false)
+	require.Eventually(t, func() bool {
+		return runner.Stats().Position == 5
+	}, time.Second, 5*time.Millisecond, "recovers once the handler succeeds")
+	require.NoError(t, runner.Healthcheck(ctx))
+
+	cancel()
+	require.NoError(t, <-errCh)
+	assert.False(t, runner.Stats().IsRunning)
+}
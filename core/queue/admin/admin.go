@@ -0,0 +1,205 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/dmitrymomot/foundation/core/handler"
+	"github.com/dmitrymomot/foundation/core/queue"
+	"github.com/dmitrymomot/foundation/core/response"
+	"github.com/dmitrymomot/foundation/core/router"
+)
+
+const (
+	// DefaultBasePath is the path the admin is expected to be mounted at.
+	DefaultBasePath = "/admin/queue"
+
+	// overviewLimit caps the failures and dead letters shown on the overview.
+	overviewLimit = 10
+)
+
+type admin struct {
+	stats     queue.StatsRepository
+	dlq       *queue.DeadLetterQueue
+	scheduler *queue.Scheduler
+	workers   []*queue.Worker
+	basePath  string
+	pageSize  int
+}
+
+// New creates the admin sub-app. Mount it on the application router, behind
+// authentication:
+//
+//	r.Mount("/admin/queue", admin.New[*AppContext](admin.WithStats(storage)))
+//
+// Sections whose source is not configured are hidden from the pages and answer
+// 501 Not Implemented on the JSON API.
+func New[C handler.Context](opts ...Option) router.Router[C] {
+	a := &admin{
+		basePath: DefaultBasePath,
+		pageSize: 50,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	r := router.New[C]()
+
+	// HTML pages
+	r.Get("/", handle[C](a.overviewPage))
+	r.Get("/dlq", handle[C](a.dlqPage))
+	r.Get("/dlq/{id}", handle[C](a.dlqEntryPage))
+	r.Post("/dlq/{id}/requeue", handle[C](a.requeuePage))
+	r.Post("/dlq/{id}/delete", handle[C](a.deletePage))
+
+	// JSON API
+	r.Get("/api/overview", handle[C](a.getOverview))
+	r.Get("/api/stats", handle[C](a.getStats))
+	r.Get("/api/failures", handle[C](a.getFailures))
+	r.Get("/api/dlq", handle[C](a.listDLQ))
+	r.Get("/api/dlq/{id}", handle[C](a.getDLQEntry))
+	r.Post("/api/dlq/{id}/requeue", handle[C](a.requeueDLQEntry))
+	r.Delete("/api/dlq/{id}", handle[C](a.deleteDLQEntry))
+	r.Get("/api/scheduler", handle[C](a.getScheduler))
+	r.Get("/api/workers", handle[C](a.getWorkers))
+
+	return r
+}
+
+// handle adapts a handler to the application's context type.
+func handle[C handler.Context](fn func(ctx handler.Context) handler.Response) handler.HandlerFunc[C] {
+	return func(ctx C) handler.Response {
+		return fn(ctx)
+	}
+}
+
+var (
+	errStatsDisabled     = response.ErrNotImplemented.WithMessage("Task statistics are not configured")
+	errDLQDisabled       = response.ErrNotImplemented.WithMessage("Dead letter queue is not configured")
+	errSchedulerDisabled = response.ErrNotImplemented.WithMessage("Scheduler is not configured")
+)
+
+func (a *admin) overview(ctx context.Context) (*Overview, error) {
+	o := &Overview{}
+
+	if a.stats != nil {
+		stats, err := a.loadStats(ctx)
+		if err != nil {
+			return nil, err
+		}
+		o.Stats = stats
+
+		if o.Failures, err = a.loadFailures(ctx, overviewLimit); err != nil {
+			return nil, err
+		}
+	}
+
+	if a.dlq != nil {
+		entries, err := a.loadDLQ(ctx, queue.DLQFilter{Limit: overviewLimit})
+		if err != nil {
+			return nil, err
+		}
+		o.DeadLetters = entries
+	}
+
+	if a.scheduler != nil {
+		o.Scheduler = newSchedulerState(a.scheduler)
+	}
+	o.Workers = a.workerStates()
+
+	return o, nil
+}
+
+func (a *admin) loadStats(ctx context.Context) (*Stats, error) {
+	counts, err := a.stats.CountTasks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newStats(counts), nil
+}
+
+func (a *admin) loadFailures(ctx context.Context, limit int) ([]FailedTask, error) {
+	tasks, err := a.stats.ListRetryingTasks(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	failures := make([]FailedTask, 0, len(tasks))
+	for _, task := range tasks {
+		failures = append(failures, newFailedTask(task))
+	}
+	return failures, nil
+}
+
+func (a *admin) loadDLQ(ctx context.Context, filter queue.DLQFilter) ([]DeadLetter, error) {
+	entries, err := a.dlq.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	dls := make([]DeadLetter, 0, len(entries))
+	for _, entry := range entries {
+		dls = append(dls, newDeadLetter(entry))
+	}
+	return dls, nil
+}
+
+func (a *admin) loadDLQEntry(ctx handler.Context) (*DeadLetter, error) {
+	id, err := dlqID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := a.dlq.Get(ctx, id)
+	if err != nil {
+		return nil, dlqError(err)
+	}
+	dl := newDeadLetter(entry)
+	return &dl, nil
+}
+
+func (a *admin) workerStates() []WorkerState {
+	if len(a.workers) == 0 {
+		return nil
+	}
+	states := make([]WorkerState, 0, len(a.workers))
+	for _, w := range a.workers {
+		states = append(states, newWorkerState(w))
+	}
+	return states
+}
+
+// dlqFilter reads the queue, task_name, limit and offset query parameters.
+// Limit defaults to, and is capped at, the page size.
+func (a *admin) dlqFilter(query url.Values) queue.DLQFilter {
+	filter := queue.DLQFilter{
+		Queue:    query.Get("queue"),
+		TaskName: query.Get("task_name"),
+		Limit:    a.pageSize,
+	}
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit > 0 && limit < a.pageSize {
+		filter.Limit = limit
+	}
+	if offset, err := strconv.Atoi(query.Get("offset")); err == nil && offset > 0 {
+		filter.Offset = offset
+	}
+	return filter
+}
+
+// dlqID parses the entry ID path parameter.
+func dlqID(ctx handler.Context) (uuid.UUID, error) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		return uuid.Nil, response.ErrBadRequest.WithMessage("Invalid dead letter queue entry ID")
+	}
+	return id, nil
+}
+
+// dlqError maps a missing entry to 404 Not Found.
+func dlqError(err error) error {
+	if errors.Is(err, queue.ErrDLQEntryNotFound) {
+		return response.ErrNotFound.WithMessage("Dead letter queue entry not found").WithError(err)
+	}
+	return err
+}
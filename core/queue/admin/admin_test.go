@@ -0,0 +1,233 @@
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+	"github.com/dmitrymomot/foundation/core/queue/admin"
+	"github.com/dmitrymomot/foundation/core/router"
+)
+
+type invoicePayload struct {
+	Amount int `json:"amount"`
+}
+
+// newAdmin mounts the admin on a router over a storage holding one pending task,
+// one task awaiting a retry and one dead letter.
+func newAdmin(t *testing.T, opts ...admin.Option) (router.Router[*router.Context], *queue.MemoryStorage, uuid.UUID) {
+	t.Helper()
+	ctx := context.Background()
+
+	storage := queue.NewMemoryStorage()
+	enqueuer, err := queue.NewEnqueuer(storage)
+	require.NoError(t, err)
+
+	for _, amount := range []int{1, 2, 3} {
+		_, err := enqueuer.Enqueue(ctx, invoicePayload{Amount: amount}, queue.WithQueue("billing"))
+		require.NoError(t, err)
+	}
+	for _, dead := range []bool{true, false} {
+		task, err := storage.ClaimTask(ctx, uuid.New(), []string{"billing"}, time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, storage.FailTask(ctx, task.ID, "card declined", time.Hour))
+		if dead {
+			require.NoError(t, storage.MoveToDLQ(ctx, task.ID))
+		}
+	}
+
+	dlq, err := queue.NewDeadLetterQueue(storage)
+	require.NoError(t, err)
+	entries, err := dlq.List(ctx, queue.DLQFilter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	r := router.New[*router.Context]()
+	r.Mount(admin.DefaultBasePath, admin.New[*router.Context](append([]admin.Option{
+		admin.WithStats(storage),
+		admin.WithDeadLetterQueue(dlq),
+	}, opts...)...))
+
+	return r, storage, entries[0].ID
+}
+
+func serve(r http.Handler, method, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(method, admin.DefaultBasePath+target, nil))
+	return rec
+}
+
+func TestAdmin_API(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stats", func(t *testing.T) {
+		t.Parallel()
+
+		r, _, _ := newAdmin(t)
+		rec := serve(r, http.MethodGet, "/api/stats")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var stats admin.Stats
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+		require.Len(t, stats.Queues, 1)
+		assert.Equal(t, "billing", stats.Queues[0].Queue)
+		assert.Equal(t, int64(2), stats.Queues[0].Statuses[queue.TaskStatusPending])
+		assert.Equal(t, int64(2), stats.Total, "dead letters are not tasks")
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		t.Parallel()
+
+		r, _, _ := newAdmin(t)
+		rec := serve(r, http.MethodGet, "/api/failures")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var failures []admin.FailedTask
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &failures))
+		require.Len(t, failures, 1)
+		assert.Equal(t, "card declined", failures[0].Error)
+		assert.True(t, failures[0].NextAttemptAt.After(time.Now()))
+	})
+
+	t.Run("dead letters", func(t *testing.T) {
+		t.Parallel()
+
+		r, _, id := newAdmin(t)
+
+		rec := serve(r, http.MethodGet, "/api/dlq?queue=billing")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var entries []admin.DeadLetter
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &entries))
+		require.Len(t, entries, 1)
+		assert.JSONEq(t, `{"amount":1}`, string(entries[0].Payload))
+
+		rec = serve(r, http.MethodGet, "/api/dlq?queue=emails")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `[]`, rec.Body.String())
+
+		rec = serve(r, http.MethodGet, "/api/dlq/"+id.String())
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		assert.Equal(t, http.StatusBadRequest, serve(r, http.MethodGet, "/api/dlq/not-a-uuid").Code)
+		assert.Equal(t, http.StatusNotFound, serve(r, http.MethodGet, "/api/dlq/"+uuid.NewString()).Code)
+	})
+
+	t.Run("requeue", func(t *testing.T) {
+		t.Parallel()
+
+		r, storage, id := newAdmin(t)
+		entry, err := storage.GetDLQ(context.Background(), id)
+		require.NoError(t, err)
+
+		rec := serve(r, http.MethodPost, "/api/dlq/"+id.String()+"/requeue")
+		require.Equal(t, http.StatusCreated, rec.Code)
+		var body struct {
+			TaskID uuid.UUID `json:"task_id"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+
+		assert.Equal(t, entry.TaskID, body.TaskID)
+
+		counts, err := storage.CountTasks(context.Background())
+		require.NoError(t, err)
+		assert.Contains(t, counts, queue.TaskCount{Queue: "billing", Status: queue.TaskStatusPending, Count: 3})
+
+		assert.Equal(t, http.StatusNotFound, serve(r, http.MethodPost, "/api/dlq/"+id.String()+"/requeue").Code)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		t.Parallel()
+
+		r, _, id := newAdmin(t)
+
+		assert.Equal(t, http.StatusNoContent, serve(r, http.MethodDelete, "/api/dlq/"+id.String()).Code)
+		assert.Equal(t, http.StatusNotFound, serve(r, http.MethodDelete, "/api/dlq/"+id.String()).Code)
+	})
+
+	t.Run("scheduler and workers", func(t *testing.T) {
+		t.Parallel()
+
+		storage := queue.NewMemoryStorage()
+		scheduler, err := queue.NewScheduler(storage)
+		require.NoError(t, err)
+		require.NoError(t, scheduler.AddTask("reports.daily", queue.DailyAt(6, 0)))
+		require.NoError(t, scheduler.AddTask("cleanup", queue.Hourly()))
+		worker, err := queue.NewWorker(storage, queue.WithQueues("billing", "emails"))
+		require.NoError(t, err)
+
+		r, _, _ := newAdmin(t, admin.WithScheduler(scheduler), admin.WithWorkers(worker))
+
+		rec := serve(r, http.MethodGet, "/api/scheduler")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var state admin.SchedulerState
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &state))
+		assert.Equal(t, []string{"cleanup", "reports.daily"}, state.Tasks)
+		assert.False(t, state.Running)
+
+		rec = serve(r, http.MethodGet, "/api/workers")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var workers []admin.WorkerState
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &workers))
+		require.Len(t, workers, 1)
+		assert.Equal(t, []string{"billing", "emails"}, workers[0].Queues)
+	})
+
+	t.Run("sections not configured", func(t *testing.T) {
+		t.Parallel()
+
+		r := router.New[*router.Context]()
+		r.Mount(admin.DefaultBasePath, admin.New[*router.Context]())
+
+		for _, target := range []string{"/api/stats", "/api/failures", "/api/dlq", "/api/scheduler"} {
+			assert.Equal(t, http.StatusNotImplemented, serve(r, http.MethodGet, target).Code, target)
+		}
+
+		rec := serve(r, http.MethodGet, "/api/overview")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{}`, rec.Body.String())
+	})
+}
+
+func TestAdmin_Pages(t *testing.T) {
+	t.Parallel()
+
+	t.Run("overview", func(t *testing.T) {
+		t.Parallel()
+
+		r, _, id := newAdmin(t)
+		rec := serve(r, http.MethodGet, "/")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		body := rec.Body.String()
+		assert.Contains(t, body, "billing")
+		assert.Contains(t, body, "card declined")
+		assert.Contains(t, body, admin.DefaultBasePath+"/dlq/"+id.String()+"/requeue")
+		assert.NotContains(t, body, "Scheduler")
+	})
+
+	t.Run("dead letter queue", func(t *testing.T) {
+		t.Parallel()
+
+		r, _, id := newAdmin(t)
+
+		rec := serve(r, http.MethodGet, "/dlq/"+id.String())
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "&#34;amount&#34;: 1")
+
+		rec = serve(r, http.MethodPost, "/dlq/"+id.String()+"/delete")
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+		assert.Equal(t, admin.DefaultBasePath+"/dlq", rec.Header().Get("Location"))
+
+		rec = serve(r, http.MethodGet, "/dlq")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), "The dead letter queue is empty.")
+	})
+}
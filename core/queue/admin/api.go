@@ -0,0 +1,104 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/dmitrymomot/foundation/core/handler"
+	"github.com/dmitrymomot/foundation/core/response"
+)
+
+func (a *admin) getOverview(ctx handler.Context) handler.Response {
+	o, err := a.overview(ctx)
+	if err != nil {
+		return response.Error(err)
+	}
+	return response.JSON(o)
+}
+
+func (a *admin) getStats(ctx handler.Context) handler.Response {
+	if a.stats == nil {
+		return response.Error(errStatsDisabled)
+	}
+	stats, err := a.loadStats(ctx)
+	if err != nil {
+		return response.Error(err)
+	}
+	return response.JSON(stats)
+}
+
+func (a *admin) getFailures(ctx handler.Context) handler.Response {
+	if a.stats == nil {
+		return response.Error(errStatsDisabled)
+	}
+	failures, err := a.loadFailures(ctx, a.pageSize)
+	if err != nil {
+		return response.Error(err)
+	}
+	return response.JSON(failures)
+}
+
+func (a *admin) listDLQ(ctx handler.Context) handler.Response {
+	if a.dlq == nil {
+		return response.Error(errDLQDisabled)
+	}
+	entries, err := a.loadDLQ(ctx, a.dlqFilter(ctx.Request().URL.Query()))
+	if err != nil {
+		return response.Error(err)
+	}
+	return response.JSON(entries)
+}
+
+func (a *admin) getDLQEntry(ctx handler.Context) handler.Response {
+	if a.dlq == nil {
+		return response.Error(errDLQDisabled)
+	}
+	entry, err := a.loadDLQEntry(ctx)
+	if err != nil {
+		return response.Error(err)
+	}
+	return response.JSON(entry)
+}
+
+func (a *admin) requeueDLQEntry(ctx handler.Context) handler.Response {
+	if a.dlq == nil {
+		return response.Error(errDLQDisabled)
+	}
+	id, err := dlqID(ctx)
+	if err != nil {
+		return response.Error(err)
+	}
+	taskID, err := a.dlq.Requeue(ctx, id)
+	if err != nil {
+		return response.Error(dlqError(err))
+	}
+	return response.JSONWithStatus(map[string]any{"task_id": taskID}, http.StatusCreated)
+}
+
+func (a *admin) deleteDLQEntry(ctx handler.Context) handler.Response {
+	if a.dlq == nil {
+		return response.Error(errDLQDisabled)
+	}
+	id, err := dlqID(ctx)
+	if err != nil {
+		return response.Error(err)
+	}
+	if err := a.dlq.Delete(ctx, id); err != nil {
+		return response.Error(dlqError(err))
+	}
+	return response.NoContent()
+}
+
+func (a *admin) getScheduler(ctx handler.Context) handler.Response {
+	if a.scheduler == nil {
+		return response.Error(errSchedulerDisabled)
+	}
+	return response.JSON(newSchedulerState(a.scheduler))
+}
+
+func (a *admin) getWorkers(ctx handler.Context) handler.Response {
+	workers := a.workerStates()
+	if workers == nil {
+		workers = []WorkerState{}
+	}
+	return response.JSON(workers)
+}
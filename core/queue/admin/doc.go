@@ -0,0 +1,62 @@
+// Package admin provides a mountable router sub-app that shows the state of the
+// task queue as server-rendered pages and as a JSON API.
+//
+// It shows task counts by queue and status, failing tasks awaiting a retry, the
+// dead letter queue with requeue and delete actions, the periodic tasks of the
+// scheduler and the live statistics of workers. Each section is enabled by the
+// option that provides its source; the rest are hidden.
+//
+// # Usage
+//
+//	storage := queue.NewMemoryStorage() // or pgstorage / redisstorage
+//	dlq, _ := queue.NewDeadLetterQueue(storage)
+//
+//	r := router.New[*router.Context]()
+//	r.Route("/admin", func(r router.Router[*router.Context]) {
+//		r.Use(requireAdmin) // the admin has no authentication of its own
+//		r.Mount("/queue", admin.New[*router.Context](
+//			admin.WithStats(storage),
+//			admin.WithDeadLetterQueue(dlq),
+//			admin.WithScheduler(scheduler),
+//			admin.WithWorkers(worker),
+//			admin.WithBasePath("/admin/queue"),
+//		))
+//	})
+//
+// The base path must match the mount point: the pages build their links and form
+// actions from it. The sub-app inherits the error handler, logger and context
+// factory of the router it is mounted on.
+//
+// # Security
+//
+// The admin exposes task payloads and errors and can requeue or delete dead
+// letters. Mount it behind authentication, and behind CSRF protection when the
+// pages are used from a browser: the requeue and delete actions are plain POST forms.
+//
+// # Pages
+//
+//	GET  /                     Overview of all configured sections
+//	GET  /dlq                  Dead letter queue, filtered by ?queue= and ?task_name=, paged by ?page=
+//	GET  /dlq/{id}             Dead letter entry with its payload
+//	POST /dlq/{id}/requeue     Requeue the entry and redirect to the dead letter queue
+//	POST /dlq/{id}/delete      Delete the entry and redirect to the dead letter queue
+//
+// # JSON API
+//
+//	GET    /api/overview       Everything the overview page shows (Overview)
+//	GET    /api/stats          Task counts by queue and status (Stats)
+//	GET    /api/failures       Failing tasks awaiting a retry, soonest first ([]FailedTask)
+//	GET    /api/dlq            Dead letters, filtered by ?queue=, ?task_name=, ?limit= and ?offset= ([]DeadLetter)
+//	GET    /api/dlq/{id}       A single dead letter (DeadLetter)
+//	POST   /api/dlq/{id}/requeue  Requeue the entry; 201 with {"task_id": "..."}
+//	DELETE /api/dlq/{id}       Delete the entry; 204
+//	GET    /api/scheduler      Scheduler state and periodic task names (SchedulerState)
+//	GET    /api/workers        Worker statistics ([]WorkerState)
+//
+// Endpoints of a section that is not configured answer 501 Not Implemented. A
+// malformed entry ID answers 400 and a missing entry 404.
+//
+// Worker and scheduler statistics are read from the instances passed to the
+// options, so they only cover the serving process. Counts and dead letters come
+// from storage and cover every process sharing it.
+package admin
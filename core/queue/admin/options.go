@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"strings"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+// Option configures the admin sub-app.
+type Option func(*admin)
+
+// WithStats shows task counts by queue and status, and tasks waiting for a retry.
+// Every storage of this module implements queue.StatsRepository.
+func WithStats(repo queue.StatsRepository) Option {
+	return func(a *admin) {
+		if repo != nil {
+			a.stats = repo
+		}
+	}
+}
+
+// WithDeadLetterQueue shows the dead letter queue and enables its requeue and delete actions.
+func WithDeadLetterQueue(dlq *queue.DeadLetterQueue) Option {
+	return func(a *admin) {
+		if dlq != nil {
+			a.dlq = dlq
+		}
+	}
+}
+
+// WithScheduler shows the periodic tasks and state of the scheduler.
+func WithScheduler(scheduler *queue.Scheduler) Option {
+	return func(a *admin) {
+		if scheduler != nil {
+			a.scheduler = scheduler
+		}
+	}
+}
+
+// WithWorkers shows the live statistics of the given workers. Only workers of the
+// serving process are visible; mount the admin in every process to see all of them.
+func WithWorkers(workers ...*queue.Worker) Option {
+	return func(a *admin) {
+		for _, w := range workers {
+			if w != nil {
+				a.workers = append(a.workers, w)
+			}
+		}
+	}
+}
+
+// WithBasePath sets the path the admin is mounted at, used for links and form
+// actions in the HTML pages. Defaults to DefaultBasePath.
+func WithBasePath(path string) Option {
+	return func(a *admin) {
+		if path = strings.TrimRight(path, "/"); path != "" {
+			a.basePath = path
+		}
+	}
+}
+
+// WithPageSize sets how many entries the dead letter queue and failure lists show.
+// Defaults to 50; non-positive values are ignored.
+func WithPageSize(n int) Option {
+	return func(a *admin) {
+		if n > 0 {
+			a.pageSize = n
+		}
+	}
+}
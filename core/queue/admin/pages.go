@@ -0,0 +1,125 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dmitrymomot/foundation/core/handler"
+	"github.com/dmitrymomot/foundation/core/queue"
+	"github.com/dmitrymomot/foundation/core/response"
+)
+
+func (a *admin) overviewPage(ctx handler.Context) handler.Response {
+	o, err := a.overview(ctx)
+	if err != nil {
+		return response.Error(err)
+	}
+	return response.Templ(overviewPage(a.basePath, o))
+}
+
+func (a *admin) dlqPage(ctx handler.Context) handler.Response {
+	if a.dlq == nil {
+		return response.Error(errDLQDisabled)
+	}
+
+	query := ctx.Request().URL.Query()
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	filter := queue.DLQFilter{
+		Queue:    query.Get("queue"),
+		TaskName: query.Get("task_name"),
+		Limit:    a.pageSize + 1, // one extra entry tells whether there is a next page
+		Offset:   (page - 1) * a.pageSize,
+	}
+
+	entries, err := a.loadDLQ(ctx, filter)
+	if err != nil {
+		return response.Error(err)
+	}
+	hasNext := len(entries) > a.pageSize
+	if hasNext {
+		entries = entries[:a.pageSize]
+	}
+
+	return response.Templ(dlqPage(a.basePath, filter, entries, page, hasNext))
+}
+
+func (a *admin) dlqEntryPage(ctx handler.Context) handler.Response {
+	if a.dlq == nil {
+		return response.Error(errDLQDisabled)
+	}
+	entry, err := a.loadDLQEntry(ctx)
+	if err != nil {
+		return response.Error(err)
+	}
+	return response.Templ(dlqEntryPage(a.basePath, entry))
+}
+
+func (a *admin) requeuePage(ctx handler.Context) handler.Response {
+	if a.dlq == nil {
+		return response.Error(errDLQDisabled)
+	}
+	id, err := dlqID(ctx)
+	if err != nil {
+		return response.Error(err)
+	}
+	if _, err := a.dlq.Requeue(ctx, id); err != nil {
+		return response.Error(dlqError(err))
+	}
+	return response.RedirectSeeOther(a.basePath + "/dlq")
+}
+
+func (a *admin) deletePage(ctx handler.Context) handler.Response {
+	if a.dlq == nil {
+		return response.Error(errDLQDisabled)
+	}
+	id, err := dlqID(ctx)
+	if err != nil {
+		return response.Error(err)
+	}
+	if err := a.dlq.Delete(ctx, id); err != nil {
+		return response.Error(dlqError(err))
+	}
+	return response.RedirectSeeOther(a.basePath + "/dlq")
+}
+
+func dlqPageURL(basePath string, filter queue.DLQFilter, page int) string {
+	query := url.Values{}
+	if filter.Queue != "" {
+		query.Set("queue", filter.Queue)
+	}
+	if filter.TaskName != "" {
+		query.Set("task_name", filter.TaskName)
+	}
+	query.Set("page", strconv.Itoa(page))
+	return basePath + "/dlq?" + query.Encode()
+}
+
+func formatInt(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.UTC().Format(time.DateTime + " MST")
+}
+
+func formatPayload(payload json.RawMessage) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, payload, "", "  "); err != nil {
+		return string(payload)
+	}
+	return buf.String()
+}
+
+func joinQueues(queues []string) string {
+	return strings.Join(queues, ", ")
+}
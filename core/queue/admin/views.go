@@ -0,0 +1,186 @@
+package admin
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+// Statuses lists task statuses in the order the dashboard shows them.
+var Statuses = []queue.TaskStatus{
+	queue.TaskStatusPending,
+	queue.TaskStatusWaiting,
+	queue.TaskStatusProcessing,
+	queue.TaskStatusCompleted,
+	queue.TaskStatusFailed,
+	queue.TaskStatusCancelled,
+}
+
+// Overview is everything the dashboard shows. Sections whose source is not
+// configured are nil.
+type Overview struct {
+	Stats       *Stats          `json:"stats,omitempty"`
+	Failures    []FailedTask    `json:"failures,omitempty"`
+	DeadLetters []DeadLetter    `json:"dead_letters,omitempty"`
+	Scheduler   *SchedulerState `json:"scheduler,omitempty"`
+	Workers     []WorkerState   `json:"workers,omitempty"`
+}
+
+// Stats holds task counts by queue and status.
+type Stats struct {
+	Queues []QueueStats               `json:"queues"`
+	Totals map[queue.TaskStatus]int64 `json:"totals"`
+	Total  int64                      `json:"total"`
+}
+
+// QueueStats holds the task counts of one queue.
+type QueueStats struct {
+	Queue    string                     `json:"queue"`
+	Statuses map[queue.TaskStatus]int64 `json:"statuses"`
+	Total    int64                      `json:"total"`
+}
+
+// FailedTask is a task waiting for a retry after a failed attempt.
+type FailedTask struct {
+	ID            uuid.UUID `json:"id"`
+	Queue         string    `json:"queue"`
+	TaskName      string    `json:"task_name"`
+	Error         string    `json:"error"`
+	RetryCount    int8      `json:"retry_count"`
+	MaxRetries    int8      `json:"max_retries"`
+	NextAttemptAt time.Time `json:"next_attempt_at"`
+}
+
+// DeadLetter is a dead letter queue entry.
+type DeadLetter struct {
+	ID         uuid.UUID       `json:"id"`
+	TaskID     uuid.UUID       `json:"task_id"`
+	Queue      string          `json:"queue"`
+	TaskName   string          `json:"task_name"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	Priority   queue.Priority  `json:"priority"`
+	Error      string          `json:"error"`
+	RetryCount int8            `json:"retry_count"`
+	FailedAt   time.Time       `json:"failed_at"`
+}
+
+// SchedulerState describes the scheduler and its periodic tasks.
+type SchedulerState struct {
+	Tasks          []string  `json:"tasks"`
+	Running        bool      `json:"running"`
+	Leader         bool      `json:"leader"`
+	LeaderSince    time.Time `json:"leader_since,omitzero"`
+	TasksScheduled int64     `json:"tasks_scheduled"`
+	LastActivityAt time.Time `json:"last_activity_at,omitzero"`
+}
+
+// WorkerState describes a worker and its live statistics.
+type WorkerState struct {
+	ID             string    `json:"id"`
+	Hostname       string    `json:"hostname"`
+	PID            int       `json:"pid"`
+	Queues         []string  `json:"queues"`
+	Running        bool      `json:"running"`
+	ActiveTasks    int32     `json:"active_tasks"`
+	TasksProcessed int64     `json:"tasks_processed"`
+	TasksFailed    int64     `json:"tasks_failed"`
+	TasksCancelled int64     `json:"tasks_cancelled"`
+	TasksTimedOut  int64     `json:"tasks_timed_out"`
+	RateLimited    int64     `json:"rate_limited"`
+	LastActivityAt time.Time `json:"last_activity_at,omitzero"`
+}
+
+func newStats(counts []queue.TaskCount) *Stats {
+	stats := &Stats{Totals: make(map[queue.TaskStatus]int64)}
+	for _, c := range counts {
+		i := slices.IndexFunc(stats.Queues, func(q QueueStats) bool { return q.Queue == c.Queue })
+		if i < 0 {
+			stats.Queues = append(stats.Queues, QueueStats{
+				Queue:    c.Queue,
+				Statuses: make(map[queue.TaskStatus]int64),
+			})
+			i = len(stats.Queues) - 1
+		}
+		stats.Queues[i].Statuses[c.Status] += c.Count
+		stats.Queues[i].Total += c.Count
+		stats.Totals[c.Status] += c.Count
+		stats.Total += c.Count
+	}
+	return stats
+}
+
+func newFailedTask(task *queue.Task) FailedTask {
+	failed := FailedTask{
+		ID:            task.ID,
+		Queue:         task.Queue,
+		TaskName:      task.TaskName,
+		RetryCount:    task.RetryCount,
+		MaxRetries:    task.MaxRetries,
+		NextAttemptAt: task.ScheduledAt,
+	}
+	if task.Error != nil {
+		failed.Error = *task.Error
+	}
+	return failed
+}
+
+func newDeadLetter(entry *queue.TasksDlq) DeadLetter {
+	dl := DeadLetter{
+		ID:         entry.ID,
+		TaskID:     entry.TaskID,
+		Queue:      entry.Queue,
+		TaskName:   entry.TaskName,
+		Priority:   entry.Priority,
+		Error:      entry.Error,
+		RetryCount: entry.RetryCount,
+		FailedAt:   entry.FailedAt,
+	}
+	switch {
+	case len(entry.Payload) == 0:
+	case json.Valid(entry.Payload):
+		dl.Payload = entry.Payload
+	default:
+		// Payloads are JSON unless a storage was written to directly
+		dl.Payload, _ = json.Marshal(string(entry.Payload))
+	}
+	return dl
+}
+
+func newSchedulerState(scheduler *queue.Scheduler) *SchedulerState {
+	stats := scheduler.Stats()
+	tasks := scheduler.ListTasks()
+	slices.Sort(tasks)
+
+	return &SchedulerState{
+		Tasks:          tasks,
+		Running:        stats.IsRunning,
+		Leader:         stats.IsLeader,
+		LeaderSince:    stats.LeaderSince,
+		TasksScheduled: stats.TasksScheduled,
+		LastActivityAt: stats.LastActivityAt,
+	}
+}
+
+func newWorkerState(worker *queue.Worker) WorkerState {
+	id, hostname, pid := worker.WorkerInfo()
+	stats := worker.Stats()
+
+	return WorkerState{
+		ID:             id,
+		Hostname:       hostname,
+		PID:            pid,
+		Queues:         worker.Queues(),
+		Running:        stats.IsRunning,
+		ActiveTasks:    stats.ActiveTasks,
+		TasksProcessed: stats.TasksProcessed,
+		TasksFailed:    stats.TasksFailed,
+		TasksCancelled: stats.TasksCancelled,
+		TasksTimedOut:  stats.TasksTimedOut,
+		RateLimited:    stats.RateLimited,
+		LastActivityAt: stats.LastActivityAt,
+	}
+}
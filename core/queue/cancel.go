@@ -0,0 +1,43 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// CancelRepository is implemented by storages that support task cancellation.
+//
+// A cancelled task ends in TaskStatusCancelled: it is neither retried nor moved to
+// the dead letter queue, its unique key is released right away, and the waiting rest
+// of its workflow, including error callbacks, is dropped.
+type CancelRepository interface {
+	// CancelTask cancels a pending or waiting task right away. A processing task is
+	// flagged with CancelRequested and finished by its worker with MarkCancelled.
+	// Returns ErrTaskNotFound or ErrTaskNotCancellable for finished tasks.
+	CancelTask(ctx context.Context, taskID uuid.UUID) error
+
+	// IsCancelRequested reports whether the task was cancelled or flagged for cancellation.
+	IsCancelRequested(ctx context.Context, taskID uuid.UUID) (bool, error)
+
+	// MarkCancelled finishes a processing task as cancelled once its handler has stopped.
+	MarkCancelled(ctx context.Context, taskID uuid.UUID) error
+}
+
+// Cancel cancels a task that has not finished yet. A pending task is never run;
+// a running task has its handler context cancelled with ErrTaskCancelled as the
+// cause when the worker sees the request on its next check.
+// The repository must implement CancelRepository.
+func (e *Enqueuer) Cancel(ctx context.Context, taskID uuid.UUID) error {
+	repo, ok := e.repo.(CancelRepository)
+	if !ok {
+		return ErrCancelNotSupported
+	}
+
+	if err := repo.CancelTask(ctx, taskID); err != nil {
+		return fmt.Errorf("failed to cancel task %s: %w", taskID, err)
+	}
+
+	return nil
+}
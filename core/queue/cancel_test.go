@@ -0,0 +1,290 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+type (
+	exportPayload struct {
+		Report string `json:"report"`
+	}
+	slowPayload struct{}
+)
+
+func TestEnqueuer_Cancel(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	queues := []string{queue.DefaultQueueName}
+
+	newEnqueuer := func(t *testing.T) (*queue.Enqueuer, *queue.MemoryStorage) {
+		t.Helper()
+		storage := queue.NewMemoryStorage()
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+		return enqueuer, storage
+	}
+
+	t.Run("pending task is never claimed", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, storage := newEnqueuer(t)
+		taskID := uuid.New()
+		_, err := enqueuer.Enqueue(ctx, exportPayload{Report: "sales"}, queue.WithTaskID(taskID))
+		require.NoError(t, err)
+
+		require.NoError(t, enqueuer.Cancel(ctx, taskID))
+
+		_, err = storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		assert.ErrorIs(t, err, queue.ErrNoTaskToClaim)
+
+		err = enqueuer.Cancel(ctx, taskID)
+		assert.ErrorIs(t, err, queue.ErrTaskNotCancellable, "already cancelled")
+	})
+
+	t.Run("unknown task", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, _ := newEnqueuer(t)
+		assert.ErrorIs(t, enqueuer.Cancel(ctx, uuid.New()), queue.ErrTaskNotFound)
+	})
+
+	t.Run("processing task is flagged", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, storage := newEnqueuer(t)
+		_, err := enqueuer.Enqueue(ctx, exportPayload{})
+		require.NoError(t, err)
+		task, err := storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		require.NoError(t, err)
+
+		requested, err := storage.IsCancelRequested(ctx, task.ID)
+		require.NoError(t, err)
+		assert.False(t, requested)
+
+		require.NoError(t, enqueuer.Cancel(ctx, task.ID))
+
+		requested, err = storage.IsCancelRequested(ctx, task.ID)
+		require.NoError(t, err)
+		assert.True(t, requested)
+
+		require.NoError(t, storage.MarkCancelled(ctx, task.ID))
+		assert.ErrorIs(t, enqueuer.Cancel(ctx, task.ID), queue.ErrTaskNotCancellable)
+	})
+
+	t.Run("expired lock finishes the cancellation", func(t *testing.T) {
+		t.Parallel()
+
+		storage := queue.NewMemoryStorage(queue.WithLockCheckInterval(5 * time.Millisecond))
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() { _ = storage.Start(runCtx) }()
+
+		_, err = enqueuer.Enqueue(ctx, exportPayload{})
+		require.NoError(t, err)
+		task, err := storage.ClaimTask(ctx, uuid.New(), queues, time.Millisecond)
+		require.NoError(t, err)
+		require.NoError(t, enqueuer.Cancel(ctx, task.ID))
+
+		require.Eventually(t, func() bool {
+			return errors.Is(enqueuer.Cancel(ctx, task.ID), queue.ErrTaskNotCancellable)
+		}, time.Second, 10*time.Millisecond)
+
+		_, err = storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		assert.ErrorIs(t, err, queue.ErrNoTaskToClaim, "cancelled task is not returned to the queue")
+	})
+
+	t.Run("releases unique key", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, _ := newEnqueuer(t)
+		taskID := uuid.New()
+		_, err := enqueuer.Enqueue(ctx, exportPayload{}, queue.WithTaskID(taskID), queue.WithUniqueKey("export", time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, enqueuer.Cancel(ctx, taskID))
+
+		_, err = enqueuer.Enqueue(ctx, exportPayload{}, queue.WithUniqueKey("export", time.Hour))
+		assert.NoError(t, err)
+	})
+
+	t.Run("drops the rest of the workflow", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, storage := newEnqueuer(t)
+		stepID := uuid.New()
+		_, err := enqueuer.EnqueueWorkflow(ctx, queue.Chain(
+			queue.Step(downloadStep{}, queue.WithTaskID(stepID)),
+			queue.Step(parseStep{}),
+		).OnError(queue.Step(failureStep{})))
+		require.NoError(t, err)
+
+		require.NoError(t, enqueuer.Cancel(ctx, stepID))
+
+		_, err = storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		assert.ErrorIs(t, err, queue.ErrNoTaskToClaim)
+		assert.Equal(t, 1, storage.Stats().ActiveTasks, "only the cancelled step is left")
+	})
+
+	t.Run("requires cancel repository", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, err := queue.NewEnqueuer(&mockEnqueuerRepo{})
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, enqueuer.Cancel(ctx, uuid.New()), queue.ErrCancelNotSupported)
+	})
+}
+
+func TestWorker_CancelAndTimeout(t *testing.T) {
+	t.Parallel()
+
+	newWorker := func(t *testing.T, storage *queue.MemoryStorage, handlers ...queue.Handler) *queue.Worker {
+		t.Helper()
+		worker, err := queue.NewWorker(storage,
+			queue.WithPullInterval(5*time.Millisecond),
+			queue.WithCancelCheckInterval(5*time.Millisecond),
+			queue.WithWorkerLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		)
+		require.NoError(t, err)
+		require.NoError(t, worker.RegisterHandlers(handlers...))
+		return worker
+	}
+
+	run := func(t *testing.T, worker *queue.Worker) context.Context {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		errCh := make(chan error, 1)
+		go func() { errCh <- worker.Run(ctx)() }()
+		t.Cleanup(func() {
+			cancel()
+			require.NoError(t, <-errCh)
+		})
+		return ctx
+	}
+
+	t.Run("cancel stops running handler", func(t *testing.T) {
+		t.Parallel()
+
+		storage := queue.NewMemoryStorage()
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+
+		started := make(chan struct{})
+		cause := make(chan error, 1)
+		worker := newWorker(t, storage, queue.NewTaskHandler(func(ctx context.Context, _ slowPayload) error {
+			close(started)
+			<-ctx.Done()
+			cause <- context.Cause(ctx)
+			return ctx.Err()
+		}))
+		ctx := run(t, worker)
+
+		taskID := uuid.New()
+		_, err = enqueuer.Enqueue(ctx, slowPayload{}, queue.WithTaskID(taskID))
+		require.NoError(t, err)
+
+		<-started
+		require.NoError(t, enqueuer.Cancel(ctx, taskID))
+
+		select {
+		case err := <-cause:
+			assert.ErrorIs(t, err, queue.ErrTaskCancelled)
+		case <-ctx.Done():
+			t.Fatal("handler was not cancelled")
+		}
+
+		require.Eventually(t, func() bool {
+			return worker.Stats().TasksCancelled == 1
+		}, time.Second, 5*time.Millisecond)
+		assert.Zero(t, worker.Stats().TasksFailed, "cancelled task is not a failure")
+
+		entries, err := storage.ListDLQ(ctx, queue.DLQFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("timeout is recorded as failure reason", func(t *testing.T) {
+		t.Parallel()
+
+		storage := queue.NewMemoryStorage()
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+
+		worker := newWorker(t, storage, queue.NewTaskHandler(func(ctx context.Context, _ slowPayload) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}, queue.WithHandlerTimeout(time.Hour)))
+		ctx := run(t, worker)
+
+		_, err = enqueuer.Enqueue(ctx, slowPayload{},
+			queue.WithTaskTimeout(20*time.Millisecond),
+			queue.WithMaxRetries(0),
+		)
+		require.NoError(t, err)
+
+		var entries []*queue.TasksDlq
+		require.Eventually(t, func() bool {
+			entries, err = storage.ListDLQ(ctx, queue.DLQFilter{})
+			return err == nil && len(entries) == 1
+		}, time.Second, 5*time.Millisecond, "task timeout overrides the handler timeout")
+
+		assert.Contains(t, entries[0].Error, queue.ErrTaskTimeout.Error())
+		assert.Equal(t, int64(1), worker.Stats().TasksTimedOut)
+	})
+
+	t.Run("default timeout bounds tasks without one", func(t *testing.T) {
+		t.Parallel()
+
+		for name, opt := range map[string]queue.WorkerOption{
+			"explicit":     queue.WithDefaultTaskTimeout(20 * time.Millisecond),
+			"lock timeout": queue.WithLockTimeout(60 * time.Millisecond),
+		} {
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+
+				storage := queue.NewMemoryStorage()
+				enqueuer, err := queue.NewEnqueuer(storage)
+				require.NoError(t, err)
+
+				worker, err := queue.NewWorker(storage,
+					queue.WithPullInterval(5*time.Millisecond),
+					queue.WithWorkerLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+					opt,
+				)
+				require.NoError(t, err)
+				require.NoError(t, worker.RegisterHandler(queue.NewTaskHandler(func(ctx context.Context, _ slowPayload) error {
+					// Hangs until the worker gives up on it; the heartbeat keeps the lock alive meanwhile
+					<-ctx.Done()
+					return ctx.Err()
+				})))
+				ctx := run(t, worker)
+
+				_, err = enqueuer.Enqueue(ctx, slowPayload{}, queue.WithMaxRetries(0))
+				require.NoError(t, err)
+
+				var entries []*queue.TasksDlq
+				require.Eventually(t, func() bool {
+					entries, err = storage.ListDLQ(ctx, queue.DLQFilter{})
+					return err == nil && len(entries) == 1
+				}, 2*time.Second, 5*time.Millisecond, "hung task is timed out and dead-lettered")
+
+				assert.Contains(t, entries[0].Error, queue.ErrTaskTimeout.Error())
+				assert.Equal(t, int64(1), worker.Stats().TasksTimedOut)
+			})
+		}
+	})
+}
@@ -6,14 +6,17 @@ import "time"
 // Designed for environment-based configuration using popular env parsing libraries.
 type Config struct {
 	// Worker configuration
-	PollInterval       time.Duration `env:"QUEUE_POLL_INTERVAL" envDefault:"5s"`
-	LockTimeout        time.Duration `env:"QUEUE_LOCK_TIMEOUT" envDefault:"5m"`
-	ShutdownTimeout    time.Duration `env:"QUEUE_SHUTDOWN_TIMEOUT" envDefault:"30s"`
-	MaxConcurrentTasks int           `env:"QUEUE_MAX_CONCURRENT_TASKS" envDefault:"10"`
-	Queues             []string      `env:"QUEUE_WORKER_QUEUES" envDefault:"default" envSeparator:","`
+	PollInterval       time.Duration  `env:"QUEUE_POLL_INTERVAL" envDefault:"5s"`
+	LockTimeout        time.Duration  `env:"QUEUE_LOCK_TIMEOUT" envDefault:"5m"`
+	DefaultTaskTimeout time.Duration  `env:"QUEUE_DEFAULT_TASK_TIMEOUT"` // Defaults to LockTimeout
+	ShutdownTimeout    time.Duration  `env:"QUEUE_SHUTDOWN_TIMEOUT" envDefault:"30s"`
+	MaxConcurrentTasks int            `env:"QUEUE_MAX_CONCURRENT_TASKS" envDefault:"10"`
+	Queues             []string       `env:"QUEUE_WORKER_QUEUES" envDefault:"default" envSeparator:","`
+	QueueWeights       map[string]int `env:"QUEUE_WORKER_QUEUE_WEIGHTS" envSeparator:"," envKeyValSeparator:":"`
 
 	// Scheduler configuration
 	CheckInterval time.Duration `env:"QUEUE_CHECK_INTERVAL" envDefault:"10s"`
+	LeaseTTL      time.Duration `env:"QUEUE_SCHEDULER_LEASE_TTL" envDefault:"15s"`
 
 	// Enqueuer configuration
 	DefaultQueue    string   `env:"QUEUE_DEFAULT_QUEUE" envDefault:"default"`
@@ -29,6 +32,7 @@ func DefaultConfig() Config {
 		MaxConcurrentTasks: 10,
 		Queues:             []string{"default"},
 		CheckInterval:      10 * time.Second,
+		LeaseTTL:           15 * time.Second,
 		DefaultQueue:       "default",
 		DefaultPriority:    PriorityMedium,
 	}
@@ -0,0 +1,79 @@
+package queue
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskInfo describes the task a handler is running.
+// The worker stores it in the handler context; read it with TaskInfoFromContext.
+type TaskInfo struct {
+	ID          uuid.UUID
+	Name        string
+	Queue       string
+	Type        TaskType
+	Priority    Priority
+	RetryCount  int8 // Number of failed attempts before this one
+	MaxRetries  int8
+	WorkflowID  *uuid.UUID
+	ScheduledAt time.Time
+	CreatedAt   time.Time
+	WorkerID    uuid.UUID
+}
+
+type taskInfoKey struct{}
+
+func withTaskInfo(ctx context.Context, task *Task, workerID uuid.UUID) context.Context {
+	return context.WithValue(ctx, taskInfoKey{}, TaskInfo{
+		ID:          task.ID,
+		Name:        task.TaskName,
+		Queue:       task.Queue,
+		Type:        task.TaskType,
+		Priority:    task.Priority,
+		RetryCount:  task.RetryCount,
+		MaxRetries:  task.MaxRetries,
+		WorkflowID:  task.WorkflowID,
+		ScheduledAt: task.ScheduledAt,
+		CreatedAt:   task.CreatedAt,
+		WorkerID:    workerID,
+	})
+}
+
+// TaskInfoFromContext returns the metadata of the running task.
+// Reports false if the context does not belong to a running task.
+func TaskInfoFromContext(ctx context.Context) (TaskInfo, bool) {
+	info, ok := ctx.Value(taskInfoKey{}).(TaskInfo)
+	return info, ok
+}
+
+// TaskLogAttrs returns the running task's metadata as a "task" log group.
+// Its signature matches logger.ContextExtractor, so every record logged with
+// the handler context carries the task metadata:
+//
+//	log := logger.New(logger.WithContextExtractors(queue.TaskLogAttrs))
+func TaskLogAttrs(ctx context.Context) (slog.Attr, bool) {
+	info, ok := TaskInfoFromContext(ctx)
+	if !ok {
+		return slog.Attr{}, false
+	}
+	return slog.Group("task", info.logAttrs()...), true
+}
+
+// logAttrs returns the task metadata as log attributes.
+func (i TaskInfo) logAttrs() []any {
+	attrs := []any{
+		slog.String("id", i.ID.String()),
+		slog.String("name", i.Name),
+		slog.String("queue", i.Queue),
+		slog.Int("retry_count", int(i.RetryCount)),
+		slog.Int("max_retries", int(i.MaxRetries)),
+		slog.String("worker_id", i.WorkerID.String()),
+	}
+	if i.WorkflowID != nil {
+		attrs = append(attrs, slog.String("workflow_id", i.WorkflowID.String()))
+	}
+	return attrs
+}
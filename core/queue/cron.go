@@ -0,0 +1,584 @@
+package queue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronMacros maps predefined schedules to their 5-field equivalents
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	monthNames = map[string]int{
+		"JAN": 1, "FEB": 2, "MAR": 3, "APR": 4, "MAY": 5, "JUN": 6,
+		"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
+	}
+	weekdayNames = map[string]int{
+		"SUN": 0, "MON": 1, "TUE": 2, "WED": 3, "THU": 4, "FRI": 5, "SAT": 6,
+	}
+)
+
+// cronSearchYears bounds the search for the next run. Any satisfiable
+// expression matches within 8 years (Feb 29 across a skipped leap year).
+const cronSearchYears = 10
+
+// cronBounds describes the valid values of a cron field
+type cronBounds struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	secondBounds  = cronBounds{name: "second", min: 0, max: 59}
+	minuteBounds  = cronBounds{name: "minute", min: 0, max: 59}
+	hourBounds    = cronBounds{name: "hour", min: 0, max: 23}
+	domBounds     = cronBounds{name: "day of month", min: 1, max: 31}
+	monthBounds   = cronBounds{name: "month", min: 1, max: 12, names: monthNames}
+	weekdayBounds = cronBounds{name: "day of week", min: 0, max: 7, names: weekdayNames}
+)
+
+// cronSchedule runs at times matching a cron expression, evaluated in a fixed location.
+// Fields are stored as bitsets where bit n is set when value n matches.
+type cronSchedule struct {
+	expr string
+	loc  *time.Location
+
+	second, minute, hour, dom, month, dow uint64
+
+	// Day-of-month and day-of-week specials that depend on the month layout
+	lastDayOffsets []int          // L, L-n
+	lastWeekdayDom bool           // LW
+	nearestWeekday []int          // nW
+	lastWeekdays   []time.Weekday // nL
+	nthWeekdays    []nthWeekday   // n#k
+
+	// A field starting with * or ? does not restrict the day, so days match
+	// on the other field only. When both are restricted either one matches.
+	domAny, dowAny bool
+}
+
+type nthWeekday struct {
+	weekday time.Weekday
+	n       int
+}
+
+// Cron creates a schedule from a cron expression evaluated in loc.
+// A nil loc means UTC.
+//
+// Both the standard 5-field form (minute hour day-of-month month day-of-week)
+// and a 6-field form with leading seconds are accepted. Fields support
+// *, lists (1,15), ranges (1-5), steps (*/15, 10-40/10, 5/20), month and
+// weekday names (JAN, MON-FRI), and ? in the day fields. Day-of-month also
+// accepts L (last day), L-n (n days before the last), LW (last weekday) and
+// nW (weekday nearest to day n); day-of-week accepts nL (last weekday n of
+// the month) and n#k (k-th weekday n of the month). The macros @yearly,
+// @annually, @monthly, @weekly, @daily, @midnight and @hourly are supported.
+//
+// Schedules follow wall-clock time in loc. A run whose local time is skipped
+// by a DST transition fires at the moment the transition ends. A local time
+// repeated by a DST transition runs once, unless the expression matches every
+// hour, in which case the repeated times run as well.
+func Cron(expr string, loc *time.Location) (Schedule, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	spec := strings.TrimSpace(expr)
+	if strings.HasPrefix(spec, "@") {
+		macro, ok := cronMacros[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown macro %q", ErrInvalidSchedule, spec)
+		}
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("%w: expected 5 or 6 fields in %q, got %d", ErrInvalidSchedule, expr, len(fields))
+	}
+
+	s := &cronSchedule{expr: strings.TrimSpace(expr), loc: loc}
+
+	var err error
+	if s.second, err = parseCronField(fields[0], secondBounds); err != nil {
+		return nil, err
+	}
+	if s.minute, err = parseCronField(fields[1], minuteBounds); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseCronField(fields[2], hourBounds); err != nil {
+		return nil, err
+	}
+	if err = s.parseDayOfMonth(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseCronField(fields[4], monthBounds); err != nil {
+		return nil, err
+	}
+	if err = s.parseDayOfWeek(fields[5]); err != nil {
+		return nil, err
+	}
+
+	if !s.satisfiable() {
+		return nil, fmt.Errorf("%w: %q never matches", ErrInvalidSchedule, expr)
+	}
+
+	return s, nil
+}
+
+// MustCron is like Cron but panics if the expression is invalid.
+// Intended for schedules defined at program start.
+func MustCron(expr string, loc *time.Location) Schedule {
+	s, err := Cron(expr, loc)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (s *cronSchedule) String() string {
+	return fmt.Sprintf("cron %q in %s", s.expr, s.loc)
+}
+
+// Next returns the first matching time strictly after from, in the schedule location.
+// Returns the zero time if nothing matches within the search horizon.
+func (s *cronSchedule) Next(from time.Time) time.Time {
+	next := s.nextWallTime(from)
+
+	// Times repeated by a DST fall-back are only reachable through real time
+	if s.hour == fullHours {
+		if repeated, ok := s.nextRepeated(from, next); ok {
+			return repeated
+		}
+	}
+
+	return next
+}
+
+// fullHours is the hour bitset of an expression that matches every hour
+const fullHours = 1<<24 - 1
+
+// nextWallTime walks matching wall-clock times in order and returns the first
+// one whose earliest occurrence is after from.
+func (s *cronSchedule) nextWallTime(from time.Time) time.Time {
+	local := from.In(s.loc)
+	y, m, d := local.Date()
+	fromHour, fromMinute, fromSecond := local.Clock()
+
+	// Dates are walked in UTC so that day arithmetic is unaffected by DST
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	limit := day.AddDate(cronSearchYears, 0, 0)
+	first := true
+
+	for ; day.Before(limit); day, first = day.AddDate(0, 0, 1), false {
+		if s.month&(1<<uint(day.Month())) == 0 {
+			day = time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.matchDay(day) {
+			continue
+		}
+
+		for h := 0; h < 24; h++ {
+			if s.hour&(1<<uint(h)) == 0 || (first && h < fromHour) {
+				continue
+			}
+			for mi := 0; mi < 60; mi++ {
+				if s.minute&(1<<uint(mi)) == 0 || (first && h == fromHour && mi < fromMinute) {
+					continue
+				}
+				for sec := 0; sec < 60; sec++ {
+					if s.second&(1<<uint(sec)) == 0 || (first && h == fromHour && mi == fromMinute && sec < fromSecond) {
+						continue
+					}
+					if next := s.resolve(day, h, mi, sec); next.After(from) {
+						return next
+					}
+				}
+			}
+		}
+	}
+
+	return time.Time{}
+}
+
+// resolve maps a wall-clock time to the instant it fires at.
+// Wall times skipped by a DST gap fire when the gap ends; wall times
+// repeated by a DST overlap resolve to their first occurrence.
+func (s *cronSchedule) resolve(day time.Time, hour, minute, second int) time.Time {
+	t := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, s.loc)
+	start, end := t.ZoneBounds()
+
+	// time.Date normalizes a skipped wall time to one side of the gap
+	wall := time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, time.UTC)
+	if got := wallClock(t); !got.Equal(wall) {
+		if got.Before(wall) {
+			return end
+		}
+		return start
+	}
+
+	// If the zone period began with clocks turned back, t may be the second occurrence
+	if !start.IsZero() {
+		_, offset := t.Zone()
+		_, prevOffset := start.Add(-time.Second).In(s.loc).Zone()
+		if prevOffset > offset {
+			earlier := t.Add(-time.Duration(prevOffset-offset) * time.Second)
+			if wallClock(earlier.In(s.loc)).Equal(wall) {
+				return earlier
+			}
+		}
+	}
+
+	return t
+}
+
+// nextRepeated finds the first match after from and before limit among the
+// second occurrences of wall times repeated by a DST fall-back.
+// A zero limit means the search horizon.
+func (s *cronSchedule) nextRepeated(from, limit time.Time) (time.Time, bool) {
+	if limit.IsZero() {
+		limit = from.AddDate(cronSearchYears, 0, 0)
+	}
+
+	start, end := from.In(s.loc).ZoneBounds()
+	for {
+		if !start.IsZero() {
+			_, offset := start.In(s.loc).Zone()
+			_, prevOffset := start.Add(-time.Second).In(s.loc).Zone()
+
+			// The first prevOffset-offset seconds of the period repeat the previous wall times
+			if prevOffset > offset {
+				windowEnd := start.Add(time.Duration(prevOffset-offset) * time.Second)
+				t := from.Truncate(time.Second).Add(time.Second)
+				if t.Before(start) {
+					t = start
+				}
+				for ; t.Before(windowEnd) && t.Before(limit); t = t.Add(time.Second) {
+					if s.matchInstant(t) {
+						return t, true
+					}
+				}
+			}
+		}
+
+		if end.IsZero() || !end.Before(limit) {
+			return time.Time{}, false
+		}
+		start, end = end.In(s.loc).ZoneBounds()
+	}
+}
+
+// matchInstant reports whether t matches every field of the expression in the schedule location.
+func (s *cronSchedule) matchInstant(t time.Time) bool {
+	local := t.In(s.loc)
+	h, m, sec := local.Clock()
+	if s.second&(1<<uint(sec)) == 0 || s.minute&(1<<uint(m)) == 0 || s.hour&(1<<uint(h)) == 0 {
+		return false
+	}
+	if s.month&(1<<uint(local.Month())) == 0 {
+		return false
+	}
+	y, mo, d := local.Date()
+	return s.matchDay(time.Date(y, mo, d, 0, 0, 0, 0, time.UTC))
+}
+
+// wallClock returns the civil date and time of t as a UTC time, for comparing wall times.
+func wallClock(t time.Time) time.Time {
+	y, m, d := t.Date()
+	h, mi, sec := t.Clock()
+	return time.Date(y, m, d, h, mi, sec, 0, time.UTC)
+}
+
+// matchDay reports whether the date (in UTC, representing a civil date) matches the day fields.
+func (s *cronSchedule) matchDay(day time.Time) bool {
+	domMatch := s.matchDayOfMonth(day)
+	dowMatch := s.matchDayOfWeek(day)
+
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dowMatch
+	case s.dowAny:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+func (s *cronSchedule) matchDayOfMonth(day time.Time) bool {
+	d := day.Day()
+	if s.dom&(1<<uint(d)) != 0 {
+		return true
+	}
+
+	last := daysInMonth(day.Year(), day.Month())
+	for _, offset := range s.lastDayOffsets {
+		if d == last-offset {
+			return true
+		}
+	}
+	if s.lastWeekdayDom && d == nearestWeekday(day.Year(), day.Month(), last) {
+		return true
+	}
+	for _, n := range s.nearestWeekday {
+		if n <= last && d == nearestWeekday(day.Year(), day.Month(), n) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *cronSchedule) matchDayOfWeek(day time.Time) bool {
+	wd := day.Weekday()
+	if s.dow&(1<<uint(wd)) != 0 {
+		return true
+	}
+
+	d := day.Day()
+	for _, lw := range s.lastWeekdays {
+		if wd == lw && d+7 > daysInMonth(day.Year(), day.Month()) {
+			return true
+		}
+	}
+	for _, nw := range s.nthWeekdays {
+		if wd == nw.weekday && (d-1)/7+1 == nw.n {
+			return true
+		}
+	}
+
+	return false
+}
+
+// satisfiable rejects expressions whose plain day-of-month values never occur in the selected months.
+func (s *cronSchedule) satisfiable() bool {
+	if !s.dowAny || s.domAny || len(s.lastDayOffsets) > 0 || s.lastWeekdayDom {
+		return true
+	}
+
+	for m := time.January; m <= time.December; m++ {
+		if s.month&(1<<uint(m)) == 0 {
+			continue
+		}
+		// 2024 is a leap year, so February allows the 29th
+		maxDay := daysInMonth(2024, m)
+		if s.dom&(1<<uint(maxDay+1)-1) != 0 {
+			return true
+		}
+		for _, n := range s.nearestWeekday {
+			if n <= maxDay {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parseDayOfMonth parses the day-of-month field, including L, L-n, LW and nW items.
+func (s *cronSchedule) parseDayOfMonth(field string) error {
+	s.domAny = field == "?" || strings.HasPrefix(field, "*")
+	if field == "?" {
+		field = "*"
+	}
+
+	var plain []string
+	for item := range strings.SplitSeq(field, ",") {
+		upper := strings.ToUpper(item)
+		switch {
+		case upper == "L":
+			s.lastDayOffsets = append(s.lastDayOffsets, 0)
+		case upper == "LW":
+			s.lastWeekdayDom = true
+		case strings.HasPrefix(upper, "L-"):
+			n, err := strconv.Atoi(upper[2:])
+			if err != nil || n < 0 || n > 30 {
+				return fmt.Errorf("%w: invalid day of month %q", ErrInvalidSchedule, item)
+			}
+			s.lastDayOffsets = append(s.lastDayOffsets, n)
+		case strings.HasSuffix(upper, "W"):
+			n, err := strconv.Atoi(upper[:len(upper)-1])
+			if err != nil || n < domBounds.min || n > domBounds.max {
+				return fmt.Errorf("%w: invalid day of month %q", ErrInvalidSchedule, item)
+			}
+			s.nearestWeekday = append(s.nearestWeekday, n)
+		default:
+			plain = append(plain, item)
+		}
+	}
+
+	if len(plain) > 0 {
+		bits, err := parseCronField(strings.Join(plain, ","), domBounds)
+		if err != nil {
+			return err
+		}
+		s.dom = bits
+	}
+
+	return nil
+}
+
+// parseDayOfWeek parses the day-of-week field, including nL and n#k items.
+// Both 0 and 7 mean Sunday.
+func (s *cronSchedule) parseDayOfWeek(field string) error {
+	s.dowAny = field == "?" || strings.HasPrefix(field, "*")
+	if field == "?" {
+		field = "*"
+	}
+
+	var plain []string
+	for item := range strings.SplitSeq(field, ",") {
+		upper := strings.ToUpper(item)
+		switch {
+		case strings.Contains(upper, "#"):
+			weekday, n, _ := strings.Cut(upper, "#")
+			wd, err := parseCronValue(weekday, weekdayBounds)
+			if err != nil {
+				return err
+			}
+			k, err := strconv.Atoi(n)
+			if err != nil || k < 1 || k > 5 {
+				return fmt.Errorf("%w: invalid day of week %q", ErrInvalidSchedule, item)
+			}
+			s.nthWeekdays = append(s.nthWeekdays, nthWeekday{weekday: time.Weekday(wd % 7), n: k})
+		case len(upper) > 1 && strings.HasSuffix(upper, "L"):
+			wd, err := parseCronValue(upper[:len(upper)-1], weekdayBounds)
+			if err != nil {
+				return err
+			}
+			s.lastWeekdays = append(s.lastWeekdays, time.Weekday(wd%7))
+		default:
+			plain = append(plain, item)
+		}
+	}
+
+	if len(plain) > 0 {
+		bits, err := parseCronField(strings.Join(plain, ","), weekdayBounds)
+		if err != nil {
+			return err
+		}
+		// Fold 7 (Sunday) onto 0
+		if bits&(1<<7) != 0 {
+			bits = bits&^(1<<7) | 1
+		}
+		s.dow = bits
+	}
+
+	return nil
+}
+
+// parseCronField parses a comma-separated list of values, ranges and steps into a bitset.
+func parseCronField(field string, b cronBounds) (uint64, error) {
+	var set uint64
+	for item := range strings.SplitSeq(field, ",") {
+		bits, err := parseCronItem(item, b)
+		if err != nil {
+			return 0, err
+		}
+		set |= bits
+	}
+	return set, nil
+}
+
+// parseCronItem parses a single value, range or stepped range: 5, 1-5, */15, 10-40/10, 5/20.
+func parseCronItem(item string, b cronBounds) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+	step := 1
+	if hasStep {
+		n, err := strconv.Atoi(stepPart)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("%w: invalid step in %s field %q", ErrInvalidSchedule, b.name, item)
+		}
+		step = n
+	}
+
+	var lo, hi int
+	switch {
+	case rangePart == "*":
+		lo, hi = b.min, b.max
+		if b.name == weekdayBounds.name {
+			hi = 6 // 7 duplicates Sunday
+		}
+	case strings.Contains(rangePart, "-"):
+		start, end, _ := strings.Cut(rangePart, "-")
+		var err error
+		if lo, err = parseCronValue(start, b); err != nil {
+			return 0, err
+		}
+		if hi, err = parseCronValue(end, b); err != nil {
+			return 0, err
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("%w: range start exceeds end in %s field %q", ErrInvalidSchedule, b.name, item)
+		}
+	default:
+		var err error
+		if lo, err = parseCronValue(rangePart, b); err != nil {
+			return 0, err
+		}
+		hi = lo
+		// "5/20" means from 5 to the end of the range in steps of 20
+		if hasStep {
+			hi = b.max
+		}
+	}
+
+	var set uint64
+	for v := lo; v <= hi; v += step {
+		set |= 1 << uint(v)
+	}
+	return set, nil
+}
+
+// parseCronValue parses a number or a name within bounds.
+func parseCronValue(value string, b cronBounds) (int, error) {
+	if n, ok := b.names[strings.ToUpper(value)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < b.min || n > b.max {
+		return 0, fmt.Errorf("%w: invalid %s %q", ErrInvalidSchedule, b.name, value)
+	}
+	return n, nil
+}
+
+// nearestWeekday returns the weekday (Mon-Fri) closest to the given day
+// without leaving the month, following the Quartz W semantics.
+func nearestWeekday(year int, month time.Month, day int) int {
+	wd := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday()
+	last := daysInMonth(year, month)
+
+	switch wd {
+	case time.Saturday:
+		if day == 1 {
+			return 3 // Monday the 3rd
+		}
+		return day - 1
+	case time.Sunday:
+		if day == last {
+			return day - 2 // Friday before
+		}
+		return day + 1
+	default:
+		return day
+	}
+}
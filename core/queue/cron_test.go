@@ -0,0 +1,311 @@
+package queue_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+func TestCron(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		expr     string
+		from     time.Time
+		expected time.Time
+	}{
+		{
+			name:     "weekdays at 9:30 from friday",
+			expr:     "30 9 * * 1-5",
+			from:     time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC), // Friday
+			expected: time.Date(2024, 1, 8, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekday names",
+			expr:     "30 9 * * MON-FRI",
+			from:     time.Date(2024, 1, 6, 10, 0, 0, 0, time.UTC), // Saturday
+			expected: time.Date(2024, 1, 8, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "strictly after from",
+			expr:     "30 9 * * *",
+			from:     time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC),
+			expected: time.Date(2024, 1, 2, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "steps",
+			expr:     "*/15 * * * *",
+			from:     time.Date(2024, 1, 1, 10, 16, 30, 0, time.UTC),
+			expected: time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "range with step",
+			expr:     "0 10-18/4 * * *",
+			from:     time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "start with step",
+			expr:     "5/20 * * * *",
+			from:     time.Date(2024, 1, 1, 10, 26, 0, 0, time.UTC),
+			expected: time.Date(2024, 1, 1, 10, 45, 0, 0, time.UTC),
+		},
+		{
+			name:     "lists",
+			expr:     "0 8,12,17 * * *",
+			from:     time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 1, 1, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "seconds field",
+			expr:     "*/10 * * * * *",
+			from:     time.Date(2024, 1, 1, 10, 0, 5, 500, time.UTC),
+			expected: time.Date(2024, 1, 1, 10, 0, 10, 0, time.UTC),
+		},
+		{
+			name:     "month names wrap to next year",
+			expr:     "0 0 1 JAN *",
+			from:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "sunday as 7",
+			expr:     "0 0 * * 7",
+			from:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), // Monday
+			expected: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "last day of leap february",
+			expr:     "0 0 L * *",
+			from:     time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "days before last day",
+			expr:     "0 0 L-2 * *",
+			from:     time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 4, 28, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "last weekday of month",
+			expr:     "0 0 LW * *",
+			from:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), // March 31 2024 is Sunday
+			expected: time.Date(2024, 3, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "nearest weekday to a saturday",
+			expr:     "0 0 15W * *",
+			from:     time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), // June 15 2024 is Saturday
+			expected: time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "nearest weekday does not leave the month",
+			expr:     "0 0 1W * *",
+			from:     time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC), // June 1 2024 is Saturday
+			expected: time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "first monday of the month",
+			expr:     "0 9 * * MON#1",
+			from:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 2, 5, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "last friday of the month",
+			expr:     "0 17 * * 5L",
+			from:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 1, 26, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "restricted day of month and day of week match either",
+			expr:     "0 0 15 * MON",
+			from:     time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC), // Tuesday
+			expected: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "question mark leaves the day to the other field",
+			expr:     "0 0 ? * FRI",
+			from:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "leap day",
+			expr:     "0 0 29 2 *",
+			from:     time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "daily macro",
+			expr:     "@daily",
+			from:     time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "hourly macro",
+			expr:     "@hourly",
+			from:     time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekly macro",
+			expr:     "@weekly",
+			from:     time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "yearly macro",
+			expr:     "@yearly",
+			from:     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := queue.Cron(tt.expr, time.UTC)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, schedule.Next(tt.from))
+		})
+	}
+}
+
+func TestCron_Location(t *testing.T) {
+	t.Parallel()
+
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	t.Run("evaluates in schedule location", func(t *testing.T) {
+		t.Parallel()
+
+		schedule, err := queue.Cron("0 9 * * *", loc)
+		require.NoError(t, err)
+
+		next := schedule.Next(time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC)) // 10:00 in New York
+		assert.Equal(t, time.Date(2024, 1, 2, 9, 0, 0, 0, loc), next)
+		assert.Equal(t, loc, next.Location())
+	})
+
+	t.Run("nil location means UTC", func(t *testing.T) {
+		t.Parallel()
+
+		schedule, err := queue.Cron("0 9 * * *", nil)
+		require.NoError(t, err)
+
+		next := schedule.Next(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+		assert.Equal(t, time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC), next)
+		assert.Equal(t, `cron "0 9 * * *" in UTC`, schedule.String())
+	})
+
+	t.Run("keeps wall clock across DST", func(t *testing.T) {
+		t.Parallel()
+
+		schedule, err := queue.Cron("30 9 * * *", loc)
+		require.NoError(t, err)
+
+		// March 10 2024: clocks move from 2:00 EST to 3:00 EDT
+		next := schedule.Next(time.Date(2024, 3, 9, 12, 0, 0, 0, loc))
+		assert.Equal(t, time.Date(2024, 3, 10, 13, 30, 0, 0, time.UTC), next.UTC())
+
+		next = schedule.Next(time.Date(2024, 3, 8, 12, 0, 0, 0, loc))
+		assert.Equal(t, time.Date(2024, 3, 9, 14, 30, 0, 0, time.UTC), next.UTC())
+	})
+
+	t.Run("skipped time fires when the gap ends", func(t *testing.T) {
+		t.Parallel()
+
+		schedule, err := queue.Cron("30 2 * * *", loc)
+		require.NoError(t, err)
+
+		next := schedule.Next(time.Date(2024, 3, 9, 20, 0, 0, 0, loc))
+		assert.Equal(t, time.Date(2024, 3, 10, 7, 0, 0, 0, time.UTC), next.UTC()) // 3:00 EDT
+
+		next = schedule.Next(next)
+		assert.Equal(t, time.Date(2024, 3, 11, 2, 30, 0, 0, loc), next)
+	})
+
+	t.Run("repeated time fires once", func(t *testing.T) {
+		t.Parallel()
+
+		schedule, err := queue.Cron("30 1 * * *", loc)
+		require.NoError(t, err)
+
+		// November 3 2024: clocks move from 2:00 EDT back to 1:00 EST
+		next := schedule.Next(time.Date(2024, 11, 2, 12, 0, 0, 0, loc))
+		assert.Equal(t, time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC), next.UTC()) // 1:30 EDT
+
+		next = schedule.Next(next)
+		assert.Equal(t, time.Date(2024, 11, 4, 6, 30, 0, 0, time.UTC), next.UTC()) // 1:30 EST
+	})
+
+	t.Run("hourly schedules run through the repeated hour", func(t *testing.T) {
+		t.Parallel()
+
+		schedule, err := queue.Cron("*/30 * * * *", loc)
+		require.NoError(t, err)
+
+		next := time.Date(2024, 11, 3, 5, 0, 0, 0, time.UTC) // 1:00 EDT
+		var got []time.Time
+		for range 4 {
+			next = schedule.Next(next)
+			got = append(got, next.UTC())
+		}
+
+		assert.Equal(t, []time.Time{
+			time.Date(2024, 11, 3, 5, 30, 0, 0, time.UTC), // 1:30 EDT
+			time.Date(2024, 11, 3, 6, 0, 0, 0, time.UTC),  // 1:00 EST
+			time.Date(2024, 11, 3, 6, 30, 0, 0, time.UTC), // 1:30 EST
+			time.Date(2024, 11, 3, 7, 0, 0, 0, time.UTC),  // 2:00 EST
+		}, got)
+	})
+}
+
+func TestCron_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{"empty", ""},
+		{"too few fields", "* * * *"},
+		{"too many fields", "* * * * * * *"},
+		{"unknown macro", "@sometimes"},
+		{"minute out of range", "60 * * * *"},
+		{"hour out of range", "0 24 * * *"},
+		{"day of month zero", "0 0 0 * *"},
+		{"month out of range", "0 0 1 13 *"},
+		{"weekday out of range", "0 0 * * 8"},
+		{"reversed range", "0 10-5 * * *"},
+		{"zero step", "*/0 * * * *"},
+		{"bad name", "0 0 1 FOO *"},
+		{"bad nearest weekday", "0 0 32W * *"},
+		{"bad nth weekday", "0 0 * * 1#6"},
+		{"bad last offset", "0 0 L-31 * *"},
+		{"never matches", "0 0 30 2 *"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := queue.Cron(tt.expr, time.UTC)
+			require.ErrorIs(t, err, queue.ErrInvalidSchedule)
+			assert.Nil(t, schedule)
+		})
+	}
+
+	t.Run("must cron panics", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Panics(t, func() { queue.MustCron("bad", time.UTC) })
+		assert.NotPanics(t, func() { queue.MustCron("@daily", time.UTC) })
+	})
+}
@@ -0,0 +1,290 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DLQRepository is implemented by storages that expose their dead letter queue.
+type DLQRepository interface {
+	// ListDLQ returns the entries matching filter, most recently failed first.
+	ListDLQ(ctx context.Context, filter DLQFilter) ([]*TasksDlq, error)
+
+	// GetDLQ returns a single entry or ErrDLQEntryNotFound.
+	GetDLQ(ctx context.Context, id uuid.UUID) (*TasksDlq, error)
+
+	// RequeueDLQ atomically removes the entry and stores task in its place.
+	// Returns ErrDLQEntryNotFound if the entry is gone, e.g. requeued concurrently.
+	RequeueDLQ(ctx context.Context, id uuid.UUID, task *Task) error
+
+	// PurgeDLQ deletes entries that failed before the given time and returns how many were removed.
+	PurgeDLQ(ctx context.Context, before time.Time) (int64, error)
+
+	// DeleteDLQ deletes a single entry. Returns ErrDLQEntryNotFound if it is gone.
+	DeleteDLQ(ctx context.Context, id uuid.UUID) error
+
+	// AddDLQ stores an entry for a failure that happened outside the worker,
+	// e.g. in a command or event handler.
+	AddDLQ(ctx context.Context, entry *TasksDlq) error
+}
+
+// DLQFilter selects dead letter queue entries. Zero fields match everything.
+type DLQFilter struct {
+	Queue        string
+	TaskName     string
+	FailedAfter  time.Time // Inclusive lower bound of FailedAt
+	FailedBefore time.Time // Exclusive upper bound of FailedAt
+	Limit        int
+	Offset       int
+}
+
+// Matches reports whether the entry satisfies the filter, ignoring Limit and Offset.
+func (f DLQFilter) Matches(entry *TasksDlq) bool {
+	switch {
+	case f.Queue != "" && entry.Queue != f.Queue:
+		return false
+	case f.TaskName != "" && entry.TaskName != f.TaskName:
+		return false
+	case !f.FailedAfter.IsZero() && entry.FailedAt.Before(f.FailedAfter):
+		return false
+	case !f.FailedBefore.IsZero() && !entry.FailedAt.Before(f.FailedBefore):
+		return false
+	}
+	return true
+}
+
+// DeadLetterQueue lets operators inspect, requeue and purge tasks that exhausted their retries.
+type DeadLetterQueue struct {
+	repo DLQRepository
+}
+
+// NewDeadLetterQueue creates a dead letter queue manager on top of the repository.
+func NewDeadLetterQueue(repo DLQRepository) (*DeadLetterQueue, error) {
+	if repo == nil {
+		return nil, ErrRepositoryNil
+	}
+	return &DeadLetterQueue{repo: repo}, nil
+}
+
+// List returns the entries matching filter, most recently failed first.
+func (d *DeadLetterQueue) List(ctx context.Context, filter DLQFilter) ([]*TasksDlq, error) {
+	entries, err := d.repo.ListDLQ(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dead letter queue: %w", err)
+	}
+	return entries, nil
+}
+
+// Get returns a single entry with its payload and last error.
+func (d *DeadLetterQueue) Get(ctx context.Context, id uuid.UUID) (*TasksDlq, error) {
+	entry, err := d.repo.GetDLQ(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dead letter queue entry %s: %w", id, err)
+	}
+	return entry, nil
+}
+
+// Requeue moves an entry back to its queue as a pending task with a fresh retry budget.
+// The task keeps its original ID. Returns the task ID.
+func (d *DeadLetterQueue) Requeue(ctx context.Context, id uuid.UUID, opts ...RequeueOption) (uuid.UUID, error) {
+	options, err := newRequeueOptions(opts)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	entry, err := d.repo.GetDLQ(ctx, id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get dead letter queue entry %s: %w", id, err)
+	}
+
+	return d.requeue(ctx, entry, options)
+}
+
+// RequeueMany requeues every entry matching filter and returns how many were requeued.
+// It keeps going after a failed entry and returns the joined errors.
+func (d *DeadLetterQueue) RequeueMany(ctx context.Context, filter DLQFilter, opts ...RequeueOption) (int, error) {
+	options, err := newRequeueOptions(opts)
+	if err != nil {
+		return 0, err
+	}
+
+	entries, err := d.List(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		requeued int
+		errs     []error
+	)
+	for _, entry := range entries {
+		if _, err := d.requeue(ctx, entry, options); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		requeued++
+	}
+
+	return requeued, errors.Join(errs...)
+}
+
+// Purge deletes entries that failed more than olderThan ago and returns how many were removed.
+func (d *DeadLetterQueue) Purge(ctx context.Context, olderThan time.Duration) (int64, error) {
+	purged, err := d.repo.PurgeDLQ(ctx, time.Now().Add(-olderThan))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge dead letter queue: %w", err)
+	}
+	return purged, nil
+}
+
+// Delete removes a single entry without requeueing it.
+func (d *DeadLetterQueue) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := d.repo.DeleteDLQ(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete dead letter queue entry %s: %w", id, err)
+	}
+	return nil
+}
+
+// Add stores a failure from outside the worker, such as a command or event whose
+// handler failed permanently, so it can be inspected and requeued like a failed task.
+// Missing IDs, task type and timestamps are filled in.
+func (d *DeadLetterQueue) Add(ctx context.Context, entry *TasksDlq) error {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.TaskID == uuid.Nil {
+		entry.TaskID = uuid.New()
+	}
+	if entry.Queue == "" {
+		entry.Queue = DefaultQueueName
+	}
+	if entry.TaskType == "" {
+		entry.TaskType = TaskTypeOneTime
+	}
+	if entry.FailedAt.IsZero() {
+		entry.FailedAt = time.Now()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = entry.FailedAt
+	}
+
+	if err := d.repo.AddDLQ(ctx, entry); err != nil {
+		return fmt.Errorf("failed to add dead letter queue entry %s: %w", entry.ID, err)
+	}
+	return nil
+}
+
+func (d *DeadLetterQueue) requeue(ctx context.Context, entry *TasksDlq, options *requeueOptions) (uuid.UUID, error) {
+	now := time.Now()
+	task := &Task{
+		ID:          entry.TaskID,
+		Queue:       entry.Queue,
+		TaskType:    entry.TaskType,
+		TaskName:    entry.TaskName,
+		Payload:     entry.Payload,
+		Status:      TaskStatusPending,
+		Priority:    entry.Priority,
+		MaxRetries:  options.maxRetries,
+		ScheduledAt: now.Add(options.delay),
+		CreatedAt:   now,
+	}
+
+	if options.payload != nil {
+		task.Payload = options.payload
+	}
+	if options.priority != nil {
+		task.Priority = *options.priority
+	}
+	if options.queue != "" {
+		task.Queue = options.queue
+	}
+
+	if err := d.repo.RequeueDLQ(ctx, entry.ID, task); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to requeue dead letter queue entry %s: %w", entry.ID, err)
+	}
+
+	return task.ID, nil
+}
+
+// RequeueOption is a functional option for requeueing dead letter queue entries
+type RequeueOption func(*requeueOptions)
+
+type requeueOptions struct {
+	payload    []byte
+	payloadErr error
+	priority   *Priority
+	queue      string
+	delay      time.Duration
+	maxRetries int8
+}
+
+func newRequeueOptions(opts []RequeueOption) (*requeueOptions, error) {
+	options := &requeueOptions{maxRetries: 3}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.payloadErr != nil {
+		return nil, options.payloadErr
+	}
+	if options.priority != nil && !options.priority.Valid() {
+		return nil, ErrInvalidPriority
+	}
+
+	return options, nil
+}
+
+// WithRequeuePayload replaces the payload, e.g. to fix the data that made the task fail.
+// The payload is marshaled to JSON; a nil payload keeps the original.
+func WithRequeuePayload(payload any) RequeueOption {
+	return func(o *requeueOptions) {
+		if payload == nil {
+			return
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			o.payloadErr = fmt.Errorf("failed to marshal payload of type %T: %w", payload, err)
+			return
+		}
+		o.payload = data
+	}
+}
+
+// WithRequeuePriority overrides the priority of the requeued task.
+func WithRequeuePriority(priority Priority) RequeueOption {
+	return func(o *requeueOptions) {
+		o.priority = &priority
+	}
+}
+
+// WithRequeueQueue moves the requeued task to another queue.
+func WithRequeueQueue(queue string) RequeueOption {
+	return func(o *requeueOptions) {
+		if queue != "" {
+			o.queue = queue
+		}
+	}
+}
+
+// WithRequeueDelay schedules the requeued task to run after the specified duration.
+func WithRequeueDelay(delay time.Duration) RequeueOption {
+	return func(o *requeueOptions) {
+		if delay > 0 {
+			o.delay = delay
+		}
+	}
+}
+
+// WithRequeueMaxRetries sets the retry budget of the requeued task (0-10, default 3).
+func WithRequeueMaxRetries(maxRetries int8) RequeueOption {
+	return func(o *requeueOptions) {
+		if maxRetries >= 0 && maxRetries <= 10 {
+			o.maxRetries = maxRetries
+		}
+	}
+}
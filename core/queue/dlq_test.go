@@ -0,0 +1,290 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+type (
+	chargePayload struct {
+		Amount int `json:"amount"`
+	}
+	refundPayload struct {
+		Amount int `json:"amount"`
+	}
+)
+
+func TestDeadLetterQueue(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	// newDLQ fails one task per payload into the dead letter queue
+	newDLQ := func(t *testing.T, payloads ...any) (*queue.DeadLetterQueue, *queue.MemoryStorage) {
+		t.Helper()
+
+		storage := queue.NewMemoryStorage()
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+
+		for _, payload := range payloads {
+			_, err := enqueuer.Enqueue(ctx, payload, queue.WithQueue("billing"), queue.WithMaxRetries(0))
+			require.NoError(t, err)
+			task, err := storage.ClaimTask(ctx, uuid.New(), []string{"billing"}, time.Minute)
+			require.NoError(t, err)
+			require.NoError(t, storage.FailTask(ctx, task.ID, "card declined", 0))
+			require.NoError(t, storage.MoveToDLQ(ctx, task.ID))
+		}
+
+		dlq, err := queue.NewDeadLetterQueue(storage)
+		require.NoError(t, err)
+		return dlq, storage
+	}
+
+	t.Run("nil repository", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := queue.NewDeadLetterQueue(nil)
+		assert.ErrorIs(t, err, queue.ErrRepositoryNil)
+	})
+
+	t.Run("list and filter", func(t *testing.T) {
+		t.Parallel()
+
+		dlq, _ := newDLQ(t, chargePayload{Amount: 1}, refundPayload{Amount: 2}, chargePayload{Amount: 3})
+
+		entries, err := dlq.List(ctx, queue.DLQFilter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		assert.False(t, entries[0].FailedAt.Before(entries[2].FailedAt), "most recent first")
+
+		entries, err = dlq.List(ctx, queue.DLQFilter{TaskName: "queue_test.chargePayload"})
+		require.NoError(t, err)
+		assert.Len(t, entries, 2)
+
+		entries, err = dlq.List(ctx, queue.DLQFilter{Queue: "default"})
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+
+		entries, err = dlq.List(ctx, queue.DLQFilter{FailedBefore: time.Now().Add(-time.Hour)})
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+
+		entries, err = dlq.List(ctx, queue.DLQFilter{Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("get shows payload and error", func(t *testing.T) {
+		t.Parallel()
+
+		dlq, _ := newDLQ(t, chargePayload{Amount: 10})
+
+		entries, err := dlq.List(ctx, queue.DLQFilter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		entry, err := dlq.Get(ctx, entries[0].ID)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"amount":10}`, string(entry.Payload))
+		assert.Equal(t, "card declined", entry.Error)
+
+		_, err = dlq.Get(ctx, uuid.New())
+		assert.ErrorIs(t, err, queue.ErrDLQEntryNotFound)
+	})
+
+	t.Run("requeue with changed payload and priority", func(t *testing.T) {
+		t.Parallel()
+
+		dlq, storage := newDLQ(t, chargePayload{Amount: 10})
+
+		entries, err := dlq.List(ctx, queue.DLQFilter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		taskID, err := dlq.Requeue(ctx, entries[0].ID,
+			queue.WithRequeuePayload(chargePayload{Amount: 5}),
+			queue.WithRequeuePriority(queue.PriorityHigh),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, entries[0].TaskID, taskID)
+
+		task, err := storage.ClaimTask(ctx, uuid.New(), []string{"billing"}, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, taskID, task.ID)
+		assert.JSONEq(t, `{"amount":5}`, string(task.Payload))
+		assert.Equal(t, queue.PriorityHigh, task.Priority)
+		assert.Equal(t, int8(0), task.RetryCount)
+
+		_, err = dlq.Requeue(ctx, entries[0].ID)
+		assert.ErrorIs(t, err, queue.ErrDLQEntryNotFound, "entry is gone after requeue")
+	})
+
+	t.Run("requeue rejects invalid options", func(t *testing.T) {
+		t.Parallel()
+
+		dlq, _ := newDLQ(t)
+
+		_, err := dlq.Requeue(ctx, uuid.New(), queue.WithRequeuePriority(101))
+		assert.ErrorIs(t, err, queue.ErrInvalidPriority)
+
+		_, err = dlq.Requeue(ctx, uuid.New(), queue.WithRequeuePayload(make(chan int)))
+		assert.Error(t, err)
+	})
+
+	t.Run("requeue many", func(t *testing.T) {
+		t.Parallel()
+
+		dlq, storage := newDLQ(t, chargePayload{Amount: 1}, refundPayload{Amount: 2}, chargePayload{Amount: 3})
+
+		requeued, err := dlq.RequeueMany(ctx, queue.DLQFilter{TaskName: "queue_test.chargePayload"}, queue.WithRequeueQueue("default"))
+		require.NoError(t, err)
+		assert.Equal(t, 2, requeued)
+
+		for range 2 {
+			task, err := storage.ClaimTask(ctx, uuid.New(), []string{"default"}, time.Minute)
+			require.NoError(t, err)
+			assert.Equal(t, "queue_test.chargePayload", task.TaskName)
+		}
+
+		entries, err := dlq.List(ctx, queue.DLQFilter{})
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("purge by age", func(t *testing.T) {
+		t.Parallel()
+
+		dlq, _ := newDLQ(t, chargePayload{Amount: 1}, chargePayload{Amount: 2})
+
+		purged, err := dlq.Purge(ctx, time.Hour)
+		require.NoError(t, err)
+		assert.Zero(t, purged)
+
+		purged, err = dlq.Purge(ctx, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+
+		entries, err := dlq.List(ctx, queue.DLQFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("add external failure", func(t *testing.T) {
+		t.Parallel()
+
+		dlq, _ := newDLQ(t)
+
+		entry := &queue.TasksDlq{Queue: "commands", TaskName: "CreateUser", Payload: []byte(`{"email":"a@b.c"}`), Error: "smtp down"}
+		require.NoError(t, dlq.Add(ctx, entry))
+		assert.NotEqual(t, uuid.Nil, entry.ID)
+		assert.Equal(t, queue.TaskTypeOneTime, entry.TaskType)
+
+		stored, err := dlq.Get(ctx, entry.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "CreateUser", stored.TaskName)
+		assert.Equal(t, "smtp down", stored.Error)
+		assert.False(t, stored.FailedAt.IsZero())
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		t.Parallel()
+
+		dlq, _ := newDLQ(t, chargePayload{Amount: 1}, chargePayload{Amount: 2})
+
+		entries, err := dlq.List(ctx, queue.DLQFilter{})
+		require.NoError(t, err)
+		require.NoError(t, dlq.Delete(ctx, entries[0].ID))
+
+		err = dlq.Delete(ctx, entries[0].ID)
+		assert.ErrorIs(t, err, queue.ErrDLQEntryNotFound)
+
+		remaining, err := dlq.List(ctx, queue.DLQFilter{})
+		require.NoError(t, err)
+		require.Len(t, remaining, 1)
+		assert.Equal(t, entries[1].ID, remaining[0].ID)
+	})
+}
+
+func TestDeadLetterQueue_WorkerExhaustedTask(t *testing.T) {
+	t.Parallel()
+
+	storage := queue.NewMemoryStorage()
+	enqueuer, err := queue.NewEnqueuer(storage)
+	require.NoError(t, err)
+	dlq, err := queue.NewDeadLetterQueue(storage)
+	require.NoError(t, err)
+
+	worker, err := queue.NewWorker(storage,
+		queue.WithPullInterval(5*time.Millisecond),
+		queue.WithQueues("billing"),
+		queue.WithWorkerLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	require.NoError(t, err)
+
+	var (
+		declining atomic.Bool
+		attempts  atomic.Int32
+	)
+	declining.Store(true)
+	charged := make(chan int, 1)
+	require.NoError(t, worker.RegisterHandler(queue.NewTaskHandler(func(ctx context.Context, p chargePayload) error {
+		attempts.Add(1)
+		if declining.Load() {
+			return errors.New("card declined")
+		}
+		charged <- p.Amount
+		return nil
+	}, queue.WithHandlerRetryPolicy(queue.FixedBackoff(time.Millisecond)))))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- worker.Run(ctx)() }()
+
+	// Default MaxRetries: the worker moves the task to the DLQ once they are used up
+	taskID, err := enqueuer.Enqueue(ctx, chargePayload{Amount: 10}, queue.WithQueue("billing"))
+	require.NoError(t, err)
+
+	var entries []*queue.TasksDlq
+	require.Eventually(t, func() bool {
+		entries, err = dlq.List(ctx, queue.DLQFilter{Queue: "billing"})
+		return err == nil && len(entries) == 1
+	}, 2*time.Second, 5*time.Millisecond, "exhausted task reaches the DLQ")
+
+	assert.Equal(t, taskID, entries[0].TaskID)
+	assert.Equal(t, "card declined", entries[0].Error)
+	assert.Equal(t, int8(3), entries[0].RetryCount)
+	assert.Equal(t, int32(3), attempts.Load())
+
+	declining.Store(false)
+	requeuedID, err := dlq.Requeue(ctx, entries[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, taskID, requeuedID)
+
+	select {
+	case amount := <-charged:
+		assert.Equal(t, 10, amount)
+	case <-ctx.Done():
+		t.Fatal("requeued task did not run")
+	}
+
+	entries, err = dlq.List(ctx, queue.DLQFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	cancel()
+	require.NoError(t, <-errCh)
+}
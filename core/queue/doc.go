@@ -12,13 +12,17 @@
 //   - Priority-based task processing (0-100 scale)
 //   - Concurrent worker execution
 //   - Scheduled/periodic task support
-//   - Automatic retries with exponential backoff (max 10 retries)
+//   - Automatic retries with pluggable backoff policies (max 10 retries)
 //   - In-memory storage (development) and extensible storage interface
 //   - Graceful shutdown with Run() and Stop() methods
 //   - Type-safe handlers using Go generics
-//   - Dead letter queue for failed tasks
-//   - Multiple queue support
-//   - Task locking to prevent duplicate processing
+//   - Dead letter queue for failed tasks with inspection, requeue and purge
+//   - Task cancellation and per-task or per-handler execution timeouts
+//   - Multiple queue support with weighted fair claiming and per-queue rate limits
+//   - Task locking with automatic heartbeat to prevent duplicate processing
+//   - Worker and per-handler middleware (recovery, logging, concurrency limits, instrumentation)
+//   - Task results with a retention TTL, readable with Result or awaited with Await
+//   - Mountable admin dashboard and JSON API in the queue/admin package
 //
 // # Quick Start
 //
@@ -136,8 +140,8 @@
 //	ctx := context.Background()
 //	go worker.Start(ctx)
 //
-//	// Enqueue tasks
-//	err = enqueuer.Enqueue(ctx, EmailPayload{
+//	// Enqueue tasks; the returned ID identifies the task for Cancel and Result
+//	taskID, err := enqueuer.Enqueue(ctx, EmailPayload{
 //		To:      "user@example.com",
 //		Subject: "Welcome!",
 //		Body:    "Welcome to our service!",
@@ -211,9 +215,30 @@
 //	// Start scheduler
 //	go scheduler.Start(ctx)
 //
+// # Leader Election
+//
+// When every replica runs a Scheduler, give each one a LeaderElector so only the
+// instance holding the lease creates periodic tasks. Standby instances keep
+// campaigning and take over once the leader's lease expires or is released on
+// shutdown:
+//
+//	elector, _ := pgstorage.NewLeaderElector(pool, "")   // Postgres advisory lock
+//	elector, _ := redisstorage.NewLeaderElector(client, "") // Redis SET NX PX
+//	elector := lease.NewElector()                          // In-process, lease := queue.NewMemoryLease()
+//
+//	scheduler, _ := queue.NewScheduler(storage,
+//		queue.WithLeaderElector(elector),
+//		queue.WithLeaseTTL(15*time.Second),
+//	)
+//
+// The leader renews its lease every third of the TTL and stops scheduling as soon
+// as a renewal fails past the lease deadline. Stats().IsLeader reports the current
+// role, and Healthcheck returns ErrLeaderElection while the elector's backend is
+// unreachable; a healthy standby passes the healthcheck.
+//
 // # Retry Mechanisms
 //
-// Failed tasks automatically retry with exponential backoff:
+// Failed tasks automatically retry after a backoff delay:
 //
 //	// Set max retries (default is 3, max is 10)
 //	enqueuer.Enqueue(ctx, payload, queue.WithMaxRetries(5))
@@ -221,13 +246,230 @@
 //	// Returning an error triggers automatic retry
 //	handler := queue.NewTaskHandler(func(ctx context.Context, data ProcessingPayload) error {
 //		if err := performOperation(data); err != nil {
-//			return err // Will retry with exponential backoff
+//			return err // Will retry after the backoff delay
 //		}
 //		return nil
 //	})
 //
 //	// Tasks exceeding max retries move to dead letter queue
 //
+// The delay is computed by the worker from a RetryPolicy and passed to the
+// repository. Policies are fixed, linear or exponential, optionally jittered.
+// The task policy wins over the handler policy, which wins over the worker
+// default (DefaultRetryPolicy: linear 30s steps unless WithDefaultRetryPolicy is set):
+//
+//	// Flaky third-party API: aggressive jittered exponential backoff
+//	handler := queue.NewTaskHandler(callPartnerAPI,
+//		queue.WithHandlerRetryPolicy(queue.JitteredBackoff(
+//			queue.ExponentialBackoff(time.Second, 10*time.Minute), 1,
+//		)),
+//	)
+//
+//	// Internal task: quick fixed retries for this task only
+//	enqueuer.Enqueue(ctx, payload, queue.WithRetryPolicy(queue.FixedBackoff(2*time.Second)))
+//
+//	// Worker-wide default
+//	worker, _ := queue.NewWorker(storage, queue.WithDefaultRetryPolicy(queue.LinearBackoff(10*time.Second)))
+//
+// # Unique Tasks
+//
+// A unique key deduplicates tasks across enqueues. The key is held while its task is
+// pending or processing, and after that until the TTL passed to WithUniqueKey has
+// elapsed since the enqueue:
+//
+//	// At most one invoice reminder per invoice per hour
+//	_, err := enqueuer.Enqueue(ctx, ReminderPayload{InvoiceID: id},
+//		queue.WithUniqueKey("invoice-reminder:"+id, time.Hour),
+//	)
+//	if errors.Is(err, queue.ErrDuplicateTask) {
+//		// Already queued or recently sent
+//	}
+//
+// WithUniqueConflictMode selects what happens on a conflict: UniqueConflictReject
+// (default) returns ErrDuplicateTask, UniqueConflictKeepExisting drops the new task
+// silently, and UniqueConflictReplace swaps a pending holder for the new task. A task
+// that is already processing is never replaced.
+//
+// The storage must implement UniqueTaskRepository and check the key atomically with
+// the insert; otherwise Enqueue returns ErrUniqueTasksNotSupported. MemoryStorage,
+// pgstorage and redisstorage all implement it.
+//
+// # Workflows
+//
+// EnqueueWorkflow stores a multi-step job in one call. A chain runs its stages one
+// after another; a group fans steps out in parallel, and the stage after it starts
+// once every member has completed. An error callback runs when any task of the
+// workflow reaches the dead letter queue, and the remaining stages are dropped:
+//
+//	workflowID, err := enqueuer.EnqueueWorkflow(ctx, queue.Chain(
+//		queue.Step(DownloadPayload{URL: url}),
+//		queue.Step(ParsePayload{}, queue.WithQueue("cpu")),
+//		queue.Group(
+//			queue.Step(ShardPayload{N: 0}),
+//			queue.Step(ShardPayload{N: 1}),
+//		),
+//		queue.Step(NotifyPayload{}),
+//	).OnError(queue.Step(ImportFailedPayload{})))
+//
+// Handlers pass data to the next stage with SetResult and read the previous stage's
+// result with ParentResult. A group hands over a JSON array of member results in
+// completion order, and an error callback receives a WorkflowFailure:
+//
+//	download := queue.NewTaskHandler(func(ctx context.Context, p DownloadPayload) error {
+//		path, err := fetch(ctx, p.URL)
+//		if err != nil {
+//			return err
+//		}
+//		return queue.SetResult(ctx, path)
+//	})
+//
+//	parse := queue.NewTaskHandler(func(ctx context.Context, _ ParsePayload) error {
+//		var path string
+//		if err := queue.ParentResult(ctx, &path); err != nil {
+//			return err
+//		}
+//		return parseFile(ctx, path)
+//	})
+//
+// Later stages are stored up front with TaskStatusWaiting and activated by the
+// storage, so a workflow survives worker restarts. The storage must implement
+// WorkflowRepository; otherwise EnqueueWorkflow returns ErrWorkflowsNotSupported.
+//
+// # Dead Letter Queue
+//
+// Tasks that exhaust their retries are moved to the dead letter queue. DeadLetterQueue
+// lets operators inspect them, fix and requeue them, or purge old entries:
+//
+//	dlq, _ := queue.NewDeadLetterQueue(storage)
+//
+//	entries, _ := dlq.List(ctx, queue.DLQFilter{
+//		Queue:       "billing",
+//		FailedAfter: time.Now().Add(-24 * time.Hour),
+//		Limit:       50,
+//	})
+//
+//	// Requeue one entry with a corrected payload
+//	taskID, err := dlq.Requeue(ctx, entries[0].ID,
+//		queue.WithRequeuePayload(ChargePayload{Amount: 100}),
+//		queue.WithRequeuePriority(queue.PriorityHigh),
+//	)
+//
+//	// Requeue everything that failed because of a downstream outage
+//	n, err := dlq.RequeueMany(ctx, queue.DLQFilter{TaskName: "main.ChargePayload"})
+//
+//	// Drop entries older than 30 days
+//	purged, err := dlq.Purge(ctx, 30*24*time.Hour)
+//
+//	// Drop a single entry without requeueing it
+//	err = dlq.Delete(ctx, entries[1].ID)
+//
+// A requeued task keeps its original ID and gets a fresh retry budget. The entry is
+// removed in the same operation that stores the task, so concurrent requeues of one
+// entry yield a single task; the loser gets ErrDLQEntryNotFound.
+//
+// Add stores failures from outside the worker in the same place; core/command and
+// core/event use it to dead-letter messages whose handler failed permanently.
+//
+// # Cancellation and Timeouts
+//
+// Cancel stops a task that has not finished yet, identified by the ID returned from
+// Enqueue (or picked up front with WithTaskID):
+//
+//	exportID, err := enqueuer.Enqueue(ctx, ExportPayload{Report: "sales"})
+//
+//	if err := enqueuer.Cancel(ctx, exportID); errors.Is(err, queue.ErrTaskNotCancellable) {
+//		// Already completed, failed or cancelled
+//	}
+//
+// A pending task is cancelled right away. A running task is flagged, and its worker
+// cancels the handler context with ErrTaskCancelled as the cause on its next check
+// (see WithCancelCheckInterval). Cancelled tasks end in TaskStatusCancelled: they are
+// not retried, their unique key is released, and the waiting rest of their workflow
+// is dropped. The storage must implement CancelRepository.
+//
+// Timeouts limit a single execution, per task or per handler (the task wins):
+//
+//	handler := queue.NewTaskHandler(exportReport, queue.WithHandlerTimeout(time.Minute))
+//	enqueuer.Enqueue(ctx, payload, queue.WithTaskTimeout(5*time.Minute))
+//
+// Tasks with neither are bounded by the worker's WithDefaultTaskTimeout, which
+// defaults to the lock timeout, so a hung handler cannot hold its slot forever.
+//
+// When the timeout elapses the handler context is cancelled with ErrTaskTimeout as the
+// cause, and the attempt fails with an error wrapping ErrTaskTimeout, so the task error
+// and DLQ entry show why it failed. Timeouts are retried like any other failure.
+// Handlers must respect ctx; the worker waits for them to return.
+//
+// # Task Results
+//
+// A handler registered with NewResultTaskHandler returns a value that is stored as the
+// task result. Callers read it with Result, or block until the task finishes with Await:
+//
+//	worker.RegisterHandler(queue.NewResultTaskHandler(func(ctx context.Context, p ReportPayload) (ReportResult, error) {
+//		return buildReport(ctx, p)
+//	}))
+//
+//	taskID, err := enqueuer.Enqueue(ctx, ReportPayload{Month: "2025-01"}, queue.WithResultTTL(24*time.Hour))
+//
+//	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+//	defer cancel()
+//	report, err := queue.Await[ReportResult](ctx, enqueuer, taskID)
+//
+// Result returns ErrResultNotReady until the task finishes, an error wrapping
+// ErrTaskFailed with the failure message once it has failed or reached the dead letter
+// queue, and ErrTaskCancelled for cancelled tasks. Status returns the raw TaskResult.
+//
+// Completed tasks are kept until their result TTL (WithResultTTL, or the enqueuer's
+// WithDefaultResultTTL) has passed, after which the storage deletes them; without a TTL
+// they are kept forever. Tasks still holding a unique key or needed by an unfinished
+// workflow are kept until they are released. The storage must implement
+// ResultRepository; otherwise Status, Result and Await return ErrResultsNotSupported.
+//
+// # Lock Heartbeat
+//
+// A claimed task is locked for the lock timeout so that tasks of crashed workers
+// return to the queue. While the handler runs, the worker extends the lock every
+// heartbeat interval (a third of the lock timeout by default), so long-running tasks
+// are never claimed twice. Let tasks run past the lock timeout with a task, handler
+// or default timeout:
+//
+//	worker, _ := queue.NewWorker(storage,
+//		queue.WithLockTimeout(time.Minute),
+//		queue.WithHeartbeatInterval(15*time.Second),
+//		queue.WithDefaultTaskTimeout(30*time.Minute),
+//	)
+//
+// If the lock is lost, because the task was reclaimed after the worker stalled or
+// the storage refused every extension until the lock expired, the handler context is
+// cancelled with ErrLockLost as the cause. The worker then leaves the task alone,
+// since its new owner decides the outcome.
+//
+// # Middleware
+//
+// Middleware wraps task execution for cross-cutting concerns. Worker middleware wraps
+// every handler; handler middleware wraps one handler and runs inside it:
+//
+//	worker, _ := queue.NewWorker(storage,
+//		queue.WithMiddleware(
+//			queue.LoggingMiddleware(logger),
+//			queue.InstrumentMiddleware(metricsHook),
+//			queue.ConcurrencyLimitMiddleware(5), // Per task name
+//		),
+//	)
+//
+//	handler := queue.NewTaskHandler(syncCRM,
+//		queue.WithHandlerMiddleware(queue.ConcurrencyLimitMiddleware(1)),
+//	)
+//
+// The worker recovers handler panics into a *PanicError carrying the stack, so
+// middleware observe them as errors matching ErrHandlerPanic and the task is
+// retried like any other failure.
+//
+// The handler context carries the task metadata. Read it with TaskInfoFromContext,
+// or attach it to every log record with the core/logger context extractor:
+//
+//	log := logger.New(logger.WithContextExtractors(queue.TaskLogAttrs))
+//
 // # Multiple Queues
 //
 // Use separate queues for different workload types:
@@ -250,6 +492,32 @@
 //	go emailWorker.Start(ctx)
 //	go imageWorker.Start(ctx)
 //
+// A worker with several queues claims the highest-priority task across all of them,
+// so a busy high-priority queue can starve the rest. Weights share the claims out
+// instead: before each claim the queues are ordered at random in proportion to their
+// weights and tried one by one, and priority applies within each queue:
+//
+//	worker, _ := queue.NewWorker(storage,
+//		queue.WithQueueWeights(map[string]int{"critical": 6, "default": 3, "low": 1}),
+//	)
+//
+// Rate limits cap the throughput of a queue, e.g. to respect a third-party API quota,
+// without sleeping in handlers. Each claim from the queue takes a token from a
+// pkg/ratelimiter limiter keyed by RateLimitKeyPrefix plus the queue name; a denied
+// queue is skipped until its next refill and counted in WorkerStats.RateLimited.
+// Share the limiter's store between processes to enforce the cap across all workers:
+//
+//	// At most 10 emails per second
+//	limiter, _ := ratelimiter.NewBucket(store, ratelimiter.Config{
+//		Capacity:       10,
+//		RefillRate:     10,
+//		RefillInterval: time.Second,
+//	})
+//	worker, _ := queue.NewWorker(storage,
+//		queue.WithQueues("default", "emails"),
+//		queue.WithQueueRateLimit("emails", limiter),
+//	)
+//
 // # Error Handling
 //
 // Handle errors and context cancellation properly:
@@ -306,10 +574,10 @@
 //	// Required for Worker
 //	type WorkerRepository interface {
 //		ClaimTask(ctx context.Context, workerID uuid.UUID, queues []string, lockDuration time.Duration) (*Task, error)
-//		CompleteTask(ctx context.Context, taskID uuid.UUID) error
-//		FailTask(ctx context.Context, taskID uuid.UUID, errorMsg string) error
+//		CompleteTask(ctx context.Context, taskID uuid.UUID, result []byte) error
+//		FailTask(ctx context.Context, taskID uuid.UUID, errorMsg string, retryDelay time.Duration) error
 //		MoveToDLQ(ctx context.Context, taskID uuid.UUID) error
-//		ExtendLock(ctx context.Context, taskID uuid.UUID, duration time.Duration) error
+//		ExtendLock(ctx context.Context, taskID, workerID uuid.UUID, duration time.Duration) error
 //	}
 //
 //	// Required for Scheduler
@@ -318,8 +586,41 @@
 //		GetPendingTaskByName(ctx context.Context, taskName string) (*Task, error)
 //	}
 //
+//	// Optional: unique keys, workflows, dead letter queue management, cancellation, results and statistics
+//	type UniqueTaskRepository interface {
+//		CreateUniqueTask(ctx context.Context, task *Task, mode UniqueConflictMode) (uuid.UUID, error)
+//	}
+//	type WorkflowRepository interface {
+//		CreateWorkflow(ctx context.Context, tasks []*Task) error
+//	}
+//	type DLQRepository interface {
+//		ListDLQ(ctx context.Context, filter DLQFilter) ([]*TasksDlq, error)
+//		GetDLQ(ctx context.Context, id uuid.UUID) (*TasksDlq, error)
+//		RequeueDLQ(ctx context.Context, id uuid.UUID, task *Task) error
+//		PurgeDLQ(ctx context.Context, before time.Time) (int64, error)
+//		DeleteDLQ(ctx context.Context, id uuid.UUID) error
+//		AddDLQ(ctx context.Context, entry *TasksDlq) error
+//	}
+//	type CancelRepository interface {
+//		CancelTask(ctx context.Context, taskID uuid.UUID) error
+//		IsCancelRequested(ctx context.Context, taskID uuid.UUID) (bool, error)
+//		MarkCancelled(ctx context.Context, taskID uuid.UUID) error
+//	}
+//	type ResultRepository interface {
+//		GetTaskResult(ctx context.Context, taskID uuid.UUID) (*TaskResult, error)
+//	}
+//	type StatsRepository interface {
+//		CountTasks(ctx context.Context) ([]TaskCount, error)
+//		ListRetryingTasks(ctx context.Context, limit int) ([]*Task, error)
+//	}
+//
 //	// Use queue.NewMemoryStorage() for development
-//	// Implement your own for production (e.g., PostgreSQL, Redis)
+//	// Use integration/queue/pgstorage or integration/queue/redisstorage for production,
+//	// or implement your own
+//
+// The context passed to Enqueue reaches the storage unchanged, so storages can join
+// a transaction it carries: integration/queue/pgstorage inserts the task through a
+// transaction stored with pg.WithTx, making the enqueue atomic with the business write.
 //
 // # Delayed Tasks
 //
@@ -354,6 +655,8 @@
 //		TaskStatusProcessing TaskStatus = "processing" // Currently being processed
 //		TaskStatusCompleted  TaskStatus = "completed"  // Successfully completed
 //		TaskStatusFailed     TaskStatus = "failed"     // Failed (may retry)
+//		TaskStatusWaiting    TaskStatus = "waiting"    // Workflow task waiting for its parent
+//		TaskStatusCancelled  TaskStatus = "cancelled"  // Cancelled with Enqueuer.Cancel
 //	)
 //
 // # Schedule Types
@@ -378,6 +681,26 @@
 //	Weekly(time.Friday)        // Weekly on Friday at midnight
 //	Monthly(15)                // Monthly on 15th at midnight
 //
+// # Cron Schedules
+//
+// Cron parses standard 5-field expressions (or 6 fields with leading seconds)
+// and evaluates them in an explicit location, so runs keep their wall-clock
+// time across DST transitions:
+//
+//	loc, _ := time.LoadLocation("Europe/Berlin")
+//
+//	weekdays, err := queue.Cron("30 9 * * MON-FRI", loc) // Weekdays at 9:30
+//	if err != nil {
+//		return err
+//	}
+//	scheduler.AddTask("standup_reminder", weekdays)
+//
+//	queue.MustCron("0 9 * * 1#1", loc)    // First Monday of the month at 9:00
+//	queue.MustCron("0 18 L * *", loc)     // Last day of the month at 18:00
+//	queue.MustCron("0 8 15W * *", loc)    // Weekday nearest the 15th at 8:00
+//	queue.MustCron("*/10 * * * * *", nil) // Every 10 seconds, UTC
+//	queue.MustCron("@daily", loc)         // Midnight
+//
 // # Configuration Options
 //
 // Common configuration options:
@@ -386,6 +709,7 @@
 //	enqueuer, _ := queue.NewEnqueuer(storage,
 //		queue.WithDefaultQueue("email"),
 //		queue.WithDefaultPriority(queue.PriorityHigh),
+//		queue.WithDefaultResultTTL(24*time.Hour),
 //	)
 //
 //	// Worker options
@@ -394,13 +718,18 @@
 //		queue.WithMaxConcurrentTasks(10),
 //		queue.WithPullInterval(5*time.Second),
 //		queue.WithLockTimeout(5*time.Minute),
+//		queue.WithHeartbeatInterval(time.Minute),
 //		queue.WithShutdownTimeout(60*time.Second),
+//		queue.WithCancelCheckInterval(time.Second),
+//		queue.WithMiddleware(queue.LoggingMiddleware(logger)),
 //	)
 //
 //	// Scheduler options
 //	scheduler, _ := queue.NewScheduler(storage,
 //		queue.WithCheckInterval(30*time.Second),
 //		queue.WithSchedulerShutdownTimeout(60*time.Second),
+//		queue.WithLeaderElector(elector),
+//		queue.WithLeaseTTL(15*time.Second),
 //	)
 //
 //	// Task options
@@ -409,6 +738,8 @@
 //		queue.WithPriority(queue.PriorityMax),
 //		queue.WithMaxRetries(5),
 //		queue.WithDelay(time.Hour),
+//		queue.WithTaskTimeout(10*time.Minute),
+//		queue.WithResultTTL(time.Hour),
 //	)
 //
 // # Observability
@@ -16,9 +16,11 @@ type EnqueuerRepository interface {
 
 // Enqueuer handles task enqueueing with configurable defaults.
 type Enqueuer struct {
-	repo            EnqueuerRepository
-	defaultQueue    string
-	defaultPriority Priority
+	repo             EnqueuerRepository
+	defaultQueue     string
+	defaultPriority  Priority
+	defaultResultTTL time.Duration
+	pollInterval     time.Duration
 }
 
 // NewEnqueuer creates a new Enqueuer with the given repository and options.
@@ -30,6 +32,7 @@ func NewEnqueuer(repo EnqueuerRepository, opts ...EnqueuerOption) (*Enqueuer, er
 	options := &enqueuerOptions{
 		defaultQueue:    DefaultQueueName,
 		defaultPriority: PriorityDefault,
+		pollInterval:    500 * time.Millisecond,
 	}
 
 	for _, opt := range opts {
@@ -37,9 +40,11 @@ func NewEnqueuer(repo EnqueuerRepository, opts ...EnqueuerOption) (*Enqueuer, er
 	}
 
 	return &Enqueuer{
-		repo:            repo,
-		defaultQueue:    options.defaultQueue,
-		defaultPriority: options.defaultPriority,
+		repo:             repo,
+		defaultQueue:     options.defaultQueue,
+		defaultPriority:  options.defaultPriority,
+		defaultResultTTL: options.defaultResultTTL,
+		pollInterval:     options.pollInterval,
 	}, nil
 }
 
@@ -57,15 +62,50 @@ func NewEnqueuerFromConfig(cfg Config, repo EnqueuerRepository, opts ...Enqueuer
 }
 
 // Enqueue adds a new task to the queue with the given payload and options.
-func (e *Enqueuer) Enqueue(ctx context.Context, payload any, opts ...EnqueueOption) error {
+// Returns the task ID, which identifies the task for Status, Result, Await and Cancel.
+// A unique task kept by UniqueConflictKeepExisting returns the ID of the existing task.
+func (e *Enqueuer) Enqueue(ctx context.Context, payload any, opts ...EnqueueOption) (uuid.UUID, error) {
 	if payload == nil {
-		return ErrPayloadNil
+		return uuid.Nil, ErrPayloadNil
 	}
 
+	options, err := e.enqueueOptions(opts)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	task, err := e.buildTask(payload, options)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if task.UniqueKey != nil {
+		repo, ok := e.repo.(UniqueTaskRepository)
+		if !ok {
+			return uuid.Nil, ErrUniqueTasksNotSupported
+		}
+		id, err := repo.CreateUniqueTask(ctx, task, options.onConflict)
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("failed to create unique task %q in queue %q: %w", task.TaskName, task.Queue, err)
+		}
+		return id, nil
+	}
+
+	if err := e.repo.CreateTask(ctx, task); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create task %q in queue %q: %w", task.TaskName, task.Queue, err)
+	}
+
+	return task.ID, nil
+}
+
+// enqueueOptions applies opts on top of the enqueuer defaults.
+func (e *Enqueuer) enqueueOptions(opts []EnqueueOption) (*enqueueOptions, error) {
 	options := &enqueueOptions{
 		queue:      e.defaultQueue,
 		priority:   e.defaultPriority,
 		maxRetries: 3,
+		onConflict: UniqueConflictReject,
+		resultTTL:  e.defaultResultTTL,
 	}
 
 	for _, opt := range opts {
@@ -73,19 +113,10 @@ func (e *Enqueuer) Enqueue(ctx context.Context, payload any, opts ...EnqueueOpti
 	}
 
 	if !options.priority.Valid() {
-		return ErrInvalidPriority
+		return nil, ErrInvalidPriority
 	}
 
-	task, err := e.buildTask(payload, options)
-	if err != nil {
-		return err
-	}
-
-	if err := e.repo.CreateTask(ctx, task); err != nil {
-		return fmt.Errorf("failed to create task %q in queue %q: %w", task.TaskName, task.Queue, err)
-	}
-
-	return nil
+	return options, nil
 }
 
 // buildTask constructs a Task from payload and options.
@@ -100,15 +131,21 @@ func (e *Enqueuer) buildTask(payload any, options *enqueueOptions) (*Task, error
 		taskName = qualifiedStructName(payload)
 	}
 
-	scheduledAt := time.Now()
+	now := time.Now()
+	scheduledAt := now
 	if options.scheduledAt != nil {
 		scheduledAt = *options.scheduledAt
 	} else if options.delay > 0 {
 		scheduledAt = scheduledAt.Add(options.delay)
 	}
 
-	return &Task{
-		ID:          uuid.New(),
+	taskID := options.taskID
+	if taskID == uuid.Nil {
+		taskID = uuid.New()
+	}
+
+	task := &Task{
+		ID:          taskID,
 		Queue:       options.queue,
 		TaskType:    TaskTypeOneTime,
 		TaskName:    taskName,
@@ -118,6 +155,17 @@ func (e *Enqueuer) buildTask(payload any, options *enqueueOptions) (*Task, error
 		RetryCount:  0,
 		MaxRetries:  options.maxRetries,
 		ScheduledAt: scheduledAt,
-		CreatedAt:   time.Now(),
-	}, nil
+		CreatedAt:   now,
+		RetryPolicy: options.retryPolicy,
+		Timeout:     options.timeout,
+		ResultTTL:   options.resultTTL,
+	}
+
+	if options.uniqueKey != "" {
+		uniqueUntil := now.Add(options.uniqueTTL)
+		task.UniqueKey = &options.uniqueKey
+		task.UniqueUntil = &uniqueUntil
+	}
+
+	return task, nil
 }
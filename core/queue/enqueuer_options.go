@@ -1,13 +1,19 @@
 package queue
 
-import "time"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // EnqueuerOption is a functional option for configuring an Enqueuer
 type EnqueuerOption func(*enqueuerOptions)
 
 type enqueuerOptions struct {
-	defaultQueue    string
-	defaultPriority Priority
+	defaultQueue     string
+	defaultPriority  Priority
+	defaultResultTTL time.Duration
+	pollInterval     time.Duration
 }
 
 // WithDefaultQueue sets the default queue for tasks when WithQueue is not specified.
@@ -28,6 +34,25 @@ func WithDefaultPriority(priority Priority) EnqueuerOption {
 	}
 }
 
+// WithDefaultResultTTL sets how long completed tasks and their results are kept
+// when WithResultTTL is not specified. By default they are kept until removed by hand.
+func WithDefaultResultTTL(ttl time.Duration) EnqueuerOption {
+	return func(o *enqueuerOptions) {
+		if ttl > 0 {
+			o.defaultResultTTL = ttl
+		}
+	}
+}
+
+// WithResultPollInterval sets how often Await checks whether a task has finished.
+func WithResultPollInterval(d time.Duration) EnqueuerOption {
+	return func(o *enqueuerOptions) {
+		if d > 0 {
+			o.pollInterval = d
+		}
+	}
+}
+
 // EnqueueOption is a functional option for the Enqueue method
 type EnqueueOption func(*enqueueOptions)
 
@@ -38,6 +63,13 @@ type enqueueOptions struct {
 	delay       time.Duration
 	scheduledAt *time.Time
 	taskName    string
+	retryPolicy *RetryPolicy
+	uniqueKey   string
+	uniqueTTL   time.Duration
+	onConflict  UniqueConflictMode
+	taskID      uuid.UUID
+	timeout     time.Duration
+	resultTTL   time.Duration
 }
 
 // WithQueue overrides the default queue for a specific task.
@@ -90,3 +122,65 @@ func WithTaskName(name string) EnqueueOption {
 		}
 	}
 }
+
+// WithRetryPolicy sets the retry backoff for a specific task,
+// overriding the policy of its handler and the worker default.
+func WithRetryPolicy(policy RetryPolicy) EnqueueOption {
+	return func(o *enqueueOptions) {
+		o.retryPolicy = &policy
+	}
+}
+
+// WithUniqueKey deduplicates the task by key. While another task with the same key
+// is pending or processing, or finished less than ttl after it was enqueued,
+// Enqueue resolves the conflict according to WithUniqueConflictMode (reject by default).
+// The repository must implement UniqueTaskRepository.
+func WithUniqueKey(key string, ttl time.Duration) EnqueueOption {
+	return func(o *enqueueOptions) {
+		if key != "" {
+			o.uniqueKey = key
+			o.uniqueTTL = max(ttl, 0)
+		}
+	}
+}
+
+// WithUniqueConflictMode sets how Enqueue resolves a unique key conflict.
+func WithUniqueConflictMode(mode UniqueConflictMode) EnqueueOption {
+	return func(o *enqueueOptions) {
+		switch mode {
+		case UniqueConflictReject, UniqueConflictReplace, UniqueConflictKeepExisting:
+			o.onConflict = mode
+		}
+	}
+}
+
+// WithTaskID sets the task ID instead of a random one, e.g. to cancel the task later
+// with Enqueuer.Cancel. Creating a task with an ID that is already stored fails.
+func WithTaskID(id uuid.UUID) EnqueueOption {
+	return func(o *enqueueOptions) {
+		if id != uuid.Nil {
+			o.taskID = id
+		}
+	}
+}
+
+// WithTaskTimeout limits a single execution of the task, overriding the handler timeout.
+// The handler context is cancelled when the timeout elapses and the attempt is recorded
+// as a failure wrapping ErrTaskTimeout, so it is retried like any other failure.
+func WithTaskTimeout(timeout time.Duration) EnqueueOption {
+	return func(o *enqueueOptions) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
+
+// WithResultTTL sets how long the completed task and its result are kept for
+// Enqueuer.Status, Result and Await. Overrides WithDefaultResultTTL.
+func WithResultTTL(ttl time.Duration) EnqueueOption {
+	return func(o *enqueueOptions) {
+		if ttl > 0 {
+			o.resultTTL = ttl
+		}
+	}
+}
@@ -82,7 +82,7 @@ func TestEnqueuer_Enqueue(t *testing.T) {
 		require.NoError(t, err)
 
 		payload := enqueueTestPayload{Message: "test", Value: 42}
-		err = enqueuer.Enqueue(context.Background(), payload)
+		_, err = enqueuer.Enqueue(context.Background(), payload)
 		require.NoError(t, err)
 
 		// Verify task was created
@@ -110,7 +110,7 @@ func TestEnqueuer_Enqueue(t *testing.T) {
 		payload := enqueueTestPayload{Message: "custom", Value: 100}
 		scheduledTime := time.Now().Add(time.Hour)
 
-		err = enqueuer.Enqueue(context.Background(), payload,
+		_, err = enqueuer.Enqueue(context.Background(), payload,
 			queue.WithQueue("priority-queue"),
 			queue.WithPriority(queue.PriorityMax),
 			queue.WithMaxRetries(5),
@@ -138,7 +138,7 @@ func TestEnqueuer_Enqueue(t *testing.T) {
 		payload := enqueueTestPayload{Message: "delayed", Value: 1}
 		beforeEnqueue := time.Now()
 
-		err = enqueuer.Enqueue(context.Background(), payload,
+		_, err = enqueuer.Enqueue(context.Background(), payload,
 			queue.WithDelay(30*time.Second),
 		)
 		require.NoError(t, err)
@@ -159,7 +159,7 @@ func TestEnqueuer_Enqueue(t *testing.T) {
 		payload := enqueueTestPayload{Message: "scheduled", Value: 1}
 		scheduledTime := time.Now().Add(2 * time.Hour)
 
-		err = enqueuer.Enqueue(context.Background(), payload,
+		_, err = enqueuer.Enqueue(context.Background(), payload,
 			queue.WithDelay(30*time.Second),      // This should be ignored
 			queue.WithScheduledAt(scheduledTime), // This takes precedence
 		)
@@ -177,7 +177,7 @@ func TestEnqueuer_Enqueue(t *testing.T) {
 		enqueuer, err := queue.NewEnqueuer(repo)
 		require.NoError(t, err)
 
-		err = enqueuer.Enqueue(context.Background(), nil)
+		_, err = enqueuer.Enqueue(context.Background(), nil)
 		assert.ErrorIs(t, err, queue.ErrPayloadNil)
 		assert.Empty(t, repo.tasks)
 	})
@@ -189,7 +189,7 @@ func TestEnqueuer_Enqueue(t *testing.T) {
 		require.NoError(t, err)
 
 		payload := enqueueTestPayload{Message: "invalid", Value: 1}
-		err = enqueuer.Enqueue(context.Background(), payload,
+		_, err = enqueuer.Enqueue(context.Background(), payload,
 			queue.WithPriority(queue.Priority(101)), // Invalid priority
 		)
 		assert.ErrorIs(t, err, queue.ErrInvalidPriority)
@@ -204,7 +204,7 @@ func TestEnqueuer_Enqueue(t *testing.T) {
 
 		// Channel cannot be marshaled to JSON
 		payload := unmarshalablePayload{Ch: make(chan int)}
-		err = enqueuer.Enqueue(context.Background(), payload)
+		_, err = enqueuer.Enqueue(context.Background(), payload)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to marshal payload")
 		assert.Empty(t, repo.tasks)
@@ -222,7 +222,7 @@ func TestEnqueuer_Enqueue(t *testing.T) {
 		require.NoError(t, err)
 
 		payload := enqueueTestPayload{Message: "fail", Value: 1}
-		err = enqueuer.Enqueue(context.Background(), payload)
+		_, err = enqueuer.Enqueue(context.Background(), payload)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to create task")
 		assert.Contains(t, err.Error(), "database connection lost")
@@ -239,7 +239,7 @@ func TestEnqueuer_TaskNameGeneration(t *testing.T) {
 		require.NoError(t, err)
 
 		payload := enqueueTestPayload{Message: "test", Value: 1}
-		err = enqueuer.Enqueue(context.Background(), payload)
+		_, err = enqueuer.Enqueue(context.Background(), payload)
 		require.NoError(t, err)
 
 		require.Len(t, repo.tasks, 1)
@@ -253,7 +253,7 @@ func TestEnqueuer_TaskNameGeneration(t *testing.T) {
 		require.NoError(t, err)
 
 		payload := &enqueueTestPayload{Message: "test", Value: 1}
-		err = enqueuer.Enqueue(context.Background(), payload)
+		_, err = enqueuer.Enqueue(context.Background(), payload)
 		require.NoError(t, err)
 
 		require.Len(t, repo.tasks, 1)
@@ -267,7 +267,7 @@ func TestEnqueuer_TaskNameGeneration(t *testing.T) {
 		require.NoError(t, err)
 
 		payload := map[string]any{"message": "test", "value": 1}
-		err = enqueuer.Enqueue(context.Background(), payload)
+		_, err = enqueuer.Enqueue(context.Background(), payload)
 		require.NoError(t, err)
 
 		require.Len(t, repo.tasks, 1)
@@ -281,7 +281,7 @@ func TestEnqueuer_TaskNameGeneration(t *testing.T) {
 		require.NoError(t, err)
 
 		payload := enqueueTestPayload{Message: "test", Value: 1}
-		err = enqueuer.Enqueue(context.Background(), payload,
+		_, err = enqueuer.Enqueue(context.Background(), payload,
 			queue.WithTaskName("my.custom.TaskName"),
 		)
 		require.NoError(t, err)
@@ -305,7 +305,7 @@ func TestEnqueuer_DefaultConfiguration(t *testing.T) {
 		require.NoError(t, err)
 
 		payload := enqueueTestPayload{Message: "test", Value: 1}
-		err = enqueuer.Enqueue(context.Background(), payload)
+		_, err = enqueuer.Enqueue(context.Background(), payload)
 		require.NoError(t, err)
 
 		// Verify defaults were used
@@ -326,7 +326,7 @@ func TestEnqueuer_DefaultConfiguration(t *testing.T) {
 		require.NoError(t, err)
 
 		payload := enqueueTestPayload{Message: "test", Value: 1}
-		err = enqueuer.Enqueue(context.Background(), payload,
+		_, err = enqueuer.Enqueue(context.Background(), payload,
 			queue.WithQueue("override-queue"),
 			queue.WithPriority(queue.PriorityMax),
 		)
@@ -354,7 +354,7 @@ func TestEnqueuer_PayloadMarshaling(t *testing.T) {
 			Message: "test message with special chars: 👍",
 			Value:   -12345,
 		}
-		err = enqueuer.Enqueue(context.Background(), payload)
+		_, err = enqueuer.Enqueue(context.Background(), payload)
 		require.NoError(t, err)
 
 		// Verify payload was correctly marshaled
@@ -396,7 +396,7 @@ func TestEnqueuer_PayloadMarshaling(t *testing.T) {
 			},
 		}
 
-		err = enqueuer.Enqueue(context.Background(), payload)
+		_, err = enqueuer.Enqueue(context.Background(), payload)
 		require.NoError(t, err)
 
 		// Verify complex payload was marshaled correctly
@@ -19,10 +19,28 @@ var (
 	ErrFailedToUpdateTaskStatus = errors.New("failed to update task status")
 	ErrFailedToMoveToDLQ        = errors.New("failed to move task to dead letter queue")
 	ErrNoTaskToClaim            = errors.New("no task available to claim")
+	ErrDuplicateTask            = errors.New("task with the same unique key already exists")
+	ErrUniqueTasksNotSupported  = errors.New("repository does not support unique tasks")
+	ErrInvalidWorkflow          = errors.New("invalid workflow")
+	ErrWorkflowsNotSupported    = errors.New("repository does not support workflows")
+	ErrNoTaskContext            = errors.New("context does not belong to a running task")
+	ErrNoParentResult           = errors.New("task has no parent result")
+	ErrDLQEntryNotFound         = errors.New("dead letter queue entry not found")
+	ErrTaskNotFound             = errors.New("task not found")
+	ErrTaskNotCancellable       = errors.New("task has already finished and cannot be cancelled")
+	ErrCancelNotSupported       = errors.New("repository does not support task cancellation")
+	ErrTaskCancelled            = errors.New("task cancelled")
+	ErrTaskTimeout              = errors.New("task execution timed out")
+	ErrLockLost                 = errors.New("task lock lost")
+	ErrHandlerPanic             = errors.New("panic in handler")
+	ErrResultsNotSupported      = errors.New("repository does not support task results")
+	ErrResultNotReady           = errors.New("task has not finished yet")
+	ErrTaskFailed               = errors.New("task failed")
 
 	// Lifecycle errors
 	ErrSchedulerAlreadyStarted     = errors.New("scheduler already started")
 	ErrSchedulerNotStarted         = errors.New("scheduler not started")
+	ErrLeadershipLost              = errors.New("scheduler leadership lost")
 	ErrWorkerAlreadyStarted        = errors.New("worker already started")
 	ErrWorkerNotStarted            = errors.New("worker not started")
 	ErrMemoryStorageAlreadyStarted = errors.New("memory storage already started")
@@ -34,4 +52,5 @@ var (
 	ErrWorkerOverloaded    = errors.New("worker overloaded")
 	ErrSchedulerNotRunning = errors.New("scheduler not running")
 	ErrNoTasksRegistered   = errors.New("no tasks registered in scheduler")
+	ErrLeaderElection      = errors.New("scheduler leader election failed")
 )
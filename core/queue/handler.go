@@ -3,6 +3,7 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"time"
 )
 
 type (
@@ -21,41 +22,113 @@ type (
 	// The generic type T represents the expected payload structure.
 	TaskHandlerFunc[T any] func(ctx context.Context, payload T) error
 
+	// ResultTaskHandlerFunc is a type-safe handler function for one-time tasks that
+	// produce a result. The result is stored with the completed task.
+	ResultTaskHandlerFunc[T, R any] func(ctx context.Context, payload T) (R, error)
+
 	// PeriodicTaskHandlerFunc is a handler function for periodic tasks.
 	// Periodic tasks have no payload and are triggered by the scheduler.
 	PeriodicTaskHandlerFunc func(ctx context.Context) error
+
+	// HandlerOption is a functional option for configuring a task handler
+	HandlerOption func(*handlerOptions)
 )
 
+type handlerOptions struct {
+	retryPolicy *RetryPolicy
+	timeout     time.Duration
+	middleware  []Middleware
+}
+
+// configuredHandler exposes registration options of handlers built by this package.
+type configuredHandler interface {
+	handlerOptions() handlerOptions
+}
+
+// WithHandlerRetryPolicy sets the retry backoff for tasks processed by the handler.
+// A policy set on the task itself with WithRetryPolicy takes precedence.
+func WithHandlerRetryPolicy(policy RetryPolicy) HandlerOption {
+	return func(o *handlerOptions) {
+		o.retryPolicy = &policy
+	}
+}
+
+// WithHandlerTimeout limits a single execution of tasks processed by the handler.
+// A timeout set on the task itself with WithTaskTimeout takes precedence.
+func WithHandlerTimeout(timeout time.Duration) HandlerOption {
+	return func(o *handlerOptions) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
+
+// WithHandlerMiddleware wraps the handler with middleware.
+// Handler middleware runs inside the worker middleware, closest to the handler.
+func WithHandlerMiddleware(middleware ...Middleware) HandlerOption {
+	return func(o *handlerOptions) {
+		o.middleware = append(o.middleware, middleware...)
+	}
+}
+
+func newHandlerOptions(opts []HandlerOption) handlerOptions {
+	var options handlerOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
 // NewTaskHandler creates a type-safe handler for one-time tasks.
 // The handler function receives a strongly-typed payload and the task name
 // is automatically derived from the payload type (e.g., "EmailPayload").
-func NewTaskHandler[T any](handler TaskHandlerFunc[T]) Handler {
+func NewTaskHandler[T any](handler TaskHandlerFunc[T], opts ...HandlerOption) Handler {
 	var payload T
 	return &oneTimeTaskHandler[T]{
 		name:    qualifiedStructName(payload),
 		handler: handler,
+		options: newHandlerOptions(opts),
 	}
 }
 
+// NewResultTaskHandler creates a type-safe handler for one-time tasks that return a result.
+// The result is JSON-encoded and stored when the task completes, to be read with
+// Result or Await and passed to the next step of a workflow.
+func NewResultTaskHandler[T, R any](handler ResultTaskHandlerFunc[T, R], opts ...HandlerOption) Handler {
+	return NewTaskHandler(func(ctx context.Context, payload T) error {
+		result, err := handler(ctx, payload)
+		if err != nil {
+			return err
+		}
+		return SetResult(ctx, result)
+	}, opts...)
+}
+
 // NewPeriodicTaskHandler creates a handler for periodic tasks.
 // The name parameter specifies the task name used for scheduling.
 // Periodic tasks have no payload and are triggered by the scheduler.
-func NewPeriodicTaskHandler(name string, handler PeriodicTaskHandlerFunc) Handler {
+func NewPeriodicTaskHandler(name string, handler PeriodicTaskHandlerFunc, opts ...HandlerOption) Handler {
 	return &periodicTaskHandler{
 		name:    name,
 		handler: handler,
+		options: newHandlerOptions(opts),
 	}
 }
 
 type oneTimeTaskHandler[T any] struct {
 	name    string
 	handler TaskHandlerFunc[T]
+	options handlerOptions
 }
 
 func (h *oneTimeTaskHandler[T]) Name() string {
 	return h.name
 }
 
+func (h *oneTimeTaskHandler[T]) handlerOptions() handlerOptions {
+	return h.options
+}
+
 func (h *oneTimeTaskHandler[T]) Handle(ctx context.Context, payload json.RawMessage) error {
 	var t T
 	if err := json.Unmarshal(payload, &t); err != nil {
@@ -67,12 +140,17 @@ func (h *oneTimeTaskHandler[T]) Handle(ctx context.Context, payload json.RawMess
 type periodicTaskHandler struct {
 	name    string
 	handler PeriodicTaskHandlerFunc
+	options handlerOptions
 }
 
 func (h *periodicTaskHandler) Name() string {
 	return h.name
 }
 
+func (h *periodicTaskHandler) handlerOptions() handlerOptions {
+	return h.options
+}
+
 func (h *periodicTaskHandler) Handle(ctx context.Context, _ json.RawMessage) error {
 	return h.handler(ctx)
 }
@@ -0,0 +1,145 @@
+package queue_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+type longPayload struct{}
+
+// lostLockStorage reports every lock extension as lost, as if another worker took the task.
+type lostLockStorage struct {
+	*queue.MemoryStorage
+}
+
+func (s lostLockStorage) ExtendLock(ctx context.Context, taskID, workerID uuid.UUID, duration time.Duration) error {
+	return queue.ErrLockLost
+}
+
+func TestWorker_LockHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	run := func(t *testing.T, worker *queue.Worker) context.Context {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		errCh := make(chan error, 1)
+		go func() { errCh <- worker.Run(ctx)() }()
+		t.Cleanup(func() {
+			cancel()
+			require.NoError(t, <-errCh)
+		})
+		return ctx
+	}
+
+	t.Run("task outlives lock timeout without being reclaimed", func(t *testing.T) {
+		t.Parallel()
+
+		storage := queue.NewMemoryStorage(queue.WithLockCheckInterval(5 * time.Millisecond))
+		storageCtx, cancelStorage := context.WithCancel(context.Background())
+		defer cancelStorage()
+		go func() { _ = storage.Start(storageCtx) }()
+
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+
+		var runs atomic.Int32
+		worker, err := queue.NewWorker(storage,
+			queue.WithPullInterval(5*time.Millisecond),
+			queue.WithMaxConcurrentTasks(2),
+			queue.WithLockTimeout(30*time.Millisecond),
+			// Execution is bounded separately from the lock, which defaults to the lock timeout
+			queue.WithDefaultTaskTimeout(time.Second),
+			queue.WithWorkerLogger(logger),
+		)
+		require.NoError(t, err)
+		require.NoError(t, worker.RegisterHandler(queue.NewTaskHandler(func(ctx context.Context, _ longPayload) error {
+			runs.Add(1)
+			select {
+			case <-time.After(150 * time.Millisecond):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})))
+		ctx := run(t, worker)
+
+		_, err = enqueuer.Enqueue(ctx, longPayload{})
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			return worker.Stats().TasksProcessed == 1
+		}, 2*time.Second, 5*time.Millisecond)
+		assert.Equal(t, int32(1), runs.Load(), "task ran only once")
+	})
+
+	t.Run("lost lock cancels handler context", func(t *testing.T) {
+		t.Parallel()
+
+		storage := lostLockStorage{queue.NewMemoryStorage()}
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+
+		cause := make(chan error, 1)
+		worker, err := queue.NewWorker(storage,
+			queue.WithPullInterval(5*time.Millisecond),
+			queue.WithLockTimeout(time.Minute),
+			queue.WithHeartbeatInterval(10*time.Millisecond),
+			queue.WithWorkerLogger(logger),
+		)
+		require.NoError(t, err)
+		require.NoError(t, worker.RegisterHandler(queue.NewTaskHandler(func(ctx context.Context, _ longPayload) error {
+			<-ctx.Done()
+			cause <- context.Cause(ctx)
+			return ctx.Err()
+		})))
+		ctx := run(t, worker)
+
+		_, err = enqueuer.Enqueue(ctx, longPayload{})
+		require.NoError(t, err)
+
+		select {
+		case err := <-cause:
+			assert.ErrorIs(t, err, queue.ErrLockLost)
+		case <-ctx.Done():
+			t.Fatal("handler was not cancelled")
+		}
+
+		stats := worker.Stats()
+		assert.Zero(t, stats.TasksProcessed)
+		assert.Zero(t, stats.TasksFailed, "the new lock owner decides the task outcome")
+	})
+}
+
+func TestMemoryStorage_ExtendLockOwnership(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage := queue.NewMemoryStorage()
+	enqueuer, err := queue.NewEnqueuer(storage)
+	require.NoError(t, err)
+	_, err = enqueuer.Enqueue(ctx, longPayload{})
+	require.NoError(t, err)
+
+	workerID := uuid.New()
+	task, err := storage.ClaimTask(ctx, workerID, []string{queue.DefaultQueueName}, time.Minute)
+	require.NoError(t, err)
+
+	assert.NoError(t, storage.ExtendLock(ctx, task.ID, workerID, time.Minute))
+	assert.ErrorIs(t, storage.ExtendLock(ctx, task.ID, uuid.New(), time.Minute), queue.ErrLockLost)
+	assert.ErrorIs(t, storage.ExtendLock(ctx, uuid.New(), workerID, time.Minute), queue.ErrLockLost)
+
+	require.NoError(t, storage.CompleteTask(ctx, task.ID, nil))
+	assert.ErrorIs(t, storage.ExtendLock(ctx, task.ID, workerID, time.Minute), queue.ErrLockLost)
+}
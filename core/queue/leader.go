@@ -0,0 +1,89 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LeaderElector grants a time-limited leadership lease to one instance in a cluster.
+// The Scheduler only creates periodic tasks while its elector holds the lease.
+//
+// Each LeaderElector value represents one candidate; create one per Scheduler.
+type LeaderElector interface {
+	// TryAcquire acquires the lease if it is free or expired, or renews it if the
+	// candidate already holds it. Reports whether the candidate holds the lease
+	// for the next ttl.
+	//
+	// Other errors leave the outcome unknown, and the Scheduler keeps leading
+	// until the lease it was granted runs out. Return an error wrapping
+	// ErrLeadershipLost when the lease is known to be gone already, such as a
+	// lock bound to a session that ended, so the Scheduler steps down at once.
+	TryAcquire(ctx context.Context, ttl time.Duration) (bool, error)
+
+	// Release gives the lease up so another candidate can take over right away.
+	// Releasing a lease the candidate does not hold is a no-op.
+	Release(ctx context.Context) error
+}
+
+// MemoryLease is an in-process leadership lease shared by several candidates.
+// Useful for tests and for running several schedulers in one process.
+type MemoryLease struct {
+	mu        sync.Mutex
+	holder    uuid.UUID
+	expiresAt time.Time
+}
+
+// NewMemoryLease creates a free in-memory lease.
+func NewMemoryLease() *MemoryLease {
+	return &MemoryLease{}
+}
+
+// NewElector returns a new candidate competing for the lease.
+func (l *MemoryLease) NewElector() LeaderElector {
+	return &memoryElector{lease: l, id: uuid.New()}
+}
+
+// Holder returns the candidate holding an unexpired lease, or uuid.Nil.
+func (l *MemoryLease) Holder() uuid.UUID {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if time.Now().After(l.expiresAt) {
+		return uuid.Nil
+	}
+	return l.holder
+}
+
+// memoryElector is a candidate for a MemoryLease.
+type memoryElector struct {
+	lease *MemoryLease
+	id    uuid.UUID
+}
+
+func (e *memoryElector) TryAcquire(_ context.Context, ttl time.Duration) (bool, error) {
+	e.lease.mu.Lock()
+	defer e.lease.mu.Unlock()
+
+	now := time.Now()
+	if e.lease.holder != e.id && e.lease.holder != uuid.Nil && now.Before(e.lease.expiresAt) {
+		return false, nil
+	}
+
+	e.lease.holder = e.id
+	e.lease.expiresAt = now.Add(ttl)
+	return true, nil
+}
+
+func (e *memoryElector) Release(_ context.Context) error {
+	e.lease.mu.Lock()
+	defer e.lease.mu.Unlock()
+
+	if e.lease.holder == e.id {
+		e.lease.holder = uuid.Nil
+		e.lease.expiresAt = time.Time{}
+	}
+	return nil
+}
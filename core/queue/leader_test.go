@@ -0,0 +1,221 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+// failingElector simulates an unreachable election backend
+type failingElector struct {
+	fail atomic.Bool
+}
+
+func (e *failingElector) TryAcquire(_ context.Context, _ time.Duration) (bool, error) {
+	if e.fail.Load() {
+		return false, errors.New("backend unavailable")
+	}
+	return true, nil
+}
+
+func (e *failingElector) Release(_ context.Context) error { return nil }
+
+// sessionElector simulates a lock bound to a session that ends, as the Postgres advisory lock
+type sessionElector struct {
+	lost atomic.Bool
+}
+
+func (e *sessionElector) TryAcquire(_ context.Context, _ time.Duration) (bool, error) {
+	if e.lost.Load() {
+		return false, fmt.Errorf("%w: session closed", queue.ErrLeadershipLost)
+	}
+	return true, nil
+}
+
+func (e *sessionElector) Release(_ context.Context) error { return nil }
+
+func TestMemoryLease(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("one holder at a time", func(t *testing.T) {
+		t.Parallel()
+
+		lease := queue.NewMemoryLease()
+		a, b := lease.NewElector(), lease.NewElector()
+
+		ok, err := a.TryAcquire(ctx, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.NotEqual(t, uuid.Nil, lease.Holder())
+
+		ok, err = b.TryAcquire(ctx, time.Minute)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = a.TryAcquire(ctx, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, ok, "holder renews its lease")
+
+		require.NoError(t, b.Release(ctx), "releasing a lease not held is a no-op")
+		require.NoError(t, a.Release(ctx))
+		assert.Equal(t, uuid.Nil, lease.Holder())
+
+		ok, err = b.TryAcquire(ctx, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("expired lease is taken over", func(t *testing.T) {
+		t.Parallel()
+
+		lease := queue.NewMemoryLease()
+		a, b := lease.NewElector(), lease.NewElector()
+
+		ok, err := a.TryAcquire(ctx, 10*time.Millisecond)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		require.Eventually(t, func() bool {
+			ok, err := b.TryAcquire(ctx, time.Minute)
+			return err == nil && ok
+		}, time.Second, 5*time.Millisecond)
+	})
+}
+
+func TestScheduler_LeaderElection(t *testing.T) {
+	t.Parallel()
+
+	newScheduler := func(t *testing.T, repo queue.SchedulerRepository, elector queue.LeaderElector) *queue.Scheduler {
+		t.Helper()
+		scheduler, err := queue.NewScheduler(repo,
+			queue.WithCheckInterval(10*time.Millisecond),
+			queue.WithLeaderElector(elector),
+			queue.WithLeaseTTL(60*time.Millisecond),
+		)
+		require.NoError(t, err)
+		require.NoError(t, scheduler.AddTask("report", queue.EveryInterval(10*time.Millisecond)))
+		return scheduler
+	}
+
+	start := func(t *testing.T, scheduler *queue.Scheduler) context.CancelFunc {
+		t.Helper()
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_ = scheduler.Run(ctx)()
+		}()
+		t.Cleanup(func() {
+			cancel()
+			<-done
+		})
+		return func() {
+			cancel()
+			<-done
+		}
+	}
+
+	t.Run("only the leader schedules and a standby takes over", func(t *testing.T) {
+		t.Parallel()
+
+		lease := queue.NewMemoryLease()
+		leaderRepo, standbyRepo := newMockSchedulerRepo(), newMockSchedulerRepo()
+		leader := newScheduler(t, leaderRepo, lease.NewElector())
+		standby := newScheduler(t, standbyRepo, lease.NewElector())
+
+		stopLeader := start(t, leader)
+		require.Eventually(t, func() bool {
+			return leader.Stats().IsLeader
+		}, time.Second, 5*time.Millisecond)
+
+		start(t, standby)
+		require.Eventually(t, func() bool {
+			return leaderRepo.countTasksByName("report") > 0
+		}, time.Second, 5*time.Millisecond)
+
+		time.Sleep(50 * time.Millisecond)
+		assert.False(t, standby.Stats().IsLeader)
+		assert.Zero(t, standbyRepo.countTasksByName("report"), "standby must not create periodic tasks")
+		assert.NoError(t, standby.Healthcheck(context.Background()), "standby is healthy")
+
+		stopLeader()
+
+		require.Eventually(t, func() bool {
+			return standby.Stats().IsLeader && standbyRepo.countTasksByName("report") > 0
+		}, time.Second, 5*time.Millisecond)
+		assert.False(t, standby.Stats().LeaderSince.IsZero())
+	})
+
+	t.Run("election failure is reported by healthcheck", func(t *testing.T) {
+		t.Parallel()
+
+		elector := &failingElector{}
+		scheduler := newScheduler(t, newMockSchedulerRepo(), elector)
+		start(t, scheduler)
+
+		require.Eventually(t, func() bool {
+			return scheduler.Stats().IsLeader
+		}, time.Second, 5*time.Millisecond)
+		require.NoError(t, scheduler.Healthcheck(context.Background()))
+
+		elector.fail.Store(true)
+
+		require.Eventually(t, func() bool {
+			return errors.Is(scheduler.Healthcheck(context.Background()), queue.ErrLeaderElection)
+		}, time.Second, 5*time.Millisecond)
+		require.Eventually(t, func() bool {
+			return !scheduler.Stats().IsLeader
+		}, time.Second, 5*time.Millisecond, "leadership ends when the lease cannot be renewed")
+	})
+
+	t.Run("lost session steps down at once", func(t *testing.T) {
+		t.Parallel()
+
+		elector := &sessionElector{}
+		scheduler, err := queue.NewScheduler(newMockSchedulerRepo(),
+			queue.WithCheckInterval(10*time.Millisecond),
+			queue.WithLeaderElector(elector),
+			queue.WithLeaseTTL(900*time.Millisecond),
+		)
+		require.NoError(t, err)
+		require.NoError(t, scheduler.AddTask("report", queue.EveryInterval(time.Minute)))
+		start(t, scheduler)
+
+		require.Eventually(t, func() bool {
+			return scheduler.Stats().IsLeader
+		}, time.Second, 5*time.Millisecond)
+
+		elector.lost.Store(true)
+
+		// The next renewal (every 300ms) steps down, well before the 900ms lease would run out
+		require.Eventually(t, func() bool {
+			return !scheduler.Stats().IsLeader
+		}, 500*time.Millisecond, 5*time.Millisecond, "a standby may already hold the released lock")
+	})
+
+	t.Run("without elector the running scheduler leads", func(t *testing.T) {
+		t.Parallel()
+
+		scheduler, err := queue.NewScheduler(newMockSchedulerRepo(), queue.WithCheckInterval(10*time.Millisecond))
+		require.NoError(t, err)
+		require.NoError(t, scheduler.AddTask("report", queue.EveryInterval(time.Minute)))
+		assert.False(t, scheduler.Stats().IsLeader)
+
+		start(t, scheduler)
+		require.Eventually(t, func() bool {
+			return scheduler.Stats().IsLeader
+		}, time.Second, 5*time.Millisecond)
+		assert.True(t, scheduler.Stats().LeaderSince.IsZero())
+	})
+}
@@ -2,10 +2,12 @@ package queue
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"slices"
 	"sync"
 	"sync/atomic"
@@ -29,8 +31,12 @@ type MemoryStorage struct {
 	dlq   map[uuid.UUID]*TasksDlq
 
 	// Indexes for efficient queries
-	byQueue  map[string][]uuid.UUID
-	byStatus map[TaskStatus][]uuid.UUID
+	byQueue     map[string][]uuid.UUID
+	byStatus    map[TaskStatus][]uuid.UUID
+	byUniqueKey map[string]uuid.UUID
+	byParent    map[uuid.UUID][]uuid.UUID // Workflow tasks by the task, group or workflow they wait for
+	byGroup     map[uuid.UUID][]uuid.UUID
+	byWorkflow  map[uuid.UUID][]uuid.UUID
 
 	// Configuration
 	lockCheckInterval time.Duration
@@ -86,6 +92,10 @@ func NewMemoryStorage(opts ...MemoryStorageOption) *MemoryStorage {
 		dlq:               make(map[uuid.UUID]*TasksDlq),
 		byQueue:           make(map[string][]uuid.UUID),
 		byStatus:          make(map[TaskStatus][]uuid.UUID),
+		byUniqueKey:       make(map[string]uuid.UUID),
+		byParent:          make(map[uuid.UUID][]uuid.UUID),
+		byGroup:           make(map[uuid.UUID][]uuid.UUID),
+		byWorkflow:        make(map[uuid.UUID][]uuid.UUID),
 		lockCheckInterval: time.Second,
 		shutdownTimeout:   30 * time.Second,
 		logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
@@ -117,13 +127,110 @@ func (ms *MemoryStorage) CreateTask(ctx context.Context, task *Task) error {
 		return fmt.Errorf("task with ID %s already exists", task.ID)
 	}
 
+	ms.insertTask(task)
+
+	return nil
+}
+
+// CreateUniqueTask stores a new task unless its unique key is held by another task.
+// The check and the insert happen under one lock, so concurrent enqueues of the
+// same key never both succeed.
+func (ms *MemoryStorage) CreateUniqueTask(ctx context.Context, task *Task, mode UniqueConflictMode) (uuid.UUID, error) {
+	if task == nil {
+		return uuid.Nil, errors.New("task cannot be nil")
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, exists := ms.tasks[task.ID]; exists {
+		return uuid.Nil, fmt.Errorf("task with ID %s already exists", task.ID)
+	}
+
+	if task.UniqueKey != nil {
+		key := *task.UniqueKey
+		holder, exists := ms.tasks[ms.byUniqueKey[key]]
+		if exists && holder.holdsUniqueKey(time.Now()) {
+			switch mode {
+			case UniqueConflictKeepExisting:
+				return holder.ID, nil
+			case UniqueConflictReplace:
+				if holder.Status == TaskStatusProcessing {
+					return uuid.Nil, fmt.Errorf("%w: %q is held by running task %s", ErrDuplicateTask, key, holder.ID)
+				}
+				if holder.Status == TaskStatusPending {
+					ms.deleteTask(holder)
+				}
+			default:
+				return uuid.Nil, fmt.Errorf("%w: %q", ErrDuplicateTask, key)
+			}
+		}
+	}
+
+	ms.insertTask(task)
+
+	return task.ID, nil
+}
+
+// CreateWorkflow stores all tasks of a workflow under one lock.
+func (ms *MemoryStorage) CreateWorkflow(ctx context.Context, tasks []*Task) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	for _, task := range tasks {
+		if task == nil {
+			return errors.New("task cannot be nil")
+		}
+		if _, exists := ms.tasks[task.ID]; exists {
+			return fmt.Errorf("task with ID %s already exists", task.ID)
+		}
+	}
+
+	for _, task := range tasks {
+		ms.insertTask(task)
+	}
+
+	return nil
+}
+
+// insertTask stores a copy of the task and indexes it. Caller must hold the write lock.
+func (ms *MemoryStorage) insertTask(task *Task) {
 	taskCopy := *task
 	ms.tasks[task.ID] = &taskCopy
 
 	ms.byQueue[task.Queue] = append(ms.byQueue[task.Queue], task.ID)
 	ms.byStatus[task.Status] = append(ms.byStatus[task.Status], task.ID)
+	if task.UniqueKey != nil {
+		ms.byUniqueKey[*task.UniqueKey] = task.ID
+	}
+	if task.ParentID != nil {
+		ms.byParent[*task.ParentID] = append(ms.byParent[*task.ParentID], task.ID)
+	}
+	if task.GroupID != nil {
+		ms.byGroup[*task.GroupID] = append(ms.byGroup[*task.GroupID], task.ID)
+	}
+	if task.WorkflowID != nil {
+		ms.byWorkflow[*task.WorkflowID] = append(ms.byWorkflow[*task.WorkflowID], task.ID)
+	}
+}
 
-	return nil
+// deleteTask removes the task and its index entries. Caller must hold the write lock.
+func (ms *MemoryStorage) deleteTask(task *Task) {
+	ms.removeFromStatusIndex(task.ID, task.Status)
+	ms.removeFromQueueIndex(task.ID, task.Queue)
+	if task.UniqueKey != nil && ms.byUniqueKey[*task.UniqueKey] == task.ID {
+		delete(ms.byUniqueKey, *task.UniqueKey)
+	}
+	if task.ParentID != nil {
+		ms.byParent[*task.ParentID] = removeID(ms.byParent[*task.ParentID], task.ID)
+	}
+	if task.GroupID != nil {
+		ms.byGroup[*task.GroupID] = removeID(ms.byGroup[*task.GroupID], task.ID)
+	}
+	if task.WorkflowID != nil {
+		ms.byWorkflow[*task.WorkflowID] = removeID(ms.byWorkflow[*task.WorkflowID], task.ID)
+	}
+	delete(ms.tasks, task.ID)
 }
 
 // ClaimTask atomically claims the next highest-priority eligible task.
@@ -183,8 +290,9 @@ func (ms *MemoryStorage) ClaimTask(ctx context.Context, workerID uuid.UUID, queu
 	return &taskCopy, nil
 }
 
-// CompleteTask marks a task as successfully completed.
-func (ms *MemoryStorage) CompleteTask(ctx context.Context, taskID uuid.UUID) error {
+// CompleteTask marks a task as successfully completed and activates the
+// workflow tasks waiting for it.
+func (ms *MemoryStorage) CompleteTask(ctx context.Context, taskID uuid.UUID, result []byte) error {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
 
@@ -202,15 +310,27 @@ func (ms *MemoryStorage) CompleteTask(ctx context.Context, taskID uuid.UUID) err
 	task.ProcessedAt = &now
 	task.LockedUntil = nil
 	task.LockedBy = nil
+	task.Result = result
 
 	ms.removeFromStatusIndex(taskID, TaskStatusProcessing)
 	ms.byStatus[TaskStatusCompleted] = append(ms.byStatus[TaskStatusCompleted], taskID)
 
+	ms.activateChildren(taskID, result, now)
+	if task.GroupID != nil {
+		if results, done := ms.groupResults(*task.GroupID); done {
+			ms.activateChildren(*task.GroupID, results, now)
+		}
+	}
+	if task.WorkflowID != nil && ms.workflowFinished(*task.WorkflowID) {
+		// Error callbacks wait for the workflow ID and are no longer needed
+		ms.dropWaiting(ms.byParent[*task.WorkflowID])
+	}
+
 	return nil
 }
 
-// FailTask records a task failure and resets to pending for retry if retries remain.
-func (ms *MemoryStorage) FailTask(ctx context.Context, taskID uuid.UUID, errorMsg string) error {
+// FailTask records a task failure and reschedules it after retryDelay if retries remain.
+func (ms *MemoryStorage) FailTask(ctx context.Context, taskID uuid.UUID, errorMsg string, retryDelay time.Duration) error {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
 
@@ -236,13 +356,7 @@ func (ms *MemoryStorage) FailTask(ctx context.Context, taskID uuid.UUID, errorMs
 		task.Status = TaskStatusPending
 		ms.removeFromStatusIndex(taskID, TaskStatusProcessing)
 		ms.byStatus[TaskStatusPending] = append(ms.byStatus[TaskStatusPending], taskID)
-
-		// Retry backoff strategy: Linear progression prevents system overload
-		// Formula: retryCount * 30s (30s, 60s, 90s, 120s...)
-		// Rationale: Faster than exponential for transient issues,
-		// but still protects against persistent failures causing thundering herd
-		backoff := time.Duration(task.RetryCount) * 30 * time.Second
-		task.ScheduledAt = time.Now().Add(backoff)
+		task.ScheduledAt = time.Now().Add(retryDelay)
 	}
 
 	return nil
@@ -277,28 +391,305 @@ func (ms *MemoryStorage) MoveToDLQ(ctx context.Context, taskID uuid.UUID) error
 	}
 
 	ms.dlq[dlqEntry.ID] = dlqEntry
+	ms.deleteTask(task)
+
+	// The rest of the workflow can never run: drop it and hand the failure to the error callbacks
+	if task.WorkflowID != nil {
+		workflowID := *task.WorkflowID
+		ms.dropWaiting(ms.byWorkflow[workflowID])
+
+		failure, err := json.Marshal(WorkflowFailure{
+			WorkflowID: workflowID,
+			TaskID:     task.ID,
+			TaskName:   task.TaskName,
+			Error:      dlqEntry.Error,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal workflow failure: %w", err)
+		}
+		ms.activateChildren(workflowID, failure, dlqEntry.FailedAt)
+	}
+
+	return nil
+}
+
+// CancelTask cancels a pending or waiting task, or flags a processing one for its worker.
+func (ms *MemoryStorage) CancelTask(ctx context.Context, taskID uuid.UUID) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	task, exists := ms.tasks[taskID]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, taskID)
+	}
 
-	ms.removeFromStatusIndex(taskID, task.Status)
-	ms.removeFromQueueIndex(taskID, task.Queue)
-	delete(ms.tasks, taskID)
+	switch task.Status {
+	case TaskStatusPending, TaskStatusWaiting:
+		ms.cancelTask(task)
+	case TaskStatusProcessing:
+		task.CancelRequested = true
+	default:
+		return fmt.Errorf("%w: %s is %s", ErrTaskNotCancellable, taskID, task.Status)
+	}
 
 	return nil
 }
 
-// ExtendLock extends the lock duration for a long-running task.
-func (ms *MemoryStorage) ExtendLock(ctx context.Context, taskID uuid.UUID, duration time.Duration) error {
+// IsCancelRequested reports whether the task was cancelled or flagged for cancellation.
+func (ms *MemoryStorage) IsCancelRequested(ctx context.Context, taskID uuid.UUID) (bool, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	task, exists := ms.tasks[taskID]
+	if !exists {
+		return false, fmt.Errorf("%w: %s", ErrTaskNotFound, taskID)
+	}
+
+	return task.CancelRequested || task.Status == TaskStatusCancelled, nil
+}
+
+// MarkCancelled finishes a processing task as cancelled.
+func (ms *MemoryStorage) MarkCancelled(ctx context.Context, taskID uuid.UUID) error {
 	ms.mu.Lock()
 	defer ms.mu.Unlock()
 
 	task, exists := ms.tasks[taskID]
 	if !exists {
-		return fmt.Errorf("task %s not found", taskID)
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, taskID)
 	}
 
 	if task.Status != TaskStatusProcessing {
 		return fmt.Errorf("task %s is not in processing state", taskID)
 	}
 
+	ms.cancelTask(task)
+
+	return nil
+}
+
+// cancelTask finishes the task as cancelled, releases its unique key and drops the
+// waiting rest of its workflow. Caller must hold the write lock.
+func (ms *MemoryStorage) cancelTask(task *Task) {
+	now := time.Now()
+	ms.removeFromStatusIndex(task.ID, task.Status)
+	ms.byStatus[TaskStatusCancelled] = append(ms.byStatus[TaskStatusCancelled], task.ID)
+
+	task.Status = TaskStatusCancelled
+	task.ProcessedAt = &now
+	task.LockedUntil = nil
+	task.LockedBy = nil
+	task.UniqueUntil = nil
+	task.CancelRequested = false
+
+	if task.WorkflowID != nil {
+		ms.dropWaiting(ms.byWorkflow[*task.WorkflowID])
+		ms.dropWaiting(ms.byParent[*task.WorkflowID])
+	}
+}
+
+// ListDLQ returns the dead letter queue entries matching filter, most recently failed first.
+func (ms *MemoryStorage) ListDLQ(ctx context.Context, filter DLQFilter) ([]*TasksDlq, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	entries := make([]*TasksDlq, 0, len(ms.dlq))
+	for _, entry := range ms.dlq {
+		if filter.Matches(entry) {
+			entryCopy := *entry
+			entries = append(entries, &entryCopy)
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b *TasksDlq) int {
+		return b.FailedAt.Compare(a.FailedAt)
+	})
+
+	if filter.Offset > 0 {
+		entries = entries[min(filter.Offset, len(entries)):]
+	}
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+
+	return entries, nil
+}
+
+// GetDLQ returns a single dead letter queue entry.
+func (ms *MemoryStorage) GetDLQ(ctx context.Context, id uuid.UUID) (*TasksDlq, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	entry, exists := ms.dlq[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrDLQEntryNotFound, id)
+	}
+
+	entryCopy := *entry
+	return &entryCopy, nil
+}
+
+// RequeueDLQ removes the dead letter queue entry and stores task in its place under one lock.
+func (ms *MemoryStorage) RequeueDLQ(ctx context.Context, id uuid.UUID, task *Task) error {
+	if task == nil {
+		return errors.New("task cannot be nil")
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, exists := ms.dlq[id]; !exists {
+		return fmt.Errorf("%w: %s", ErrDLQEntryNotFound, id)
+	}
+	if _, exists := ms.tasks[task.ID]; exists {
+		return fmt.Errorf("task with ID %s already exists", task.ID)
+	}
+
+	ms.insertTask(task)
+	delete(ms.dlq, id)
+
+	return nil
+}
+
+// PurgeDLQ deletes dead letter queue entries that failed before the given time.
+func (ms *MemoryStorage) PurgeDLQ(ctx context.Context, before time.Time) (int64, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	var purged int64
+	for id, entry := range ms.dlq {
+		if entry.FailedAt.Before(before) {
+			delete(ms.dlq, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// DeleteDLQ deletes a single dead letter queue entry.
+func (ms *MemoryStorage) DeleteDLQ(ctx context.Context, id uuid.UUID) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, exists := ms.dlq[id]; !exists {
+		return fmt.Errorf("%w: %s", ErrDLQEntryNotFound, id)
+	}
+	delete(ms.dlq, id)
+
+	return nil
+}
+
+// AddDLQ stores a dead letter queue entry for a failure outside the worker.
+func (ms *MemoryStorage) AddDLQ(ctx context.Context, entry *TasksDlq) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	stored := *entry
+	ms.dlq[stored.ID] = &stored
+
+	return nil
+}
+
+// CountTasks returns the number of stored tasks by queue and status.
+func (ms *MemoryStorage) CountTasks(ctx context.Context) ([]TaskCount, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var counts []TaskCount
+	for _, queue := range slices.Sorted(maps.Keys(ms.byQueue)) {
+		byStatus := make(map[TaskStatus]int64)
+		for _, taskID := range ms.byQueue[queue] {
+			byStatus[ms.tasks[taskID].Status]++
+		}
+		for _, status := range slices.Sorted(maps.Keys(byStatus)) {
+			counts = append(counts, TaskCount{Queue: queue, Status: status, Count: byStatus[status]})
+		}
+	}
+
+	return counts, nil
+}
+
+// ListRetryingTasks returns pending tasks whose last attempt failed, soonest retry first.
+func (ms *MemoryStorage) ListRetryingTasks(ctx context.Context, limit int) ([]*Task, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	var tasks []*Task
+	for _, taskID := range ms.byStatus[TaskStatusPending] {
+		if task := ms.tasks[taskID]; task.Error != nil {
+			taskCopy := *task
+			tasks = append(tasks, &taskCopy)
+		}
+	}
+
+	slices.SortFunc(tasks, func(a, b *Task) int {
+		return a.ScheduledAt.Compare(b.ScheduledAt)
+	})
+	if limit > 0 && len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+
+	return tasks, nil
+}
+
+// GetTaskResult returns the status and result of a task, falling back to the
+// dead letter queue for tasks that exhausted their retries.
+func (ms *MemoryStorage) GetTaskResult(ctx context.Context, taskID uuid.UUID) (*TaskResult, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	if task, exists := ms.tasks[taskID]; exists {
+		if resultExpired(task, time.Now()) {
+			return nil, ErrTaskNotFound
+		}
+
+		result := &TaskResult{
+			TaskID:      task.ID,
+			Status:      task.Status,
+			Result:      task.Result,
+			ProcessedAt: task.ProcessedAt,
+		}
+		if task.Error != nil {
+			result.Error = *task.Error
+		}
+		return result, nil
+	}
+
+	var latest *TasksDlq
+	for _, entry := range ms.dlq {
+		if entry.TaskID == taskID && (latest == nil || entry.FailedAt.After(latest.FailedAt)) {
+			latest = entry
+		}
+	}
+	if latest == nil {
+		return nil, ErrTaskNotFound
+	}
+
+	return &TaskResult{
+		TaskID:      taskID,
+		Status:      TaskStatusFailed,
+		Error:       latest.Error,
+		ProcessedAt: &latest.FailedAt,
+	}, nil
+}
+
+// ExtendLock extends the lock duration for a long-running task.
+func (ms *MemoryStorage) ExtendLock(ctx context.Context, taskID, workerID uuid.UUID, duration time.Duration) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	task, exists := ms.tasks[taskID]
+	if !exists {
+		return fmt.Errorf("%w: task %s not found", ErrLockLost, taskID)
+	}
+
+	if task.Status != TaskStatusProcessing {
+		return fmt.Errorf("%w: task %s is not in processing state", ErrLockLost, taskID)
+	}
+	if task.LockedBy == nil || *task.LockedBy != workerID {
+		return fmt.Errorf("%w: task %s is locked by another worker", ErrLockLost, taskID)
+	}
+
 	lockUntil := time.Now().Add(duration)
 	task.LockedUntil = &lockUntil
 
@@ -321,6 +712,82 @@ func (ms *MemoryStorage) GetPendingTaskByName(ctx context.Context, taskName stri
 	return nil, nil
 }
 
+// activateChildren makes the tasks waiting for parentID pending, handing over parentResult.
+// Step delays count from activation. Caller must hold the write lock.
+func (ms *MemoryStorage) activateChildren(parentID uuid.UUID, parentResult []byte, now time.Time) {
+	for _, id := range ms.byParent[parentID] {
+		child, exists := ms.tasks[id]
+		if !exists || child.Status != TaskStatusWaiting {
+			continue
+		}
+
+		child.Status = TaskStatusPending
+		child.ParentResult = parentResult
+		child.ScheduledAt = now.Add(child.ScheduledAt.Sub(child.CreatedAt))
+
+		ms.removeFromStatusIndex(id, TaskStatusWaiting)
+		ms.byStatus[TaskStatusPending] = append(ms.byStatus[TaskStatusPending], id)
+	}
+}
+
+// groupResults returns the member results as a JSON array in completion order,
+// and whether every member has completed. Caller must hold the lock.
+func (ms *MemoryStorage) groupResults(groupID uuid.UUID) ([]byte, bool) {
+	members := make([]*Task, 0, len(ms.byGroup[groupID]))
+	for _, id := range ms.byGroup[groupID] {
+		member := ms.tasks[id]
+		if member.Status != TaskStatusCompleted {
+			return nil, false
+		}
+		members = append(members, member)
+	}
+
+	slices.SortStableFunc(members, func(a, b *Task) int {
+		return a.ProcessedAt.Compare(*b.ProcessedAt)
+	})
+
+	results := make([]json.RawMessage, len(members))
+	for i, member := range members {
+		results[i] = member.Result
+	}
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		// Results were produced by json.Marshal, so this only happens on corrupted data
+		ms.logger.Error("failed to marshal group results",
+			slog.String("group_id", groupID.String()),
+			slog.String("error", err.Error()))
+		return nil, true
+	}
+	return data, true
+}
+
+// workflowFinished reports whether no task of the workflow is left to run. Caller must hold the lock.
+func (ms *MemoryStorage) workflowFinished(workflowID uuid.UUID) bool {
+	for _, id := range ms.byWorkflow[workflowID] {
+		switch ms.tasks[id].Status {
+		case TaskStatusWaiting, TaskStatusPending, TaskStatusProcessing:
+			return false
+		}
+	}
+	return true
+}
+
+// dropWaiting deletes the waiting tasks among ids. Caller must hold the write lock.
+func (ms *MemoryStorage) dropWaiting(ids []uuid.UUID) {
+	for _, id := range slices.Clone(ids) {
+		if task, exists := ms.tasks[id]; exists && task.Status == TaskStatusWaiting {
+			ms.deleteTask(task)
+		}
+	}
+}
+
+func removeID(ids []uuid.UUID, id uuid.UUID) []uuid.UUID {
+	return slices.DeleteFunc(ids, func(v uuid.UUID) bool {
+		return v == id
+	})
+}
+
 func (ms *MemoryStorage) removeFromStatusIndex(taskID uuid.UUID, status TaskStatus) {
 	ms.byStatus[status] = slices.DeleteFunc(ms.byStatus[status], func(id uuid.UUID) bool {
 		return id == taskID
@@ -456,9 +923,15 @@ func (ms *MemoryStorage) expireLocks() {
 
 	now := time.Now()
 	freed := 0
-	for _, taskID := range ms.byStatus[TaskStatusProcessing] {
+	for _, taskID := range slices.Clone(ms.byStatus[TaskStatusProcessing]) {
 		task := ms.tasks[taskID]
 		if task.LockedUntil != nil && task.LockedUntil.Before(now) {
+			// The worker is gone, so nobody else will finish a cancellation request
+			if task.CancelRequested {
+				ms.cancelTask(task)
+				continue
+			}
+
 			task.Status = TaskStatusPending
 			task.LockedUntil = nil
 			task.LockedBy = nil
@@ -473,6 +946,27 @@ func (ms *MemoryStorage) expireLocks() {
 		ms.expiredLocksFreed.Add(int64(freed))
 		ms.lastActivityAt.Store(time.Now().Unix())
 	}
+
+	ms.purgeExpiredResults(now)
+}
+
+// purgeExpiredResults deletes completed tasks whose result TTL has expired.
+// Tasks still holding a unique key or needed by an unfinished workflow are kept.
+// Caller must hold the write lock.
+func (ms *MemoryStorage) purgeExpiredResults(now time.Time) {
+	for _, taskID := range slices.Clone(ms.byStatus[TaskStatusCompleted]) {
+		task := ms.tasks[taskID]
+		if !resultExpired(task, now) {
+			continue
+		}
+		if task.UniqueUntil != nil && task.UniqueUntil.After(now) {
+			continue
+		}
+		if task.WorkflowID != nil && !ms.workflowFinished(*task.WorkflowID) {
+			continue
+		}
+		ms.deleteTask(task)
+	}
 }
 
 // Stats returns current memory storage statistics for observability and monitoring.
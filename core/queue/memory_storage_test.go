@@ -217,7 +217,7 @@ func TestMemoryStorage_CompleteTask(t *testing.T) {
 		claimed, err := storage.ClaimTask(context.Background(), workerID, []string{queue.DefaultQueueName}, 5*time.Minute)
 		require.NoError(t, err)
 
-		err = storage.CompleteTask(context.Background(), claimed.ID)
+		err = storage.CompleteTask(context.Background(), claimed.ID, nil)
 		require.NoError(t, err)
 
 		// Task should not be claimable anymore
@@ -227,7 +227,7 @@ func TestMemoryStorage_CompleteTask(t *testing.T) {
 	})
 
 	t.Run("fails on non-existent task", func(t *testing.T) {
-		err := storage.CompleteTask(context.Background(), uuid.New())
+		err := storage.CompleteTask(context.Background(), uuid.New(), nil)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "not found")
 	})
@@ -246,7 +246,7 @@ func TestMemoryStorage_CompleteTask(t *testing.T) {
 		err := storage.CreateTask(context.Background(), task)
 		require.NoError(t, err)
 
-		err = storage.CompleteTask(context.Background(), task.ID)
+		err = storage.CompleteTask(context.Background(), task.ID, nil)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "not in processing state")
 	})
@@ -275,7 +275,7 @@ func TestMemoryStorage_FailTask(t *testing.T) {
 		claimed, err := storage.ClaimTask(context.Background(), workerID, []string{queue.DefaultQueueName}, 5*time.Minute)
 		require.NoError(t, err)
 
-		err = storage.FailTask(context.Background(), claimed.ID, "test error")
+		err = storage.FailTask(context.Background(), claimed.ID, "test error", 30*time.Second)
 		require.NoError(t, err)
 
 		// Task should be claimable again but with backoff
@@ -305,7 +305,7 @@ func TestMemoryStorage_FailTask(t *testing.T) {
 		claimed, err := storage.ClaimTask(context.Background(), workerID, []string{queue.DefaultQueueName}, 5*time.Minute)
 		require.NoError(t, err)
 
-		err = storage.FailTask(context.Background(), claimed.ID, "final error")
+		err = storage.FailTask(context.Background(), claimed.ID, "final error", 30*time.Second)
 		require.NoError(t, err)
 
 		// Task should not be claimable (failed permanently)
@@ -369,7 +369,7 @@ func TestMemoryStorage_ExtendLock(t *testing.T) {
 
 		originalLock := *claimed.LockedUntil
 
-		err = storage.ExtendLock(context.Background(), claimed.ID, 5*time.Minute)
+		err = storage.ExtendLock(context.Background(), claimed.ID, workerID, 5*time.Minute)
 		require.NoError(t, err)
 
 		// Verify lock was extended by trying to claim with another worker
@@ -0,0 +1,186 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// HandlerFunc processes the raw payload of a task. It is the unit wrapped by Middleware.
+type HandlerFunc func(ctx context.Context, payload json.RawMessage) error
+
+// Middleware wraps task execution to add cross-cutting functionality.
+// It follows the same pattern as HTTP middleware; task metadata is available
+// in the context through TaskInfoFromContext.
+//
+// Example:
+//
+//	func AuditMiddleware(next queue.HandlerFunc) queue.HandlerFunc {
+//		return func(ctx context.Context, payload json.RawMessage) error {
+//			info, _ := queue.TaskInfoFromContext(ctx)
+//			audit.Record(ctx, "task", info.Name)
+//			return next(ctx, payload)
+//		}
+//	}
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// ApplyMiddleware wraps a handler function with middleware.
+// The first middleware in the list becomes the outermost wrapper (executes first).
+func ApplyMiddleware(fn HandlerFunc, middleware ...Middleware) HandlerFunc {
+	// Reverse iteration ensures first middleware becomes outermost wrapper
+	for i := range len(middleware) {
+		fn = middleware[len(middleware)-1-i](fn)
+	}
+	return fn
+}
+
+// PanicError is returned for a handler that panicked.
+// It matches ErrHandlerPanic with errors.Is and keeps the stack of the panic.
+type PanicError struct {
+	Value any    // Value passed to panic
+	Stack []byte // Stack trace of the panicking goroutine
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%s: %v", ErrHandlerPanic, e.Value)
+}
+
+// Unwrap exposes ErrHandlerPanic and, if the panic value is an error, the value itself.
+func (e *PanicError) Unwrap() []error {
+	if err, ok := e.Value.(error); ok {
+		return []error{ErrHandlerPanic, err}
+	}
+	return []error{ErrHandlerPanic}
+}
+
+// RecoverMiddleware turns a panic in the wrapped chain into a *PanicError, so the
+// task fails and is retried like any other failure. The worker always installs it
+// right around the handler, so other middleware observe handler panics as errors.
+func RecoverMiddleware() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, payload json.RawMessage) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = &PanicError{Value: r, Stack: debug.Stack()}
+				}
+			}()
+			return next(ctx, payload)
+		}
+	}
+}
+
+// LoggingMiddleware logs the start and outcome of every task with its metadata.
+// Panics recovered further down the chain are logged with their stack.
+// A nil logger falls back to slog.Default().
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, payload json.RawMessage) error {
+			info, _ := TaskInfoFromContext(ctx)
+			log := logger.With(slog.Group("task", info.logAttrs()...))
+
+			start := time.Now()
+			log.DebugContext(ctx, "task started")
+
+			err := next(ctx, payload)
+			duration := time.Since(start)
+
+			if err == nil {
+				log.InfoContext(ctx, "task completed", slog.Duration("duration", duration))
+				return nil
+			}
+
+			attrs := []any{slog.Duration("duration", duration), slog.String("error", err.Error())}
+			var pe *PanicError
+			if errors.As(err, &pe) {
+				attrs = append(attrs, slog.String("stack", string(pe.Stack)))
+			}
+			log.ErrorContext(ctx, "task failed", attrs...)
+			return err
+		}
+	}
+}
+
+// ConcurrencyLimitMiddleware limits how many tasks with the same name run at once.
+// Tasks over the limit wait for a slot while holding their worker slot and lock, so
+// keep the limit below the worker's maximum concurrency. Each call creates its own
+// set of limits; non-positive limits disable the middleware.
+//
+// Use it as worker middleware to limit every task name, or as handler middleware
+// to limit a single handler:
+//
+//	queue.NewTaskHandler(syncAccount, queue.WithHandlerMiddleware(queue.ConcurrencyLimitMiddleware(2)))
+func ConcurrencyLimitMiddleware(limit int) Middleware {
+	if limit <= 0 {
+		return func(next HandlerFunc) HandlerFunc { return next }
+	}
+
+	var mu sync.Mutex
+	slots := make(map[string]chan struct{})
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, payload json.RawMessage) error {
+			info, _ := TaskInfoFromContext(ctx)
+
+			mu.Lock()
+			sem, ok := slots[info.Name]
+			if !ok {
+				sem = make(chan struct{}, limit)
+				slots[info.Name] = sem
+			}
+			mu.Unlock()
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			}
+			defer func() { <-sem }()
+
+			return next(ctx, payload)
+		}
+	}
+}
+
+// TaskHook observes task execution for metrics and tracing. It is called before the
+// task runs; the returned context is passed down the chain (e.g. carrying a span),
+// and the returned function is called with the outcome when the task finishes.
+type TaskHook func(ctx context.Context, info TaskInfo) (context.Context, func(err error, duration time.Duration))
+
+// InstrumentMiddleware calls hook around every task. A nil hook disables the middleware.
+//
+// Example:
+//
+//	queue.InstrumentMiddleware(func(ctx context.Context, info queue.TaskInfo) (context.Context, func(error, time.Duration)) {
+//		ctx, span := tracer.Start(ctx, info.Name)
+//		return ctx, func(err error, d time.Duration) {
+//			taskDuration.WithLabelValues(info.Name, strconv.FormatBool(err == nil)).Observe(d.Seconds())
+//			span.End()
+//		}
+//	})
+func InstrumentMiddleware(hook TaskHook) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		if hook == nil {
+			return next
+		}
+		return func(ctx context.Context, payload json.RawMessage) error {
+			info, _ := TaskInfoFromContext(ctx)
+
+			ctx, done := hook(ctx, info)
+			start := time.Now()
+			err := next(ctx, payload)
+			if done != nil {
+				done(err, time.Since(start))
+			}
+			return err
+		}
+	}
+}
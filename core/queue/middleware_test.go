@@ -0,0 +1,248 @@
+package queue_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+type reportPayload struct {
+	ID int `json:"id"`
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent log writes
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestApplyMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var order []string
+	record := func(name string) queue.Middleware {
+		return func(next queue.HandlerFunc) queue.HandlerFunc {
+			return func(ctx context.Context, payload json.RawMessage) error {
+				order = append(order, name)
+				return next(ctx, payload)
+			}
+		}
+	}
+
+	fn := queue.ApplyMiddleware(func(context.Context, json.RawMessage) error {
+		order = append(order, "handler")
+		return nil
+	}, record("first"), record("second"))
+
+	require.NoError(t, fn(context.Background(), nil))
+	assert.Equal(t, []string{"first", "second", "handler"}, order)
+}
+
+func TestRecoverMiddleware(t *testing.T) {
+	t.Parallel()
+
+	t.Run("string value", func(t *testing.T) {
+		t.Parallel()
+
+		fn := queue.ApplyMiddleware(func(context.Context, json.RawMessage) error {
+			panic("boom")
+		}, queue.RecoverMiddleware())
+
+		err := fn(context.Background(), nil)
+		require.ErrorIs(t, err, queue.ErrHandlerPanic)
+
+		var pe *queue.PanicError
+		require.ErrorAs(t, err, &pe)
+		assert.Equal(t, "boom", pe.Value)
+		assert.Contains(t, string(pe.Stack), "middleware_test.go")
+	})
+
+	t.Run("error value is unwrapped", func(t *testing.T) {
+		t.Parallel()
+
+		cause := errors.New("nil map")
+		fn := queue.ApplyMiddleware(func(context.Context, json.RawMessage) error {
+			panic(cause)
+		}, queue.RecoverMiddleware())
+
+		err := fn(context.Background(), nil)
+		assert.ErrorIs(t, err, queue.ErrHandlerPanic)
+		assert.ErrorIs(t, err, cause)
+	})
+}
+
+func TestConcurrencyLimitMiddleware(t *testing.T) {
+	t.Parallel()
+
+	var running, peak atomic.Int32
+	release := make(chan struct{})
+	fn := queue.ApplyMiddleware(func(context.Context, json.RawMessage) error {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+		running.Add(-1)
+		return nil
+	}, queue.ConcurrencyLimitMiddleware(2))
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = fn(context.Background(), nil)
+		}()
+	}
+
+	require.Eventually(t, func() bool { return running.Load() == 2 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(2), peak.Load())
+
+	t.Run("waiting task gives up when its context ends", func(t *testing.T) {
+		t.Parallel()
+
+		block := make(chan struct{})
+		defer close(block)
+		limited := queue.ApplyMiddleware(func(context.Context, json.RawMessage) error {
+			<-block
+			return nil
+		}, queue.ConcurrencyLimitMiddleware(1))
+
+		go func() { _ = limited(context.Background(), nil) }()
+		time.Sleep(10 * time.Millisecond)
+
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(queue.ErrTaskCancelled)
+		assert.ErrorIs(t, limited(ctx, nil), queue.ErrTaskCancelled)
+	})
+}
+
+func TestWorker_Middleware(t *testing.T) {
+	t.Parallel()
+
+	storage := queue.NewMemoryStorage()
+	enqueuer, err := queue.NewEnqueuer(storage)
+	require.NoError(t, err)
+
+	var (
+		mu     sync.Mutex
+		events []string
+		infos  []queue.TaskInfo
+	)
+	record := func(name string) queue.Middleware {
+		return func(next queue.HandlerFunc) queue.HandlerFunc {
+			return func(ctx context.Context, payload json.RawMessage) error {
+				mu.Lock()
+				events = append(events, name)
+				mu.Unlock()
+				return next(ctx, payload)
+			}
+		}
+	}
+	hook := func(ctx context.Context, info queue.TaskInfo) (context.Context, func(error, time.Duration)) {
+		return ctx, func(err error, _ time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			infos = append(infos, info)
+			events = append(events, "done:"+errString(err))
+		}
+	}
+
+	logs := &syncBuffer{}
+	worker, err := queue.NewWorker(storage,
+		queue.WithPullInterval(5*time.Millisecond),
+		queue.WithWorkerLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		queue.WithMiddleware(
+			queue.LoggingMiddleware(slog.New(slog.NewTextHandler(logs, nil))),
+			queue.InstrumentMiddleware(hook),
+			record("worker"),
+		),
+	)
+	require.NoError(t, err)
+
+	var calls atomic.Int32
+	require.NoError(t, worker.RegisterHandler(queue.NewTaskHandler(func(ctx context.Context, p reportPayload) error {
+		info, ok := queue.TaskInfoFromContext(ctx)
+		if !ok || info.Name != "queue_test.reportPayload" {
+			return errors.New("task info missing")
+		}
+		if calls.Add(1) == 1 {
+			panic("first attempt")
+		}
+		return nil
+	}, queue.WithHandlerMiddleware(record("handler")))))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() { errCh <- worker.Run(ctx)() }()
+
+	_, err = enqueuer.Enqueue(ctx, reportPayload{ID: 1}, queue.WithRetryPolicy(queue.FixedBackoff(time.Millisecond)))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return worker.Stats().TasksProcessed == 1
+	}, 2*time.Second, 5*time.Millisecond)
+	cancel()
+	require.NoError(t, <-errCh)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{
+		"worker", "handler", "done:" + queue.ErrHandlerPanic.Error() + ": first attempt",
+		"worker", "handler", "done:",
+	}, events, "panics reach middleware as errors")
+	require.Len(t, infos, 2)
+	assert.Equal(t, int8(0), infos[0].RetryCount)
+	assert.Equal(t, int8(1), infos[1].RetryCount)
+	assert.Equal(t, infos[0].ID, infos[1].ID)
+
+	out := logs.String()
+	assert.Contains(t, out, "task failed")
+	assert.Contains(t, out, "stack=")
+	assert.Contains(t, out, "task completed")
+	assert.Contains(t, out, "task.name=queue_test.reportPayload")
+}
+
+func TestTaskLogAttrs(t *testing.T) {
+	t.Parallel()
+
+	_, ok := queue.TaskLogAttrs(context.Background())
+	assert.False(t, ok)
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,120 @@
+package queue
+
+import (
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// RateLimitKeyPrefix prefixes the rate limiter key of a queue: the key of the
+// "emails" queue is "queue:emails".
+const RateLimitKeyPrefix = "queue:"
+
+// claimTask claims the next task from the worker's queues.
+// Without weights or rate limits all queues are claimed from at once, so the task
+// with the highest priority wins across them.
+func (w *Worker) claimTask() (*Task, error) {
+	if len(w.weights) == 0 && len(w.limiters) == 0 {
+		return w.repo.ClaimTask(w.ctx, w.workerID, w.queues, w.lockTimeout)
+	}
+
+	for _, queues := range w.claimOrder() {
+		if len(queues) == 1 && !w.allowQueue(queues[0]) {
+			continue
+		}
+
+		task, err := w.repo.ClaimTask(w.ctx, w.workerID, queues, w.lockTimeout)
+		if errors.Is(err, ErrNoTaskToClaim) || (err == nil && task == nil) {
+			continue
+		}
+		return task, err
+	}
+
+	return nil, ErrNoTaskToClaim
+}
+
+// claimOrder returns the sets of queues to claim from, in the order to try them.
+// Weighted queues are tried one by one in a random order drawn in proportion to
+// their weights, so every queue gets its share of claims. Without weights,
+// rate-limited queues are tried one by one first, then the rest at once.
+func (w *Worker) claimOrder() [][]string {
+	if len(w.weights) > 0 {
+		return weightedOrder(w.queues, w.weights)
+	}
+
+	order := make([][]string, 0, len(w.limiters)+1)
+	var free []string
+	for _, q := range w.queues {
+		if _, ok := w.limiters[q]; ok {
+			order = append(order, []string{q})
+		} else {
+			free = append(free, q)
+		}
+	}
+	if len(free) > 0 {
+		order = append(order, free)
+	}
+	return order
+}
+
+// weightedOrder draws queues without replacement, each with probability proportional to its weight.
+func weightedOrder(queues []string, weights map[string]int) [][]string {
+	remaining := slices.Clone(queues)
+	total := 0
+	for _, q := range remaining {
+		total += weights[q]
+	}
+
+	order := make([][]string, 0, len(remaining))
+	for len(remaining) > 0 {
+		n := rand.IntN(total)
+		i := 0
+		for ; i < len(remaining)-1; i++ {
+			n -= weights[remaining[i]]
+			if n < 0 {
+				break
+			}
+		}
+		order = append(order, []string{remaining[i]})
+		total -= weights[remaining[i]]
+		remaining = slices.Delete(remaining, i, i+1)
+	}
+	return order
+}
+
+// allowQueue takes a token from the queue's rate limiter, if it has one.
+// A denied queue is skipped until its next refill instead of asking the limiter
+// on every poll; limiter errors skip the queue for this poll.
+func (w *Worker) allowQueue(queue string) bool {
+	limiter, ok := w.limiters[queue]
+	if !ok {
+		return true
+	}
+
+	w.throttleMu.Lock()
+	until, paused := w.throttled[queue]
+	w.throttleMu.Unlock()
+	if paused && time.Now().Before(until) {
+		return false
+	}
+
+	result, err := limiter.Allow(w.ctx, RateLimitKeyPrefix+queue)
+	if err != nil {
+		w.logger.WarnContext(w.ctx, "queue rate limiter failed, skipping queue",
+			slog.String("worker_id", w.workerID.String()),
+			slog.String("queue", queue),
+			slog.String("error", err.Error()))
+		return false
+	}
+	if result.Allowed() {
+		return true
+	}
+
+	w.rateLimited.Add(1)
+	w.throttleMu.Lock()
+	w.throttled[queue] = time.Now().Add(result.RetryAfter())
+	w.throttleMu.Unlock()
+	return false
+}
@@ -0,0 +1,135 @@
+package queue_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+	"github.com/dmitrymomot/foundation/pkg/ratelimiter"
+)
+
+type notifyPayload struct {
+	N int `json:"n"`
+}
+
+// runQueueWorker processes notifyPayload tasks and records the queue of each, in order.
+func runQueueWorker(t *testing.T, storage *queue.MemoryStorage, opts ...queue.WorkerOption) (*queue.Worker, func() []string) {
+	t.Helper()
+
+	opts = append([]queue.WorkerOption{
+		queue.WithPullInterval(time.Millisecond),
+		queue.WithWorkerLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	}, opts...)
+	worker, err := queue.NewWorker(storage, opts...)
+	require.NoError(t, err)
+
+	var (
+		mu     sync.Mutex
+		queues []string
+	)
+	require.NoError(t, worker.RegisterHandler(queue.NewTaskHandler(func(ctx context.Context, _ notifyPayload) error {
+		info, _ := queue.TaskInfoFromContext(ctx)
+		mu.Lock()
+		queues = append(queues, info.Queue)
+		mu.Unlock()
+		return nil
+	})))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- worker.Run(ctx)() }()
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-errCh)
+	})
+
+	return worker, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), queues...)
+	}
+}
+
+func enqueueNotifications(t *testing.T, enqueuer *queue.Enqueuer, queueName string, n int, priority queue.Priority) {
+	t.Helper()
+	for i := range n {
+		_, err := enqueuer.Enqueue(context.Background(), notifyPayload{N: i},
+			queue.WithQueue(queueName),
+			queue.WithPriority(priority),
+		)
+		require.NoError(t, err)
+	}
+}
+
+func TestWorker_QueueWeights(t *testing.T) {
+	t.Parallel()
+
+	storage := queue.NewMemoryStorage()
+	enqueuer, err := queue.NewEnqueuer(storage)
+	require.NoError(t, err)
+
+	// Without weights the high priority tasks of "critical" would all run first
+	enqueueNotifications(t, enqueuer, "critical", 100, queue.PriorityMax)
+	enqueueNotifications(t, enqueuer, "low", 100, queue.PriorityMin)
+
+	worker, processed := runQueueWorker(t, storage,
+		queue.WithQueueWeights(map[string]int{"critical": 3, "low": 1, "ignored": 0}),
+	)
+	assert.Equal(t, []string{"critical", "low"}, worker.Queues())
+
+	require.Eventually(t, func() bool { return len(processed()) >= 40 }, 5*time.Second, time.Millisecond)
+
+	counts := make(map[string]int)
+	for _, q := range processed()[:40] {
+		counts[q]++
+	}
+	assert.Positive(t, counts["low"], "low queue is not starved")
+	assert.Greater(t, counts["critical"], counts["low"])
+}
+
+func TestWorker_QueueRateLimit(t *testing.T) {
+	t.Parallel()
+
+	storage := queue.NewMemoryStorage()
+	enqueuer, err := queue.NewEnqueuer(storage)
+	require.NoError(t, err)
+
+	store := ratelimiter.NewMemoryStore()
+	t.Cleanup(store.Close)
+	limiter, err := ratelimiter.NewBucket(store, ratelimiter.Config{
+		Capacity:       2,
+		RefillRate:     1,
+		RefillInterval: time.Hour,
+	})
+	require.NoError(t, err)
+
+	enqueueNotifications(t, enqueuer, "emails", 5, queue.PriorityMax)
+	enqueueNotifications(t, enqueuer, queue.DefaultQueueName, 5, queue.PriorityMin)
+
+	worker, processed := runQueueWorker(t, storage,
+		queue.WithQueues("emails", queue.DefaultQueueName),
+		queue.WithQueueRateLimit("emails", limiter),
+	)
+
+	require.Eventually(t, func() bool { return len(processed()) == 7 }, 5*time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	counts := make(map[string]int)
+	for _, q := range processed() {
+		counts[q]++
+	}
+	assert.Equal(t, 2, counts["emails"], "emails are capped by the limiter")
+	assert.Equal(t, 5, counts[queue.DefaultQueueName])
+	assert.Positive(t, worker.Stats().RateLimited)
+
+	result, err := limiter.Status(context.Background(), queue.RateLimitKeyPrefix+"emails")
+	require.NoError(t, err)
+	assert.False(t, result.Allowed())
+}
@@ -0,0 +1,120 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ResultRepository is implemented by storages that can report the outcome of a task.
+type ResultRepository interface {
+	// GetTaskResult returns the status and result of a task. A task moved to the
+	// dead letter queue is reported as failed. Returns ErrTaskNotFound for unknown
+	// tasks and for completed tasks whose result TTL has expired.
+	GetTaskResult(ctx context.Context, taskID uuid.UUID) (*TaskResult, error)
+}
+
+// TaskResult is the status and result of a task returned by Enqueuer.Status.
+type TaskResult struct {
+	TaskID      uuid.UUID
+	Status      TaskStatus
+	Result      []byte     // JSON result recorded with SetResult; nil until the task completes
+	Error       string     // Last failure message, kept while the task is retried
+	ProcessedAt *time.Time // When the task finished (nil while it is running)
+}
+
+// Done reports whether the task has finished: completed, failed or cancelled.
+func (r *TaskResult) Done() bool {
+	switch r.Status {
+	case TaskStatusCompleted, TaskStatusFailed, TaskStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// resultExpired reports whether a completed task has outlived its result TTL.
+func resultExpired(task *Task, now time.Time) bool {
+	return task.Status == TaskStatusCompleted &&
+		task.ResultTTL > 0 &&
+		task.ProcessedAt != nil &&
+		now.After(task.ProcessedAt.Add(task.ResultTTL))
+}
+
+// Status returns the status and raw result of a task.
+// The repository must implement ResultRepository.
+func (e *Enqueuer) Status(ctx context.Context, taskID uuid.UUID) (*TaskResult, error) {
+	repo, ok := e.repo.(ResultRepository)
+	if !ok {
+		return nil, ErrResultsNotSupported
+	}
+
+	result, err := repo.GetTaskResult(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get result of task %s: %w", taskID, err)
+	}
+	return result, nil
+}
+
+// Result returns the decoded result of a finished task.
+// Returns ErrResultNotReady while the task is pending or running, an error
+// wrapping ErrTaskFailed with the failure message if it failed, and
+// ErrTaskCancelled if it was cancelled.
+//
+//	report, err := queue.Result[ReportResult](ctx, enqueuer, taskID)
+//	if errors.Is(err, queue.ErrResultNotReady) {
+//		return response.JSON(w, http.StatusAccepted, nil)
+//	}
+func Result[T any](ctx context.Context, e *Enqueuer, taskID uuid.UUID) (T, error) {
+	var v T
+
+	result, err := e.Status(ctx, taskID)
+	if err != nil {
+		return v, err
+	}
+
+	switch result.Status {
+	case TaskStatusCompleted:
+		if len(result.Result) == 0 {
+			return v, nil
+		}
+		if err := json.Unmarshal(result.Result, &v); err != nil {
+			return v, fmt.Errorf("failed to unmarshal result of task %s into %T: %w", taskID, v, err)
+		}
+		return v, nil
+	case TaskStatusFailed:
+		return v, fmt.Errorf("%w: %s", ErrTaskFailed, result.Error)
+	case TaskStatusCancelled:
+		return v, ErrTaskCancelled
+	default:
+		return v, ErrResultNotReady
+	}
+}
+
+// Await waits until the task finishes and returns its decoded result, checking
+// every result poll interval (see WithResultPollInterval). Bound the wait with
+// the context. Errors are the same as for Result.
+//
+//	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+//	defer cancel()
+//	report, err := queue.Await[ReportResult](ctx, enqueuer, taskID)
+func Await[T any](ctx context.Context, e *Enqueuer, taskID uuid.UUID) (T, error) {
+	ticker := time.NewTicker(e.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		v, err := Result[T](ctx, e, taskID)
+		if !errors.Is(err, ErrResultNotReady) {
+			return v, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return v, fmt.Errorf("failed to await task %s: %w", taskID, context.Cause(ctx))
+		case <-ticker.C:
+		}
+	}
+}
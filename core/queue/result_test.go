@@ -0,0 +1,201 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+type (
+	totalsPayload struct {
+		Values []int `json:"values"`
+	}
+	totalsResult struct {
+		Sum int `json:"sum"`
+	}
+)
+
+func TestEnqueuer_Result(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	queues := []string{queue.DefaultQueueName}
+
+	t.Run("completed task", func(t *testing.T) {
+		t.Parallel()
+
+		storage := queue.NewMemoryStorage()
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+
+		taskID, err := enqueuer.Enqueue(ctx, totalsPayload{Values: []int{1, 2}})
+		require.NoError(t, err)
+
+		_, err = queue.Result[totalsResult](ctx, enqueuer, taskID)
+		assert.ErrorIs(t, err, queue.ErrResultNotReady)
+
+		task, err := storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, taskID, task.ID)
+		require.NoError(t, storage.CompleteTask(ctx, task.ID, []byte(`{"sum":3}`)))
+
+		result, err := enqueuer.Status(ctx, taskID)
+		require.NoError(t, err)
+		assert.True(t, result.Done())
+		assert.Equal(t, queue.TaskStatusCompleted, result.Status)
+		assert.NotNil(t, result.ProcessedAt)
+
+		totals, err := queue.Result[totalsResult](ctx, enqueuer, taskID)
+		require.NoError(t, err)
+		assert.Equal(t, 3, totals.Sum)
+	})
+
+	t.Run("dead lettered task is failed", func(t *testing.T) {
+		t.Parallel()
+
+		storage := queue.NewMemoryStorage()
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+
+		taskID, err := enqueuer.Enqueue(ctx, totalsPayload{}, queue.WithMaxRetries(1))
+		require.NoError(t, err)
+
+		task, err := storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, storage.FailTask(ctx, task.ID, "no values", 0))
+		require.NoError(t, storage.MoveToDLQ(ctx, task.ID))
+
+		_, err = queue.Result[totalsResult](ctx, enqueuer, taskID)
+		assert.ErrorIs(t, err, queue.ErrTaskFailed)
+		assert.ErrorContains(t, err, "no values")
+	})
+
+	t.Run("cancelled task", func(t *testing.T) {
+		t.Parallel()
+
+		storage := queue.NewMemoryStorage()
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+
+		taskID, err := enqueuer.Enqueue(ctx, totalsPayload{}, queue.WithDelay(time.Hour))
+		require.NoError(t, err)
+		require.NoError(t, enqueuer.Cancel(ctx, taskID))
+
+		_, err = queue.Result[totalsResult](ctx, enqueuer, taskID)
+		assert.ErrorIs(t, err, queue.ErrTaskCancelled)
+	})
+
+	t.Run("unknown task", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, err := queue.NewEnqueuer(queue.NewMemoryStorage())
+		require.NoError(t, err)
+
+		_, err = queue.Result[totalsResult](ctx, enqueuer, uuid.New())
+		assert.ErrorIs(t, err, queue.ErrTaskNotFound)
+	})
+
+	t.Run("expired result is purged", func(t *testing.T) {
+		t.Parallel()
+
+		storage := queue.NewMemoryStorage(queue.WithLockCheckInterval(5 * time.Millisecond))
+		enqueuer, err := queue.NewEnqueuer(storage, queue.WithDefaultResultTTL(20*time.Millisecond))
+		require.NoError(t, err)
+
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() { _ = storage.Start(runCtx) }()
+
+		taskID, err := enqueuer.Enqueue(ctx, totalsPayload{})
+		require.NoError(t, err)
+		keptID, err := enqueuer.Enqueue(ctx, totalsPayload{}, queue.WithResultTTL(time.Hour))
+		require.NoError(t, err)
+
+		for range 2 {
+			task, err := storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+			require.NoError(t, err)
+			require.NoError(t, storage.CompleteTask(ctx, task.ID, nil))
+		}
+
+		require.Eventually(t, func() bool {
+			_, err := enqueuer.Status(ctx, taskID)
+			return errors.Is(err, queue.ErrTaskNotFound) && storage.Stats().ActiveTasks == 1
+		}, time.Second, 5*time.Millisecond)
+
+		_, err = enqueuer.Status(ctx, keptID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("requires result repository", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, err := queue.NewEnqueuer(&mockEnqueuerRepo{})
+		require.NoError(t, err)
+
+		_, err = enqueuer.Status(ctx, uuid.New())
+		assert.ErrorIs(t, err, queue.ErrResultsNotSupported)
+	})
+}
+
+func TestAwait(t *testing.T) {
+	t.Parallel()
+
+	storage := queue.NewMemoryStorage()
+	enqueuer, err := queue.NewEnqueuer(storage, queue.WithResultPollInterval(5*time.Millisecond))
+	require.NoError(t, err)
+
+	worker, err := queue.NewWorker(storage,
+		queue.WithPullInterval(5*time.Millisecond),
+		queue.WithWorkerLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	require.NoError(t, err)
+	require.NoError(t, worker.RegisterHandler(queue.NewResultTaskHandler(func(_ context.Context, p totalsPayload) (totalsResult, error) {
+		if len(p.Values) == 0 {
+			return totalsResult{}, errors.New("no values")
+		}
+		var sum int
+		for _, v := range p.Values {
+			sum += v
+		}
+		return totalsResult{Sum: sum}, nil
+	})))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	errCh := make(chan error, 1)
+	go func() { errCh <- worker.Run(ctx)() }()
+
+	taskID, err := enqueuer.Enqueue(ctx, totalsPayload{Values: []int{2, 3, 4}})
+	require.NoError(t, err)
+	totals, err := queue.Await[totalsResult](ctx, enqueuer, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, 9, totals.Sum)
+
+	failedID, err := enqueuer.Enqueue(ctx, totalsPayload{}, queue.WithMaxRetries(1))
+	require.NoError(t, err)
+	_, err = queue.Await[totalsResult](ctx, enqueuer, failedID)
+	assert.ErrorIs(t, err, queue.ErrTaskFailed)
+	assert.ErrorContains(t, err, "no values")
+
+	t.Run("gives up when the context ends", func(t *testing.T) {
+		delayedID, err := enqueuer.Enqueue(ctx, totalsPayload{Values: []int{1}}, queue.WithDelay(time.Hour))
+		require.NoError(t, err)
+
+		waitCtx, waitCancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer waitCancel()
+		_, err = queue.Await[totalsResult](waitCtx, enqueuer, delayedID)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	cancel()
+	require.NoError(t, <-errCh)
+}
@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// BackoffStrategy selects how the retry delay grows with each attempt.
+type BackoffStrategy string
+
+const (
+	BackoffFixed       BackoffStrategy = "fixed"       // Same delay for every retry
+	BackoffLinear      BackoffStrategy = "linear"      // BaseDelay * retry number
+	BackoffExponential BackoffStrategy = "exponential" // BaseDelay * Multiplier^(retry number - 1)
+)
+
+// RetryPolicy computes the delay before a failed task is retried.
+// It is a plain value so it can be stored with the task and applied by any worker.
+type RetryPolicy struct {
+	Strategy   BackoffStrategy `json:"strategy"`
+	BaseDelay  time.Duration   `json:"base_delay"`
+	MaxDelay   time.Duration   `json:"max_delay,omitempty"`  // Upper bound for the delay (0 = unbounded)
+	Multiplier float64         `json:"multiplier,omitempty"` // Growth factor for exponential backoff (default 2)
+	Jitter     float64         `json:"jitter,omitempty"`     // Fraction of the delay randomized away (0-1)
+}
+
+// DefaultRetryPolicy is used when neither the task nor its handler sets a policy.
+// Linear 30s steps (30s, 60s, 90s...) are fast enough for transient issues
+// while still protecting against persistent failures causing thundering herd.
+var DefaultRetryPolicy = LinearBackoff(30 * time.Second)
+
+// FixedBackoff retries after the same delay every time.
+func FixedBackoff(delay time.Duration) RetryPolicy {
+	return RetryPolicy{Strategy: BackoffFixed, BaseDelay: delay}
+}
+
+// LinearBackoff retries after step, 2*step, 3*step...
+func LinearBackoff(step time.Duration) RetryPolicy {
+	return RetryPolicy{Strategy: BackoffLinear, BaseDelay: step}
+}
+
+// ExponentialBackoff retries after base, 2*base, 4*base... capped at maxDelay (0 = unbounded).
+func ExponentialBackoff(base, maxDelay time.Duration) RetryPolicy {
+	return RetryPolicy{Strategy: BackoffExponential, BaseDelay: base, MaxDelay: maxDelay, Multiplier: 2}
+}
+
+// JitteredBackoff randomizes the delay of policy to spread out retries of tasks that failed together.
+// The delay is drawn uniformly from [delay*(1-fraction), delay]; fraction 1 is "full jitter".
+// Values outside 0-1 are clamped.
+func JitteredBackoff(policy RetryPolicy, fraction float64) RetryPolicy {
+	policy.Jitter = min(max(fraction, 0), 1)
+	return policy
+}
+
+// Delay returns the wait before the given retry (1 for the first retry).
+func (p RetryPolicy) Delay(retry int) time.Duration {
+	if retry < 1 {
+		retry = 1
+	}
+
+	base := float64(max(p.BaseDelay, 0))
+	var delay float64
+	switch p.Strategy {
+	case BackoffLinear:
+		delay = base * float64(retry)
+	case BackoffExponential:
+		multiplier := p.Multiplier
+		if multiplier <= 1 {
+			multiplier = 2
+		}
+		delay = base * math.Pow(multiplier, float64(retry-1))
+	default:
+		delay = base
+	}
+
+	if p.MaxDelay > 0 {
+		delay = min(delay, float64(p.MaxDelay))
+	}
+
+	if jitter := min(max(p.Jitter, 0), 1); jitter > 0 {
+		delay -= delay * jitter * rand.Float64()
+	}
+
+	// Float math avoids overflow for large retry counts; clamp before converting back
+	if delay >= float64(math.MaxInt64) {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay)
+}
+
+func (p RetryPolicy) String() string {
+	s := fmt.Sprintf("%s backoff %v", p.Strategy, p.BaseDelay)
+	if p.MaxDelay > 0 {
+		s += fmt.Sprintf(" (max %v)", p.MaxDelay)
+	}
+	if p.Jitter > 0 {
+		s += fmt.Sprintf(" with %.0f%% jitter", p.Jitter*100)
+	}
+	return s
+}
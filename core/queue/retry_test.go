@@ -0,0 +1,81 @@
+package queue_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		policy   queue.RetryPolicy
+		retry    int
+		expected time.Duration
+	}{
+		{"fixed", queue.FixedBackoff(10 * time.Second), 3, 10 * time.Second},
+		{"linear first retry", queue.LinearBackoff(30 * time.Second), 1, 30 * time.Second},
+		{"linear third retry", queue.LinearBackoff(30 * time.Second), 3, 90 * time.Second},
+		{"exponential first retry", queue.ExponentialBackoff(time.Second, 0), 1, time.Second},
+		{"exponential fourth retry", queue.ExponentialBackoff(time.Second, 0), 4, 8 * time.Second},
+		{"exponential capped", queue.ExponentialBackoff(time.Second, 5*time.Second), 10, 5 * time.Second},
+		{"exponential custom multiplier", queue.RetryPolicy{Strategy: queue.BackoffExponential, BaseDelay: time.Second, Multiplier: 3}, 3, 9 * time.Second},
+		{"linear capped", queue.RetryPolicy{Strategy: queue.BackoffLinear, BaseDelay: time.Minute, MaxDelay: 2 * time.Minute}, 5, 2 * time.Minute},
+		{"retry below one treated as first", queue.LinearBackoff(time.Second), 0, time.Second},
+		{"negative base delay", queue.FixedBackoff(-time.Second), 1, 0},
+		{"huge retry does not overflow", queue.ExponentialBackoff(time.Hour, 0), 100, time.Duration(1<<63 - 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, tt.policy.Delay(tt.retry))
+		})
+	}
+}
+
+func TestRetryPolicy_Jitter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("delay stays within jitter range", func(t *testing.T) {
+		t.Parallel()
+
+		policy := queue.JitteredBackoff(queue.FixedBackoff(10*time.Second), 0.5)
+		for range 100 {
+			delay := policy.Delay(1)
+			assert.GreaterOrEqual(t, delay, 5*time.Second)
+			assert.LessOrEqual(t, delay, 10*time.Second)
+		}
+	})
+
+	t.Run("full jitter", func(t *testing.T) {
+		t.Parallel()
+
+		policy := queue.JitteredBackoff(queue.ExponentialBackoff(time.Second, time.Minute), 1)
+		for range 100 {
+			delay := policy.Delay(3)
+			assert.GreaterOrEqual(t, delay, time.Duration(0))
+			assert.LessOrEqual(t, delay, 4*time.Second)
+		}
+	})
+
+	t.Run("fraction is clamped", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, 1.0, queue.JitteredBackoff(queue.FixedBackoff(time.Second), 5).Jitter)
+		assert.Equal(t, 0.0, queue.JitteredBackoff(queue.FixedBackoff(time.Second), -1).Jitter)
+	})
+}
+
+func TestRetryPolicy_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "linear backoff 30s", queue.DefaultRetryPolicy.String())
+	assert.Equal(t, "exponential backoff 1s (max 1m0s) with 50% jitter",
+		queue.JitteredBackoff(queue.ExponentialBackoff(time.Second, time.Minute), 0.5).String())
+}
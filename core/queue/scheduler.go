@@ -38,6 +38,14 @@ type Scheduler struct {
 	wg              sync.WaitGroup
 	shutdownTimeout time.Duration
 
+	// Leader election
+	elector        LeaderElector
+	leaseTTL       time.Duration
+	leader         atomic.Bool
+	leaseUntil     atomic.Int64 // Unix nanoseconds when the held lease runs out
+	leaderSince    atomic.Int64 // Unix timestamp of the last leadership acquisition
+	electionFailed atomic.Bool
+
 	// Observability metrics
 	tasksScheduled atomic.Int64
 	activeChecks   atomic.Int32
@@ -50,6 +58,8 @@ type SchedulerStats struct {
 	ActiveChecks   int32     // Number of check operations currently running
 	IsRunning      bool      // Whether the scheduler is currently running
 	LastActivityAt time.Time // Timestamp of last task scheduling (zero if never)
+	IsLeader       bool      // Whether this instance creates periodic tasks (always true without a LeaderElector while running)
+	LeaderSince    time.Time // Timestamp of the last leadership acquisition (zero without a LeaderElector)
 }
 
 // scheduledTask holds configuration for a periodic task
@@ -72,6 +82,7 @@ func NewScheduler(repo SchedulerRepository, opts ...SchedulerOption) (*Scheduler
 	options := &schedulerOptions{
 		checkInterval:   30 * time.Second,
 		shutdownTimeout: 30 * time.Second,
+		leaseTTL:        15 * time.Second,
 		logger:          slog.New(slog.NewTextHandler(io.Discard, nil)), // No-op logger by default
 	}
 
@@ -86,6 +97,8 @@ func NewScheduler(repo SchedulerRepository, opts ...SchedulerOption) (*Scheduler
 		interval:        options.checkInterval,
 		shutdownTimeout: options.shutdownTimeout,
 		logger:          options.logger,
+		elector:         options.elector,
+		leaseTTL:        options.leaseTTL,
 	}, nil
 }
 
@@ -97,6 +110,7 @@ func NewSchedulerFromConfig(cfg Config, repo SchedulerRepository, opts ...Schedu
 	allOpts := append([]SchedulerOption{
 		WithCheckInterval(cfg.CheckInterval),
 		WithSchedulerShutdownTimeout(cfg.ShutdownTimeout),
+		WithLeaseTTL(cfg.LeaseTTL),
 	}, opts...)
 
 	return NewScheduler(repo, allOpts...)
@@ -166,6 +180,16 @@ func (s *Scheduler) Start(ctx context.Context) error {
 		slog.Int("task_count", taskCount),
 		slog.Duration("check_interval", s.interval))
 
+	// Followers keep campaigning so one of them takes over when the leader dies
+	var leaseC <-chan time.Time
+	if s.elector != nil {
+		leaseTicker := time.NewTicker(s.renewInterval())
+		defer leaseTicker.Stop()
+		defer s.resign()
+		leaseC = leaseTicker.C
+		s.campaign(s.ctx)
+	}
+
 	s.checkTasksWithWait()
 
 	for {
@@ -176,8 +200,94 @@ func (s *Scheduler) Start(ctx context.Context) error {
 			return s.ctx.Err()
 		case <-s.ticker.C:
 			s.checkTasksWithWait()
+		case <-leaseC:
+			if s.campaign(s.ctx) {
+				// A new leader catches up right away instead of waiting for the next check
+				s.checkTasksWithWait()
+			}
+		}
+	}
+}
+
+// renewInterval returns how often the lease is acquired or renewed.
+func (s *Scheduler) renewInterval() time.Duration {
+	return s.leaseTTL / 3
+}
+
+// campaign acquires or renews the leadership lease.
+// Returns true if this call made the scheduler the leader.
+func (s *Scheduler) campaign(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, s.renewInterval())
+	defer cancel()
+
+	// The lease is counted from before the request, so the local view never outlives the real lease
+	start := time.Now()
+	acquired, err := s.elector.TryAcquire(ctx, s.leaseTTL)
+	if err != nil {
+		s.electionFailed.Store(true)
+		s.logger.ErrorContext(ctx, "failed to acquire scheduler lease",
+			slog.String("error", err.Error()))
+
+		// The elector knows the lease is gone: another instance may lead already
+		if errors.Is(err, ErrLeadershipLost) {
+			s.leaseUntil.Store(0)
+			if s.leader.Swap(false) {
+				s.logger.WarnContext(ctx, "scheduler leadership lost: lease released by the backend")
+			}
+			return false
 		}
+
+		// Keep leading until the lease that was already granted runs out
+		if s.leader.Load() && !s.holdsLease() {
+			s.leader.Store(false)
+			s.logger.WarnContext(ctx, "scheduler leadership lost: lease expired")
+		}
+		return false
+	}
+	s.electionFailed.Store(false)
+
+	if !acquired {
+		if s.leader.Swap(false) {
+			s.logger.WarnContext(ctx, "scheduler leadership lost: lease taken by another instance")
+		}
+		return false
+	}
+
+	s.leaseUntil.Store(start.Add(s.leaseTTL).UnixNano())
+	if s.leader.Swap(true) {
+		return false
 	}
+
+	s.leaderSince.Store(time.Now().Unix())
+	s.logger.InfoContext(ctx, "scheduler became leader",
+		slog.Duration("lease_ttl", s.leaseTTL))
+	return true
+}
+
+// resign releases the lease on shutdown so a standby instance takes over without waiting for expiry.
+func (s *Scheduler) resign() {
+	s.leader.Store(false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	if err := s.elector.Release(ctx); err != nil {
+		s.logger.WarnContext(ctx, "failed to release scheduler lease",
+			slog.String("error", err.Error()))
+	}
+}
+
+// holdsLease reports whether the scheduler holds an unexpired leadership lease.
+func (s *Scheduler) holdsLease() bool {
+	return s.leader.Load() && time.Now().UnixNano() < s.leaseUntil.Load()
+}
+
+// isLeader reports whether the scheduler may create periodic tasks.
+func (s *Scheduler) isLeader() bool {
+	if s.elector == nil {
+		return true
+	}
+	return s.holdsLease()
 }
 
 // Stop gracefully shuts down the scheduler with a timeout.
@@ -260,6 +370,10 @@ func (s *Scheduler) checkTasksWithWait() {
 
 	defer s.wg.Done()
 
+	if !s.isLeader() {
+		return
+	}
+
 	// Track active checks for metrics
 	s.activeChecks.Add(1)
 	defer s.activeChecks.Add(-1)
@@ -436,11 +550,18 @@ func (s *Scheduler) Stats() SchedulerStats {
 		lastActivityTime = time.Unix(lastActivity, 0)
 	}
 
+	var leaderSinceTime time.Time
+	if since := s.leaderSince.Load(); since > 0 {
+		leaderSinceTime = time.Unix(since, 0)
+	}
+
 	return SchedulerStats{
 		TasksScheduled: s.tasksScheduled.Load(),
 		ActiveChecks:   s.activeChecks.Load(),
 		IsRunning:      isRunning,
 		LastActivityAt: lastActivityTime,
+		IsLeader:       isRunning && s.isLeader(),
+		LeaderSince:    leaderSinceTime,
 	}
 }
 
@@ -451,6 +572,9 @@ func (s *Scheduler) Stats() SchedulerStats {
 // Health criteria:
 //   - Scheduler must be running
 //   - Must have at least one registered task
+//   - The last leader election attempt must have reached the elector's backend
+//
+// A standby instance that is not the leader is healthy.
 //
 // Use with health check frameworks:
 //
@@ -460,6 +584,7 @@ func (s *Scheduler) Stats() SchedulerStats {
 //
 //	if errors.Is(err, queue.ErrSchedulerNotRunning) { ... }
 //	if errors.Is(err, queue.ErrNoTasksRegistered) { ... }
+//	if errors.Is(err, queue.ErrLeaderElection) { ... }
 func (s *Scheduler) Healthcheck(ctx context.Context) error {
 	stats := s.Stats()
 
@@ -475,5 +600,9 @@ func (s *Scheduler) Healthcheck(ctx context.Context) error {
 		return errors.Join(ErrHealthcheckFailed, ErrNoTasksRegistered)
 	}
 
+	if s.electionFailed.Load() {
+		return errors.Join(ErrHealthcheckFailed, ErrLeaderElection)
+	}
+
 	return nil
 }
@@ -12,6 +12,8 @@ type schedulerOptions struct {
 	checkInterval   time.Duration
 	shutdownTimeout time.Duration
 	logger          *slog.Logger
+	elector         LeaderElector
+	leaseTTL        time.Duration
 }
 
 // WithCheckInterval configures how frequently the scheduler checks for due tasks.
@@ -44,6 +46,28 @@ func WithSchedulerLogger(logger *slog.Logger) SchedulerOption {
 	}
 }
 
+// WithLeaderElector makes the scheduler create periodic tasks only while it holds
+// the elector's lease, so one instance per cluster fires periodic tasks.
+// Other instances stand by and take over once the leader's lease expires.
+func WithLeaderElector(elector LeaderElector) SchedulerOption {
+	return func(o *schedulerOptions) {
+		if elector != nil {
+			o.elector = elector
+		}
+	}
+}
+
+// WithLeaseTTL configures how long a leadership lease lasts without renewal.
+// The leader renews the lease every third of the TTL, so a crashed leader is
+// replaced within one TTL. Only used together with WithLeaderElector.
+func WithLeaseTTL(d time.Duration) SchedulerOption {
+	return func(o *schedulerOptions) {
+		if d > 0 {
+			o.leaseTTL = d
+		}
+	}
+}
+
 // SchedulerTaskOption is a functional option for configuring a scheduled task
 type SchedulerTaskOption func(*schedulerTaskOptions)
 
@@ -0,0 +1,22 @@
+package queue
+
+import "context"
+
+// StatsRepository is implemented by storages that can summarise the tasks they hold
+// for monitoring, e.g. by the queue/admin dashboard.
+type StatsRepository interface {
+	// CountTasks returns the number of stored tasks by queue and status,
+	// sorted by queue, then status. Combinations without tasks are omitted.
+	CountTasks(ctx context.Context) ([]TaskCount, error)
+
+	// ListRetryingTasks returns up to limit pending tasks whose last attempt failed,
+	// soonest retry first. A non-positive limit returns all of them.
+	ListRetryingTasks(ctx context.Context, limit int) ([]*Task, error)
+}
+
+// TaskCount is the number of stored tasks in one queue with one status.
+type TaskCount struct {
+	Queue  string     `json:"queue"`
+	Status TaskStatus `json:"status"`
+	Count  int64      `json:"count"`
+}
@@ -25,6 +25,8 @@ const (
 	TaskStatusProcessing TaskStatus = "processing"
 	TaskStatusCompleted  TaskStatus = "completed"
 	TaskStatusFailed     TaskStatus = "failed"
+	TaskStatusWaiting    TaskStatus = "waiting"   // Workflow task waiting for its parent to complete
+	TaskStatusCancelled  TaskStatus = "cancelled" // Cancelled with Enqueuer.Cancel before it finished
 )
 
 // Priority represents task priority (0-100, higher is more important)
@@ -62,6 +64,36 @@ type Task struct {
 	ProcessedAt *time.Time `json:"processed_at,omitempty"`
 	Error       *string    `json:"error,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
+
+	// RetryPolicy overrides the handler and worker retry backoff for this task
+	RetryPolicy *RetryPolicy `json:"retry_policy,omitempty"`
+
+	// UniqueKey deduplicates tasks: the key is held while the task is pending or
+	// processing, and after that until UniqueUntil
+	UniqueKey   *string    `json:"unique_key,omitempty"`
+	UniqueUntil *time.Time `json:"unique_until,omitempty"`
+
+	// Workflow links of tasks created by EnqueueWorkflow. ParentID is the task or
+	// group that must complete before a waiting task is activated; error callbacks
+	// use the workflow ID as their parent.
+	WorkflowID *uuid.UUID `json:"workflow_id,omitempty"`
+	ParentID   *uuid.UUID `json:"parent_id,omitempty"`
+	GroupID    *uuid.UUID `json:"group_id,omitempty"`
+
+	// Timeout limits a single execution of the task; zero falls back to the handler timeout
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// CancelRequested is set when a processing task is cancelled; its worker stops
+	// the handler on the next check and finishes the task as cancelled
+	CancelRequested bool `json:"cancel_requested,omitempty"`
+
+	// Result is recorded by the handler with SetResult; ParentResult is handed
+	// over from the parent when a waiting task is activated
+	Result       []byte `json:"result,omitempty"`
+	ParentResult []byte `json:"parent_result,omitempty"`
+
+	// ResultTTL is how long a completed task and its result are kept; zero keeps them
+	ResultTTL time.Duration `json:"result_ttl,omitempty"`
 }
 
 // TasksDlq represents a task in the dead letter queue
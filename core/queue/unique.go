@@ -0,0 +1,45 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UniqueConflictMode decides what happens when a task is enqueued with a
+// unique key that is already held by another task.
+type UniqueConflictMode string
+
+const (
+	// UniqueConflictReject fails the enqueue with ErrDuplicateTask.
+	UniqueConflictReject UniqueConflictMode = "reject"
+	// UniqueConflictReplace replaces a pending holder with the new task and takes
+	// the key over from a finished one. A processing holder cannot be replaced,
+	// so the enqueue fails with ErrDuplicateTask.
+	UniqueConflictReplace UniqueConflictMode = "replace"
+	// UniqueConflictKeepExisting silently drops the new task and keeps the holder.
+	UniqueConflictKeepExisting UniqueConflictMode = "keep-existing"
+)
+
+// UniqueTaskRepository is implemented by storages that enforce task unique keys.
+// Enqueue uses it for tasks created with WithUniqueKey.
+type UniqueTaskRepository interface {
+	// CreateUniqueTask stores the task unless its unique key is held, resolving
+	// conflicts according to mode. The check and the write must be atomic.
+	// Returns the ID of the task holding the key afterwards: the new task's ID,
+	// or the existing holder's ID when mode is UniqueConflictKeepExisting.
+	CreateUniqueTask(ctx context.Context, task *Task, mode UniqueConflictMode) (uuid.UUID, error)
+}
+
+// holdsUniqueKey reports whether the task still holds its unique key:
+// while it is pending or processing, and after that until its window expires.
+func (t *Task) holdsUniqueKey(now time.Time) bool {
+	if t.UniqueKey == nil {
+		return false
+	}
+	if t.Status == TaskStatusPending || t.Status == TaskStatusProcessing {
+		return true
+	}
+	return t.UniqueUntil != nil && t.UniqueUntil.After(now)
+}
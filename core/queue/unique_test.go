@@ -0,0 +1,193 @@
+package queue_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+type invoicePayload struct {
+	InvoiceID string `json:"invoice_id"`
+	Attempt   int    `json:"attempt"`
+}
+
+func TestEnqueuer_UniqueKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	queues := []string{queue.DefaultQueueName}
+
+	newEnqueuer := func(t *testing.T) (*queue.Enqueuer, *queue.MemoryStorage) {
+		t.Helper()
+		storage := queue.NewMemoryStorage()
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+		return enqueuer, storage
+	}
+
+	t.Run("rejects duplicate while pending", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, _ := newEnqueuer(t)
+
+		_, err := enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-1"}, queue.WithUniqueKey("invoice:inv-1", time.Minute))
+		require.NoError(t, err)
+
+		_, err = enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-1"}, queue.WithUniqueKey("invoice:inv-1", time.Minute))
+		assert.ErrorIs(t, err, queue.ErrDuplicateTask)
+
+		// Different keys do not conflict
+		_, err = enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-2"}, queue.WithUniqueKey("invoice:inv-2", time.Minute))
+		require.NoError(t, err)
+	})
+
+	t.Run("keep existing drops the new task", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, storage := newEnqueuer(t)
+
+		firstID, err := enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-1", Attempt: 1}, queue.WithUniqueKey("invoice:inv-1", time.Minute))
+		require.NoError(t, err)
+		keptID, err := enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-1", Attempt: 2},
+			queue.WithUniqueKey("invoice:inv-1", time.Minute),
+			queue.WithUniqueConflictMode(queue.UniqueConflictKeepExisting),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, firstID, keptID, "the existing task's ID is returned")
+
+		claimed, err := storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		require.NoError(t, err)
+		assertAttempt(t, claimed, 1)
+
+		_, err = storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		assert.ErrorIs(t, err, queue.ErrNoTaskToClaim)
+	})
+
+	t.Run("replace swaps a pending task", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, storage := newEnqueuer(t)
+
+		_, err := enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-1", Attempt: 1}, queue.WithUniqueKey("invoice:inv-1", time.Minute))
+		require.NoError(t, err)
+		_, err = enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-1", Attempt: 2},
+			queue.WithUniqueKey("invoice:inv-1", time.Minute),
+			queue.WithUniqueConflictMode(queue.UniqueConflictReplace),
+		)
+		require.NoError(t, err)
+
+		claimed, err := storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		require.NoError(t, err)
+		assertAttempt(t, claimed, 2)
+
+		_, err = storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		assert.ErrorIs(t, err, queue.ErrNoTaskToClaim)
+	})
+
+	t.Run("replace cannot swap a processing task", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, storage := newEnqueuer(t)
+
+		_, err := enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-1"}, queue.WithUniqueKey("invoice:inv-1", time.Minute))
+		require.NoError(t, err)
+		_, err = storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		require.NoError(t, err)
+
+		_, err = enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-1"},
+			queue.WithUniqueKey("invoice:inv-1", time.Minute),
+			queue.WithUniqueConflictMode(queue.UniqueConflictReplace),
+		)
+		assert.ErrorIs(t, err, queue.ErrDuplicateTask)
+	})
+
+	t.Run("finished task holds the key until the window expires", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, storage := newEnqueuer(t)
+
+		_, err := enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-1"}, queue.WithUniqueKey("invoice:inv-1", time.Hour))
+		require.NoError(t, err)
+		claimed, err := storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, storage.CompleteTask(ctx, claimed.ID, nil))
+
+		_, err = enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-1"}, queue.WithUniqueKey("invoice:inv-1", time.Hour))
+		assert.ErrorIs(t, err, queue.ErrDuplicateTask)
+
+		// Replace takes the key over from a finished task
+		_, err = enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-1"},
+			queue.WithUniqueKey("invoice:inv-1", time.Hour),
+			queue.WithUniqueConflictMode(queue.UniqueConflictReplace),
+		)
+		require.NoError(t, err)
+	})
+
+	t.Run("finished task releases the key after the window", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, storage := newEnqueuer(t)
+
+		_, err := enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-1"}, queue.WithUniqueKey("invoice:inv-1", 0))
+		require.NoError(t, err)
+		claimed, err := storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, storage.CompleteTask(ctx, claimed.ID, nil))
+
+		_, err = enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-1"}, queue.WithUniqueKey("invoice:inv-1", 0))
+		require.NoError(t, err)
+	})
+
+	t.Run("concurrent enqueues create one task", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, _ := newEnqueuer(t)
+
+		var (
+			wg        sync.WaitGroup
+			succeeded atomic.Int32
+		)
+		for range 20 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-1"}, queue.WithUniqueKey("invoice:inv-1", time.Minute))
+				if err == nil {
+					succeeded.Add(1)
+				} else {
+					assert.ErrorIs(t, err, queue.ErrDuplicateTask)
+				}
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), succeeded.Load())
+	})
+
+	t.Run("requires unique task repository", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, err := queue.NewEnqueuer(&mockEnqueuerRepo{})
+		require.NoError(t, err)
+
+		_, err = enqueuer.Enqueue(ctx, invoicePayload{InvoiceID: "inv-1"}, queue.WithUniqueKey("invoice:inv-1", time.Minute))
+		assert.ErrorIs(t, err, queue.ErrUniqueTasksNotSupported)
+	})
+}
+
+func assertAttempt(t *testing.T, task *queue.Task, attempt int) {
+	t.Helper()
+
+	var payload invoicePayload
+	require.NoError(t, json.Unmarshal(task.Payload, &payload))
+	assert.Equal(t, attempt, payload.Attempt)
+}
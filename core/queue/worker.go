@@ -6,12 +6,17 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"os"
+	"runtime/debug"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/dmitrymomot/foundation/pkg/ratelimiter"
 )
 
 // WorkerRepository defines the interface for worker operations
@@ -19,24 +24,29 @@ type WorkerRepository interface {
 	// ClaimTask atomically claims the next available task
 	ClaimTask(ctx context.Context, workerID uuid.UUID, queues []string, lockDuration time.Duration) (*Task, error)
 
-	// CompleteTask marks task as completed
-	CompleteTask(ctx context.Context, taskID uuid.UUID) error
+	// CompleteTask marks task as completed and stores the result recorded with SetResult (nil if none)
+	CompleteTask(ctx context.Context, taskID uuid.UUID, result []byte) error
 
-	// FailTask marks task as failed and increments retry count
-	FailTask(ctx context.Context, taskID uuid.UUID, errorMsg string) error
+	// FailTask marks task as failed and increments retry count.
+	// If retries remain, the task is rescheduled to run after retryDelay.
+	FailTask(ctx context.Context, taskID uuid.UUID, errorMsg string, retryDelay time.Duration) error
 
 	// MoveToDLQ moves task to dead letter queue
 	MoveToDLQ(ctx context.Context, taskID uuid.UUID) error
 
-	// ExtendLock extends the lock timeout for long-running tasks (optional)
-	ExtendLock(ctx context.Context, taskID uuid.UUID, duration time.Duration) error
+	// ExtendLock extends the lock of a processing task held by workerID.
+	// Returns ErrLockLost if the task is gone, finished or locked by another worker.
+	ExtendLock(ctx context.Context, taskID, workerID uuid.UUID, duration time.Duration) error
 }
 
 // Worker processes tasks from the queue
 type Worker struct {
 	repo     WorkerRepository
+	cancels  CancelRepository // nil if the repository does not support cancellation
 	handlers map[string]Handler
 	queues   []string
+	weights  map[string]int                     // Queue weights; nil claims from all queues at once
+	limiters map[string]ratelimiter.RateLimiter // Rate limiters by queue
 	workerID uuid.UUID
 	sem      chan struct{}
 	wg       sync.WaitGroup
@@ -45,7 +55,12 @@ type Worker struct {
 	// Configuration
 	pullInterval    time.Duration
 	lockTimeout     time.Duration
+	taskTimeout     time.Duration
+	heartbeat       time.Duration
 	shutdownTimeout time.Duration
+	retryPolicy     RetryPolicy
+	cancelCheck     time.Duration
+	middleware      []Middleware
 	logger          *slog.Logger
 
 	// State management
@@ -53,9 +68,15 @@ type Worker struct {
 	cancel   context.CancelFunc
 	stopping atomic.Bool
 
+	throttleMu sync.Mutex
+	throttled  map[string]time.Time // Rate-limited queues skipped until the given time
+
 	// Observability metrics
 	tasksProcessed atomic.Int64
 	tasksFailed    atomic.Int64
+	tasksCancelled atomic.Int64
+	tasksTimedOut  atomic.Int64
+	rateLimited    atomic.Int64
 	activeTasks    atomic.Int32
 	lastActivityAt atomic.Int64 // Unix timestamp of last task processing
 }
@@ -64,6 +85,9 @@ type Worker struct {
 type WorkerStats struct {
 	TasksProcessed int64     // Total number of successfully completed tasks
 	TasksFailed    int64     // Total number of failed tasks (including those moved to DLQ)
+	TasksCancelled int64     // Total number of running tasks stopped by Enqueuer.Cancel
+	TasksTimedOut  int64     // Failed tasks that exceeded their timeout (included in TasksFailed)
+	RateLimited    int64     // Claims from a queue denied by its rate limiter
 	ActiveTasks    int32     // Number of tasks currently being processed
 	IsRunning      bool      // Whether the worker is currently running
 	LastActivityAt time.Time // Timestamp of last task processing (zero if never)
@@ -82,6 +106,8 @@ func NewWorker(repo WorkerRepository, opts ...WorkerOption) (*Worker, error) {
 		lockTimeout:        5 * time.Minute,
 		shutdownTimeout:    30 * time.Second,
 		maxConcurrentTasks: 1,
+		retryPolicy:        DefaultRetryPolicy,
+		cancelCheck:        time.Second,
 		logger:             slog.New(slog.NewTextHandler(io.Discard, nil)), // No-op logger by default
 	}
 
@@ -90,15 +116,41 @@ func NewWorker(repo WorkerRepository, opts ...WorkerOption) (*Worker, error) {
 		opt(options)
 	}
 
+	// Extending well before expiry leaves room for a few failed attempts
+	if options.heartbeat <= 0 || options.heartbeat >= options.lockTimeout {
+		options.heartbeat = options.lockTimeout / 3
+	}
+
+	// Without a timeout of their own, tasks are bounded as before heartbeats kept locks alive
+	if options.taskTimeout <= 0 {
+		options.taskTimeout = options.lockTimeout
+	}
+
+	// Weights define the queue list; sorted for stable logs and Queues output
+	if len(options.weights) > 0 {
+		options.queues = slices.Sorted(maps.Keys(options.weights))
+	}
+
+	cancels, _ := repo.(CancelRepository)
+
 	return &Worker{
 		repo:            repo,
+		cancels:         cancels,
 		handlers:        make(map[string]Handler),
 		queues:          options.queues,
+		weights:         options.weights,
+		limiters:        options.limiters,
+		throttled:       make(map[string]time.Time),
 		workerID:        uuid.New(),
 		sem:             make(chan struct{}, options.maxConcurrentTasks),
 		pullInterval:    options.pullInterval,
 		lockTimeout:     options.lockTimeout,
+		taskTimeout:     options.taskTimeout,
+		heartbeat:       options.heartbeat,
 		shutdownTimeout: options.shutdownTimeout,
+		retryPolicy:     options.retryPolicy,
+		cancelCheck:     options.cancelCheck,
+		middleware:      options.middleware,
 		logger:          options.logger,
 	}, nil
 }
@@ -111,9 +163,11 @@ func NewWorkerFromConfig(cfg Config, repo WorkerRepository, opts ...WorkerOption
 	allOpts := append([]WorkerOption{
 		WithPullInterval(cfg.PollInterval),
 		WithLockTimeout(cfg.LockTimeout),
+		WithDefaultTaskTimeout(cfg.DefaultTaskTimeout),
 		WithShutdownTimeout(cfg.ShutdownTimeout),
 		WithMaxConcurrentTasks(cfg.MaxConcurrentTasks),
 		WithQueues(cfg.Queues...),
+		WithQueueWeights(cfg.QueueWeights),
 	}, opts...)
 
 	return NewWorker(repo, allOpts...)
@@ -279,7 +333,7 @@ func (w *Worker) Run(ctx context.Context) func() error {
 
 // pullAndProcess pulls a task and processes it.
 func (w *Worker) pullAndProcess() error {
-	task, err := w.repo.ClaimTask(w.ctx, w.workerID, w.queues, w.lockTimeout)
+	task, err := w.claimTask()
 	if err != nil {
 		if errors.Is(err, ErrNoTaskToClaim) {
 			return nil
@@ -307,17 +361,13 @@ func (w *Worker) processTask(task *Task) (retErr error) {
 	w.activeTasks.Add(1)
 	defer w.activeTasks.Add(-1)
 
-	// Panic recovery ensures system stability
+	// Last-resort panic recovery: handler panics are already turned into errors by
+	// RecoverMiddleware, this catches panics in middleware and the worker's own bookkeeping
 	// Strategy: Treat panics as task failures with retry eligibility
-	// This prevents a single bad handler from crashing the entire worker
 	defer func() {
 		if r := recover(); r != nil {
-			retErr = fmt.Errorf("panic in handler: %v", r)
-			w.logger.ErrorContext(w.ctx, "handler panicked",
-				slog.String("worker_id", w.workerID.String()),
-				slog.String("task_id", task.ID.String()),
-				slog.String("task_name", task.TaskName),
-				slog.Any("panic", r))
+			// handleTaskFailure logs the panic with its stack
+			retErr = &PanicError{Value: r, Stack: debug.Stack()}
 			duration := time.Since(start)
 			_ = w.handleTaskFailure(task, retErr, duration)
 		}
@@ -333,18 +383,188 @@ func (w *Worker) processTask(task *Task) (retErr error) {
 
 	// Isolation strategy: Create independent context for task execution
 	// Rationale: Worker shutdown should not interrupt running tasks
-	// Tasks get full lockTimeout to complete even during graceful shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), w.lockTimeout)
+	// The lock is kept alive by the heartbeat, so only the task timeout bounds execution
+	timeout := w.timeoutFor(task, handler)
+	ctx, cancel := context.WithTimeoutCause(context.Background(), timeout, ErrTaskTimeout)
 	defer cancel()
 
-	err := handler.Handle(ctx, task.Payload)
+	ctx, cancelTask := context.WithCancelCause(ctx)
+	defer cancelTask(nil)
+	stopHeartbeat := w.heartbeatLock(ctx, task, cancelTask)
+	stopWatching := w.watchCancellation(ctx, task.ID, cancelTask)
+
+	ctx, state := withTaskState(ctx, task)
+	ctx = withTaskInfo(ctx, task, w.workerID)
+
+	err := w.chain(handler)(ctx, task.Payload)
 	duration := time.Since(start)
+	stopWatching()
+	stopHeartbeat()
+
+	cause := context.Cause(ctx)
+	if errors.Is(cause, ErrLockLost) {
+		// Another worker may own the task by now: leave its state alone
+		w.logger.WarnContext(w.ctx, "task lock lost, result discarded",
+			slog.String("worker_id", w.workerID.String()),
+			slog.String("task_id", task.ID.String()),
+			slog.String("task_name", task.TaskName),
+			slog.Duration("duration", duration))
+		return fmt.Errorf("task %s: %w", task.ID, ErrLockLost)
+	}
+
+	if errors.Is(cause, ErrTaskCancelled) || (err != nil && w.cancelRequested(task.ID)) {
+		return w.handleTaskCancelled(task, duration)
+	}
 
 	if err != nil {
+		if errors.Is(cause, ErrTaskTimeout) {
+			w.tasksTimedOut.Add(1)
+			err = fmt.Errorf("%w after %s: %w", ErrTaskTimeout, timeout, err)
+		}
 		return w.handleTaskFailure(task, err, duration)
 	}
 
-	return w.handleTaskSuccess(task, duration)
+	return w.handleTaskSuccess(task, state.result, duration)
+}
+
+// chain wraps the handler with worker middleware, then handler middleware.
+// Panics are recovered right around the handler, so every middleware sees them as errors.
+func (w *Worker) chain(handler Handler) HandlerFunc {
+	middleware := slices.Clip(w.middleware)
+	if h, ok := handler.(configuredHandler); ok {
+		middleware = append(middleware, h.handlerOptions().middleware...)
+	}
+	return ApplyMiddleware(handler.Handle, append(middleware, RecoverMiddleware())...)
+}
+
+// timeoutFor resolves the execution timeout of a task: task, then handler, then the worker default.
+func (w *Worker) timeoutFor(task *Task, handler Handler) time.Duration {
+	if task.Timeout > 0 {
+		return task.Timeout
+	}
+	if h, ok := handler.(configuredHandler); ok && h.handlerOptions().timeout > 0 {
+		return h.handlerOptions().timeout
+	}
+	return w.taskTimeout
+}
+
+// heartbeatLock extends the task lock every heartbeat interval while the handler runs.
+// When the lock is lost, or cannot be extended before it expires, the task context is
+// cancelled with ErrLockLost: another worker may claim the task at any moment.
+func (w *Worker) heartbeatLock(ctx context.Context, task *Task, cancel context.CancelCauseFunc) (stop func()) {
+	lockedUntil := time.Now().Add(w.lockTimeout)
+	if task.LockedUntil != nil {
+		lockedUntil = *task.LockedUntil
+	}
+
+	return w.every(ctx, w.heartbeat, func() bool {
+		err := w.repo.ExtendLock(ctx, task.ID, w.workerID, w.lockTimeout)
+		if err == nil {
+			lockedUntil = time.Now().Add(w.lockTimeout)
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+
+		w.logger.WarnContext(w.ctx, "failed to extend task lock",
+			slog.String("worker_id", w.workerID.String()),
+			slog.String("task_id", task.ID.String()),
+			slog.String("task_name", task.TaskName),
+			slog.String("error", err.Error()))
+
+		if errors.Is(err, ErrLockLost) || time.Now().After(lockedUntil) {
+			cancel(ErrLockLost)
+			return false
+		}
+		return true
+	})
+}
+
+// watchCancellation polls the repository for a cancellation request while the task
+// runs and cancels its context with ErrTaskCancelled when one is found.
+func (w *Worker) watchCancellation(ctx context.Context, taskID uuid.UUID, cancel context.CancelCauseFunc) (stop func()) {
+	if w.cancels == nil {
+		return func() {}
+	}
+
+	return w.every(ctx, w.cancelCheck, func() bool {
+		if w.cancelRequested(taskID) {
+			cancel(ErrTaskCancelled)
+			return false
+		}
+		return true
+	})
+}
+
+// every runs fn on each tick of interval until fn returns false or ctx is done.
+// The returned function stops the loop and waits for it to exit, so fn never
+// runs after the task has been finished.
+func (w *Worker) every(ctx context.Context, interval time.Duration, fn func() bool) (stop func()) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if !fn() {
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-exited
+	}
+}
+
+// cancelRequested reports whether the task was cancelled. Lookup errors are logged
+// and treated as no request, so a flaky storage never stops a healthy task.
+func (w *Worker) cancelRequested(taskID uuid.UUID) bool {
+	if w.cancels == nil {
+		return false
+	}
+
+	requested, err := w.cancels.IsCancelRequested(w.ctx, taskID)
+	if err != nil {
+		w.logger.WarnContext(w.ctx, "failed to check task cancellation",
+			slog.String("worker_id", w.workerID.String()),
+			slog.String("task_id", taskID.String()),
+			slog.String("error", err.Error()))
+		return false
+	}
+	return requested
+}
+
+// handleTaskCancelled finishes a task whose cancellation was requested while it ran.
+// Cancelled tasks are not retried, whatever the handler returned.
+func (w *Worker) handleTaskCancelled(task *Task, duration time.Duration) error {
+	if err := w.cancels.MarkCancelled(w.ctx, task.ID); err != nil {
+		return fmt.Errorf("failed to mark task %s as cancelled: %w", task.ID, err)
+	}
+
+	w.tasksCancelled.Add(1)
+	w.lastActivityAt.Store(time.Now().Unix())
+
+	w.logger.InfoContext(w.ctx, "task cancelled",
+		slog.String("worker_id", w.workerID.String()),
+		slog.String("task_id", task.ID.String()),
+		slog.String("task_name", task.TaskName),
+		slog.String("queue", task.Queue),
+		slog.Duration("duration", duration))
+
+	return nil
 }
 
 // handleMissingHandler processes tasks that have no registered handler
@@ -365,7 +585,7 @@ func (w *Worker) handleMissingHandler(task *Task) error {
 		slog.String("task_name", task.TaskName))
 
 	errorMsg := "no handler registered for task type: " + task.TaskName
-	if err := w.repo.FailTask(w.ctx, task.ID, errorMsg); err != nil {
+	if err := w.repo.FailTask(w.ctx, task.ID, errorMsg, 0); err != nil {
 		return fmt.Errorf("failed to mark task %s as failed: %w", task.ID, err)
 	}
 
@@ -380,13 +600,15 @@ func (w *Worker) handleMissingHandler(task *Task) error {
 //
 // Retry decision logic:
 // 1. Always calls FailTask first to record the error and increment retry count
-// 2. Checks if task has exhausted all retries (RetryCount >= MaxRetries)
+// 2. Checks if task has exhausted all retries (RetryCount+1 >= MaxRetries, counting this failure)
 // 3. If retries remain: FailTask already reset task to "pending" with backoff
 // 4. If no retries remain: Move to DLQ for manual inspection
 //
+// The retry delay is computed here from the task, handler or worker retry policy
+// (in that order of precedence), so storages only persist the resulting schedule.
+//
 // The separation of FailTask and MoveToDLQ allows the storage layer to:
 // - Track failure history and error messages
-// - Implement exponential backoff strategies
 // - Maintain audit trails of task processing attempts
 func (w *Worker) handleTaskFailure(task *Task, execErr error, duration time.Duration) error {
 	w.tasksFailed.Add(1)
@@ -401,11 +623,23 @@ func (w *Worker) handleTaskFailure(task *Task, execErr error, duration time.Dura
 		slog.Duration("duration", duration),
 		slog.String("error", execErr.Error()))
 
-	if err := w.repo.FailTask(w.ctx, task.ID, execErr.Error()); err != nil {
+	var pe *PanicError
+	if errors.As(execErr, &pe) {
+		w.logger.ErrorContext(w.ctx, "handler panicked",
+			slog.String("worker_id", w.workerID.String()),
+			slog.String("task_id", task.ID.String()),
+			slog.String("task_name", task.TaskName),
+			slog.Any("panic", pe.Value),
+			slog.String("stack", string(pe.Stack)))
+	}
+
+	retryDelay := w.retryPolicyFor(task).Delay(int(task.RetryCount) + 1)
+	if err := w.repo.FailTask(w.ctx, task.ID, execErr.Error(), retryDelay); err != nil {
 		return fmt.Errorf("failed to update task %s status to failed: %w", task.ID, err)
 	}
 
-	if task.RetryCount >= task.MaxRetries {
+	// task holds the retry count from before FailTask recorded this failure
+	if task.RetryCount+1 >= task.MaxRetries {
 		if err := w.repo.MoveToDLQ(w.ctx, task.ID); err != nil {
 			return fmt.Errorf("failed to move task %s to DLQ after max retries: %w", task.ID, err)
 		}
@@ -421,9 +655,28 @@ func (w *Worker) handleTaskFailure(task *Task, execErr error, duration time.Dura
 	return nil
 }
 
+// retryPolicyFor resolves the retry policy of a task: task, then handler, then worker default.
+func (w *Worker) retryPolicyFor(task *Task) RetryPolicy {
+	if task.RetryPolicy != nil {
+		return *task.RetryPolicy
+	}
+
+	w.mu.RLock()
+	handler, ok := w.handlers[task.TaskName]
+	w.mu.RUnlock()
+
+	if h, isConfigured := handler.(configuredHandler); ok && isConfigured {
+		if policy := h.handlerOptions().retryPolicy; policy != nil {
+			return *policy
+		}
+	}
+
+	return w.retryPolicy
+}
+
 // handleTaskSuccess processes successful task completion.
-func (w *Worker) handleTaskSuccess(task *Task, duration time.Duration) error {
-	if err := w.repo.CompleteTask(w.ctx, task.ID); err != nil {
+func (w *Worker) handleTaskSuccess(task *Task, result []byte, duration time.Duration) error {
+	if err := w.repo.CompleteTask(w.ctx, task.ID, result); err != nil {
 		return fmt.Errorf("failed to mark task %s as completed: %w", task.ID, err)
 	}
 
@@ -440,10 +693,11 @@ func (w *Worker) handleTaskSuccess(task *Task, duration time.Duration) error {
 	return nil
 }
 
-// ExtendLockForTask extends the lock timeout for a long-running task.
-// Call this periodically for tasks that take longer than lockTimeout.
+// ExtendLockForTask extends the lock of a task held by this worker.
+// Running tasks are extended automatically by the heartbeat (see WithHeartbeatInterval);
+// call this only to push the lock further out than the lock timeout.
 func (w *Worker) ExtendLockForTask(ctx context.Context, taskID uuid.UUID, extension time.Duration) error {
-	return w.repo.ExtendLock(ctx, taskID, extension)
+	return w.repo.ExtendLock(ctx, taskID, w.workerID, extension)
 }
 
 // WorkerInfo returns identifying information about the worker instance.
@@ -505,6 +759,9 @@ func (w *Worker) Stats() WorkerStats {
 	return WorkerStats{
 		TasksProcessed: w.tasksProcessed.Load(),
 		TasksFailed:    w.tasksFailed.Load(),
+		TasksCancelled: w.tasksCancelled.Load(),
+		TasksTimedOut:  w.tasksTimedOut.Load(),
+		RateLimited:    w.rateLimited.Load(),
 		ActiveTasks:    w.activeTasks.Load(),
 		IsRunning:      isRunning,
 		LastActivityAt: lastActivityTime,
@@ -115,7 +115,7 @@ func TestWorker_Healthcheck(t *testing.T) {
 			Return(nil, queue.ErrNoTaskToClaim).Maybe()
 
 		for _, task := range tasks {
-			mockRepo.On("CompleteTask", mock.Anything, task.ID).Return(nil).Maybe()
+			mockRepo.On("CompleteTask", mock.Anything, task.ID, mock.Anything).Return(nil).Maybe()
 		}
 
 		// Handler blocks until released
@@ -3,6 +3,8 @@ package queue
 import (
 	"log/slog"
 	"time"
+
+	"github.com/dmitrymomot/foundation/pkg/ratelimiter"
 )
 
 // WorkerOption is a functional option for configuring a worker
@@ -10,21 +12,74 @@ type WorkerOption func(*workerOptions)
 
 type workerOptions struct {
 	queues             []string
+	weights            map[string]int
+	limiters           map[string]ratelimiter.RateLimiter
 	pullInterval       time.Duration
 	lockTimeout        time.Duration
+	heartbeat          time.Duration
+	taskTimeout        time.Duration
 	shutdownTimeout    time.Duration
 	maxConcurrentTasks int
+	retryPolicy        RetryPolicy
+	cancelCheck        time.Duration
+	middleware         []Middleware
 	logger             *slog.Logger
 }
 
 // WithQueues specifies which queues this worker should process tasks from.
-// Worker will claim tasks from any of the specified queues based on priority.
+// Worker will claim tasks from any of the specified queues based on priority;
+// use WithQueueWeights to share claims between the queues instead.
 func WithQueues(queues ...string) WorkerOption {
 	return func(o *workerOptions) {
 		o.queues = queues
 	}
 }
 
+// WithQueueWeights makes the worker process the given queues in proportion to their
+// weights, e.g. {"critical": 6, "default": 3, "low": 1}. Before each claim the queues
+// are ordered at random by weight and tried one by one, so a busy queue with a high
+// weight cannot starve the others. Task priority then applies within a queue only.
+// Replaces the queues set with WithQueues; non-positive weights are ignored.
+func WithQueueWeights(weights map[string]int) WorkerOption {
+	return func(o *workerOptions) {
+		valid := make(map[string]int, len(weights))
+		for queue, weight := range weights {
+			if queue != "" && weight > 0 {
+				valid[queue] = weight
+			}
+		}
+		if len(valid) > 0 {
+			o.weights = valid
+		}
+	}
+}
+
+// WithQueueRateLimit caps how fast the worker claims tasks from a queue. Every claim
+// from the queue takes a token from limiter under the key RateLimitKeyPrefix+queue;
+// give workers limiters backed by a shared ratelimiter.Store to enforce the cap
+// across all of them. A poll of an empty queue takes a token as well.
+//
+//	limiter, _ := ratelimiter.NewBucket(store, ratelimiter.Config{
+//		Capacity:       10,
+//		RefillRate:     10,
+//		RefillInterval: time.Second,
+//	})
+//	worker, _ := queue.NewWorker(storage,
+//		queue.WithQueues("default", "emails"),
+//		queue.WithQueueRateLimit("emails", limiter),
+//	)
+func WithQueueRateLimit(queue string, limiter ratelimiter.RateLimiter) WorkerOption {
+	return func(o *workerOptions) {
+		if queue == "" || limiter == nil {
+			return
+		}
+		if o.limiters == nil {
+			o.limiters = make(map[string]ratelimiter.RateLimiter)
+		}
+		o.limiters[queue] = limiter
+	}
+}
+
 // WithPullInterval configures how frequently the worker checks for new tasks.
 // Shorter intervals reduce task latency but increase database load.
 func WithPullInterval(d time.Duration) WorkerOption {
@@ -36,8 +91,9 @@ func WithPullInterval(d time.Duration) WorkerOption {
 }
 
 // WithLockTimeout sets how long a worker holds exclusive lock on a claimed task.
-// Tasks exceeding this duration may be reclaimed by other workers.
-// Use ExtendLockForTask for legitimately long-running operations.
+// The lock is extended by a heartbeat while the task runs, so this bounds how long
+// the task of a crashed worker stays locked, not how long a task may run
+// (see WithDefaultTaskTimeout).
 func WithLockTimeout(d time.Duration) WorkerOption {
 	return func(o *workerOptions) {
 		if d > 0 {
@@ -46,6 +102,18 @@ func WithLockTimeout(d time.Duration) WorkerOption {
 	}
 }
 
+// WithDefaultTaskTimeout bounds the execution of tasks that set no timeout of
+// their own, neither on the task nor with the handler's WithTimeout. A hung handler
+// is cancelled with ErrTaskTimeout and retried instead of holding its lock and
+// concurrency slot forever. Default is the lock timeout.
+func WithDefaultTaskTimeout(d time.Duration) WorkerOption {
+	return func(o *workerOptions) {
+		if d > 0 {
+			o.taskTimeout = d
+		}
+	}
+}
+
 // WithMaxConcurrentTasks limits how many tasks can be processed simultaneously.
 // Tune based on workload characteristics and available resources.
 func WithMaxConcurrentTasks(n int) WorkerOption {
@@ -75,3 +143,40 @@ func WithWorkerLogger(logger *slog.Logger) WorkerOption {
 		}
 	}
 }
+
+// WithMiddleware wraps every handler of the worker with middleware.
+// The first middleware becomes the outermost wrapper; handler middleware set with
+// WithHandlerMiddleware runs inside it. Handler panics are recovered into a
+// *PanicError before they reach any middleware.
+func WithMiddleware(middleware ...Middleware) WorkerOption {
+	return func(o *workerOptions) {
+		o.middleware = append(o.middleware, middleware...)
+	}
+}
+
+// WithDefaultRetryPolicy sets the retry backoff for tasks whose task and handler set no policy.
+func WithDefaultRetryPolicy(policy RetryPolicy) WorkerOption {
+	return func(o *workerOptions) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithCancelCheckInterval configures how often a running task is checked for cancellation
+// requested with Enqueuer.Cancel. Only used when the repository implements CancelRepository.
+func WithCancelCheckInterval(d time.Duration) WorkerOption {
+	return func(o *workerOptions) {
+		if d > 0 {
+			o.cancelCheck = d
+		}
+	}
+}
+
+// WithHeartbeatInterval sets how often the lock of a running task is extended.
+// Defaults to a third of the lock timeout; values not below the lock timeout are ignored.
+func WithHeartbeatInterval(d time.Duration) WorkerOption {
+	return func(o *workerOptions) {
+		if d > 0 {
+			o.heartbeat = d
+		}
+	}
+}
@@ -46,7 +46,7 @@ func TestWorker_ShutdownTimeout(t *testing.T) {
 			Return(nil, queue.ErrNoTaskToClaim).Maybe()
 		// CompleteTask may or may not be called depending on timing (task completes after timeout)
 		// Using .Maybe() because shutdown timeout may occur before task completes
-		mockRepo.On("CompleteTask", mock.Anything, task.ID).Return(nil).Maybe()
+		mockRepo.On("CompleteTask", mock.Anything, task.ID, mock.Anything).Return(nil).Maybe()
 
 		// Use short timeout
 		worker, err := queue.NewWorker(mockRepo,
@@ -33,13 +33,13 @@ func (m *MockWorkerRepository) ClaimTask(ctx context.Context, workerID uuid.UUID
 	return args.Get(0).(*queue.Task), args.Error(1)
 }
 
-func (m *MockWorkerRepository) CompleteTask(ctx context.Context, taskID uuid.UUID) error {
-	args := m.Called(ctx, taskID)
+func (m *MockWorkerRepository) CompleteTask(ctx context.Context, taskID uuid.UUID, result []byte) error {
+	args := m.Called(ctx, taskID, result)
 	return args.Error(0)
 }
 
-func (m *MockWorkerRepository) FailTask(ctx context.Context, taskID uuid.UUID, errorMsg string) error {
-	args := m.Called(ctx, taskID, errorMsg)
+func (m *MockWorkerRepository) FailTask(ctx context.Context, taskID uuid.UUID, errorMsg string, retryDelay time.Duration) error {
+	args := m.Called(ctx, taskID, errorMsg, retryDelay)
 	return args.Error(0)
 }
 
@@ -48,8 +48,8 @@ func (m *MockWorkerRepository) MoveToDLQ(ctx context.Context, taskID uuid.UUID)
 	return args.Error(0)
 }
 
-func (m *MockWorkerRepository) ExtendLock(ctx context.Context, taskID uuid.UUID, duration time.Duration) error {
-	args := m.Called(ctx, taskID, duration)
+func (m *MockWorkerRepository) ExtendLock(ctx context.Context, taskID, workerID uuid.UUID, duration time.Duration) error {
+	args := m.Called(ctx, taskID, workerID, duration)
 	return args.Error(0)
 }
 
@@ -285,7 +285,7 @@ func TestWorker_ProcessTask(t *testing.T) {
 		// Set up expectations
 		mockRepo.On("ClaimTask", mock.Anything, mock.Anything, []string{queue.DefaultQueueName}, mock.Anything).
 			Return(task, nil).Once()
-		mockRepo.On("CompleteTask", mock.Anything, task.ID).Return(nil).Once()
+		mockRepo.On("CompleteTask", mock.Anything, task.ID, mock.Anything).Return(nil).Once()
 
 		worker, err := queue.NewWorker(mockRepo, queue.WithPullInterval(5*time.Millisecond))
 		require.NoError(t, err)
@@ -358,7 +358,7 @@ func TestWorker_ProcessTask(t *testing.T) {
 		// Set up expectations
 		mockRepo.On("ClaimTask", mock.Anything, mock.Anything, []string{queue.DefaultQueueName}, mock.Anything).
 			Return(task, nil).Once()
-		mockRepo.On("FailTask", mock.Anything, task.ID, "processing failed").Return(nil).Once()
+		mockRepo.On("FailTask", mock.Anything, task.ID, "processing failed", 30*time.Second).Return(nil).Once()
 
 		worker, err := queue.NewWorker(mockRepo, queue.WithPullInterval(5*time.Millisecond))
 		require.NoError(t, err)
@@ -432,7 +432,7 @@ func TestWorker_ProcessTask(t *testing.T) {
 			Return(task, nil).Once()
 		mockRepo.On("ClaimTask", mock.Anything, mock.Anything, []string{queue.DefaultQueueName}, mock.Anything).
 			Return(nil, queue.ErrNoTaskToClaim)
-		mockRepo.On("FailTask", mock.Anything, task.ID, "permanent failure").Return(nil).Once()
+		mockRepo.On("FailTask", mock.Anything, task.ID, "permanent failure", mock.Anything).Return(nil).Once()
 		mockRepo.On("MoveToDLQ", mock.Anything, task.ID).Return(nil).Once()
 
 		worker, err := queue.NewWorker(mockRepo, queue.WithPullInterval(50*time.Millisecond))
@@ -485,7 +485,7 @@ func TestWorker_ProcessTask(t *testing.T) {
 			Return(task, nil).Once()
 		mockRepo.On("ClaimTask", mock.Anything, mock.Anything, []string{queue.DefaultQueueName}, mock.Anything).
 			Return(nil, queue.ErrNoTaskToClaim)
-		mockRepo.On("FailTask", mock.Anything, task.ID, "no handler registered for task type: unregistered.Handler").Return(nil).Once()
+		mockRepo.On("FailTask", mock.Anything, task.ID, "no handler registered for task type: unregistered.Handler", mock.Anything).Return(nil).Once()
 		mockRepo.On("MoveToDLQ", mock.Anything, task.ID).Return(nil).Once()
 
 		worker, err := queue.NewWorker(mockRepo, queue.WithPullInterval(50*time.Millisecond))
@@ -544,7 +544,7 @@ func TestWorker_ProcessTask(t *testing.T) {
 			Return(nil, queue.ErrNoTaskToClaim)
 		mockRepo.On("FailTask", mock.Anything, task.ID, mock.MatchedBy(func(msg string) bool {
 			return strings.Contains(msg, "panic")
-		})).Return(nil).Once()
+		}), mock.Anything).Return(nil).Once()
 
 		worker, err := queue.NewWorker(mockRepo, queue.WithPullInterval(50*time.Millisecond))
 		require.NoError(t, err)
@@ -575,6 +575,103 @@ func TestWorker_ProcessTask(t *testing.T) {
 	})
 }
 
+func TestWorker_RetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		taskPolicy    *queue.RetryPolicy
+		handlerOpts   []queue.HandlerOption
+		workerOpts    []queue.WorkerOption
+		retryCount    int8
+		expectedDelay time.Duration
+	}{
+		{
+			name:          "default linear backoff",
+			retryCount:    1,
+			expectedDelay: 60 * time.Second,
+		},
+		{
+			name:          "worker default policy",
+			workerOpts:    []queue.WorkerOption{queue.WithDefaultRetryPolicy(queue.FixedBackoff(time.Second))},
+			retryCount:    1,
+			expectedDelay: time.Second,
+		},
+		{
+			name:          "handler policy overrides worker default",
+			handlerOpts:   []queue.HandlerOption{queue.WithHandlerRetryPolicy(queue.ExponentialBackoff(time.Second, time.Minute))},
+			workerOpts:    []queue.WorkerOption{queue.WithDefaultRetryPolicy(queue.FixedBackoff(time.Hour))},
+			retryCount:    2,
+			expectedDelay: 4 * time.Second,
+		},
+		{
+			name:          "task policy overrides handler policy",
+			taskPolicy:    &queue.RetryPolicy{Strategy: queue.BackoffFixed, BaseDelay: 5 * time.Second},
+			handlerOpts:   []queue.HandlerOption{queue.WithHandlerRetryPolicy(queue.ExponentialBackoff(time.Second, time.Minute))},
+			retryCount:    2,
+			expectedDelay: 5 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mockRepo := new(MockWorkerRepository)
+			defer mockRepo.AssertExpectations(t)
+
+			payloadBytes, _ := json.Marshal(testPayload{Message: "fail"})
+			task := &queue.Task{
+				ID:          uuid.New(),
+				Queue:       queue.DefaultQueueName,
+				TaskType:    queue.TaskTypeOneTime,
+				TaskName:    "queue_test.testPayload",
+				Payload:     payloadBytes,
+				Status:      queue.TaskStatusPending,
+				Priority:    queue.PriorityMedium,
+				RetryCount:  tt.retryCount,
+				MaxRetries:  5,
+				ScheduledAt: time.Now().Add(-time.Minute),
+				CreatedAt:   time.Now(),
+				RetryPolicy: tt.taskPolicy,
+			}
+
+			done := make(chan struct{})
+			mockRepo.On("ClaimTask", mock.Anything, mock.Anything, []string{queue.DefaultQueueName}, mock.Anything).
+				Return(task, nil).Once()
+			mockRepo.On("ClaimTask", mock.Anything, mock.Anything, []string{queue.DefaultQueueName}, mock.Anything).
+				Return(nil, queue.ErrNoTaskToClaim)
+			mockRepo.On("FailTask", mock.Anything, task.ID, "failed", tt.expectedDelay).
+				Run(func(mock.Arguments) { close(done) }).
+				Return(nil).Once()
+
+			worker, err := queue.NewWorker(mockRepo, append(tt.workerOpts, queue.WithPullInterval(5*time.Millisecond))...)
+			require.NoError(t, err)
+
+			handler := queue.NewTaskHandler(func(ctx context.Context, payload testPayload) error {
+				return errors.New("failed")
+			}, tt.handlerOpts...)
+			require.NoError(t, worker.RegisterHandler(handler))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			go func() {
+				_ = worker.Start(ctx)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				t.Fatal("task not failed in time")
+			}
+
+			cancel()
+			_ = worker.Stop()
+		})
+	}
+}
+
 func TestWorker_ConcurrentProcessing(t *testing.T) {
 	t.Parallel()
 
@@ -615,7 +712,7 @@ func TestWorker_ConcurrentProcessing(t *testing.T) {
 
 		// Expect CompleteTask for each task
 		for _, task := range tasks {
-			mockRepo.On("CompleteTask", mock.Anything, task.ID).Return(nil).Once()
+			mockRepo.On("CompleteTask", mock.Anything, task.ID, mock.Anything).Return(nil).Once()
 		}
 
 		worker, err := queue.NewWorker(mockRepo,
@@ -715,7 +812,7 @@ func TestWorker_GracefulShutdown(t *testing.T) {
 			Return(task, nil).Once()
 		mockRepo.On("ClaimTask", mock.Anything, mock.Anything, []string{queue.DefaultQueueName}, mock.Anything).
 			Return(nil, queue.ErrNoTaskToClaim)
-		mockRepo.On("CompleteTask", mock.Anything, task.ID).Return(nil).Once()
+		mockRepo.On("CompleteTask", mock.Anything, task.ID, mock.Anything).Return(nil).Once()
 
 		worker, err := queue.NewWorker(mockRepo, queue.WithPullInterval(10*time.Millisecond))
 		require.NoError(t, err)
@@ -806,7 +903,7 @@ func TestWorker_ExtendLockForTask(t *testing.T) {
 		taskID := uuid.New()
 
 		// Set up expectation
-		mockRepo.On("ExtendLock", mock.Anything, taskID, 5*time.Minute).Return(nil).Once()
+		mockRepo.On("ExtendLock", mock.Anything, taskID, mock.Anything, 5*time.Minute).Return(nil).Once()
 
 		worker, err := queue.NewWorker(mockRepo)
 		require.NoError(t, err)
@@ -882,8 +979,8 @@ func TestWorker_QueueFiltering(t *testing.T) {
 			Return(nil, queue.ErrNoTaskToClaim)
 
 		// Expect CompleteTask for the two tasks that should be processed
-		mockRepo.On("CompleteTask", mock.Anything, tasks["priority"].ID).Return(nil).Once()
-		mockRepo.On("CompleteTask", mock.Anything, tasks["batch"].ID).Return(nil).Once()
+		mockRepo.On("CompleteTask", mock.Anything, tasks["priority"].ID, mock.Anything).Return(nil).Once()
+		mockRepo.On("CompleteTask", mock.Anything, tasks["batch"].ID, mock.Anything).Return(nil).Once()
 
 		worker, err := queue.NewWorker(mockRepo,
 			queue.WithQueues("priority", "batch"),
@@ -0,0 +1,234 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// WorkflowRepository is implemented by storages that run workflows.
+// EnqueueWorkflow uses it to store all tasks of a workflow at once.
+//
+// The storage drives the workflow from then on: CompleteTask activates the waiting
+// children of the completed task, and of its group once every member has completed,
+// handing over the result as ParentResult. MoveToDLQ drops the waiting tasks of the
+// failed task's workflow and activates its error callbacks with a WorkflowFailure.
+// When a workflow finishes successfully, its error callbacks are dropped.
+type WorkflowRepository interface {
+	// CreateWorkflow stores the tasks atomically: either all of them or none.
+	CreateWorkflow(ctx context.Context, tasks []*Task) error
+}
+
+// WorkflowNode is a stage of a workflow: a single Step or a Group of steps.
+type WorkflowNode interface {
+	workflowSteps() (steps []WorkflowStep, group bool)
+}
+
+// WorkflowStep is a task of a workflow, built like a regular Enqueue call.
+type WorkflowStep struct {
+	payload any
+	opts    []EnqueueOption
+}
+
+// Step creates a workflow task from a payload and the usual enqueue options.
+// Delays count from the moment the step is activated, not from EnqueueWorkflow.
+func Step(payload any, opts ...EnqueueOption) WorkflowStep {
+	return WorkflowStep{payload: payload, opts: opts}
+}
+
+func (s WorkflowStep) workflowSteps() ([]WorkflowStep, bool) {
+	return []WorkflowStep{s}, false
+}
+
+// WorkflowGroup is a set of steps that run in parallel.
+type WorkflowGroup struct {
+	steps []WorkflowStep
+}
+
+// Group fans out steps in parallel. The next stage of the workflow starts once
+// every member has completed and receives their results as a JSON array in
+// completion order.
+func Group(steps ...WorkflowStep) WorkflowGroup {
+	return WorkflowGroup{steps: steps}
+}
+
+func (g WorkflowGroup) workflowSteps() ([]WorkflowStep, bool) {
+	return g.steps, true
+}
+
+// Workflow is a chain of stages run one after another.
+type Workflow struct {
+	nodes   []WorkflowNode
+	onError *WorkflowStep
+}
+
+// Chain runs nodes in order; each stage starts after the previous one has completed
+// and can read its result with ParentResult. A group followed by a step is the
+// classic "fan out, then callback" pattern:
+//
+//	queue.Chain(
+//		queue.Step(DownloadPayload{URL: url}),
+//		queue.Step(ParsePayload{}),
+//		queue.Group(queue.Step(ShardPayload{N: 0}), queue.Step(ShardPayload{N: 1})),
+//		queue.Step(NotifyPayload{}),
+//	)
+func Chain(nodes ...WorkflowNode) *Workflow {
+	return &Workflow{nodes: nodes}
+}
+
+// OnError sets the error callback, enqueued when any task of the workflow reaches
+// the dead letter queue. It receives a WorkflowFailure as its parent result.
+// The remaining stages of the workflow are dropped.
+func (w *Workflow) OnError(step WorkflowStep) *Workflow {
+	w.onError = &step
+	return w
+}
+
+// WorkflowFailure is the parent result of an error callback.
+type WorkflowFailure struct {
+	WorkflowID uuid.UUID `json:"workflow_id"`
+	TaskID     uuid.UUID `json:"task_id"`
+	TaskName   string    `json:"task_name"`
+	Error      string    `json:"error"`
+}
+
+// EnqueueWorkflow stores all tasks of a workflow and returns the workflow ID.
+// Tasks of the first stage are pending right away; the rest wait for their parent.
+// The repository must implement WorkflowRepository.
+func (e *Enqueuer) EnqueueWorkflow(ctx context.Context, workflow *Workflow) (uuid.UUID, error) {
+	if workflow == nil || len(workflow.nodes) == 0 {
+		return uuid.Nil, fmt.Errorf("%w: no steps", ErrInvalidWorkflow)
+	}
+
+	repo, ok := e.repo.(WorkflowRepository)
+	if !ok {
+		return uuid.Nil, ErrWorkflowsNotSupported
+	}
+
+	workflowID := uuid.New()
+	var (
+		tasks  []*Task
+		parent *uuid.UUID
+	)
+
+	for i, node := range workflow.nodes {
+		steps, isGroup := node.workflowSteps()
+		if len(steps) == 0 {
+			return uuid.Nil, fmt.Errorf("%w: stage %d has no steps", ErrInvalidWorkflow, i)
+		}
+
+		var groupID *uuid.UUID
+		if isGroup {
+			id := uuid.New()
+			groupID = &id
+		}
+
+		for _, step := range steps {
+			task, err := e.buildStepTask(step, parent)
+			if err != nil {
+				return uuid.Nil, fmt.Errorf("stage %d: %w", i, err)
+			}
+			task.WorkflowID = &workflowID
+			task.GroupID = groupID
+			tasks = append(tasks, task)
+		}
+
+		if isGroup {
+			parent = groupID
+		} else {
+			parent = &tasks[len(tasks)-1].ID
+		}
+	}
+
+	if workflow.onError != nil {
+		task, err := e.buildStepTask(*workflow.onError, &workflowID)
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("error callback: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := repo.CreateWorkflow(ctx, tasks); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create workflow %s: %w", workflowID, err)
+	}
+
+	return workflowID, nil
+}
+
+// buildStepTask builds the task of a workflow step waiting for parent (nil for the first stage).
+func (e *Enqueuer) buildStepTask(step WorkflowStep, parent *uuid.UUID) (*Task, error) {
+	if step.payload == nil {
+		return nil, ErrPayloadNil
+	}
+
+	options, err := e.enqueueOptions(step.opts)
+	if err != nil {
+		return nil, err
+	}
+	if options.uniqueKey != "" {
+		return nil, fmt.Errorf("%w: unique keys are not supported in workflow steps", ErrInvalidWorkflow)
+	}
+
+	task, err := e.buildTask(step.payload, options)
+	if err != nil {
+		return nil, err
+	}
+	if parent != nil {
+		task.Status = TaskStatusWaiting
+		task.ParentID = parent
+	}
+
+	return task, nil
+}
+
+type taskStateKey struct{}
+
+// taskState carries workflow data between the worker and a running handler.
+type taskState struct {
+	parentResult []byte
+	result       []byte
+}
+
+func withTaskState(ctx context.Context, task *Task) (context.Context, *taskState) {
+	state := &taskState{parentResult: task.ParentResult}
+	return context.WithValue(ctx, taskStateKey{}, state), state
+}
+
+// SetResult records the result of the running task. The worker stores it when the
+// task completes, and the next workflow stage reads it with ParentResult.
+// Call it from the handler goroutine before returning; the last call wins.
+func SetResult(ctx context.Context, v any) error {
+	state, ok := ctx.Value(taskStateKey{}).(*taskState)
+	if !ok {
+		return ErrNoTaskContext
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result of type %T: %w", v, err)
+	}
+	state.result = data
+
+	return nil
+}
+
+// ParentResult decodes the result handed over by the previous workflow stage into v:
+// the result of a step, a JSON array of member results for a group, or a
+// WorkflowFailure for an error callback.
+func ParentResult(ctx context.Context, v any) error {
+	state, ok := ctx.Value(taskStateKey{}).(*taskState)
+	if !ok {
+		return ErrNoTaskContext
+	}
+	if len(state.parentResult) == 0 {
+		return ErrNoParentResult
+	}
+
+	if err := json.Unmarshal(state.parentResult, v); err != nil {
+		return fmt.Errorf("failed to unmarshal parent result into %T: %w", v, err)
+	}
+
+	return nil
+}
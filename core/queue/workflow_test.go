@@ -0,0 +1,316 @@
+package queue_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+type (
+	downloadStep struct {
+		URL string `json:"url"`
+	}
+	parseStep struct{}
+	shardStep struct {
+		N int `json:"n"`
+	}
+	notifyStep  struct{}
+	failureStep struct{}
+)
+
+func TestEnqueuer_EnqueueWorkflow(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	queues := []string{queue.DefaultQueueName}
+
+	newEnqueuer := func(t *testing.T) (*queue.Enqueuer, *queue.MemoryStorage) {
+		t.Helper()
+		storage := queue.NewMemoryStorage()
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+		return enqueuer, storage
+	}
+
+	claim := func(t *testing.T, storage *queue.MemoryStorage) *queue.Task {
+		t.Helper()
+		task, err := storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		require.NoError(t, err)
+		return task
+	}
+
+	assertNothingToClaim := func(t *testing.T, storage *queue.MemoryStorage) {
+		t.Helper()
+		_, err := storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		assert.ErrorIs(t, err, queue.ErrNoTaskToClaim)
+	}
+
+	t.Run("chain runs steps in order", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, storage := newEnqueuer(t)
+
+		workflowID, err := enqueuer.EnqueueWorkflow(ctx, queue.Chain(
+			queue.Step(downloadStep{URL: "https://example.com/data.csv"}),
+			queue.Step(parseStep{}),
+		))
+		require.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, workflowID)
+
+		download := claim(t, storage)
+		assert.Equal(t, "queue_test.downloadStep", download.TaskName)
+		assert.Equal(t, workflowID, *download.WorkflowID)
+		assertNothingToClaim(t, storage)
+
+		require.NoError(t, storage.CompleteTask(ctx, download.ID, []byte(`"/tmp/data.csv"`)))
+
+		parse := claim(t, storage)
+		assert.Equal(t, "queue_test.parseStep", parse.TaskName)
+		assert.Equal(t, download.ID, *parse.ParentID)
+		assert.JSONEq(t, `"/tmp/data.csv"`, string(parse.ParentResult))
+	})
+
+	t.Run("group callback waits for every member", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, storage := newEnqueuer(t)
+
+		_, err := enqueuer.EnqueueWorkflow(ctx, queue.Chain(
+			queue.Group(queue.Step(shardStep{N: 0}), queue.Step(shardStep{N: 1})),
+			queue.Step(notifyStep{}),
+		))
+		require.NoError(t, err)
+
+		first := claim(t, storage)
+		second := claim(t, storage)
+		require.NotNil(t, first.GroupID)
+		assert.Equal(t, first.GroupID, second.GroupID)
+
+		require.NoError(t, storage.CompleteTask(ctx, second.ID, []byte(`2`)))
+		assertNothingToClaim(t, storage)
+
+		require.NoError(t, storage.CompleteTask(ctx, first.ID, []byte(`1`)))
+
+		notify := claim(t, storage)
+		assert.Equal(t, "queue_test.notifyStep", notify.TaskName)
+		assert.JSONEq(t, `[2, 1]`, string(notify.ParentResult), "results are in completion order")
+	})
+
+	t.Run("success drops error callback", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, storage := newEnqueuer(t)
+
+		_, err := enqueuer.EnqueueWorkflow(ctx, queue.Chain(
+			queue.Step(downloadStep{}),
+		).OnError(queue.Step(failureStep{})))
+		require.NoError(t, err)
+
+		download := claim(t, storage)
+		require.NoError(t, storage.CompleteTask(ctx, download.ID, nil))
+
+		assertNothingToClaim(t, storage)
+		assert.Equal(t, 1, storage.Stats().ActiveTasks, "only the completed step is left")
+	})
+
+	t.Run("step delay counts from activation", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, storage := newEnqueuer(t)
+
+		_, err := enqueuer.EnqueueWorkflow(ctx, queue.Chain(
+			queue.Step(downloadStep{}),
+			queue.Step(parseStep{}, queue.WithDelay(time.Hour)),
+		))
+		require.NoError(t, err)
+
+		download := claim(t, storage)
+		require.NoError(t, storage.CompleteTask(ctx, download.ID, nil))
+		assertNothingToClaim(t, storage)
+	})
+
+	t.Run("invalid workflows", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, _ := newEnqueuer(t)
+
+		_, err := enqueuer.EnqueueWorkflow(ctx, queue.Chain())
+		assert.ErrorIs(t, err, queue.ErrInvalidWorkflow)
+
+		_, err = enqueuer.EnqueueWorkflow(ctx, queue.Chain(queue.Group()))
+		assert.ErrorIs(t, err, queue.ErrInvalidWorkflow)
+
+		_, err = enqueuer.EnqueueWorkflow(ctx, queue.Chain(queue.Step(nil)))
+		assert.ErrorIs(t, err, queue.ErrPayloadNil)
+
+		_, err = enqueuer.EnqueueWorkflow(ctx, queue.Chain(
+			queue.Step(downloadStep{}, queue.WithUniqueKey("download", time.Minute)),
+		))
+		assert.ErrorIs(t, err, queue.ErrInvalidWorkflow)
+	})
+
+	t.Run("requires workflow repository", func(t *testing.T) {
+		t.Parallel()
+
+		enqueuer, err := queue.NewEnqueuer(&mockEnqueuerRepo{})
+		require.NoError(t, err)
+
+		_, err = enqueuer.EnqueueWorkflow(ctx, queue.Chain(queue.Step(downloadStep{})))
+		assert.ErrorIs(t, err, queue.ErrWorkflowsNotSupported)
+	})
+}
+
+func TestWorker_WorkflowResults(t *testing.T) {
+	t.Parallel()
+
+	storage := queue.NewMemoryStorage()
+	enqueuer, err := queue.NewEnqueuer(storage)
+	require.NoError(t, err)
+
+	worker, err := queue.NewWorker(storage,
+		queue.WithPullInterval(5*time.Millisecond),
+		queue.WithMaxConcurrentTasks(2),
+		queue.WithWorkerLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	require.NoError(t, err)
+
+	done := make(chan []int, 1)
+	require.NoError(t, worker.RegisterHandlers(
+		queue.NewTaskHandler(func(ctx context.Context, p downloadStep) error {
+			return queue.SetResult(ctx, 10)
+		}),
+		queue.NewTaskHandler(func(ctx context.Context, p shardStep) error {
+			var base int
+			if err := queue.ParentResult(ctx, &base); err != nil {
+				return err
+			}
+			return queue.SetResult(ctx, base+p.N)
+		}),
+		queue.NewTaskHandler(func(ctx context.Context, _ notifyStep) error {
+			var results []int
+			if err := queue.ParentResult(ctx, &results); err != nil {
+				return err
+			}
+			done <- results
+			return nil
+		}),
+	))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- worker.Run(ctx)() }()
+
+	_, err = enqueuer.EnqueueWorkflow(ctx, queue.Chain(
+		queue.Step(downloadStep{}),
+		queue.Group(queue.Step(shardStep{N: 1}), queue.Step(shardStep{N: 2})),
+		queue.Step(notifyStep{}),
+	))
+	require.NoError(t, err)
+
+	select {
+	case results := <-done:
+		assert.ElementsMatch(t, []int{11, 12}, results)
+	case <-ctx.Done():
+		t.Fatal("workflow did not finish")
+	}
+
+	cancel()
+	require.NoError(t, <-errCh)
+}
+
+func TestWorker_WorkflowFailure(t *testing.T) {
+	t.Parallel()
+
+	storage := queue.NewMemoryStorage()
+	enqueuer, err := queue.NewEnqueuer(storage)
+	require.NoError(t, err)
+
+	worker, err := queue.NewWorker(storage,
+		queue.WithPullInterval(5*time.Millisecond),
+		queue.WithWorkerLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	require.NoError(t, err)
+
+	var attempts atomic.Int32
+	failures := make(chan queue.WorkflowFailure, 1)
+	require.NoError(t, worker.RegisterHandlers(
+		queue.NewTaskHandler(func(ctx context.Context, _ downloadStep) error {
+			attempts.Add(1)
+			return errors.New("connection refused")
+		}, queue.WithHandlerRetryPolicy(queue.FixedBackoff(time.Millisecond))),
+		queue.NewTaskHandler(func(ctx context.Context, _ parseStep) error {
+			t.Error("parse step ran after the workflow failed")
+			return nil
+		}),
+		queue.NewTaskHandler(func(ctx context.Context, _ failureStep) error {
+			var failure queue.WorkflowFailure
+			if err := queue.ParentResult(ctx, &failure); err != nil {
+				return err
+			}
+			failures <- failure
+			return nil
+		}),
+	))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- worker.Run(ctx)() }()
+
+	// Default MaxRetries: the worker exhausts the retries and moves the task to the DLQ
+	workflowID, err := enqueuer.EnqueueWorkflow(ctx, queue.Chain(
+		queue.Step(downloadStep{}),
+		queue.Step(parseStep{}),
+	).OnError(queue.Step(failureStep{})))
+	require.NoError(t, err)
+
+	var failure queue.WorkflowFailure
+	select {
+	case failure = <-failures:
+	case <-ctx.Done():
+		t.Fatal("error callback did not run")
+	}
+
+	cancel()
+	require.NoError(t, <-errCh)
+
+	assert.Equal(t, int32(3), attempts.Load(), "every retry is used before giving up")
+
+	entries, err := storage.ListDLQ(context.Background(), queue.DLQFilter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "queue_test.downloadStep", entries[0].TaskName)
+	assert.Equal(t, "connection refused", entries[0].Error)
+
+	assert.Equal(t, queue.WorkflowFailure{
+		WorkflowID: workflowID,
+		TaskID:     entries[0].TaskID,
+		TaskName:   "queue_test.downloadStep",
+		Error:      "connection refused",
+	}, failure)
+
+	stats := storage.Stats()
+	assert.Equal(t, 1, stats.ActiveTasks, "parse step is dropped")
+}
+
+func TestSetResult_OutsideTask(t *testing.T) {
+	t.Parallel()
+
+	assert.ErrorIs(t, queue.SetResult(context.Background(), 1), queue.ErrNoTaskContext)
+
+	var v int
+	assert.ErrorIs(t, queue.ParentResult(context.Background(), &v), queue.ErrNoTaskContext)
+}
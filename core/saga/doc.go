@@ -0,0 +1,106 @@
+// Package saga provides sagas (process managers) for long-running business flows
+// across commands and events, with timeouts and compensation.
+//
+// A saga keeps state per instance. Events of core/event are correlated to an
+// instance by a key; each step reads and changes the instance state and sends
+// commands through a command.Sender. Timeouts are delayed tasks of core/queue
+// that fire when the flow stalls, and compensations are commands that undo
+// completed steps when the flow fails.
+//
+// # Core Components
+//
+// Saga defines a flow over state of type S and runs its steps. Handlers returns
+// its event handlers for an event.Processor, and TimeoutHandler its queue handler
+// for a queue worker.
+//
+// Step reacts to an event or a timeout: StartedBy starts an instance, On advances
+// an active one, and OnTimeout runs when a scheduled timeout fires.
+//
+// Instance is one run of a saga, with the typed State and methods to send
+// commands, schedule and cancel timeouts, record compensations and finish.
+//
+// Store persists instances with optimistic concurrency. MemoryStore serves tests
+// and single processes.
+//
+// # Basic Usage
+//
+//	type OrderState struct {
+//		Total int
+//	}
+//
+//	orders, err := saga.New[OrderState]("order_fulfillment", store, sender,
+//		saga.WithTimeouts(enqueuer),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//
+//	orderID := func(evt OrderPlaced) string { return evt.OrderID }
+//
+//	orders.Register(
+//		saga.StartedBy(orderID, func(ctx context.Context, inst *saga.Instance[OrderState], evt OrderPlaced) error {
+//			inst.State.Total = evt.Total
+//			if err := inst.Send(ctx, ReserveStock{OrderID: evt.OrderID}); err != nil {
+//				return err
+//			}
+//			inst.AddCompensation(ReleaseStock{OrderID: evt.OrderID})
+//			if err := inst.Send(ctx, ChargePayment{OrderID: evt.OrderID, Amount: evt.Total}); err != nil {
+//				return err
+//			}
+//			return inst.ScheduleTimeout(ctx, "payment", 15*time.Minute)
+//		}),
+//		saga.On(func(evt PaymentCaptured) string { return evt.OrderID },
+//			func(ctx context.Context, inst *saga.Instance[OrderState], evt PaymentCaptured) error {
+//				inst.CancelTimeout("payment")
+//				if err := inst.Send(ctx, ShipOrder{OrderID: evt.OrderID}); err != nil {
+//					return err
+//				}
+//				inst.Complete()
+//				return nil
+//			}),
+//		saga.On(func(evt PaymentDeclined) string { return evt.OrderID },
+//			func(ctx context.Context, inst *saga.Instance[OrderState], evt PaymentDeclined) error {
+//				return inst.Compensate(ctx)
+//			}),
+//		saga.OnTimeout("payment", func(ctx context.Context, inst *saga.Instance[OrderState]) error {
+//			return inst.Compensate(ctx)
+//		}),
+//	)
+//
+//	processor := event.NewProcessor(
+//		event.WithEventSource(bus),
+//		event.WithHandler(orders.Handlers()...),
+//	)
+//	worker.RegisterHandler(orders.TimeoutHandler())
+//
+// # Correlation
+//
+// Each step returns the instance key of its event. A StartedBy event creates the
+// instance and is ignored for an existing one, so duplicate deliveries do not
+// restart the flow. On events and timeouts are ignored without an active instance:
+// once an instance completes or is compensated, late events have no effect.
+// Events with an empty key are logged and ignored. Register one step per event type.
+//
+// # Timeouts
+//
+// ScheduleTimeout enqueues a delayed task named "saga.timeout.<saga name>" through
+// the enqueuer given to WithTimeouts (WithTimeoutQueue picks its queue). When the
+// task runs, the OnTimeout step of that name fires, unless the instance finished
+// or cancelled or rescheduled the timeout in the meantime.
+//
+// # Compensation
+//
+// AddCompensation records a command that undoes a step once the step succeeded.
+// Compensate sends the recorded commands, the most recent first, and marks the
+// instance compensated. Complete drops them.
+//
+// # Delivery Guarantees
+//
+// Steps run at least once. Commands are sent during a step and the instance is
+// saved after it; a failed step or a version conflict with a concurrent step
+// returns an error, and the event is retried with its redelivery, sending the
+// step's commands again. Make command handlers idempotent (see
+// command.WithIdempotency). A Store that implements RunInTx runs each step in a
+// transaction, so with the outbox of integration/bus/pgoutbox as the command bus,
+// commands are sent exactly when the instance is saved.
+package saga
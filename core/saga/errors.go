@@ -0,0 +1,25 @@
+package saga
+
+import "errors"
+
+var (
+	// ErrSagaNotFound is returned by a Store when no saga instance has the key.
+	ErrSagaNotFound = errors.New("saga instance not found")
+
+	// ErrConcurrencyConflict is returned by a Store when the saga instance was
+	// saved by someone else since it was loaded.
+	ErrConcurrencyConflict = errors.New("saga instance version conflict")
+
+	// ErrNameEmpty is returned when creating a saga without a name.
+	ErrNameEmpty = errors.New("saga name cannot be empty")
+
+	// ErrStoreNil is returned when the saga store is nil.
+	ErrStoreNil = errors.New("saga store cannot be nil")
+
+	// ErrSenderNil is returned when the command sender is nil.
+	ErrSenderNil = errors.New("command sender cannot be nil")
+
+	// ErrTimeoutsNotConfigured is returned when scheduling a timeout on a saga
+	// created without WithTimeouts.
+	ErrTimeoutsNotConfigured = errors.New("saga timeouts not configured")
+)
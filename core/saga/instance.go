@@ -0,0 +1,115 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dmitrymomot/foundation/core/command"
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+// Instance is one run of a saga, identified by its correlation key. Steps read
+// and change State, and the saga saves it after each step that succeeds.
+// An Instance is not safe for concurrent use.
+type Instance[S any] struct {
+	Key   string
+	State S
+
+	saga          *Saga[S]
+	status        Status
+	version       int64
+	timeouts      map[string]string
+	compensations []command.Command
+	createdAt     time.Time
+}
+
+// Status returns the lifecycle status of the instance.
+func (i *Instance[S]) Status() Status {
+	return i.status
+}
+
+// Send sends a command through the saga's command sender.
+//
+// Commands are sent when Send is called and the instance is saved after the
+// step: a step that fails, or whose save conflicts, is retried with the event's
+// redelivery and sends its commands again. Make command handlers idempotent, or
+// use a transactional store with the outbox of integration/bus/pgoutbox.
+func (i *Instance[S]) Send(ctx context.Context, payload any) error {
+	if err := i.saga.sender.Send(ctx, payload); err != nil {
+		return fmt.Errorf("failed to send command from saga %s instance %s: %w", i.saga.name, i.Key, err)
+	}
+	return nil
+}
+
+// ScheduleTimeout schedules the named timeout to fire after the duration, as a
+// delayed task of core/queue. Scheduling a pending timeout again replaces it.
+// Returns ErrTimeoutsNotConfigured unless the saga was created WithTimeouts.
+func (i *Instance[S]) ScheduleTimeout(ctx context.Context, name string, after time.Duration) error {
+	if i.saga.enqueuer == nil {
+		return ErrTimeoutsNotConfigured
+	}
+
+	task := timeoutTask{
+		Saga: i.saga.name,
+		Key:  i.Key,
+		Name: name,
+		ID:   uuid.New().String(),
+	}
+
+	opts := []queue.EnqueueOption{
+		queue.WithTaskName(timeoutTaskName(i.saga.name)),
+		queue.WithDelay(after),
+	}
+	if i.saga.timeoutQueue != "" {
+		opts = append(opts, queue.WithQueue(i.saga.timeoutQueue))
+	}
+
+	if _, err := i.saga.enqueuer.Enqueue(ctx, task, opts...); err != nil {
+		return fmt.Errorf("failed to schedule timeout %s of saga %s instance %s: %w", name, i.saga.name, i.Key, err)
+	}
+
+	i.timeouts[name] = task.ID
+	return nil
+}
+
+// CancelTimeout cancels the named timeout; when its task runs, it is ignored.
+func (i *Instance[S]) CancelTimeout(name string) {
+	delete(i.timeouts, name)
+}
+
+// AddCompensation records a command that undoes a completed step, to be sent by
+// Compensate. Record it once the step it undoes has succeeded.
+func (i *Instance[S]) AddCompensation(payload any) {
+	i.compensations = append(i.compensations, command.NewCommand(payload))
+}
+
+// Compensate sends the recorded compensations, the most recent first, and marks
+// the instance compensated. Each compensation keeps its command ID when a failed
+// step sends it again, so idempotent handlers skip duplicates.
+func (i *Instance[S]) Compensate(ctx context.Context) error {
+	for _, compensation := range slices.Backward(i.compensations) {
+		if err := i.saga.sender.SendCommand(ctx, compensation); err != nil {
+			return fmt.Errorf("failed to send compensation %s from saga %s instance %s: %w",
+				compensation.Name, i.saga.name, i.Key, err)
+		}
+	}
+
+	i.finish(StatusCompensated)
+	return nil
+}
+
+// Complete marks the instance completed. Pending timeouts are cancelled and
+// recorded compensations are dropped.
+func (i *Instance[S]) Complete() {
+	i.finish(StatusCompleted)
+}
+
+func (i *Instance[S]) finish(status Status) {
+	i.status = status
+	i.timeouts = make(map[string]string)
+	i.compensations = nil
+}
@@ -0,0 +1,335 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/dmitrymomot/foundation/core/command"
+	"github.com/dmitrymomot/foundation/core/event"
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+// timeoutEnqueuer schedules timeout tasks, such as *queue.Enqueuer.
+type timeoutEnqueuer interface {
+	Enqueue(ctx context.Context, payload any, opts ...queue.EnqueueOption) (uuid.UUID, error)
+}
+
+// errSkip ends the handling of an event or timeout without saving the instance.
+var errSkip = errors.New("skip")
+
+// Saga is a long-running business flow, with state of type S kept per instance.
+// Events correlated to an instance by key advance its state and send commands;
+// timeouts scheduled on the queue fire when the flow stalls.
+type Saga[S any] struct {
+	name         string
+	store        Store
+	sender       *command.Sender
+	enqueuer     timeoutEnqueuer
+	timeoutQueue string
+	logger       *slog.Logger
+
+	steps    []Step[S]
+	timeouts map[string]func(ctx context.Context, inst *Instance[S]) error
+}
+
+// Option configures a Saga.
+type Option func(*options)
+
+type options struct {
+	enqueuer     timeoutEnqueuer
+	timeoutQueue string
+	logger       *slog.Logger
+}
+
+// WithTimeouts schedules timeouts as delayed tasks through the enqueuer of core/queue.
+// Register TimeoutHandler with a worker that consumes the timeout queue.
+func WithTimeouts(enqueuer timeoutEnqueuer) Option {
+	return func(o *options) {
+		if enqueuer != nil {
+			o.enqueuer = enqueuer
+		}
+	}
+}
+
+// WithTimeoutQueue sets the queue timeout tasks are enqueued to.
+// Default is the enqueuer's default queue.
+func WithTimeoutQueue(queueName string) Option {
+	return func(o *options) {
+		if queueName != "" {
+			o.timeoutQueue = queueName
+		}
+	}
+}
+
+// WithLogger configures structured logging for saga operations.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		if logger != nil {
+			o.logger = logger
+		}
+	}
+}
+
+// New creates a saga with the given name, unique among the sagas sharing a store.
+// Register its steps with Register, then its event handlers with an event.Processor
+// and its TimeoutHandler with a queue worker.
+//
+// Example:
+//
+//	orders, err := saga.New[OrderState]("order_fulfillment", store, sender,
+//	    saga.WithTimeouts(enqueuer),
+//	)
+//	orders.Register(
+//	    saga.StartedBy(func(evt OrderPlaced) string { return evt.OrderID }, startOrder),
+//	    saga.On(func(evt PaymentCaptured) string { return evt.OrderID }, shipOrder),
+//	    saga.OnTimeout("payment", cancelUnpaidOrder),
+//	)
+func New[S any](name string, store Store, sender *command.Sender, opts ...Option) (*Saga[S], error) {
+	if name == "" {
+		return nil, ErrNameEmpty
+	}
+	if store == nil {
+		return nil, ErrStoreNil
+	}
+	if sender == nil {
+		return nil, ErrSenderNil
+	}
+
+	o := options{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Saga[S]{
+		name:         name,
+		store:        store,
+		sender:       sender,
+		enqueuer:     o.enqueuer,
+		timeoutQueue: o.timeoutQueue,
+		logger:       o.logger,
+		timeouts:     make(map[string]func(ctx context.Context, inst *Instance[S]) error),
+	}, nil
+}
+
+// Name returns the saga name.
+func (s *Saga[S]) Name() string {
+	return s.name
+}
+
+// Register adds steps to the saga. Register all steps before calling Handlers.
+func (s *Saga[S]) Register(steps ...Step[S]) {
+	for _, step := range steps {
+		if step.timeout != "" {
+			s.timeouts[step.timeout] = step.onTimeout
+			continue
+		}
+		s.steps = append(s.steps, step)
+	}
+}
+
+// Handlers returns the event handlers of the saga's steps, to register with an
+// event.Processor:
+//
+//	processor := event.NewProcessor(
+//	    event.WithEventSource(bus),
+//	    event.WithHandler(orders.Handlers()...),
+//	)
+func (s *Saga[S]) Handlers() []event.Handler {
+	handlers := make([]event.Handler, 0, len(s.steps))
+	for _, step := range s.steps {
+		handlers = append(handlers, step.handler(s))
+	}
+	return handlers
+}
+
+// TimeoutHandler returns the queue handler that fires the saga's timeouts,
+// to register with a queue worker:
+//
+//	worker.RegisterHandler(orders.TimeoutHandler())
+func (s *Saga[S]) TimeoutHandler() queue.Handler {
+	return &timeoutHandler[S]{saga: s}
+}
+
+// Get returns the instance with the key, or ErrSagaNotFound.
+func (s *Saga[S]) Get(ctx context.Context, key string) (*Instance[S], error) {
+	record, err := s.store.Load(ctx, s.name, key)
+	if err != nil {
+		return nil, err
+	}
+	return s.instanceFromRecord(record)
+}
+
+// run loads the instance with the key, runs fn on it and saves it, in one
+// transaction if the store supports it. Without an instance, start creates one
+// and other events are ignored; an existing instance ignores start events, which
+// are duplicate deliveries. Finished instances ignore everything.
+func (s *Saga[S]) run(ctx context.Context, key string, start bool, fn func(ctx context.Context, inst *Instance[S]) error) error {
+	if key == "" {
+		s.logger.WarnContext(ctx, "event not correlated to a saga instance",
+			slog.String("saga", s.name),
+			slog.String("event_id", event.EventID(ctx)),
+			slog.String("event_name", event.EventName(ctx)))
+		return nil
+	}
+
+	handle := func(ctx context.Context) error {
+		inst, err := s.Get(ctx, key)
+		switch {
+		case errors.Is(err, ErrSagaNotFound):
+			if !start {
+				s.logger.DebugContext(ctx, "no saga instance for event",
+					slog.String("saga", s.name),
+					slog.String("key", key),
+					slog.String("event_name", event.EventName(ctx)))
+				return nil
+			}
+			inst = s.newInstance(key)
+		case err != nil:
+			return fmt.Errorf("failed to load saga %s instance %s: %w", s.name, key, err)
+		case start || inst.status != StatusActive:
+			s.logger.DebugContext(ctx, "saga instance ignores event",
+				slog.String("saga", s.name),
+				slog.String("key", key),
+				slog.String("status", string(inst.status)),
+				slog.String("event_name", event.EventName(ctx)))
+			return nil
+		}
+
+		if err := fn(ctx, inst); err != nil {
+			if errors.Is(err, errSkip) {
+				return nil
+			}
+			return err
+		}
+
+		return s.save(ctx, inst)
+	}
+
+	if runner, ok := s.store.(txRunner); ok {
+		return runner.RunInTx(ctx, handle)
+	}
+	return handle(ctx)
+}
+
+func (s *Saga[S]) newInstance(key string) *Instance[S] {
+	return &Instance[S]{
+		Key:       key,
+		saga:      s,
+		status:    StatusActive,
+		timeouts:  make(map[string]string),
+		createdAt: time.Now(),
+	}
+}
+
+func (s *Saga[S]) instanceFromRecord(record Record) (*Instance[S], error) {
+	inst := &Instance[S]{
+		Key:           record.Key,
+		saga:          s,
+		status:        record.Status,
+		version:       record.Version,
+		timeouts:      record.Timeouts,
+		compensations: record.Compensations,
+		createdAt:     record.CreatedAt,
+	}
+	if inst.timeouts == nil {
+		inst.timeouts = make(map[string]string)
+	}
+	if len(record.State) > 0 {
+		if err := json.Unmarshal(record.State, &inst.State); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal saga %s instance %s state: %w", s.name, record.Key, err)
+		}
+	}
+	return inst, nil
+}
+
+func (s *Saga[S]) save(ctx context.Context, inst *Instance[S]) error {
+	state, err := json.Marshal(inst.State)
+	if err != nil {
+		return fmt.Errorf("failed to marshal saga %s instance %s state: %w", s.name, inst.Key, err)
+	}
+
+	record := Record{
+		Saga:          s.name,
+		Key:           inst.Key,
+		Status:        inst.status,
+		State:         state,
+		Timeouts:      inst.timeouts,
+		Compensations: inst.compensations,
+		Version:       inst.version + 1,
+		CreatedAt:     inst.createdAt,
+		UpdatedAt:     time.Now(),
+	}
+	if err := s.store.Save(ctx, record, inst.version); err != nil {
+		return fmt.Errorf("failed to save saga %s instance %s: %w", s.name, inst.Key, err)
+	}
+	inst.version = record.Version
+
+	s.logger.DebugContext(ctx, "saga instance saved",
+		slog.String("saga", s.name),
+		slog.String("key", inst.Key),
+		slog.String("status", string(inst.status)),
+		slog.Int64("version", inst.version))
+
+	return nil
+}
+
+// timeoutTask is the queue payload of a scheduled timeout.
+type timeoutTask struct {
+	Saga string `json:"saga"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
+	ID   string `json:"id"`
+}
+
+// timeoutTaskName is the queue task name of the saga's timeouts.
+func timeoutTaskName(saga string) string {
+	return "saga.timeout." + saga
+}
+
+type timeoutHandler[S any] struct {
+	saga *Saga[S]
+}
+
+func (h *timeoutHandler[S]) Name() string {
+	return timeoutTaskName(h.saga.name)
+}
+
+// Handle fires the timeout unless the instance has finished, or cancelled or
+// rescheduled the timeout since.
+func (h *timeoutHandler[S]) Handle(ctx context.Context, payload json.RawMessage) error {
+	var task timeoutTask
+	if err := json.Unmarshal(payload, &task); err != nil {
+		return fmt.Errorf("failed to unmarshal saga timeout: %w", err)
+	}
+
+	s := h.saga
+	return s.run(ctx, task.Key, false, func(ctx context.Context, inst *Instance[S]) error {
+		if inst.timeouts[task.Name] != task.ID {
+			return errSkip
+		}
+		delete(inst.timeouts, task.Name)
+
+		fn, ok := s.timeouts[task.Name]
+		if !ok {
+			s.logger.WarnContext(ctx, "no handler for saga timeout",
+				slog.String("saga", s.name),
+				slog.String("key", task.Key),
+				slog.String("timeout", task.Name))
+			return nil
+		}
+
+		s.logger.DebugContext(ctx, "saga timeout fired",
+			slog.String("saga", s.name),
+			slog.String("key", task.Key),
+			slog.String("timeout", task.Name))
+
+		return fn(ctx, inst)
+	})
+}
@@ -0,0 +1,378 @@
+package saga_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/command"
+	"github.com/dmitrymomot/foundation/core/event"
+	"github.com/dmitrymomot/foundation/core/queue"
+	"github.com/dmitrymomot/foundation/core/saga"
+)
+
+type OrderPlaced struct {
+	OrderID string
+	Total   int
+}
+
+type PaymentCaptured struct {
+	OrderID string
+}
+
+type PaymentDeclined struct {
+	OrderID string
+}
+
+type ChargePayment struct {
+	OrderID string
+	Amount  int
+}
+
+type ReserveStock struct {
+	OrderID string
+}
+
+type ReleaseStock struct {
+	OrderID string
+}
+
+type RefundPayment struct {
+	OrderID string
+}
+
+type ShipOrder struct {
+	OrderID string
+}
+
+type OrderState struct {
+	Total int
+	Paid  bool
+}
+
+// recordingBus records the names of published commands.
+type recordingBus struct {
+	mu       sync.Mutex
+	commands []command.Command
+}
+
+func (b *recordingBus) Publish(_ context.Context, data []byte) error {
+	var cmd command.Command
+	if err := json.Unmarshal(data, &cmd); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.commands = append(b.commands, cmd)
+	return nil
+}
+
+func (b *recordingBus) names() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	names := make([]string, 0, len(b.commands))
+	for _, cmd := range b.commands {
+		names = append(names, cmd.Name)
+	}
+	return names
+}
+
+// recordingEnqueuer records enqueued timeout tasks instead of scheduling them.
+type recordingEnqueuer struct {
+	mu    sync.Mutex
+	tasks []json.RawMessage
+}
+
+func (e *recordingEnqueuer) Enqueue(_ context.Context, payload any, _ ...queue.EnqueueOption) (uuid.UUID, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tasks = append(e.tasks, data)
+	return uuid.New(), nil
+}
+
+func (e *recordingEnqueuer) task(i int) json.RawMessage {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.tasks[i]
+}
+
+type fixture struct {
+	orders   *saga.Saga[OrderState]
+	bus      *recordingBus
+	enqueuer *recordingEnqueuer
+	handlers map[string]event.Handler
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	f := &fixture{bus: &recordingBus{}, enqueuer: &recordingEnqueuer{}}
+
+	orders, err := saga.New[OrderState]("order_fulfillment", saga.NewMemoryStore(),
+		command.NewSender(f.bus), saga.WithTimeouts(f.enqueuer))
+	require.NoError(t, err)
+
+	orders.Register(
+		saga.StartedBy(func(evt OrderPlaced) string { return evt.OrderID },
+			func(ctx context.Context, inst *saga.Instance[OrderState], evt OrderPlaced) error {
+				inst.State.Total = evt.Total
+				if err := inst.Send(ctx, ReserveStock{OrderID: evt.OrderID}); err != nil {
+					return err
+				}
+				inst.AddCompensation(ReleaseStock{OrderID: evt.OrderID})
+				if err := inst.Send(ctx, ChargePayment{OrderID: evt.OrderID, Amount: evt.Total}); err != nil {
+					return err
+				}
+				return inst.ScheduleTimeout(ctx, "payment", 15*time.Minute)
+			}),
+		saga.On(func(evt PaymentCaptured) string { return evt.OrderID },
+			func(ctx context.Context, inst *saga.Instance[OrderState], evt PaymentCaptured) error {
+				inst.State.Paid = true
+				inst.CancelTimeout("payment")
+				inst.AddCompensation(RefundPayment{OrderID: evt.OrderID})
+				if err := inst.Send(ctx, ShipOrder{OrderID: evt.OrderID}); err != nil {
+					return err
+				}
+				inst.Complete()
+				return nil
+			}),
+		saga.On(func(evt PaymentDeclined) string { return evt.OrderID },
+			func(ctx context.Context, inst *saga.Instance[OrderState], evt PaymentDeclined) error {
+				return inst.Compensate(ctx)
+			}),
+		saga.OnTimeout("payment", func(ctx context.Context, inst *saga.Instance[OrderState]) error {
+			return inst.Compensate(ctx)
+		}),
+	)
+	f.orders = orders
+
+	f.handlers = make(map[string]event.Handler)
+	for _, h := range orders.Handlers() {
+		f.handlers[h.EventName()] = h
+	}
+
+	return f
+}
+
+// publish delivers the event to the saga handler for its type, as a processor would.
+func (f *fixture) publish(t *testing.T, payload any) {
+	t.Helper()
+
+	evt := event.NewEvent(payload)
+	h, ok := f.handlers[evt.Name]
+	require.True(t, ok, "no saga handler for %s", evt.Name)
+	require.NoError(t, h.Handle(event.WithEventMeta(context.Background(), evt), payload))
+}
+
+func (f *fixture) fireTimeout(t *testing.T, i int) {
+	t.Helper()
+	require.NoError(t, f.orders.TimeoutHandler().Handle(context.Background(), f.enqueuer.task(i)))
+}
+
+func (f *fixture) status(t *testing.T, key string) saga.Status {
+	t.Helper()
+
+	inst, err := f.orders.Get(context.Background(), key)
+	require.NoError(t, err)
+	return inst.Status()
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	sender := command.NewSender(&recordingBus{})
+
+	_, err := saga.New[OrderState]("", saga.NewMemoryStore(), sender)
+	require.ErrorIs(t, err, saga.ErrNameEmpty)
+
+	_, err = saga.New[OrderState]("orders", nil, sender)
+	require.ErrorIs(t, err, saga.ErrStoreNil)
+
+	_, err = saga.New[OrderState]("orders", saga.NewMemoryStore(), nil)
+	require.ErrorIs(t, err, saga.ErrSenderNil)
+
+	orders, err := saga.New[OrderState]("orders", saga.NewMemoryStore(), sender)
+	require.NoError(t, err)
+	assert.Equal(t, "orders", orders.Name())
+	assert.Equal(t, "saga.timeout.orders", orders.TimeoutHandler().Name())
+}
+
+func TestSaga(t *testing.T) {
+	t.Parallel()
+
+	t.Run("completes on success", func(t *testing.T) {
+		t.Parallel()
+		f := newFixture(t)
+
+		f.publish(t, OrderPlaced{OrderID: "order-1", Total: 100})
+		assert.Equal(t, saga.StatusActive, f.status(t, "order-1"))
+
+		inst, err := f.orders.Get(context.Background(), "order-1")
+		require.NoError(t, err)
+		assert.Equal(t, OrderState{Total: 100}, inst.State)
+
+		f.publish(t, PaymentCaptured{OrderID: "order-1"})
+		assert.Equal(t, saga.StatusCompleted, f.status(t, "order-1"))
+		assert.Equal(t, []string{"ReserveStock", "ChargePayment", "ShipOrder"}, f.bus.names())
+
+		// The cancelled timeout is ignored when its task runs
+		f.fireTimeout(t, 0)
+		assert.Equal(t, []string{"ReserveStock", "ChargePayment", "ShipOrder"}, f.bus.names())
+	})
+
+	t.Run("compensates on failure", func(t *testing.T) {
+		t.Parallel()
+		f := newFixture(t)
+
+		f.publish(t, OrderPlaced{OrderID: "order-1", Total: 100})
+		f.publish(t, PaymentDeclined{OrderID: "order-1"})
+
+		assert.Equal(t, saga.StatusCompensated, f.status(t, "order-1"))
+		assert.Equal(t, []string{"ReserveStock", "ChargePayment", "ReleaseStock"}, f.bus.names())
+	})
+
+	t.Run("compensates on timeout", func(t *testing.T) {
+		t.Parallel()
+		f := newFixture(t)
+
+		f.publish(t, OrderPlaced{OrderID: "order-1", Total: 100})
+		f.fireTimeout(t, 0)
+
+		assert.Equal(t, saga.StatusCompensated, f.status(t, "order-1"))
+		assert.Equal(t, []string{"ReserveStock", "ChargePayment", "ReleaseStock"}, f.bus.names())
+
+		// Later events are ignored
+		f.publish(t, PaymentCaptured{OrderID: "order-1"})
+		assert.Equal(t, saga.StatusCompensated, f.status(t, "order-1"))
+		assert.Len(t, f.bus.names(), 3)
+	})
+
+	t.Run("ignores duplicate and uncorrelated events", func(t *testing.T) {
+		t.Parallel()
+		f := newFixture(t)
+
+		f.publish(t, PaymentCaptured{OrderID: "order-1"})
+		_, err := f.orders.Get(context.Background(), "order-1")
+		require.ErrorIs(t, err, saga.ErrSagaNotFound)
+
+		f.publish(t, OrderPlaced{OrderID: "order-1", Total: 100})
+		f.publish(t, OrderPlaced{OrderID: "order-1", Total: 100})
+		f.publish(t, OrderPlaced{Total: 100})
+		assert.Equal(t, []string{"ReserveStock", "ChargePayment"}, f.bus.names())
+	})
+
+	t.Run("instances are independent", func(t *testing.T) {
+		t.Parallel()
+		f := newFixture(t)
+
+		f.publish(t, OrderPlaced{OrderID: "order-1", Total: 100})
+		f.publish(t, OrderPlaced{OrderID: "order-2", Total: 200})
+		f.publish(t, PaymentCaptured{OrderID: "order-2"})
+
+		assert.Equal(t, saga.StatusActive, f.status(t, "order-1"))
+		assert.Equal(t, saga.StatusCompleted, f.status(t, "order-2"))
+	})
+}
+
+func TestInstance_ScheduleTimeoutNotConfigured(t *testing.T) {
+	t.Parallel()
+
+	orders, err := saga.New[OrderState]("orders", saga.NewMemoryStore(), command.NewSender(&recordingBus{}))
+	require.NoError(t, err)
+
+	var scheduleErr error
+	orders.Register(saga.StartedBy(func(evt OrderPlaced) string { return evt.OrderID },
+		func(ctx context.Context, inst *saga.Instance[OrderState], evt OrderPlaced) error {
+			scheduleErr = inst.ScheduleTimeout(ctx, "payment", time.Minute)
+			return scheduleErr
+		}))
+
+	err = orders.Handlers()[0].Handle(context.Background(), OrderPlaced{OrderID: "order-1"})
+	require.ErrorIs(t, err, saga.ErrTimeoutsNotConfigured)
+
+	// A failed step saves nothing
+	_, err = orders.Get(context.Background(), "order-1")
+	require.ErrorIs(t, err, saga.ErrSagaNotFound)
+}
+
+func TestMemoryStore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store := saga.NewMemoryStore()
+
+	_, err := store.Load(ctx, "orders", "order-1")
+	require.ErrorIs(t, err, saga.ErrSagaNotFound)
+
+	record := saga.Record{Saga: "orders", Key: "order-1", Status: saga.StatusActive, State: json.RawMessage(`{}`), Version: 1}
+	require.NoError(t, store.Save(ctx, record, 0))
+	require.ErrorIs(t, store.Save(ctx, record, 0), saga.ErrConcurrencyConflict)
+
+	record.Version = 2
+	require.NoError(t, store.Save(ctx, record, 1))
+
+	loaded, err := store.Load(ctx, "orders", "order-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), loaded.Version)
+
+	// Keys are scoped by saga
+	_, err = store.Load(ctx, "payments", "order-1")
+	require.ErrorIs(t, err, saga.ErrSagaNotFound)
+}
+
+func TestSaga_ProcessorAndQueue(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := queue.NewMemoryStorage()
+	defer storage.Close()
+	enqueuer, err := queue.NewEnqueuer(storage)
+	require.NoError(t, err)
+
+	commands := &recordingBus{}
+	orders, err := saga.New[OrderState]("orders", saga.NewMemoryStore(),
+		command.NewSender(commands), saga.WithTimeouts(enqueuer))
+	require.NoError(t, err)
+	orders.Register(
+		saga.StartedBy(func(evt OrderPlaced) string { return evt.OrderID },
+			func(ctx context.Context, inst *saga.Instance[OrderState], evt OrderPlaced) error {
+				inst.AddCompensation(ReleaseStock{OrderID: evt.OrderID})
+				return inst.ScheduleTimeout(ctx, "payment", 20*time.Millisecond)
+			}),
+		saga.OnTimeout("payment", func(ctx context.Context, inst *saga.Instance[OrderState]) error {
+			return inst.Compensate(ctx)
+		}),
+	)
+
+	bus := event.NewChannelBus()
+	defer bus.Close()
+	processor := event.NewProcessor(event.WithEventSource(bus), event.WithHandler(orders.Handlers()...))
+	go func() { _ = processor.Start(ctx) }()
+
+	worker, err := queue.NewWorker(storage, queue.WithPullInterval(10*time.Millisecond))
+	require.NoError(t, err)
+	require.NoError(t, worker.RegisterHandler(orders.TimeoutHandler()))
+	go func() { _ = worker.Start(ctx) }()
+
+	require.NoError(t, event.NewPublisher(bus).Publish(ctx, OrderPlaced{OrderID: "order-1"}))
+
+	require.Eventually(t, func() bool {
+		inst, err := orders.Get(ctx, "order-1")
+		return err == nil && inst.Status() == saga.StatusCompensated
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"ReleaseStock"}, commands.names())
+}
@@ -0,0 +1,59 @@
+package saga
+
+import (
+	"context"
+
+	"github.com/dmitrymomot/foundation/core/event"
+)
+
+// Step is a reaction of a saga to an event or a timeout.
+// Create it with StartedBy, On or OnTimeout.
+type Step[S any] struct {
+	handler   func(s *Saga[S]) event.Handler
+	timeout   string
+	onTimeout func(ctx context.Context, inst *Instance[S]) error
+}
+
+// StartedBy creates a step that starts a saga instance on events of type E.
+// correlate returns the instance key of an event; an event for an existing
+// instance is a duplicate delivery and is ignored.
+//
+// Example:
+//
+//	saga.StartedBy(
+//	    func(evt OrderPlaced) string { return evt.OrderID },
+//	    func(ctx context.Context, inst *saga.Instance[OrderState], evt OrderPlaced) error {
+//	        inst.State.Total = evt.Total
+//	        if err := inst.Send(ctx, ChargePayment{OrderID: evt.OrderID, Amount: evt.Total}); err != nil {
+//	            return err
+//	        }
+//	        return inst.ScheduleTimeout(ctx, "payment", 15*time.Minute)
+//	    },
+//	)
+func StartedBy[S, E any](correlate func(evt E) string, fn func(ctx context.Context, inst *Instance[S], evt E) error) Step[S] {
+	return eventStep(true, correlate, fn)
+}
+
+// On creates a step that advances the active saga instance correlated to
+// events of type E. Events without an active instance are ignored.
+func On[S, E any](correlate func(evt E) string, fn func(ctx context.Context, inst *Instance[S], evt E) error) Step[S] {
+	return eventStep(false, correlate, fn)
+}
+
+// OnTimeout creates a step that runs when the named timeout of an active
+// instance fires, unless it was cancelled or rescheduled since.
+func OnTimeout[S any](name string, fn func(ctx context.Context, inst *Instance[S]) error) Step[S] {
+	return Step[S]{timeout: name, onTimeout: fn}
+}
+
+func eventStep[S, E any](start bool, correlate func(evt E) string, fn func(ctx context.Context, inst *Instance[S], evt E) error) Step[S] {
+	return Step[S]{
+		handler: func(s *Saga[S]) event.Handler {
+			return event.NewHandlerFunc(func(ctx context.Context, evt E) error {
+				return s.run(ctx, correlate(evt), start, func(ctx context.Context, inst *Instance[S]) error {
+					return fn(ctx, inst, evt)
+				})
+			})
+		},
+	}
+}
@@ -0,0 +1,120 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/dmitrymomot/foundation/core/command"
+)
+
+// Status is the lifecycle status of a saga instance.
+type Status string
+
+const (
+	// StatusActive instances react to events and timeouts.
+	StatusActive Status = "active"
+
+	// StatusCompleted instances finished their flow; later events are ignored.
+	StatusCompleted Status = "completed"
+
+	// StatusCompensated instances were rolled back with Compensate; later events are ignored.
+	StatusCompensated Status = "compensated"
+)
+
+// Record is a saga instance as persisted by a Store.
+type Record struct {
+	Saga          string            `json:"saga"`
+	Key           string            `json:"key"`
+	Status        Status            `json:"status"`
+	State         json.RawMessage   `json:"state"`
+	Timeouts      map[string]string `json:"timeouts,omitempty"` // pending timeout name to ID
+	Compensations []command.Command `json:"compensations,omitempty"`
+	Version       int64             `json:"version"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+}
+
+// Store persists saga instances with optimistic concurrency.
+//
+// A store that also implements RunInTx(ctx, fn func(ctx) error) error runs the
+// handling of each event and timeout in one transaction, like the idempotency
+// stores of core/event.
+type Store interface {
+	// Load returns the instance of the saga with the key, or ErrSagaNotFound.
+	Load(ctx context.Context, saga, key string) (Record, error)
+
+	// Save stores the record if the stored instance is at expectedVersion (zero
+	// for a new instance), and returns ErrConcurrencyConflict otherwise.
+	Save(ctx context.Context, record Record, expectedVersion int64) error
+}
+
+// txRunner is implemented by stores that can run the handling of an event in one transaction.
+type txRunner interface {
+	RunInTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// MemoryStore keeps saga instances in memory, for tests and development.
+// Safe for concurrent use.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	records map[string][]byte
+}
+
+// NewMemoryStore creates an empty in-memory saga store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string][]byte)}
+}
+
+// Load returns the instance of the saga with the key, or ErrSagaNotFound.
+func (s *MemoryStore) Load(_ context.Context, saga, key string) (Record, error) {
+	s.mu.RLock()
+	data, ok := s.records[memoryKey(saga, key)]
+	s.mu.RUnlock()
+
+	if !ok {
+		return Record{}, ErrSagaNotFound
+	}
+
+	// Records are kept encoded, so callers never share state with the store
+	var record Record
+	if err := json.Unmarshal(data, &record); err != nil {
+		return Record{}, err
+	}
+	return record, nil
+}
+
+// Save stores the record if the stored instance is at expectedVersion.
+func (s *MemoryStore) Save(_ context.Context, record Record, expectedVersion int64) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memoryKey(record.Saga, record.Key)
+
+	var version int64
+	if stored, ok := s.records[key]; ok {
+		var current struct {
+			Version int64 `json:"version"`
+		}
+		if err := json.Unmarshal(stored, &current); err != nil {
+			return err
+		}
+		version = current.Version
+	}
+	if version != expectedVersion {
+		return ErrConcurrencyConflict
+	}
+
+	s.records[key] = data
+	return nil
+}
+
+func memoryKey(saga, key string) string {
+	return saga + "\x00" + key
+}
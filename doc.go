@@ -38,14 +38,17 @@
 //	github.com/dmitrymomot/foundation/core/email/templates - Email template rendering using templ
 //	github.com/dmitrymomot/foundation/core/email/templates/components - Reusable email template components
 //	github.com/dmitrymomot/foundation/core/event         - Event-driven architecture with type-safe handlers
+//	github.com/dmitrymomot/foundation/core/eventstore    - Event store with optimistic concurrency and event-sourced aggregates
 //	github.com/dmitrymomot/foundation/core/handler       - Type-safe HTTP handler abstractions
 //	github.com/dmitrymomot/foundation/core/health        - HTTP handlers for service health monitoring
 //	github.com/dmitrymomot/foundation/core/i18n          - Internationalization with CLDR plural rules
 //	github.com/dmitrymomot/foundation/core/letsencrypt   - Let's Encrypt certificate management with explicit control
 //	github.com/dmitrymomot/foundation/core/logger        - Structured logging built on slog
+//	github.com/dmitrymomot/foundation/core/projection    - Event replay and projection runners for read models
 //	github.com/dmitrymomot/foundation/core/queue         - Job queue system with workers and scheduling
 //	github.com/dmitrymomot/foundation/core/response      - HTTP response utilities (JSON, HTML, SSE, WebSocket)
 //	github.com/dmitrymomot/foundation/core/router        - High-performance HTTP router with middleware
+//	github.com/dmitrymomot/foundation/core/saga          - Sagas for long-running flows across commands and events
 //	github.com/dmitrymomot/foundation/core/sanitizer     - Input sanitization and data cleaning
 //	github.com/dmitrymomot/foundation/core/server        - HTTP server with graceful shutdown
 //	github.com/dmitrymomot/foundation/core/session       - Generic session management system
@@ -83,14 +86,21 @@
 //
 // # Integration Packages
 //
-// Production-ready integrations for databases, email services, and storage:
+// Production-ready integrations for databases, email services, queues, message buses, event stores, and storage:
 //
+//	github.com/dmitrymomot/foundation/integration/bus/pgoutbox        - PostgreSQL transactional outbox with a relay
+//	github.com/dmitrymomot/foundation/integration/bus/redisstream     - Redis Streams transport for commands and events
 //	github.com/dmitrymomot/foundation/integration/database/mongo      - MongoDB client with health checking
 //	github.com/dmitrymomot/foundation/integration/database/opensearch - OpenSearch client initialization
 //	github.com/dmitrymomot/foundation/integration/database/pg         - PostgreSQL with migrations and pooling
 //	github.com/dmitrymomot/foundation/integration/database/redis      - Redis client with retry logic
 //	github.com/dmitrymomot/foundation/integration/email/postmark      - Postmark email service integration
 //	github.com/dmitrymomot/foundation/integration/email/smtp          - SMTP email sending implementation
+//	github.com/dmitrymomot/foundation/integration/eventstore/pgstore  - PostgreSQL event store and projection checkpoints
+//	github.com/dmitrymomot/foundation/integration/idempotency/pgstore - PostgreSQL processed-message store for idempotent handlers
+//	github.com/dmitrymomot/foundation/integration/idempotency/redisstore - Redis processed-message store for idempotent handlers
+//	github.com/dmitrymomot/foundation/integration/queue/pgstorage     - PostgreSQL storage for the job queue
+//	github.com/dmitrymomot/foundation/integration/queue/redisstorage  - Redis storage for the job queue
 //	github.com/dmitrymomot/foundation/integration/storage/s3          - S3-compatible storage implementation
 //
 // # Architecture Patterns
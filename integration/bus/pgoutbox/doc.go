@@ -0,0 +1,89 @@
+// Package pgoutbox provides a transactional outbox on PostgreSQL for core/event
+// and core/command.
+//
+// Publishing an event after a database transaction commits loses the event if
+// the process crashes in between; publishing it before the commit announces
+// changes that may be rolled back. Outbox solves this by storing the message in
+// the same transaction as the business data, and Relay forwards stored messages
+// to the real bus afterwards.
+//
+// # Key Features
+//
+//   - Outbox implements the bus interface of event.Publisher and command.Sender
+//   - Messages join the transaction carried by the context (pg.WithTx)
+//   - Relay forwards to any bus: redisstream.Bus, event.ChannelBus, ...
+//   - LISTEN/NOTIFY wakes the relay on commit, with polling as the fallback
+//   - Exponential backoff with jitter for messages the bus rejects
+//   - FOR UPDATE SKIP LOCKED lets several relay processes share the table
+//   - Dispatched messages are kept for inspection, then deleted after a retention period
+//   - Embedded goose migrations with their own version table
+//
+// # Usage
+//
+//	if err := pgoutbox.Migrate(ctx, pool, logger); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	outbox, err := pgoutbox.NewOutbox(pool)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	publisher := event.NewPublisher(outbox)
+//
+//	// The event is stored only if the order is
+//	tx, err := pool.Begin(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	defer tx.Rollback(ctx) // No-op after commit
+//
+//	ctx = pg.WithTx(ctx, tx)
+//	if err := orders.Create(ctx, order); err != nil {
+//		return err
+//	}
+//	if err := publisher.Publish(ctx, OrderPlaced{OrderID: order.ID}); err != nil {
+//		return err
+//	}
+//
+//	return tx.Commit(ctx)
+//
+// Run the relay next to the process that consumes the events, or in any process
+// with access to the database:
+//
+//	bus, _ := redisstream.New(client, "events")
+//	relay, err := pgoutbox.NewRelay(pool, bus, pgoutbox.WithRelayLogger(logger))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	g, ctx := errgroup.WithContext(ctx)
+//	g.Go(relay.Run(ctx))
+//
+// Publishing needs a transaction in the context only for atomicity; without
+// one, each message is stored on its own.
+//
+// # Delivery Semantics
+//
+// Delivery is at least once. A message is marked dispatched in the transaction
+// that claimed it, after the bus accepted it; if the relay crashes in between,
+// the message is sent again. Consumers must be idempotent.
+//
+// Messages are forwarded in insertion order. When the bus rejects a message, the
+// relay records the error, schedules a retry with backoff and stops the batch;
+// messages behind it wait for the next round but may overtake it while it is
+// backing off. Several relays share the work without sending the same message
+// twice, but do not preserve order between each other.
+//
+// # Notifications
+//
+// Outbox.Publish signals the relay channel with pg_notify in the same statement
+// as the insert; PostgreSQL delivers the notification when the transaction
+// commits. Disable it with WithoutNotify, or stop listening with WithoutListen
+// behind poolers that do not support LISTEN, and rely on WithPollInterval.
+//
+// # Lifecycle
+//
+// Start blocks until the context is cancelled; Run adapts it to errgroup, Stop
+// shuts it down with a timeout. Stats and Healthcheck follow the conventions of
+// the other long-running components of this module.
+package pgoutbox
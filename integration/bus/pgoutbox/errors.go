@@ -0,0 +1,11 @@
+package pgoutbox
+
+import "errors"
+
+var (
+	ErrDBNil                   = errors.New("database connection cannot be nil")
+	ErrBusNil                  = errors.New("target bus cannot be nil")
+	ErrRelayAlreadyStarted     = errors.New("outbox relay already started")
+	ErrRelayNotStarted         = errors.New("outbox relay not started")
+	ErrFailedToApplyMigrations = errors.New("failed to apply outbox migrations")
+)
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    message_id VARCHAR(255) NOT NULL DEFAULT '',
+    message_name VARCHAR(255) NOT NULL DEFAULT '',
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    dispatched_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- Relay path: undispatched messages in insertion order
+CREATE INDEX IF NOT EXISTS idx_event_outbox_undispatched
+    ON event_outbox (id)
+    WHERE dispatched_at IS NULL;
+
+-- Cleanup path: dispatched messages past their retention
+CREATE INDEX IF NOT EXISTS idx_event_outbox_dispatched_at
+    ON event_outbox (dispatched_at)
+    WHERE dispatched_at IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS event_outbox;
@@ -0,0 +1,119 @@
+package pgoutbox
+
+import (
+	"log/slog"
+	"time"
+)
+
+// OutboxOption configures an Outbox.
+type OutboxOption func(*Outbox)
+
+// WithOutboxChannel sets the LISTEN/NOTIFY channel signalled on publish.
+// It must match the relay's channel. Default is DefaultChannel.
+func WithOutboxChannel(channel string) OutboxOption {
+	return func(o *Outbox) {
+		if channel != "" {
+			o.channel = channel
+		}
+	}
+}
+
+// WithoutNotify disables the notification sent on publish.
+// The relay then picks messages up on its next poll.
+func WithoutNotify() OutboxOption {
+	return func(o *Outbox) {
+		o.channel = ""
+	}
+}
+
+// WithOutboxLogger sets the logger for internal operations.
+// Use slog.New(slog.NewTextHandler(io.Discard, nil)) to disable logging.
+func WithOutboxLogger(logger *slog.Logger) OutboxOption {
+	return func(o *Outbox) {
+		if logger != nil {
+			o.logger = logger
+		}
+	}
+}
+
+// RelayOption configures a Relay.
+type RelayOption func(*Relay)
+
+// WithRelayChannel sets the LISTEN/NOTIFY channel that wakes the relay.
+// It must match the outbox's channel. Default is DefaultChannel.
+func WithRelayChannel(channel string) RelayOption {
+	return func(r *Relay) {
+		if channel != "" {
+			r.channel = channel
+		}
+	}
+}
+
+// WithoutListen disables LISTEN, e.g. behind a pooler in transaction mode.
+// The relay then relies on polling alone.
+func WithoutListen() RelayOption {
+	return func(r *Relay) {
+		r.channel = ""
+	}
+}
+
+// WithPollInterval sets how often the table is polled. With LISTEN, polling only
+// picks up retries and notifications missed while disconnected. Default is 1s.
+func WithPollInterval(interval time.Duration) RelayOption {
+	return func(r *Relay) {
+		if interval > 0 {
+			r.pollInterval = interval
+		}
+	}
+}
+
+// WithBatchSize sets how many messages are claimed per transaction. Default is 100.
+func WithBatchSize(n int) RelayOption {
+	return func(r *Relay) {
+		if n > 0 {
+			r.batchSize = n
+		}
+	}
+}
+
+// WithRetryBackoff sets the delay before the first retry of a failed message and
+// the cap of its exponential growth. Defaults are 1s and 5m.
+func WithRetryBackoff(base, max time.Duration) RelayOption {
+	return func(r *Relay) {
+		if base > 0 {
+			r.retryBase = base
+		}
+		if max > 0 {
+			r.retryMax = max
+		}
+	}
+}
+
+// WithRetention sets how long dispatched messages are kept for inspection before
+// they are deleted. Default is 24h.
+func WithRetention(retention time.Duration) RelayOption {
+	return func(r *Relay) {
+		if retention > 0 {
+			r.retention = retention
+		}
+	}
+}
+
+// WithRelayShutdownTimeout sets how long Stop waits for the current batch. Default is 30s.
+func WithRelayShutdownTimeout(timeout time.Duration) RelayOption {
+	return func(r *Relay) {
+		if timeout > 0 {
+			r.shutdownTimeout = timeout
+		}
+	}
+}
+
+// WithRelayLogger sets the logger for internal operations.
+// Use slog.New(slog.NewTextHandler(io.Discard, nil)) to disable logging.
+func WithRelayLogger(logger *slog.Logger) RelayOption {
+	return func(r *Relay) {
+		if logger != nil {
+			r.logger = logger
+		}
+	}
+}
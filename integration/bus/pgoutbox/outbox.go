@@ -0,0 +1,101 @@
+package pgoutbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/dmitrymomot/foundation/integration/database/pg"
+)
+
+// DefaultChannel is the LISTEN/NOTIFY channel the outbox signals new messages on.
+const DefaultChannel = "event_outbox"
+
+// DB defines the subset of pgx operations used by the outbox.
+// Satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx.
+type DB interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Outbox stores messages in the event_outbox table instead of sending them.
+// It implements the bus interface of event.Publisher and command.Sender; a
+// Relay forwards the stored messages to the real bus.
+//
+// Publishing inside a transaction carried by the context (see pg.WithTx) makes
+// the message part of that transaction: it is relayed only if the transaction
+// commits, and never lost between the commit and the send.
+type Outbox struct {
+	db      DB
+	channel string
+	logger  *slog.Logger
+}
+
+// NewOutbox creates an outbox on the given database.
+// Apply the schema with Migrate before use.
+func NewOutbox(db DB, opts ...OutboxOption) (*Outbox, error) {
+	if db == nil {
+		return nil, ErrDBNil
+	}
+
+	o := &Outbox{
+		db:      db,
+		channel: DefaultChannel,
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o, nil
+}
+
+// Publish stores the message in the transaction carried by ctx, or on its own
+// without one. The notification is delivered when that transaction commits.
+func (o *Outbox) Publish(ctx context.Context, data []byte) error {
+	// Events and commands share the id and name fields; they are stored for inspection only
+	var meta struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	_ = json.Unmarshal(data, &meta)
+
+	var err error
+	if o.channel == "" {
+		_, err = o.conn(ctx).Exec(ctx,
+			`INSERT INTO event_outbox (message_id, message_name, payload) VALUES ($1, $2, $3)`,
+			meta.ID, meta.Name, data)
+	} else {
+		_, err = o.conn(ctx).Exec(ctx,
+			`WITH inserted AS (
+				INSERT INTO event_outbox (message_id, message_name, payload) VALUES ($1, $2, $3) RETURNING id
+			)
+			SELECT pg_notify($4, '') FROM inserted`,
+			meta.ID, meta.Name, data, o.channel)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store message %s in outbox: %w", meta.ID, err)
+	}
+
+	o.logger.DebugContext(ctx, "message stored in outbox",
+		slog.String("message_id", meta.ID),
+		slog.String("message_name", meta.Name))
+
+	return nil
+}
+
+// conn returns the transaction carried by ctx (see pg.WithTx), or the outbox database.
+func (o *Outbox) conn(ctx context.Context) DB {
+	if tx, ok := pg.TxFromContext(ctx); ok {
+		return tx
+	}
+	return o.db
+}
@@ -0,0 +1,132 @@
+package pgoutbox_test
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/event"
+	"github.com/dmitrymomot/foundation/integration/bus/pgoutbox"
+	"github.com/dmitrymomot/foundation/integration/database/pg"
+)
+
+func TestNewOutbox(t *testing.T) {
+	t.Parallel()
+
+	outbox, err := pgoutbox.NewOutbox(nil)
+	require.ErrorIs(t, err, pgoutbox.ErrDBNil)
+	assert.Nil(t, outbox)
+}
+
+func TestNewRelay(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejects nil pool", func(t *testing.T) {
+		t.Parallel()
+
+		relay, err := pgoutbox.NewRelay(nil, event.NewChannelBus())
+		require.ErrorIs(t, err, pgoutbox.ErrDBNil)
+		assert.Nil(t, relay)
+	})
+
+	t.Run("rejects nil bus", func(t *testing.T) {
+		t.Parallel()
+
+		relay, err := pgoutbox.NewRelay(newPool(t), nil)
+		require.ErrorIs(t, err, pgoutbox.ErrBusNil)
+		assert.Nil(t, relay)
+	})
+
+	t.Run("is not running before start", func(t *testing.T) {
+		t.Parallel()
+
+		relay, err := pgoutbox.NewRelay(newPool(t), event.NewChannelBus())
+		require.NoError(t, err)
+
+		assert.False(t, relay.Stats().IsRunning)
+		assert.Error(t, relay.Healthcheck(context.Background()))
+		assert.ErrorIs(t, relay.Stop(), pgoutbox.ErrRelayNotStarted)
+	})
+}
+
+func TestMigrations(t *testing.T) {
+	t.Parallel()
+
+	files, err := fs.Glob(pgoutbox.Migrations(), "*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	data, err := fs.ReadFile(pgoutbox.Migrations(), files[0])
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), "-- +goose Up")
+	assert.Contains(t, string(data), "CREATE TABLE IF NOT EXISTS event_outbox (")
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	err := pgoutbox.Migrate(context.Background(), nil, nil)
+	require.ErrorIs(t, err, pgoutbox.ErrFailedToApplyMigrations)
+	assert.ErrorIs(t, err, pgoutbox.ErrDBNil)
+}
+
+// recordingConn records which connection executed a statement and its arguments.
+// Only Exec is implemented; the embedded nil pgx.Tx panics on anything else.
+type recordingConn struct {
+	pgx.Tx
+	name string
+	log  *[]string
+	args *[][]any
+}
+
+func (c recordingConn) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	*c.log = append(*c.log, c.name)
+	*c.args = append(*c.args, args)
+	return pgconn.NewCommandTag("SELECT 1"), nil
+}
+
+func TestOutbox_PublishInContextTx(t *testing.T) {
+	t.Parallel()
+
+	var (
+		log  []string
+		args [][]any
+	)
+	outbox, err := pgoutbox.NewOutbox(recordingConn{name: "pool", log: &log, args: &args})
+	require.NoError(t, err)
+
+	type orderPlaced struct {
+		OrderID string `json:"order_id"`
+	}
+	publisher := event.NewPublisher(outbox)
+
+	ctx := context.Background()
+	require.NoError(t, publisher.Publish(ctx, orderPlaced{OrderID: "1"}))
+
+	txCtx := pg.WithTx(ctx, recordingConn{name: "tx", log: &log, args: &args})
+	require.NoError(t, publisher.Publish(txCtx, orderPlaced{OrderID: "2"}))
+
+	assert.Equal(t, []string{"pool", "tx"}, log)
+
+	require.Len(t, args, 2)
+	assert.NotEmpty(t, args[0][0], "message id")
+	assert.Equal(t, "orderPlaced", args[0][1], "message name")
+	assert.Equal(t, pgoutbox.DefaultChannel, args[0][3], "notification channel")
+}
+
+// newPool returns a pool that never connects; pgxpool dials lazily.
+func newPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	pool, err := pgxpool.New(context.Background(), "postgres://localhost:1/outbox")
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return pool
+}
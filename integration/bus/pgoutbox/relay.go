@@ -0,0 +1,420 @@
+package pgoutbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/dmitrymomot/foundation/integration/database/pg"
+)
+
+// bus is the destination of relayed messages, e.g. a redisstream.Bus or an
+// event.ChannelBus.
+type bus interface {
+	Publish(ctx context.Context, data []byte) error
+}
+
+// RelayStats provides observability metrics for monitoring and debugging
+type RelayStats struct {
+	MessagesDispatched int64     // Messages forwarded to the bus
+	MessagesFailed     int64     // Failed forwarding attempts, each scheduled for a retry
+	MessagesDeleted    int64     // Dispatched messages deleted after the retention period
+	IsRunning          bool      // Whether the relay is running
+	LastActivityAt     time.Time // Timestamp of the last dispatched message (zero if never)
+}
+
+// Relay forwards messages stored by Outbox to a bus, in insertion order.
+//
+// Messages are claimed with SELECT ... FOR UPDATE SKIP LOCKED, so several relay
+// processes can share one table. A message is marked dispatched in the same
+// transaction that claimed it, after the bus accepted it; a crash in between
+// sends it again, so delivery is at least once. A failed message is retried with
+// exponential backoff, and the rest of its batch waits for the next round.
+type Relay struct {
+	pool *pgxpool.Pool
+	bus  bus
+
+	// Configuration
+	channel         string
+	pollInterval    time.Duration
+	batchSize       int
+	retryBase       time.Duration
+	retryMax        time.Duration
+	retention       time.Duration
+	shutdownTimeout time.Duration
+	logger          *slog.Logger
+
+	// State management
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+	running atomic.Bool
+	wake    chan struct{}
+
+	// Observability metrics
+	dispatched     atomic.Int64
+	failed         atomic.Int64
+	deleted        atomic.Int64
+	lastActivityAt atomic.Int64
+}
+
+// NewRelay creates a relay from the outbox table to the bus.
+// Call Start() or Run() to begin relaying.
+func NewRelay(pool *pgxpool.Pool, bus bus, opts ...RelayOption) (*Relay, error) {
+	if pool == nil {
+		return nil, ErrDBNil
+	}
+	if bus == nil {
+		return nil, ErrBusNil
+	}
+
+	r := &Relay{
+		pool:            pool,
+		bus:             bus,
+		channel:         DefaultChannel,
+		pollInterval:    time.Second,
+		batchSize:       100,
+		retryBase:       time.Second,
+		retryMax:        5 * time.Minute,
+		retention:       24 * time.Hour,
+		shutdownTimeout: 30 * time.Second,
+		logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+		wake:            make(chan struct{}, 1),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r, nil
+}
+
+// Start relays messages until the context is cancelled. This is a blocking
+// operation; use Run() for the errgroup pattern or call this in a goroutine.
+func (r *Relay) Start(ctx context.Context) error {
+	r.mu.Lock()
+	if r.cancel != nil {
+		r.mu.Unlock()
+		return ErrRelayAlreadyStarted
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	r.cancel = cancel
+	r.done = make(chan struct{})
+	done := r.done
+	r.mu.Unlock()
+
+	defer close(done)
+	defer cancel()
+
+	r.running.Store(true)
+	defer r.running.Store(false)
+
+	r.logger.InfoContext(ctx, "outbox relay started",
+		slog.Duration("poll_interval", r.pollInterval),
+		slog.String("channel", r.channel))
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	if r.channel != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.listen(ctx)
+		}()
+	}
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+	for {
+		r.relayAll(ctx)
+
+		if time.Since(lastCleanup) >= time.Minute {
+			r.deleteDispatched(ctx)
+			lastCleanup = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("outbox relay stopping")
+			return ctx.Err()
+		case <-ticker.C:
+		case <-r.wake:
+		}
+	}
+}
+
+// Stop gracefully shuts down the relay with a timeout.
+// Returns an error if the shutdown timeout is exceeded.
+func (r *Relay) Stop() error {
+	r.mu.Lock()
+	if r.cancel == nil {
+		r.mu.Unlock()
+		return ErrRelayNotStarted
+	}
+	cancel, done := r.cancel, r.done
+	r.cancel = nil
+	r.mu.Unlock()
+
+	cancel()
+
+	r.logger.Info("outbox relay stopping, waiting for the current batch to complete",
+		slog.Duration("timeout", r.shutdownTimeout))
+
+	select {
+	case <-done:
+		r.logger.Info("outbox relay stopped cleanly")
+		return nil
+	case <-time.After(r.shutdownTimeout):
+		r.logger.Warn("outbox relay shutdown timeout exceeded",
+			slog.Duration("timeout", r.shutdownTimeout))
+		return fmt.Errorf("shutdown timeout exceeded after %s", r.shutdownTimeout)
+	}
+}
+
+// Run provides errgroup compatibility for coordinated lifecycle management.
+// Returns a function that starts the relay, monitors context cancellation,
+// and performs graceful shutdown when the context is cancelled.
+func (r *Relay) Run(ctx context.Context) func() error {
+	return func() error {
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- r.Start(ctx)
+		}()
+
+		select {
+		case <-ctx.Done():
+			// Context cancelled - perform graceful shutdown
+			if err := r.Stop(); err != nil {
+				r.logger.Error("graceful shutdown failed", slog.String("error", err.Error()))
+			}
+			<-errCh // Wait for Start() to exit
+			return nil
+		case err := <-errCh:
+			// Start() returned - check if it's a normal shutdown
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Stats returns current relay statistics for observability and monitoring.
+func (r *Relay) Stats() RelayStats {
+	lastActivity := r.lastActivityAt.Load()
+	var lastActivityTime time.Time
+	if lastActivity > 0 {
+		lastActivityTime = time.Unix(0, lastActivity)
+	}
+
+	return RelayStats{
+		MessagesDispatched: r.dispatched.Load(),
+		MessagesFailed:     r.failed.Load(),
+		MessagesDeleted:    r.deleted.Load(),
+		IsRunning:          r.running.Load(),
+		LastActivityAt:     lastActivityTime,
+	}
+}
+
+// Healthcheck validates that the relay is running and the database is reachable.
+// Returns nil if healthy, or an error describing the health issue.
+func (r *Relay) Healthcheck(ctx context.Context) error {
+	if !r.Stats().IsRunning {
+		return fmt.Errorf("outbox relay is not running")
+	}
+
+	return pg.Healthcheck(r.pool)(ctx)
+}
+
+// relayAll relays batches until one comes back short or fails.
+func (r *Relay) relayAll(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, err := r.relayBatch(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				r.logger.ErrorContext(ctx, "failed to relay outbox messages",
+					slog.String("error", err.Error()))
+			}
+			return
+		}
+		if n < r.batchSize {
+			return
+		}
+	}
+}
+
+// outboxMessage is a claimed outbox row.
+type outboxMessage struct {
+	id       int64
+	name     string
+	payload  []byte
+	attempts int
+}
+
+// relayBatch claims due messages, forwards them in order and records the outcome
+// in the claiming transaction. It stops at the first failure, leaving the rest
+// of the batch for the next round. Returns the number of claimed messages.
+func (r *Relay) relayBatch(ctx context.Context) (int, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, message_name, payload, attempts
+		FROM event_outbox
+		WHERE dispatched_at IS NULL AND next_attempt_at <= NOW()
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`, r.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+	messages, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (outboxMessage, error) {
+		var m outboxMessage
+		err := row.Scan(&m.id, &m.name, &m.payload, &m.attempts)
+		return m, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	// The outcome is recorded even if shutdown starts mid-batch
+	dbCtx := context.WithoutCancel(ctx)
+
+	var dispatched []int64
+	for _, m := range messages {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if publishErr := r.bus.Publish(ctx, m.payload); publishErr != nil {
+			r.failed.Add(1)
+			delay := r.backoff(m.attempts)
+			r.logger.ErrorContext(ctx, "failed to relay outbox message",
+				slog.Int64("outbox_id", m.id),
+				slog.String("message_name", m.name),
+				slog.Int("attempt", m.attempts+1),
+				slog.Duration("retry_in", delay),
+				slog.String("error", publishErr.Error()))
+
+			if _, err := tx.Exec(dbCtx, `
+				UPDATE event_outbox
+				SET attempts = attempts + 1, last_error = $2, next_attempt_at = NOW() + make_interval(secs => $3)
+				WHERE id = $1`, m.id, publishErr.Error(), delay.Seconds()); err != nil {
+				return 0, fmt.Errorf("failed to schedule retry of outbox message %d: %w", m.id, err)
+			}
+			break
+		}
+		dispatched = append(dispatched, m.id)
+	}
+
+	if len(dispatched) > 0 {
+		if _, err := tx.Exec(dbCtx,
+			`UPDATE event_outbox SET dispatched_at = NOW() WHERE id = ANY($1)`, dispatched); err != nil {
+			return 0, fmt.Errorf("failed to mark outbox messages dispatched: %w", err)
+		}
+	}
+	if err := tx.Commit(dbCtx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if len(dispatched) > 0 {
+		r.dispatched.Add(int64(len(dispatched)))
+		r.lastActivityAt.Store(time.Now().UnixNano())
+	}
+	if len(dispatched) < len(messages) {
+		// Stopped early; report a short batch so relayAll waits for the next round
+		return len(dispatched), nil
+	}
+	return len(messages), nil
+}
+
+// backoff returns the retry delay after the given number of failed attempts:
+// exponential from retryBase, capped at retryMax, with up to 20% jitter.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.retryMax
+	if attempts < 32 {
+		if d := r.retryBase << attempts; d > 0 && d < r.retryMax {
+			delay = d
+		}
+	}
+	return delay - time.Duration(rand.Int64N(int64(delay)/5+1))
+}
+
+// deleteDispatched removes dispatched messages older than the retention period.
+func (r *Relay) deleteDispatched(ctx context.Context) {
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM event_outbox WHERE dispatched_at < NOW() - make_interval(secs => $1)`,
+		r.retention.Seconds())
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.ErrorContext(ctx, "failed to delete dispatched outbox messages",
+				slog.String("error", err.Error()))
+		}
+		return
+	}
+	r.deleted.Add(tag.RowsAffected())
+}
+
+// listen wakes the relay on notifications from Outbox.Publish. It holds a
+// dedicated connection and reconnects after errors; polling covers the gaps.
+func (r *Relay) listen(ctx context.Context) {
+	for ctx.Err() == nil {
+		if err := r.listenConn(ctx); err != nil && ctx.Err() == nil {
+			r.logger.WarnContext(ctx, "outbox relay listener disconnected, falling back to polling",
+				slog.String("channel", r.channel),
+				slog.String("error", err.Error()))
+
+			t := time.NewTimer(r.pollInterval)
+			select {
+			case <-ctx.Done():
+			case <-t.C:
+			}
+			t.Stop()
+		}
+	}
+}
+
+func (r *Relay) listenConn(ctx context.Context) error {
+	conn, err := r.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	// A connection interrupted mid-wait is in an unknown state
+	defer func() {
+		if ctx.Err() != nil {
+			_ = conn.Conn().Close(context.Background())
+		}
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{r.channel}.Sanitize()); err != nil {
+		return fmt.Errorf("failed to listen on channel %s: %w", r.channel, err)
+	}
+
+	for {
+		if _, err := conn.Conn().WaitForNotification(ctx); err != nil {
+			return err
+		}
+		select {
+		case r.wake <- struct{}{}:
+		default: // A wake-up is already pending
+		}
+	}
+}
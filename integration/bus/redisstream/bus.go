@@ -0,0 +1,542 @@
+package redisstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dmitrymomot/foundation/core/command"
+	"github.com/dmitrymomot/foundation/core/event"
+	redisdb "github.com/dmitrymomot/foundation/integration/database/redis"
+)
+
+// Compile-time checks that Bus acknowledges handled messages
+var (
+	_ command.Acknowledger = (*Bus)(nil)
+	_ event.Acknowledger   = (*Bus)(nil)
+)
+
+// dataField is the stream entry field holding the message.
+const dataField = "data"
+
+// delivery is a message delivered to the channel and not yet acknowledged.
+type delivery struct {
+	id string
+	at time.Time
+}
+
+// Stats provides observability metrics for monitoring and debugging
+type Stats struct {
+	MessagesPublished    int64     // Messages added to the stream by this Bus
+	MessagesReceived     int64     // Messages delivered to the command or event channel, including redeliveries
+	MessagesAcked        int64     // Messages acknowledged after successful handling
+	MessagesClaimed      int64     // Stale messages claimed from other consumers or earlier failures
+	MessagesDeadLettered int64     // Messages moved to the dead letter stream
+	InFlight             int       // Delivered messages awaiting acknowledgement
+	IsRunning            bool      // Whether the read loop is running
+	LastActivityAt       time.Time // Timestamp of the last received message (zero if never)
+}
+
+// Bus is a durable command and event bus on a Redis stream.
+//
+// Publish appends messages to the stream. Start reads them through a consumer
+// group and delivers them on the channel returned by Commands and Events; the
+// message stays pending in the group until the dispatcher or processor
+// acknowledges it with Ack. Messages left pending by a crashed consumer or a
+// failed handler are claimed and redelivered once they have been idle long enough.
+type Bus struct {
+	client   redis.UniversalClient
+	stream   string
+	group    string
+	consumer string
+	startID  string
+
+	// Configuration
+	maxLen          int64
+	batchSize       int64
+	blockTimeout    time.Duration
+	claimInterval   time.Duration
+	minIdle         time.Duration
+	maxDeliveries   int64
+	deadStream      string
+	replyTTL        time.Duration
+	shutdownTimeout time.Duration
+	logger          *slog.Logger
+
+	ch chan []byte
+
+	// Delivered, unacknowledged messages by message data
+	inFlightMu sync.Mutex
+	inFlight   map[string][]delivery
+
+	// State management
+	mu      sync.Mutex
+	cancel  context.CancelFunc
+	done    chan struct{}
+	running atomic.Bool
+
+	// Observability metrics
+	published      atomic.Int64
+	received       atomic.Int64
+	acked          atomic.Int64
+	claimed        atomic.Int64
+	deadLettered   atomic.Int64
+	lastActivityAt atomic.Int64
+}
+
+// New creates a bus on the given stream.
+// A bus used only to publish needs no Start.
+func New(client redis.UniversalClient, stream string, opts ...Option) (*Bus, error) {
+	if client == nil {
+		return nil, ErrClientNil
+	}
+	if stream == "" {
+		return nil, ErrStreamNameEmpty
+	}
+
+	hostname, _ := os.Hostname()
+	b := &Bus{
+		client:          client,
+		stream:          stream,
+		group:           DefaultGroup,
+		consumer:        hostname + "-" + strconv.Itoa(os.Getpid()),
+		startID:         "0",
+		batchSize:       10,
+		blockTimeout:    2 * time.Second,
+		claimInterval:   30 * time.Second,
+		minIdle:         DefaultMinIdle,
+		deadStream:      stream + ":dead",
+		replyTTL:        DefaultReplyTTL,
+		shutdownTimeout: 30 * time.Second,
+		logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
+		ch:              make(chan []byte),
+		inFlight:        make(map[string][]delivery),
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b, nil
+}
+
+// Publish appends the message to the stream.
+// It implements the bus interface of command.Sender and event.Publisher.
+func (b *Bus) Publish(ctx context.Context, data []byte) error {
+	args := &redis.XAddArgs{
+		Stream: b.stream,
+		Values: map[string]any{dataField: data},
+	}
+	if b.maxLen > 0 {
+		args.MaxLen = b.maxLen
+		args.Approx = true
+	}
+
+	if err := b.client.XAdd(ctx, args).Err(); err != nil {
+		return fmt.Errorf("failed to publish to stream %s: %w", b.stream, err)
+	}
+	b.published.Add(1)
+	return nil
+}
+
+// Commands returns the channel of received messages for command.Dispatcher.
+func (b *Bus) Commands() <-chan []byte {
+	return b.ch
+}
+
+// Events returns the channel of received messages for event.Processor.
+func (b *Bus) Events() <-chan []byte {
+	return b.ch
+}
+
+// Ack acknowledges a delivered message, removing it from the group's pending
+// entries. The dispatcher and processor call it after successful handling.
+// Acknowledging a message that is not in flight is a no-op.
+func (b *Bus) Ack(ctx context.Context, data []byte) error {
+	id, ok := b.popInFlight(string(data))
+	if !ok {
+		return nil
+	}
+
+	if err := b.client.XAck(ctx, b.stream, b.group, id).Err(); err != nil {
+		return fmt.Errorf("failed to acknowledge message %s: %w", id, err)
+	}
+	b.acked.Add(1)
+	return nil
+}
+
+// Start creates the consumer group if needed and delivers messages until the
+// context is cancelled. This is a blocking operation; use Run() for the errgroup
+// pattern or call this in a goroutine. Messages this consumer received but did
+// not acknowledge before a restart are delivered first.
+func (b *Bus) Start(ctx context.Context) error {
+	b.mu.Lock()
+	if b.cancel != nil {
+		b.mu.Unlock()
+		return ErrBusAlreadyStarted
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	b.cancel = cancel
+	b.done = make(chan struct{})
+	done := b.done
+	b.mu.Unlock()
+
+	defer close(done)
+	defer cancel()
+
+	if err := b.createGroup(ctx); err != nil {
+		return err
+	}
+
+	b.running.Store(true)
+	defer b.running.Store(false)
+
+	b.logger.InfoContext(ctx, "redis stream bus started",
+		slog.String("stream", b.stream),
+		slog.String("group", b.group),
+		slog.String("consumer", b.consumer))
+
+	var lastClaim time.Time
+	readID := "0" // own pending entries first, then new messages
+	for {
+		if ctx.Err() != nil {
+			b.logger.Info("redis stream bus stopping")
+			return ctx.Err()
+		}
+
+		// Claiming before the own pending entries are read would deliver them twice
+		if readID == ">" && time.Since(lastClaim) >= b.claimInterval {
+			b.claimStale(ctx)
+			lastClaim = time.Now()
+		}
+
+		args := &redis.XReadGroupArgs{
+			Group:    b.group,
+			Consumer: b.consumer,
+			Streams:  []string{b.stream, readID},
+			Count:    b.batchSize,
+		}
+		if readID == ">" {
+			args.Block = b.blockTimeout
+		}
+
+		streams, err := b.client.XReadGroup(ctx, args).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			if ctx.Err() == nil {
+				b.logger.ErrorContext(ctx, "failed to read from stream",
+					slog.String("stream", b.stream),
+					slog.String("error", err.Error()))
+				b.sleep(ctx, b.blockTimeout)
+			}
+			continue
+		}
+
+		var messages []redis.XMessage
+		if len(streams) > 0 {
+			messages = streams[0].Messages
+		}
+		if readID != ">" {
+			if len(messages) == 0 {
+				readID = ">"
+				continue
+			}
+			readID = messages[len(messages)-1].ID
+		}
+
+		for _, msg := range messages {
+			b.deliver(ctx, msg)
+		}
+	}
+}
+
+// Stop gracefully shuts down the read loop with a timeout.
+// Returns an error if the shutdown timeout is exceeded.
+func (b *Bus) Stop() error {
+	b.mu.Lock()
+	if b.cancel == nil {
+		b.mu.Unlock()
+		return ErrBusNotStarted
+	}
+	cancel, done := b.cancel, b.done
+	b.cancel = nil
+	b.mu.Unlock()
+
+	cancel()
+
+	select {
+	case <-done:
+		b.logger.Info("redis stream bus stopped cleanly")
+		return nil
+	case <-time.After(b.shutdownTimeout):
+		b.logger.Warn("redis stream bus shutdown timeout exceeded",
+			slog.Duration("timeout", b.shutdownTimeout))
+		return fmt.Errorf("shutdown timeout exceeded after %s", b.shutdownTimeout)
+	}
+}
+
+// Run provides errgroup compatibility for coordinated lifecycle management.
+// Returns a function that starts the read loop, monitors context cancellation,
+// and performs graceful shutdown when the context is cancelled.
+func (b *Bus) Run(ctx context.Context) func() error {
+	return func() error {
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- b.Start(ctx)
+		}()
+
+		select {
+		case <-ctx.Done():
+			// Context cancelled - perform graceful shutdown
+			_ = b.Stop() // Ignore stop error in normal shutdown
+			<-errCh      // Wait for Start() to exit
+			return nil
+		case err := <-errCh:
+			// Start() returned - check if it's a normal shutdown
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Stats returns current bus statistics for observability and monitoring.
+func (b *Bus) Stats() Stats {
+	b.inFlightMu.Lock()
+	inFlight := 0
+	for _, deliveries := range b.inFlight {
+		inFlight += len(deliveries)
+	}
+	b.inFlightMu.Unlock()
+
+	lastActivity := b.lastActivityAt.Load()
+	var lastActivityTime time.Time
+	if lastActivity > 0 {
+		lastActivityTime = time.Unix(0, lastActivity)
+	}
+
+	return Stats{
+		MessagesPublished:    b.published.Load(),
+		MessagesReceived:     b.received.Load(),
+		MessagesAcked:        b.acked.Load(),
+		MessagesClaimed:      b.claimed.Load(),
+		MessagesDeadLettered: b.deadLettered.Load(),
+		InFlight:             inFlight,
+		IsRunning:            b.running.Load(),
+		LastActivityAt:       lastActivityTime,
+	}
+}
+
+// Healthcheck validates that the read loop is running and Redis is reachable.
+// Returns nil if healthy, or an error describing the health issue.
+func (b *Bus) Healthcheck(ctx context.Context) error {
+	if !b.Stats().IsRunning {
+		return fmt.Errorf("redis stream bus is not running")
+	}
+
+	return redisdb.Healthcheck(b.client)(ctx)
+}
+
+// createGroup creates the consumer group and the stream if they do not exist.
+func (b *Bus) createGroup(ctx context.Context) error {
+	err := b.client.XGroupCreateMkStream(ctx, b.stream, b.group, b.startID).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group %s on stream %s: %w", b.group, b.stream, err)
+	}
+	return nil
+}
+
+// deliver sends a message to the channel and tracks it until it is acknowledged.
+// Messages without data can never be handled and are acknowledged right away.
+func (b *Bus) deliver(ctx context.Context, msg redis.XMessage) {
+	data, ok := msg.Values[dataField].(string)
+	if !ok {
+		b.logger.ErrorContext(ctx, "dropping stream message without data",
+			slog.String("stream", b.stream),
+			slog.String("message_id", msg.ID),
+			slog.String("error", ErrInvalidMessageData.Error()))
+		if err := b.client.XAck(ctx, b.stream, b.group, msg.ID).Err(); err != nil {
+			b.logger.ErrorContext(ctx, "failed to acknowledge message",
+				slog.String("message_id", msg.ID),
+				slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	b.inFlightMu.Lock()
+	deliveries := b.inFlight[data]
+	if i := slices.IndexFunc(deliveries, func(d delivery) bool { return d.id == msg.ID }); i >= 0 {
+		deliveries[i].at = time.Now() // Redelivery of a message that failed here
+	} else {
+		b.inFlight[data] = append(deliveries, delivery{id: msg.ID, at: time.Now()})
+	}
+	b.inFlightMu.Unlock()
+
+	select {
+	case b.ch <- []byte(data):
+		b.received.Add(1)
+		b.lastActivityAt.Store(time.Now().UnixNano())
+	case <-ctx.Done():
+		// Still pending in the group; claimed again after a restart
+		b.popInFlight(data)
+	}
+}
+
+// popInFlight removes and returns the stream ID of the oldest delivery of data.
+func (b *Bus) popInFlight(data string) (string, bool) {
+	b.inFlightMu.Lock()
+	defer b.inFlightMu.Unlock()
+
+	deliveries := b.inFlight[data]
+	if len(deliveries) == 0 {
+		return "", false
+	}
+	if len(deliveries) == 1 {
+		delete(b.inFlight, data)
+	} else {
+		b.inFlight[data] = deliveries[1:]
+	}
+	return deliveries[0].id, true
+}
+
+// forgetStale drops in-flight messages delivered longer than minIdle ago. Their
+// handlers failed, or ran so long that the message is claimed again anyway.
+func (b *Bus) forgetStale() {
+	cutoff := time.Now().Add(-b.minIdle)
+
+	b.inFlightMu.Lock()
+	defer b.inFlightMu.Unlock()
+
+	for data, deliveries := range b.inFlight {
+		deliveries = slices.DeleteFunc(deliveries, func(d delivery) bool { return d.at.Before(cutoff) })
+		if len(deliveries) == 0 {
+			delete(b.inFlight, data)
+		} else {
+			b.inFlight[data] = deliveries
+		}
+	}
+}
+
+// claimStale moves messages that exceeded the delivery limit to the dead letter
+// stream, then claims and redelivers the remaining stale messages of the group.
+func (b *Bus) claimStale(ctx context.Context) {
+	b.forgetStale()
+
+	if b.maxDeliveries > 0 {
+		b.deadLetterStale(ctx)
+	}
+
+	start := "0-0"
+	for ctx.Err() == nil {
+		messages, next, err := b.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   b.stream,
+			Group:    b.group,
+			Consumer: b.consumer,
+			MinIdle:  b.minIdle,
+			Start:    start,
+			Count:    b.batchSize,
+		}).Result()
+		if err != nil {
+			if ctx.Err() == nil {
+				b.logger.ErrorContext(ctx, "failed to claim stale messages",
+					slog.String("stream", b.stream),
+					slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		b.claimed.Add(int64(len(messages)))
+		for _, msg := range messages {
+			b.deliver(ctx, msg)
+		}
+
+		if next == "0-0" || next == "" {
+			return
+		}
+		start = next
+	}
+}
+
+// deadLetterStale moves stale messages delivered maxDeliveries times to the dead
+// letter stream, along with their ID, group and delivery count.
+func (b *Bus) deadLetterStale(ctx context.Context) {
+	pending, err := b.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+		Stream: b.stream,
+		Group:  b.group,
+		Idle:   b.minIdle,
+		Start:  "-",
+		End:    "+",
+		Count:  b.batchSize,
+	}).Result()
+	if err != nil {
+		b.logger.ErrorContext(ctx, "failed to list pending messages",
+			slog.String("stream", b.stream),
+			slog.String("error", err.Error()))
+		return
+	}
+
+	for _, p := range pending {
+		if p.RetryCount < b.maxDeliveries {
+			continue
+		}
+
+		messages, err := b.client.XRangeN(ctx, b.stream, p.ID, p.ID, 1).Result()
+		if err != nil {
+			b.logger.ErrorContext(ctx, "failed to read message for dead letter stream",
+				slog.String("message_id", p.ID),
+				slog.String("error", err.Error()))
+			continue
+		}
+
+		// The dead letter stream may live in another cluster slot, so no MULTI here:
+		// a crash between the two commands leaves a duplicate, not a lost message
+		if len(messages) > 0 {
+			err = b.client.XAdd(ctx, &redis.XAddArgs{
+				Stream: b.deadStream,
+				Values: map[string]any{
+					dataField:    messages[0].Values[dataField],
+					"message_id": p.ID,
+					"group":      b.group,
+					"deliveries": p.RetryCount,
+				},
+			}).Err()
+		}
+		if err == nil {
+			err = b.client.XAck(ctx, b.stream, b.group, p.ID).Err()
+		}
+		if err != nil {
+			b.logger.ErrorContext(ctx, "failed to move message to dead letter stream",
+				slog.String("message_id", p.ID),
+				slog.String("error", err.Error()))
+			continue
+		}
+
+		b.deadLettered.Add(1)
+		b.logger.WarnContext(ctx, "message moved to dead letter stream",
+			slog.String("stream", b.stream),
+			slog.String("dead_letter_stream", b.deadStream),
+			slog.String("message_id", p.ID),
+			slog.Int64("deliveries", p.RetryCount))
+	}
+}
+
+// sleep waits for d or until the context is cancelled.
+func (b *Bus) sleep(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
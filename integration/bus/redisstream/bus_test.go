@@ -0,0 +1,120 @@
+package redisstream_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/integration/bus/redisstream"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejects nil client", func(t *testing.T) {
+		t.Parallel()
+
+		bus, err := redisstream.New(nil, "commands")
+		require.ErrorIs(t, err, redisstream.ErrClientNil)
+		assert.Nil(t, bus)
+	})
+
+	t.Run("rejects empty stream name", func(t *testing.T) {
+		t.Parallel()
+
+		client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+		t.Cleanup(func() { _ = client.Close() })
+
+		bus, err := redisstream.New(client, "")
+		require.ErrorIs(t, err, redisstream.ErrStreamNameEmpty)
+		assert.Nil(t, bus)
+	})
+
+	t.Run("is not running before start", func(t *testing.T) {
+		t.Parallel()
+
+		client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+		t.Cleanup(func() { _ = client.Close() })
+
+		bus, err := redisstream.New(client, "commands")
+		require.NoError(t, err)
+
+		assert.False(t, bus.Stats().IsRunning)
+		assert.Error(t, bus.Healthcheck(context.Background()))
+		assert.ErrorIs(t, bus.Stop(), redisstream.ErrBusNotStarted)
+	})
+
+	t.Run("acknowledging an unknown message is a no-op", func(t *testing.T) {
+		t.Parallel()
+
+		client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+		t.Cleanup(func() { _ = client.Close() })
+
+		bus, err := redisstream.New(client, "commands")
+		require.NoError(t, err)
+
+		assert.NoError(t, bus.Ack(context.Background(), []byte(`{"id":"1"}`)))
+	})
+}
+
+func TestNewReplyInbox(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejects nil client", func(t *testing.T) {
+		t.Parallel()
+
+		inbox, err := redisstream.NewReplyInbox(nil)
+		require.ErrorIs(t, err, redisstream.ErrClientNil)
+		assert.Nil(t, inbox)
+	})
+
+	t.Run("generates unique addresses", func(t *testing.T) {
+		t.Parallel()
+
+		client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+		t.Cleanup(func() { _ = client.Close() })
+
+		a, err := redisstream.NewReplyInbox(client)
+		require.NoError(t, err)
+		b, err := redisstream.NewReplyInbox(client)
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(a.Address(), redisstream.DefaultReplyPrefix))
+		assert.NotEqual(t, a.Address(), b.Address())
+	})
+}
+
+func TestBus_PublishReplyRejectsForeignKeys(t *testing.T) {
+	t.Parallel()
+
+	// Nothing listens here: a rejected address must fail before reaching Redis
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	t.Cleanup(func() { _ = client.Close() })
+
+	bus, err := redisstream.New(client, "commands")
+	require.NoError(t, err)
+
+	for _, address := range []string{"", "sessions:abc", "queue:tasks", redisstream.DefaultReplyPrefix} {
+		err := bus.PublishReply(context.Background(), address, []byte(`{}`))
+		require.ErrorIs(t, err, redisstream.ErrInvalidReplyAddress, address)
+	}
+}
+
+func TestWithReplyAddress(t *testing.T) {
+	t.Parallel()
+
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	t.Cleanup(func() { _ = client.Close() })
+
+	inbox, err := redisstream.NewReplyInbox(client, redisstream.WithReplyAddress(redisstream.DefaultReplyPrefix+"orders-1"))
+	require.NoError(t, err)
+	assert.Equal(t, redisstream.DefaultReplyPrefix+"orders-1", inbox.Address())
+
+	inbox, err = redisstream.NewReplyInbox(client, redisstream.WithReplyAddress("sessions:abc"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(inbox.Address(), redisstream.DefaultReplyPrefix), "addresses outside the reply prefix are ignored")
+}
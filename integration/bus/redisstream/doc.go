@@ -0,0 +1,110 @@
+// Package redisstream provides a durable command and event bus on Redis Streams
+// for core/command and core/event.
+//
+// Bus publishes messages with XADD and consumes them through a consumer group,
+// so a command.Dispatcher or event.Processor can run in a separate process from
+// the code that sends commands or publishes events. A message stays pending in
+// its group until the dispatcher or processor acknowledges it with XACK after
+// successful handling; nothing is lost when a consumer crashes mid-handling.
+//
+// # Key Features
+//
+//   - Implements the bus and source interfaces of both core/command and core/event
+//   - Consumer groups: consumers of a group share messages, every group sees all of them
+//   - Acknowledgement after successful handling (command.Acknowledger, event.Acknowledger)
+//   - XAUTOCLAIM of messages left pending by crashed consumers or failed handlers
+//   - Redelivery of a consumer's own pending messages when it restarts under the same name
+//   - Optional delivery limit with a dead letter stream for messages that always fail
+//   - Optional approximate stream length cap on publish
+//   - Replies to command.Ask through per-process reply streams (ReplyInbox)
+//   - Works with standalone, sentinel and cluster clients from integration/database/redis
+//
+// # Usage
+//
+//	client, err := redis.Connect(ctx, redisCfg)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	bus, err := redisstream.New(client, "commands",
+//		redisstream.WithGroup("dispatchers"),
+//		redisstream.WithMaxLen(100_000),
+//		redisstream.WithMaxDeliveries(5, ""),
+//	)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	// HTTP server: publish only, no Start needed
+//	sender := command.NewSender(bus)
+//
+//	// Worker process: consume
+//	dispatcher := command.NewDispatcher(
+//		command.WithCommandSource(bus),
+//		command.WithHandler(handlers...),
+//	)
+//
+//	g, ctx := errgroup.WithContext(ctx)
+//	g.Go(bus.Run(ctx))
+//	g.Go(dispatcher.Run(ctx))
+//
+// Events work the same way with event.NewPublisher, event.WithEventSource and
+// one group per consuming service:
+//
+//	billing, _ := redisstream.New(client, "events", redisstream.WithGroup("billing"))
+//	emails, _ := redisstream.New(client, "events", redisstream.WithGroup("emails"))
+//
+// # Delivery Semantics
+//
+// Delivery is at least once. A message is redelivered when its handler fails or
+// panics, when its consumer crashes before acknowledging it, and when a handler
+// runs longer than the claim idle time (WithClaim). Handlers must be idempotent.
+// For events, a message with one failed handler is redelivered to all handlers
+// of the event.
+//
+// Stale messages are claimed by whichever consumer of the group checks first,
+// every claim interval, once they have been pending for the idle time. With
+// WithMaxDeliveries, a stale message already delivered that many times is moved
+// to the dead letter stream, together with its original ID, group and delivery
+// count, instead of being claimed again.
+//
+// Messages that cannot be decoded as commands or events, and those without a
+// handler, are acknowledged right away and dropped with an error log.
+//
+// # Replies
+//
+// Bus publishes replies to commands sent with command.Ask (command.ReplyPublisher),
+// and ReplyInbox reads the replies addressed to one process from its own stream,
+// as the source of a command.Inbox:
+//
+//	replies, _ := redisstream.NewReplyInbox(client)
+//	inbox := command.NewInbox(replies)
+//	sender := command.NewSender(bus, command.WithReplyInbox(inbox))
+//
+//	g.Go(replies.Run(ctx))
+//	g.Go(inbox.Run(ctx))
+//
+//	// In the dispatcher process
+//	dispatcher := command.NewDispatcher(
+//		command.WithCommandSource(bus),
+//		command.WithReplyPublisher(bus),
+//		command.WithHandler(command.NewReplyHandlerFunc(getBalance)),
+//	)
+//
+// Reply streams are capped and expire after WithReplyTTL without replies; a
+// stopped ReplyInbox deletes its stream.
+//
+// # Consumer Names
+//
+// Consumers default to the host name and process ID. Give a consumer a stable
+// name with WithConsumer (e.g. the pod name of a StatefulSet) to have it pick up
+// its own pending messages immediately on restart instead of after the idle
+// time. Consumers with generated names accumulate in the group; remove old ones
+// with XGROUP DELCONSUMER if they matter for monitoring.
+//
+// # Lifecycle
+//
+// Start blocks until the context is cancelled; Run adapts it to errgroup, Stop
+// shuts it down with a timeout. Stats and Healthcheck follow the conventions of
+// the other long-running components of this module.
+package redisstream
@@ -0,0 +1,12 @@
+package redisstream
+
+import "errors"
+
+var (
+	ErrClientNil           = errors.New("redis client cannot be nil")
+	ErrStreamNameEmpty     = errors.New("stream name cannot be empty")
+	ErrBusAlreadyStarted   = errors.New("redis stream bus already started")
+	ErrBusNotStarted       = errors.New("redis stream bus not started")
+	ErrInvalidMessageData  = errors.New("invalid message data in redis stream")
+	ErrInvalidReplyAddress = errors.New("reply address is not a reply stream")
+)
@@ -0,0 +1,185 @@
+package redisstream
+
+import (
+	"log/slog"
+	"time"
+)
+
+const (
+	// DefaultGroup is the consumer group of a Bus without WithGroup.
+	DefaultGroup = "default"
+
+	// DefaultMinIdle is how long a delivered message may stay unacknowledged
+	// before another consumer claims it.
+	DefaultMinIdle = 5 * time.Minute
+
+	// DefaultReplyTTL is how long a reply stream outlives its last reply, so the
+	// streams of stopped inboxes disappear.
+	DefaultReplyTTL = 5 * time.Minute
+
+	// DefaultReplyPrefix prefixes the addresses of reply inboxes. A Bus only
+	// publishes replies to streams with this prefix.
+	DefaultReplyPrefix = "replies:"
+)
+
+// Option configures a Bus.
+type Option func(*Bus)
+
+// WithGroup sets the consumer group. Consumers of one group share the messages,
+// each handled by one of them; every group receives all messages. Dispatchers of
+// one command stream use one group, while every service processing an event
+// stream needs its own.
+func WithGroup(group string) Option {
+	return func(b *Bus) {
+		if group != "" {
+			b.group = group
+		}
+	}
+}
+
+// WithConsumer sets the consumer name within the group. Defaults to the host
+// name and process ID. A stable name lets a restarted process pick up the
+// messages it received but did not acknowledge before it stopped, without
+// waiting for them to be claimed.
+func WithConsumer(consumer string) Option {
+	return func(b *Bus) {
+		if consumer != "" {
+			b.consumer = consumer
+		}
+	}
+}
+
+// WithStartID sets where a newly created group starts reading: "0" (default)
+// delivers every message still in the stream, "$" only messages published after
+// the group is created. Existing groups keep their position.
+func WithStartID(id string) Option {
+	return func(b *Bus) {
+		if id != "" {
+			b.startID = id
+		}
+	}
+}
+
+// WithMaxLen caps the stream at approximately n messages, trimming the oldest on
+// publish. Trimmed messages are lost even if a group has not read them yet, so
+// leave room for consumer downtime. Zero (default) keeps every message.
+func WithMaxLen(n int64) Option {
+	return func(b *Bus) {
+		if n > 0 {
+			b.maxLen = n
+		}
+	}
+}
+
+// WithBatchSize sets how many messages are read or claimed per request. Default is 10.
+func WithBatchSize(n int64) Option {
+	return func(b *Bus) {
+		if n > 0 {
+			b.batchSize = n
+		}
+	}
+}
+
+// WithBlockTimeout sets how long a read waits for new messages. It bounds how
+// quickly Stop returns and how often stale messages are checked. Default is 2s.
+func WithBlockTimeout(timeout time.Duration) Option {
+	return func(b *Bus) {
+		if timeout > 0 {
+			b.blockTimeout = timeout
+		}
+	}
+}
+
+// WithClaim sets how often stale messages are claimed and how long a message
+// must stay unacknowledged to count as stale. Stale messages were delivered to a
+// consumer that crashed, or whose handler failed, and are redelivered by this
+// consumer. minIdle must exceed the longest handler run, or a slow message is
+// delivered twice. Defaults are 30s and DefaultMinIdle.
+func WithClaim(interval, minIdle time.Duration) Option {
+	return func(b *Bus) {
+		if interval > 0 {
+			b.claimInterval = interval
+		}
+		if minIdle > 0 {
+			b.minIdle = minIdle
+		}
+	}
+}
+
+// WithMaxDeliveries moves a stale message that has been delivered n times to the
+// dead letter stream instead of claiming it again, so a message that always fails
+// does not loop forever. The dead letter stream defaults to the stream name with
+// a ":dead" suffix. Zero (default) redelivers without limit.
+func WithMaxDeliveries(n int64, deadLetterStream string) Option {
+	return func(b *Bus) {
+		if n > 0 {
+			b.maxDeliveries = n
+		}
+		if deadLetterStream != "" {
+			b.deadStream = deadLetterStream
+		}
+	}
+}
+
+// WithShutdownTimeout sets how long Stop waits for the read loop to exit. Default is 30s.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(b *Bus) {
+		if timeout > 0 {
+			b.shutdownTimeout = timeout
+		}
+	}
+}
+
+// WithLogger sets the logger for internal operations.
+// Use slog.New(slog.NewTextHandler(io.Discard, nil)) to disable logging.
+func WithLogger(logger *slog.Logger) Option {
+	return func(b *Bus) {
+		if logger != nil {
+			b.logger = logger
+		}
+	}
+}
+
+// WithReplyTTL sets how long a reply stream outlives its last reply.
+// Default is DefaultReplyTTL.
+func WithReplyTTL(ttl time.Duration) Option {
+	return func(b *Bus) {
+		if ttl > 0 {
+			b.replyTTL = ttl
+		}
+	}
+}
+
+// ReplyInboxOption configures a ReplyInbox.
+type ReplyInboxOption func(*ReplyInbox)
+
+// WithReplyAddress sets the reply stream of the inbox. It must be unique to the
+// process and start with DefaultReplyPrefix, the only streams a Bus publishes
+// replies to; other addresses are ignored. Defaults to DefaultReplyPrefix
+// followed by a random UUID.
+func WithReplyAddress(address string) ReplyInboxOption {
+	return func(i *ReplyInbox) {
+		if isReplyAddress(address) {
+			i.address = address
+		}
+	}
+}
+
+// WithReplyBlockTimeout sets how long a read waits for new replies. Default is 2s.
+func WithReplyBlockTimeout(timeout time.Duration) ReplyInboxOption {
+	return func(i *ReplyInbox) {
+		if timeout > 0 {
+			i.blockTimeout = timeout
+		}
+	}
+}
+
+// WithReplyLogger sets the logger for internal operations.
+// Use slog.New(slog.NewTextHandler(io.Discard, nil)) to disable logging.
+func WithReplyLogger(logger *slog.Logger) ReplyInboxOption {
+	return func(i *ReplyInbox) {
+		if logger != nil {
+			i.logger = logger
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package redisstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dmitrymomot/foundation/core/command"
+	redisdb "github.com/dmitrymomot/foundation/integration/database/redis"
+)
+
+// replyMaxLen caps reply streams; an inbox reads its replies within moments.
+const replyMaxLen = 1000
+
+// Compile-time check that Bus answers commands sent with command.Ask
+var _ command.ReplyPublisher = (*Bus)(nil)
+
+// PublishReply appends a reply to the reply stream at address and extends the
+// stream's expiry by the reply TTL (WithReplyTTL).
+//
+// The address comes from the incoming command, so only reply streams are
+// written: addresses outside DefaultReplyPrefix are rejected with
+// ErrInvalidReplyAddress instead of touching arbitrary keys.
+func (b *Bus) PublishReply(ctx context.Context, address string, data []byte) error {
+	if !isReplyAddress(address) {
+		return fmt.Errorf("%w: %q", ErrInvalidReplyAddress, address)
+	}
+
+	_, err := b.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: address,
+			Values: map[string]any{dataField: data},
+			MaxLen: replyMaxLen,
+			Approx: true,
+		})
+		pipe.Expire(ctx, address, b.replyTTL)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish reply to stream %s: %w", address, err)
+	}
+	return nil
+}
+
+// isReplyAddress reports whether the address names a reply stream.
+func isReplyAddress(address string) bool {
+	return len(address) > len(DefaultReplyPrefix) && strings.HasPrefix(address, DefaultReplyPrefix)
+}
+
+// ReplyInbox reads replies to commands sent with command.Ask from a Redis stream
+// owned by this process. It is the source of a command.Inbox:
+//
+//	replies, _ := redisstream.NewReplyInbox(client)
+//	inbox := command.NewInbox(replies)
+//	sender := command.NewSender(bus, command.WithReplyInbox(inbox))
+//
+//	g.Go(replies.Run(ctx))
+//	g.Go(inbox.Run(ctx))
+type ReplyInbox struct {
+	client       redis.UniversalClient
+	address      string
+	blockTimeout time.Duration
+	logger       *slog.Logger
+
+	ch chan []byte
+
+	// State management
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewReplyInbox creates a reply inbox on the given client.
+// Call Start() or Run() to begin reading.
+func NewReplyInbox(client redis.UniversalClient, opts ...ReplyInboxOption) (*ReplyInbox, error) {
+	if client == nil {
+		return nil, ErrClientNil
+	}
+
+	i := &ReplyInbox{
+		client:       client,
+		address:      DefaultReplyPrefix + uuid.NewString(),
+		blockTimeout: 2 * time.Second,
+		logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+		ch:           make(chan []byte),
+	}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i, nil
+}
+
+// Address returns the reply stream, set as the reply address of asked commands.
+func (i *ReplyInbox) Address() string {
+	return i.address
+}
+
+// Replies returns the channel of received replies for command.Inbox.
+func (i *ReplyInbox) Replies() <-chan []byte {
+	return i.ch
+}
+
+// Start reads replies until the context is cancelled, then deletes the reply
+// stream. This is a blocking operation; use Run() for the errgroup pattern.
+func (i *ReplyInbox) Start(ctx context.Context) error {
+	i.mu.Lock()
+	if i.cancel != nil {
+		i.mu.Unlock()
+		return ErrBusAlreadyStarted
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	i.cancel = cancel
+	i.done = make(chan struct{})
+	done := i.done
+	i.mu.Unlock()
+
+	defer close(done)
+	defer cancel()
+	defer func() {
+		if err := i.client.Del(context.WithoutCancel(ctx), i.address).Err(); err != nil {
+			i.logger.Warn("failed to delete reply stream",
+				slog.String("stream", i.address),
+				slog.String("error", err.Error()))
+		}
+	}()
+
+	i.logger.InfoContext(ctx, "redis reply inbox started", slog.String("stream", i.address))
+
+	// The stream is ours alone, so everything in it is a reply to us
+	lastID := "0"
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		streams, err := i.client.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{i.address, lastID},
+			Count:   100,
+			Block:   i.blockTimeout,
+		}).Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			if ctx.Err() == nil {
+				i.logger.ErrorContext(ctx, "failed to read replies",
+					slog.String("stream", i.address),
+					slog.String("error", err.Error()))
+				t := time.NewTimer(i.blockTimeout)
+				select {
+				case <-ctx.Done():
+				case <-t.C:
+				}
+				t.Stop()
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				lastID = msg.ID
+				data, ok := msg.Values[dataField].(string)
+				if !ok {
+					continue
+				}
+				select {
+				case i.ch <- []byte(data):
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+	}
+}
+
+// Stop stops reading and waits for Start to return.
+func (i *ReplyInbox) Stop() error {
+	i.mu.Lock()
+	if i.cancel == nil {
+		i.mu.Unlock()
+		return ErrBusNotStarted
+	}
+	cancel, done := i.cancel, i.done
+	i.cancel = nil
+	i.mu.Unlock()
+
+	cancel()
+	<-done
+	return nil
+}
+
+// Run provides errgroup compatibility for coordinated lifecycle management.
+func (i *ReplyInbox) Run(ctx context.Context) func() error {
+	return func() error {
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- i.Start(ctx)
+		}()
+
+		select {
+		case <-ctx.Done():
+			_ = i.Stop()
+			<-errCh
+			return nil
+		case err := <-errCh:
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// Healthcheck validates that Redis is reachable.
+func (i *ReplyInbox) Healthcheck(ctx context.Context) error {
+	return redisdb.Healthcheck(i.client)(ctx)
+}
@@ -10,6 +10,7 @@
 //
 //   - Connect: Creates a connection pool with retry logic and connection verification
 //   - Migrate: Applies database schema migrations using goose with pgx integration
+//   - MigrateFS: Applies embedded migrations with their own version table, for integration packages
 //   - Healthcheck: Returns a health check function for monitoring connectivity
 //   - Error classification functions for common PostgreSQL error patterns
 //
@@ -202,14 +203,15 @@
 //
 //		// 2) Enqueue task within the same transaction
 //		type OrderCreated struct { ID uuid.UUID `json:"id"` }
-//		if err := enq.Enqueue(ctx, OrderCreated{ID: orderID}, queue.WithQueue("orders")); err != nil {
+//		if _, err := enq.Enqueue(ctx, OrderCreated{ID: orderID}, queue.WithQueue("orders")); err != nil {
 //			return err
 //		}
 //
 //		return tx.Commit(ctx)
 //	}
 //
-// In your repository/storage implementation, check the context for a transaction:
+// The queue storage in integration/queue/pgstorage joins the transaction this way.
+// In your own repositories, check the context for a transaction:
 //
 //	type Storage struct {
 //		pool *pgxpool.Pool
@@ -5,12 +5,14 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"os"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
 )
 
 // Migrate applies database schema migrations using goose with pgx integration.
@@ -53,6 +55,44 @@ func Migrate(ctx context.Context, pool *pgxpool.Pool, cfg Config, log *slog.Logg
 	return nil
 }
 
+// MigrateFS applies the goose migrations in fsys, tracking versions in table.
+// Integration packages use it to ship their schema as embedded migrations: a
+// table of their own keeps those versions apart from the application's, and
+// only the SQL files in fsys are applied, never Go migrations registered with
+// goose globally. A nil log discards goose output.
+func MigrateFS(ctx context.Context, pool *pgxpool.Pool, fsys fs.FS, table string, log *slog.Logger) error {
+	db := stdlib.OpenDBFromPool(pool)
+	defer func(db *sql.DB) {
+		if err := db.Close(); err != nil && log != nil {
+			log.ErrorContext(ctx, "failed to close database connection", "error", err)
+		}
+	}(db)
+
+	store, err := database.NewStore(database.DialectPostgres, table)
+	if err != nil {
+		return errors.Join(ErrFailedToApplyMigrations, err)
+	}
+
+	opts := []goose.ProviderOption{
+		goose.WithStore(store),
+		goose.WithDisableGlobalRegistry(true),
+	}
+	if log != nil {
+		opts = append(opts, goose.WithLogger(newSlogAdapter(log)))
+	}
+
+	provider, err := goose.NewProvider(goose.DialectCustom, db, fsys, opts...)
+	if err != nil {
+		return errors.Join(ErrFailedToApplyMigrations, err)
+	}
+
+	if _, err := provider.Up(ctx); err != nil {
+		return errors.Join(ErrFailedToApplyMigrations, err)
+	}
+
+	return nil
+}
+
 // migrateSlogAdapter bridges goose's Printf-style logging to structured logging.
 type migrateSlogAdapter struct {
 	log *slog.Logger
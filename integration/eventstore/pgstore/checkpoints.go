@@ -0,0 +1,81 @@
+package pgstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/dmitrymomot/foundation/core/projection"
+)
+
+var _ projection.CheckpointStore = (*CheckpointStore)(nil)
+
+// CheckpointStore keeps projection checkpoints in the projection_checkpoints table.
+// It implements projection.CheckpointStore.
+//
+// Replays lock the checkpoint (see Lock) and save it in the transaction of the
+// batch's handlers (see RunInTx), so replicas replaying the same projection
+// take turns, and handlers that write read models through the transaction in
+// ctx project every event exactly once.
+type CheckpointStore struct {
+	db DB
+}
+
+// NewCheckpointStore creates a checkpoint store on the given database.
+// Apply the schema with Migrate before use.
+func NewCheckpointStore(db DB) (*CheckpointStore, error) {
+	if db == nil {
+		return nil, ErrDBNil
+	}
+	return &CheckpointStore{db: db}, nil
+}
+
+// Load returns the checkpoint of the projection, or 0 if it has none.
+func (s *CheckpointStore) Load(ctx context.Context, name string) (int64, error) {
+	var position int64
+	err := connFor(ctx, s.db).QueryRow(ctx,
+		`SELECT position FROM projection_checkpoints WHERE name = $1`, name).Scan(&position)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to load checkpoint of projection %s: %w", name, err)
+	}
+	return position, nil
+}
+
+// Lock waits for the transaction-scoped advisory lock of the projection's
+// checkpoint and returns the checkpoint read under it. The lock is held until
+// the transaction carried by ctx ends, so call it inside RunInTx.
+func (s *CheckpointStore) Lock(ctx context.Context, name string) (int64, error) {
+	if _, err := connFor(ctx, s.db).Exec(ctx,
+		`SELECT pg_advisory_xact_lock(hashtextextended($1, 0))`, checkpointLockKey(name)); err != nil {
+		return 0, fmt.Errorf("failed to lock checkpoint of projection %s: %w", name, err)
+	}
+	return s.Load(ctx, name)
+}
+
+// checkpointLockKey is the advisory lock key of the projection's checkpoint.
+func checkpointLockKey(name string) string {
+	return "projection_checkpoints:" + name
+}
+
+// Save stores the checkpoint of the projection in the transaction carried by
+// ctx, or on its own without one.
+func (s *CheckpointStore) Save(ctx context.Context, name string, position int64) error {
+	if _, err := connFor(ctx, s.db).Exec(ctx,
+		`INSERT INTO projection_checkpoints (name, position) VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET position = EXCLUDED.position, updated_at = NOW()`,
+		name, position); err != nil {
+		return fmt.Errorf("failed to save checkpoint of projection %s: %w", name, err)
+	}
+	return nil
+}
+
+// RunInTx runs fn in the transaction carried by ctx, or in a new one committed
+// when fn succeeds. projection.Replayer runs each batch in it.
+func (s *CheckpointStore) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return runInTx(ctx, s.db, fn)
+}
@@ -0,0 +1,86 @@
+package pgstore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/integration/database/pg"
+	"github.com/dmitrymomot/foundation/integration/eventstore/pgstore"
+)
+
+func TestNewCheckpointStore(t *testing.T) {
+	t.Parallel()
+
+	store, err := pgstore.NewCheckpointStore(nil)
+	require.ErrorIs(t, err, pgstore.ErrDBNil)
+	assert.Nil(t, store)
+}
+
+// emptyTx answers every query with no rows.
+type emptyTx struct {
+	recordingTx
+}
+
+func (tx emptyTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	*tx.log = append(*tx.log, sql)
+	return errRow{err: pgx.ErrNoRows}
+}
+
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(dest ...any) error {
+	return r.err
+}
+
+func TestCheckpointStore_InContextTx(t *testing.T) {
+	t.Parallel()
+
+	var log []string
+	tx := emptyTx{recordingTx{log: &log}}
+	store, err := pgstore.NewCheckpointStore(tx)
+	require.NoError(t, err)
+
+	ctx := pg.WithTx(context.Background(), tx)
+
+	position, err := store.Load(ctx, "catalog")
+	require.NoError(t, err)
+	assert.Zero(t, position, "a projection without a checkpoint starts at the beginning")
+
+	// RunInTx joins the transaction carried by the context instead of beginning one
+	err = store.RunInTx(ctx, func(ctx context.Context) error {
+		return store.Save(ctx, "catalog", 42)
+	})
+	require.NoError(t, err)
+
+	require.Len(t, log, 2)
+	assert.Contains(t, log[0], "SELECT position FROM projection_checkpoints")
+	assert.Contains(t, log[1], "INSERT INTO projection_checkpoints")
+}
+
+func TestCheckpointStore_Lock(t *testing.T) {
+	t.Parallel()
+
+	var log []string
+	tx := emptyTx{recordingTx{log: &log}}
+	store, err := pgstore.NewCheckpointStore(tx)
+	require.NoError(t, err)
+
+	err = store.RunInTx(pg.WithTx(context.Background(), tx), func(ctx context.Context) error {
+		position, err := store.Lock(ctx, "catalog")
+		require.NoError(t, err)
+		assert.Zero(t, position)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The checkpoint is read only once the lock is held
+	require.Len(t, log, 2)
+	assert.Contains(t, log[0], "pg_advisory_xact_lock")
+	assert.Contains(t, log[1], "SELECT position FROM projection_checkpoints")
+}
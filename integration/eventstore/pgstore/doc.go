@@ -0,0 +1,76 @@
+// Package pgstore provides a PostgreSQL event store for core/eventstore and
+// checkpoint store for core/projection.
+//
+// Events live in the event_store table, one row per event, with a global position
+// and a version within their stream. Appends check the expected stream version
+// and run in the transaction carried by the context (pg.WithTx), so events can be
+// stored atomically with other writes of the same transaction.
+//
+// # Key Features
+//
+//   - Implements eventstore.EventStore
+//   - Optimistic concurrency on the stream version, backed by a unique constraint
+//   - Gap-free reading by global position: appends serialize on a transaction-scoped advisory lock
+//   - Runs in the transaction carried by the context (pg.WithTx), or a new one
+//   - Projection checkpoints saved in the transaction of the projection's handlers
+//   - Embedded goose migrations with their own version table
+//
+// # Usage
+//
+//	if err := pgstore.Migrate(ctx, pool, logger); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	store, err := pgstore.New(pool)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	account, err := eventstore.Load(ctx, store, accountID, accountAppliers...)
+//	if err := account.Raise(MoneyDeposited{Amount: 100}); err != nil {
+//		return err
+//	}
+//	_, err = eventstore.Save(ctx, store, account)
+//
+// # Publishing Appended Events
+//
+// Wrap the store with eventstore.NewPublishingStore and the outbox of
+// integration/bus/pgoutbox to publish appended events to event.Processor
+// handlers. Both write in the transaction carried by the context, so events are
+// published exactly when they are stored:
+//
+//	outbox, _ := pgoutbox.NewOutbox(pool)
+//	events, _ := eventstore.NewPublishingStore(store, outbox)
+//
+//	err := store.RunInTx(ctx, func(ctx context.Context) error {
+//		_, err := eventstore.Save(ctx, events, account)
+//		return err
+//	})
+//
+// # Projections
+//
+// Store is a projection.Source, and CheckpointStore keeps projection checkpoints
+// in the projection_checkpoints table. Replays run each batch's handlers and its
+// checkpoint update in one transaction that holds an advisory lock on the
+// checkpoint, so replicas running the same projection take turns, and read
+// models kept in the same database are updated exactly once per event when
+// handlers write through the transaction in ctx (pg.TxFromContext):
+//
+//	checkpoints, _ := pgstore.NewCheckpointStore(pool)
+//
+//	replayer, err := projection.NewReplayer("account-balances", store,
+//		projection.WithHandlers(event.NewHandlerFunc(func(ctx context.Context, evt MoneyDeposited) error {
+//			tx, _ := pg.TxFromContext(ctx)
+//			_, err := tx.Exec(ctx, `UPDATE balances SET amount = amount + $2 WHERE account_id = $1`,
+//				evt.AccountID, evt.Amount)
+//			return err
+//		})),
+//		projection.WithCheckpointStore(checkpoints),
+//	)
+//
+// # Concurrency
+//
+// Appends to different streams wait for each other until they commit, which
+// keeps global positions in commit order for readers of ReadAll. Keep
+// transactions that append events short.
+package pgstore
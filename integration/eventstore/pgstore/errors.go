@@ -0,0 +1,8 @@
+package pgstore
+
+import "errors"
+
+var (
+	ErrDBNil                   = errors.New("database connection cannot be nil")
+	ErrFailedToApplyMigrations = errors.New("failed to apply event store migrations")
+)
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS event_store (
+    position BIGSERIAL PRIMARY KEY,
+    event_id VARCHAR(255) NOT NULL,
+    stream_id VARCHAR(255) NOT NULL,
+    stream_version BIGINT NOT NULL,
+    event_name VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    -- Backstop for optimistic concurrency: one event per stream version
+    CONSTRAINT event_store_stream_version_key UNIQUE (stream_id, stream_version)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS event_store;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Schema version of the payload; events stored before versioning are version 1
+ALTER TABLE event_store ADD COLUMN IF NOT EXISTS event_version INTEGER NOT NULL DEFAULT 1;
+
+-- +goose Down
+ALTER TABLE event_store DROP COLUMN IF EXISTS event_version;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS projection_checkpoints (
+    name VARCHAR(255) PRIMARY KEY,
+    -- Position in event_store of the last event the projection has handled
+    position BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TABLE IF EXISTS projection_checkpoints;
@@ -0,0 +1,210 @@
+package pgstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/dmitrymomot/foundation/core/event"
+	"github.com/dmitrymomot/foundation/core/eventstore"
+	"github.com/dmitrymomot/foundation/integration/database/pg"
+)
+
+var _ eventstore.EventStore = (*Store)(nil)
+
+// appendLockKey is the transaction-scoped advisory lock that serializes appends.
+const appendLockKey = "event_store:append"
+
+// DB defines the subset of pgx operations used by the store.
+// Satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx.
+type DB interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Store keeps event streams in the event_store table.
+// It implements eventstore.EventStore.
+type Store struct {
+	db DB
+}
+
+// New creates a store on the given database.
+// Apply the schema with Migrate before use.
+func New(db DB) (*Store, error) {
+	if db == nil {
+		return nil, ErrDBNil
+	}
+	return &Store{db: db}, nil
+}
+
+// Append adds events to the end of the stream in the transaction carried by ctx,
+// or in a new one.
+//
+// Appends hold a transaction-scoped advisory lock until they commit, so events
+// become visible in the order of their global positions and ReadAll never skips
+// an event that commits later with a lower position. Keep transactions that
+// append short.
+func (s *Store) Append(ctx context.Context, streamID string, expectedVersion int64, events ...event.Event) ([]eventstore.Record, error) {
+	if streamID == "" {
+		return nil, eventstore.ErrStreamIDEmpty
+	}
+	if len(events) == 0 {
+		return nil, eventstore.ErrNoEvents
+	}
+
+	var records []eventstore.Record
+	err := s.RunInTx(ctx, func(ctx context.Context) error {
+		conn := s.conn(ctx)
+
+		if _, err := conn.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1, 0))`, appendLockKey); err != nil {
+			return fmt.Errorf("failed to lock event store: %w", err)
+		}
+
+		var version int64
+		if err := conn.QueryRow(ctx,
+			`SELECT COALESCE(MAX(stream_version), 0) FROM event_store WHERE stream_id = $1`,
+			streamID).Scan(&version); err != nil {
+			return fmt.Errorf("failed to read version of stream %s: %w", streamID, err)
+		}
+		if err := eventstore.CheckVersion(streamID, version, expectedVersion); err != nil {
+			return err
+		}
+
+		records = make([]eventstore.Record, 0, len(events))
+		for i, evt := range events {
+			record, err := eventstore.NewRecord(streamID, version+int64(i)+1, evt)
+			if err != nil {
+				return err
+			}
+
+			err = conn.QueryRow(ctx,
+				`INSERT INTO event_store (event_id, stream_id, stream_version, event_name, event_version, payload, created_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING position`,
+				record.ID, record.StreamID, record.StreamVersion, record.Name, record.Version, record.Payload, record.CreatedAt,
+			).Scan(&record.Position)
+			if err != nil {
+				if pg.IsDuplicateKeyError(err) {
+					return fmt.Errorf("%w: stream %s already has version %d",
+						eventstore.ErrConcurrencyConflict, streamID, record.StreamVersion)
+				}
+				return fmt.Errorf("failed to append event %s to stream %s: %w", record.ID, streamID, err)
+			}
+
+			records = append(records, record)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// ReadStream returns events of the stream from fromVersion onwards, oldest first.
+func (s *Store) ReadStream(ctx context.Context, streamID string, fromVersion int64, limit int) ([]eventstore.Record, error) {
+	return s.query(ctx,
+		`SELECT `+recordColumns+` FROM event_store
+		WHERE stream_id = $1 AND stream_version >= $2
+		ORDER BY stream_version
+		LIMIT $3`,
+		streamID, fromVersion, limitArg(limit))
+}
+
+// ReadStreamBackward returns events of the stream from fromVersion back to the first one, newest first.
+func (s *Store) ReadStreamBackward(ctx context.Context, streamID string, fromVersion int64, limit int) ([]eventstore.Record, error) {
+	return s.query(ctx,
+		`SELECT `+recordColumns+` FROM event_store
+		WHERE stream_id = $1 AND ($2::BIGINT <= 0 OR stream_version <= $2::BIGINT)
+		ORDER BY stream_version DESC
+		LIMIT $3`,
+		streamID, fromVersion, limitArg(limit))
+}
+
+// ReadAll returns events of all streams from fromPosition onwards, in the order they were appended.
+func (s *Store) ReadAll(ctx context.Context, fromPosition int64, limit int) ([]eventstore.Record, error) {
+	return s.query(ctx,
+		`SELECT `+recordColumns+` FROM event_store
+		WHERE position >= $1
+		ORDER BY position
+		LIMIT $2`,
+		fromPosition, limitArg(limit))
+}
+
+// RunInTx runs fn in the transaction carried by ctx, or in a new one committed
+// when fn succeeds. Append events together with other writes, such as messages
+// stored in the outbox of integration/bus/pgoutbox, by appending inside fn.
+func (s *Store) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return runInTx(ctx, s.db, fn)
+}
+
+// runInTx runs fn in the transaction carried by ctx, or in a new one begun on db.
+func runInTx(ctx context.Context, db DB, fn func(ctx context.Context) error) error {
+	if _, ok := pg.TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+
+	if err := fn(pg.WithTx(ctx, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// recordColumns lists the event_store columns in the order scanRecord reads them.
+const recordColumns = `position, event_id, stream_id, stream_version, event_name, event_version, payload, created_at`
+
+// query runs a read and collects its records.
+func (s *Store) query(ctx context.Context, sql string, args ...any) ([]eventstore.Record, error) {
+	rows, err := s.conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	records, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (eventstore.Record, error) {
+		var r eventstore.Record
+		err := row.Scan(&r.Position, &r.ID, &r.StreamID, &r.StreamVersion, &r.Name, &r.Version, &r.Payload, &r.CreatedAt)
+		return r, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	return records, nil
+}
+
+// limitArg turns a limit of zero or less into NULL, which PostgreSQL treats as no limit.
+func limitArg(limit int) *int {
+	if limit <= 0 {
+		return nil
+	}
+	return &limit
+}
+
+// conn returns the transaction carried by ctx (see pg.WithTx), or the store database.
+func (s *Store) conn(ctx context.Context) DB {
+	return connFor(ctx, s.db)
+}
+
+// connFor returns the transaction carried by ctx, or db.
+func connFor(ctx context.Context, db DB) DB {
+	if tx, ok := pg.TxFromContext(ctx); ok {
+		return tx
+	}
+	return db
+}
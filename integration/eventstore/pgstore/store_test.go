@@ -0,0 +1,124 @@
+package pgstore_test
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/event"
+	"github.com/dmitrymomot/foundation/core/eventstore"
+	"github.com/dmitrymomot/foundation/integration/database/pg"
+	"github.com/dmitrymomot/foundation/integration/eventstore/pgstore"
+)
+
+type MoneyDeposited struct {
+	Amount int
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	store, err := pgstore.New(nil)
+	require.ErrorIs(t, err, pgstore.ErrDBNil)
+	assert.Nil(t, store)
+}
+
+func TestMigrations(t *testing.T) {
+	t.Parallel()
+
+	files, err := fs.Glob(pgstore.Migrations(), "*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	data, err := fs.ReadFile(pgstore.Migrations(), files[0])
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), "-- +goose Up")
+	assert.Contains(t, string(data), "CREATE TABLE IF NOT EXISTS event_store (")
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	err := pgstore.Migrate(context.Background(), nil, nil)
+	require.ErrorIs(t, err, pgstore.ErrFailedToApplyMigrations)
+	assert.ErrorIs(t, err, pgstore.ErrDBNil)
+}
+
+// recordingTx records the statements executed in it. Every row it returns scans
+// the stream version, then increasing global positions.
+// Only Exec and QueryRow are implemented; the embedded nil pgx.Tx panics on anything else.
+type recordingTx struct {
+	pgx.Tx
+	log     *[]string
+	version int64
+}
+
+func (tx recordingTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	*tx.log = append(*tx.log, sql)
+	return pgconn.NewCommandTag("SELECT 1"), nil
+}
+
+func (tx recordingTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	*tx.log = append(*tx.log, sql)
+	if len(*tx.log) == 2 {
+		return int64Row(tx.version)
+	}
+	return int64Row(100 + len(*tx.log))
+}
+
+type int64Row int64
+
+func (r int64Row) Scan(dest ...any) error {
+	*dest[0].(*int64) = int64(r)
+	return nil
+}
+
+func TestStore_AppendInContextTx(t *testing.T) {
+	t.Parallel()
+
+	var log []string
+	tx := recordingTx{log: &log, version: 2}
+	store, err := pgstore.New(tx)
+	require.NoError(t, err)
+
+	// The store joins the transaction carried by the context instead of beginning one
+	ctx := pg.WithTx(context.Background(), tx)
+	records, err := store.Append(ctx, "account-1", 2,
+		event.NewEvent(MoneyDeposited{Amount: 1}),
+		event.NewEvent(MoneyDeposited{Amount: 2}))
+	require.NoError(t, err)
+
+	require.Len(t, log, 4)
+	assert.Contains(t, log[0], "pg_advisory_xact_lock")
+	assert.Contains(t, log[1], "MAX(stream_version)")
+	assert.Contains(t, log[2], "INSERT INTO event_store")
+	assert.Contains(t, log[3], "INSERT INTO event_store")
+
+	require.Len(t, records, 2)
+	assert.Equal(t, int64(3), records[0].StreamVersion)
+	assert.Equal(t, int64(4), records[1].StreamVersion)
+	assert.Equal(t, int64(103), records[0].Position)
+	assert.Equal(t, int64(104), records[1].Position)
+	assert.Equal(t, "MoneyDeposited", records[0].Name)
+	assert.Equal(t, 1, records[0].Version)
+}
+
+func TestStore_AppendConflict(t *testing.T) {
+	t.Parallel()
+
+	var log []string
+	tx := recordingTx{log: &log, version: 5}
+	store, err := pgstore.New(tx)
+	require.NoError(t, err)
+
+	ctx := pg.WithTx(context.Background(), tx)
+	_, err = store.Append(ctx, "account-1", eventstore.NoStream, event.NewEvent(MoneyDeposited{Amount: 1}))
+	require.ErrorIs(t, err, eventstore.ErrConcurrencyConflict)
+	assert.Len(t, log, 2, "nothing is inserted after a conflict")
+}
@@ -0,0 +1,47 @@
+// Package pgstore provides a PostgreSQL store of processed messages for the
+// idempotency decorators of core/command and core/event.
+//
+// Handlers that write to PostgreSQL get effectively-once processing: the
+// decorator runs the check, the handler and the record of its success in one
+// transaction, so the handler's writes and the record commit or roll back
+// together, and a redelivered message is skipped.
+//
+// # Key Features
+//
+//   - Implements command.ProcessedStore and event.ProcessedStore
+//   - Runs the handler in the transaction carried by the context (pg.WithTx), or a new one
+//   - Concurrent deliveries of one message serialize on a transaction-scoped advisory lock
+//   - Purge of old records
+//   - Embedded goose migrations with their own version table
+//
+// # Usage
+//
+//	if err := pgstore.Migrate(ctx, pool, logger); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	store, err := pgstore.New(pool)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	handler := command.NewHandlerFunc(
+//		command.ApplyDecorators(
+//			func(ctx context.Context, cmd ChargeCard) error {
+//				tx, _ := pg.TxFromContext(ctx)
+//				_, err := tx.Exec(ctx, `UPDATE accounts SET balance = balance - $1 WHERE id = $2`, cmd.Amount, cmd.AccountID)
+//				return err
+//			},
+//			command.WithIdempotency[ChargeCard](store, "billing.charge_card"),
+//		),
+//	)
+//
+// The handler must use the transaction from its context for its writes to be
+// atomic with the record. Side effects outside PostgreSQL, such as HTTP calls,
+// can still happen twice when the transaction fails to commit after them.
+//
+// Purge old records periodically; keep them longer than a message can be
+// redelivered after:
+//
+//	purged, err := store.Purge(ctx, 7*24*time.Hour)
+package pgstore
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS processed_messages (
+    handler VARCHAR(255) NOT NULL,
+    message_id VARCHAR(255) NOT NULL,
+    processed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (handler, message_id)
+);
+-- +goose StatementEnd
+
+-- Cleanup path: records past their retention
+CREATE INDEX IF NOT EXISTS idx_processed_messages_processed_at
+    ON processed_messages (processed_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS processed_messages;
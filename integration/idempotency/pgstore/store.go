@@ -0,0 +1,121 @@
+package pgstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/dmitrymomot/foundation/core/command"
+	"github.com/dmitrymomot/foundation/core/event"
+	"github.com/dmitrymomot/foundation/integration/database/pg"
+)
+
+var (
+	_ command.ProcessedStore = (*Store)(nil)
+	_ event.ProcessedStore   = (*Store)(nil)
+)
+
+// DB defines the subset of pgx operations used by the store.
+// Satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx.
+type DB interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Store records processed messages in the processed_messages table.
+// It implements command.ProcessedStore and event.ProcessedStore.
+type Store struct {
+	db DB
+}
+
+// New creates a store on the given database.
+// Apply the schema with Migrate before use.
+func New(db DB) (*Store, error) {
+	if db == nil {
+		return nil, ErrDBNil
+	}
+	return &Store{db: db}, nil
+}
+
+// IsProcessed reports whether the handler has processed the message.
+// Inside a transaction it first locks the pair until the transaction ends, so a
+// concurrent delivery of the same message waits and then sees it processed.
+func (s *Store) IsProcessed(ctx context.Context, handler, messageID string) (bool, error) {
+	conn := s.conn(ctx)
+
+	if _, ok := pg.TxFromContext(ctx); ok {
+		if _, err := conn.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1, 0))`,
+			handler+":"+messageID); err != nil {
+			return false, fmt.Errorf("failed to lock message %s: %w", messageID, err)
+		}
+	}
+
+	var processed bool
+	err := conn.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM processed_messages WHERE handler = $1 AND message_id = $2)`,
+		handler, messageID).Scan(&processed)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up message %s: %w", messageID, err)
+	}
+	return processed, nil
+}
+
+// MarkProcessed records that the handler has processed the message,
+// in the transaction carried by ctx if there is one.
+func (s *Store) MarkProcessed(ctx context.Context, handler, messageID string) error {
+	_, err := s.conn(ctx).Exec(ctx,
+		`INSERT INTO processed_messages (handler, message_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		handler, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to record message %s: %w", messageID, err)
+	}
+	return nil
+}
+
+// RunInTx runs fn in the transaction carried by ctx, or in a new one committed
+// when fn succeeds. The idempotency decorators of core/command and core/event use
+// it to commit the handler's writes together with the record of its success;
+// the handler finds the transaction with pg.TxFromContext.
+func (s *Store) RunInTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := pg.TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(context.WithoutCancel(ctx)) }()
+
+	if err := fn(pg.WithTx(ctx, tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Purge deletes records older than olderThan and returns how many were removed.
+// Messages are redelivered within minutes or hours, so records need not live long.
+func (s *Store) Purge(ctx context.Context, olderThan time.Duration) (int64, error) {
+	tag, err := s.conn(ctx).Exec(ctx,
+		`DELETE FROM processed_messages WHERE processed_at < $1`, time.Now().Add(-olderThan))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge processed messages: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// conn returns the transaction carried by ctx (see pg.WithTx), or the store database.
+func (s *Store) conn(ctx context.Context) DB {
+	if tx, ok := pg.TxFromContext(ctx); ok {
+		return tx
+	}
+	return s.db
+}
@@ -0,0 +1,99 @@
+package pgstore_test
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/command"
+	"github.com/dmitrymomot/foundation/integration/database/pg"
+	"github.com/dmitrymomot/foundation/integration/idempotency/pgstore"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	store, err := pgstore.New(nil)
+	require.ErrorIs(t, err, pgstore.ErrDBNil)
+	assert.Nil(t, store)
+}
+
+func TestMigrations(t *testing.T) {
+	t.Parallel()
+
+	files, err := fs.Glob(pgstore.Migrations(), "*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	data, err := fs.ReadFile(pgstore.Migrations(), files[0])
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), "-- +goose Up")
+	assert.Contains(t, string(data), "CREATE TABLE IF NOT EXISTS processed_messages (")
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	err := pgstore.Migrate(context.Background(), nil, nil)
+	require.ErrorIs(t, err, pgstore.ErrFailedToApplyMigrations)
+	assert.ErrorIs(t, err, pgstore.ErrDBNil)
+}
+
+// recordingTx records the statements executed in it.
+// Only Exec and QueryRow are implemented; the embedded nil pgx.Tx panics on anything else.
+type recordingTx struct {
+	pgx.Tx
+	log *[]string
+}
+
+func (tx recordingTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	*tx.log = append(*tx.log, sql)
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (tx recordingTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	*tx.log = append(*tx.log, sql)
+	return existsRow(false)
+}
+
+type existsRow bool
+
+func (r existsRow) Scan(dest ...any) error {
+	*dest[0].(*bool) = bool(r)
+	return nil
+}
+
+func TestStore_IdempotencyInContextTx(t *testing.T) {
+	t.Parallel()
+
+	var log []string
+	store, err := pgstore.New(recordingTx{log: &log})
+	require.NoError(t, err)
+
+	var handled bool
+	handler := command.ApplyDecorators(func(ctx context.Context, cmd struct{}) error {
+		tx, ok := pg.TxFromContext(ctx)
+		require.True(t, ok)
+		_, err := tx.Exec(ctx, "UPDATE accounts")
+		handled = true
+		return err
+	}, command.WithIdempotency[struct{}](store, "billing.charge_card"))
+
+	// The store joins the transaction carried by the context instead of beginning one
+	ctx := command.WithCommandID(context.Background(), "cmd-1")
+	ctx = pg.WithTx(ctx, recordingTx{log: &log})
+	require.NoError(t, handler(ctx, struct{}{}))
+	assert.True(t, handled)
+
+	require.Len(t, log, 4)
+	assert.Contains(t, log[0], "pg_advisory_xact_lock")
+	assert.Contains(t, log[1], "SELECT EXISTS")
+	assert.Equal(t, "UPDATE accounts", log[2])
+	assert.Contains(t, log[3], "INSERT INTO processed_messages")
+}
@@ -0,0 +1,29 @@
+// Package redisstore provides a Redis store of processed messages for the
+// idempotency decorators of core/command and core/event.
+//
+// Each processed message is a key prefix:handler:message_id that expires after
+// the TTL, so the store cleans up after itself. Choose a TTL longer than the time
+// a message can be redelivered after, e.g. the claim idle time and retention of
+// integration/bus/redisstream.
+//
+// # Usage
+//
+//	store, err := redisstore.New(client, redisstore.WithTTL(24*time.Hour))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	handler := event.NewHandlerFunc(
+//		event.ApplyDecorators(
+//			sendReceipt,
+//			event.WithIdempotency[OrderPlaced](store, "emails.send_receipt"),
+//		),
+//	)
+//
+// # Guarantees
+//
+// The record is written after the handler succeeds, outside any transaction of the
+// handler: a crash in between, or two deliveries of the same message handled at
+// the same time, run the handler twice. Use integration/idempotency/pgstore when
+// the handler writes to PostgreSQL and must run exactly once.
+package redisstore
@@ -0,0 +1,7 @@
+package redisstore
+
+import "errors"
+
+var (
+	ErrClientNil = errors.New("redis client cannot be nil")
+)
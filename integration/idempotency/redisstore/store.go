@@ -0,0 +1,95 @@
+package redisstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dmitrymomot/foundation/core/command"
+	"github.com/dmitrymomot/foundation/core/event"
+)
+
+const (
+	// DefaultPrefix is the key prefix of processed message records.
+	DefaultPrefix = "processed"
+
+	// DefaultTTL is how long a processed message is remembered.
+	DefaultTTL = 7 * 24 * time.Hour
+)
+
+var (
+	_ command.ProcessedStore = (*Store)(nil)
+	_ event.ProcessedStore   = (*Store)(nil)
+)
+
+// Store records processed messages as Redis keys that expire after a TTL.
+// It implements command.ProcessedStore and event.ProcessedStore.
+type Store struct {
+	client redis.UniversalClient
+	prefix string
+	ttl    time.Duration
+}
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithPrefix sets the key prefix. Default is DefaultPrefix.
+func WithPrefix(prefix string) Option {
+	return func(s *Store) {
+		if prefix != "" {
+			s.prefix = prefix
+		}
+	}
+}
+
+// WithTTL sets how long a processed message is remembered. It must exceed the
+// longest time a message can be redelivered after. Default is DefaultTTL.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Store) {
+		if ttl > 0 {
+			s.ttl = ttl
+		}
+	}
+}
+
+// New creates a store on the given client.
+func New(client redis.UniversalClient, opts ...Option) (*Store, error) {
+	if client == nil {
+		return nil, ErrClientNil
+	}
+
+	s := &Store{
+		client: client,
+		prefix: DefaultPrefix,
+		ttl:    DefaultTTL,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+// IsProcessed reports whether the handler has processed the message within the TTL.
+func (s *Store) IsProcessed(ctx context.Context, handler, messageID string) (bool, error) {
+	n, err := s.client.Exists(ctx, s.key(handler, messageID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to look up message %s: %w", messageID, err)
+	}
+	return n > 0, nil
+}
+
+// MarkProcessed records that the handler has processed the message.
+func (s *Store) MarkProcessed(ctx context.Context, handler, messageID string) error {
+	if err := s.client.Set(ctx, s.key(handler, messageID), 1, s.ttl).Err(); err != nil {
+		return fmt.Errorf("failed to record message %s: %w", messageID, err)
+	}
+	return nil
+}
+
+func (s *Store) key(handler, messageID string) string {
+	return s.prefix + ":" + handler + ":" + messageID
+}
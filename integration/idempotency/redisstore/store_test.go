@@ -0,0 +1,18 @@
+package redisstore_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/integration/idempotency/redisstore"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	store, err := redisstore.New(nil)
+	require.ErrorIs(t, err, redisstore.ErrClientNil)
+	assert.Nil(t, store)
+}
@@ -0,0 +1,116 @@
+// Package pgstorage provides a PostgreSQL-backed storage for the core/queue job system.
+//
+// Storage implements queue.EnqueuerRepository, queue.WorkerRepository and
+// queue.SchedulerRepository on top of a pgx connection pool, so tasks survive
+// restarts and any number of queue.Worker processes can share one database.
+//
+// # Key Features
+//
+//   - Task claiming with SELECT ... FOR UPDATE SKIP LOCKED (no double processing)
+//   - Priority-first, FIFO-within-priority task selection identical to queue.MemoryStorage
+//   - Lock expiration manager that returns tasks of crashed workers to the queue
+//   - Per-task retry policies and dead letter queue semantics matching queue.MemoryStorage
+//   - Atomic unique key enforcement (queue.UniqueTaskRepository)
+//   - Workflow chains, groups and error callbacks (queue.WorkflowRepository)
+//   - Dead letter queue listing, requeueing, deleting and purging (queue.DLQRepository)
+//   - Task cancellation and per-task timeouts (queue.CancelRepository)
+//   - Task results with deletion of completed tasks past their result TTL (queue.ResultRepository)
+//   - Task counts by queue and status and retrying tasks for dashboards (queue.StatsRepository)
+//   - Scheduler leader election with a session-level advisory lock (LeaderElector)
+//   - Transactional enqueue through a transaction carried by the context (pg.WithTx)
+//   - Embedded goose migrations for the tasks and tasks_dlq tables
+//
+// # Schema Migrations
+//
+// The package ships its own goose migrations. Migrate applies them and tracks
+// versions in a dedicated table (DefaultMigrationsTable), so it can run alongside
+// pg.Migrate for application migrations:
+//
+//	pool, err := pg.Connect(ctx, pgCfg)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	if err := pgstorage.Migrate(ctx, pool, logger); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// To manage the schema with your own tooling, copy the files exposed by Migrations().
+//
+// # Usage
+//
+//	storage, err := pgstorage.New(pool,
+//		pgstorage.WithLockCheckInterval(5*time.Second),
+//		pgstorage.WithLogger(logger),
+//	)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	enqueuer, _ := queue.NewEnqueuer(storage)
+//	worker, _ := queue.NewWorker(storage, queue.WithQueues("default", "email"))
+//	scheduler, _ := queue.NewScheduler(storage)
+//
+//	g, ctx := errgroup.WithContext(ctx)
+//	g.Go(storage.Run(ctx))
+//	g.Go(worker.Run(ctx))
+//	g.Go(scheduler.Run(ctx))
+//
+// # Transactional Enqueue
+//
+// Every operation runs in the transaction stored in its context with pg.WithTx,
+// and falls back to the pool otherwise. Enqueue a task in the same transaction as
+// the business write, so a rolled-back signup never sends a welcome email:
+//
+//	tx, err := pool.Begin(ctx)
+//	if err != nil {
+//		return err
+//	}
+//	defer tx.Rollback(ctx) // No-op after commit
+//
+//	ctx = pg.WithTx(ctx, tx)
+//	if _, err := tx.Exec(ctx, `INSERT INTO users (id, email) VALUES ($1, $2)`, userID, email); err != nil {
+//		return err
+//	}
+//	if _, err := enqueuer.Enqueue(ctx, WelcomeEmail{UserID: userID}); err != nil {
+//		return err
+//	}
+//
+//	return tx.Commit(ctx)
+//
+// Workers see the task only after the commit. Operations that need several
+// statements (unique tasks, workflows) run in a savepoint of the caller's
+// transaction, so their failure leaves it usable.
+//
+// # Leader Election
+//
+// LeaderElector lets one queue.Scheduler per cluster create periodic tasks. The
+// leader holds pg_try_advisory_lock on a dedicated connection; when its process
+// dies, PostgreSQL ends the session, drops the lock, and a standby takes over on
+// its next attempt:
+//
+//	elector, err := pgstorage.NewLeaderElector(pool, pgstorage.DefaultLeaderName)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	scheduler, _ := queue.NewScheduler(storage, queue.WithLeaderElector(elector))
+//
+// Session-level locks require a direct connection; they do not survive poolers
+// running in transaction mode.
+//
+// # Lock Expiration
+//
+// Workers lock claimed tasks for the worker's lock timeout. If a worker crashes,
+// the lock expiration manager started by Start/Run moves its processing tasks
+// back to pending once the lock has expired, without touching the retry count.
+// The same pass deletes completed tasks whose result TTL has expired.
+// Run the manager in at least one process that shares the database; running it
+// in every process is safe because the release is a single idempotent UPDATE.
+//
+// # Health Checking
+//
+//	healthSrv.AddCheck("queue-storage", storage.Healthcheck)
+//
+// Healthcheck reports an error when the lock expiration manager is not running
+// or the database does not respond.
+package pgstorage
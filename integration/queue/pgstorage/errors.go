@@ -0,0 +1,14 @@
+package pgstorage
+
+import "errors"
+
+var (
+	ErrDBNil                   = errors.New("database connection cannot be nil")
+	ErrTaskNil                 = errors.New("task cannot be nil")
+	ErrTaskNotFound            = errors.New("task not found")
+	ErrTaskNotProcessing       = errors.New("task is not in processing state")
+	ErrTaskAlreadyExists       = errors.New("task already exists")
+	ErrStorageAlreadyStarted   = errors.New("postgres storage already started")
+	ErrStorageNotStarted       = errors.New("postgres storage not started")
+	ErrFailedToApplyMigrations = errors.New("failed to apply queue migrations")
+)
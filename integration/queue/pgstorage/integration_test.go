@@ -0,0 +1,475 @@
+package pgstorage_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+	"github.com/dmitrymomot/foundation/integration/queue/pgstorage"
+)
+
+// The tests below run against the PostgreSQL server in DATABASE_URL and are
+// skipped without one. Each test migrates its own schema, so they run in
+// parallel without seeing each other's tasks.
+
+type (
+	reportPayload struct {
+		N int `json:"n"`
+	}
+	exportStep struct{}
+	shardStep  struct {
+		N int `json:"n"`
+	}
+	notifyStep  struct{}
+	failureStep struct{}
+)
+
+// newTestSchema creates a schema with the queue tables and drops it after the test.
+func newTestSchema(t *testing.T) string {
+	t.Helper()
+
+	url := os.Getenv("DATABASE_URL")
+	if url == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	admin, err := pgxpool.New(ctx, url)
+	require.NoError(t, err)
+
+	schema := "queue_test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	_, err = admin.Exec(ctx, `CREATE SCHEMA `+pgx.Identifier{schema}.Sanitize())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_, _ = admin.Exec(context.Background(), `DROP SCHEMA `+pgx.Identifier{schema}.Sanitize()+` CASCADE`)
+		admin.Close()
+	})
+
+	require.NoError(t, pgstorage.Migrate(ctx, newTestPool(t, schema), nil))
+	return schema
+}
+
+// newTestPool opens a pool whose connections use the schema.
+func newTestPool(t *testing.T, schema string) *pgxpool.Pool {
+	t.Helper()
+
+	cfg, err := pgxpool.ParseConfig(os.Getenv("DATABASE_URL"))
+	require.NoError(t, err)
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+// newTestStorage opens a storage on its own pool, as a separate process would.
+func newTestStorage(t *testing.T, schema string) *pgstorage.Storage {
+	t.Helper()
+
+	storage, err := pgstorage.New(newTestPool(t, schema))
+	require.NoError(t, err)
+	return storage
+}
+
+// runTestWorker runs a worker with the handlers until the test ends.
+func runTestWorker(t *testing.T, storage *pgstorage.Storage, handlers ...queue.Handler) {
+	t.Helper()
+
+	worker, err := queue.NewWorker(storage,
+		queue.WithPullInterval(5*time.Millisecond),
+		queue.WithMaxConcurrentTasks(2),
+		queue.WithWorkerLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	require.NoError(t, err)
+	require.NoError(t, worker.RegisterHandlers(handlers...))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- worker.Run(ctx)() }()
+
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-errCh)
+	})
+}
+
+func TestStorage_ConcurrentClaims(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	queues := []string{queue.DefaultQueueName}
+	schema := newTestSchema(t)
+	storages := []*pgstorage.Storage{newTestStorage(t, schema), newTestStorage(t, schema)}
+
+	enqueuer, err := queue.NewEnqueuer(storages[0])
+	require.NoError(t, err)
+	const tasks = 50
+	for i := range tasks {
+		_, err := enqueuer.Enqueue(ctx, reportPayload{N: i})
+		require.NoError(t, err)
+	}
+
+	var (
+		mu      sync.Mutex
+		claimed = make(map[uuid.UUID]int)
+		wg      sync.WaitGroup
+	)
+	for i := range 8 {
+		storage := storages[i%len(storages)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workerID := uuid.New()
+			for {
+				task, err := storage.ClaimTask(ctx, workerID, queues, time.Minute)
+				if errors.Is(err, queue.ErrNoTaskToClaim) {
+					return
+				}
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Equal(t, workerID, *task.LockedBy)
+
+				mu.Lock()
+				claimed[task.ID]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, claimed, tasks, "every task is claimed")
+	for id, n := range claimed {
+		assert.Equal(t, 1, n, "task %s is claimed once", id)
+	}
+}
+
+func TestStorage_RetryThenDLQ(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage := newTestStorage(t, newTestSchema(t))
+
+	enqueuer, err := queue.NewEnqueuer(storage)
+	require.NoError(t, err)
+	dlq, err := queue.NewDeadLetterQueue(storage)
+	require.NoError(t, err)
+
+	var attempts atomic.Int32
+	runTestWorker(t, storage, queue.NewTaskHandler(func(ctx context.Context, p reportPayload) error {
+		attempts.Add(1)
+		return errors.New("smtp down")
+	}, queue.WithHandlerRetryPolicy(queue.FixedBackoff(time.Millisecond))))
+
+	// Default MaxRetries: the task is retried, then moved to the DLQ by the worker
+	taskID, err := enqueuer.Enqueue(ctx, reportPayload{N: 1})
+	require.NoError(t, err)
+
+	var entries []*queue.TasksDlq
+	require.Eventually(t, func() bool {
+		entries, err = dlq.List(ctx, queue.DLQFilter{})
+		return err == nil && len(entries) == 1
+	}, 5*time.Second, 10*time.Millisecond, "exhausted task reaches the DLQ")
+
+	assert.Equal(t, taskID, entries[0].TaskID)
+	assert.Equal(t, "smtp down", entries[0].Error)
+	assert.Equal(t, int8(3), entries[0].RetryCount)
+	assert.JSONEq(t, `{"n":1}`, string(entries[0].Payload))
+	assert.Equal(t, int32(3), attempts.Load())
+
+	result, err := storage.GetTaskResult(ctx, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, queue.TaskStatusFailed, result.Status)
+	assert.Equal(t, "smtp down", result.Error)
+
+	// Requeue into a queue the worker does not pull, so the task stays put
+	requeuedID, err := dlq.Requeue(ctx, entries[0].ID, queue.WithRequeueQueue("manual"))
+	require.NoError(t, err)
+	assert.Equal(t, taskID, requeuedID)
+
+	task, err := storage.ClaimTask(ctx, uuid.New(), []string{"manual"}, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, taskID, task.ID)
+	assert.Equal(t, int8(0), task.RetryCount)
+
+	entries, err = dlq.List(ctx, queue.DLQFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestStorage_UniqueKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	schema := newTestSchema(t)
+	storage := newTestStorage(t, schema)
+
+	enqueuer, err := queue.NewEnqueuer(storage)
+	require.NoError(t, err)
+
+	// Subtests use their own queue and key, so their tasks never mix
+	enqueue := func(name string, n int, opts ...queue.EnqueueOption) (uuid.UUID, error) {
+		opts = append([]queue.EnqueueOption{queue.WithQueue(name), queue.WithUniqueKey(name, time.Minute)}, opts...)
+		return enqueuer.Enqueue(ctx, reportPayload{N: n}, opts...)
+	}
+	claim := func(t *testing.T, name string) *queue.Task {
+		t.Helper()
+		task, err := storage.ClaimTask(ctx, uuid.New(), []string{name}, time.Minute)
+		require.NoError(t, err)
+		return task
+	}
+	assertNothingToClaim := func(t *testing.T, name string) {
+		t.Helper()
+		_, err := storage.ClaimTask(ctx, uuid.New(), []string{name}, time.Minute)
+		assert.ErrorIs(t, err, queue.ErrNoTaskToClaim)
+	}
+
+	t.Run("reject", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := enqueue("reject", 1)
+		require.NoError(t, err)
+		_, err = enqueue("reject", 2)
+		assert.ErrorIs(t, err, queue.ErrDuplicateTask)
+	})
+
+	t.Run("keep existing", func(t *testing.T) {
+		t.Parallel()
+
+		firstID, err := enqueue("keep", 1)
+		require.NoError(t, err)
+		keptID, err := enqueue("keep", 2, queue.WithUniqueConflictMode(queue.UniqueConflictKeepExisting))
+		require.NoError(t, err)
+		assert.Equal(t, firstID, keptID)
+
+		assert.JSONEq(t, `{"n":1}`, string(claim(t, "keep").Payload))
+		assertNothingToClaim(t, "keep")
+	})
+
+	t.Run("replace pending", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := enqueue("replace", 1)
+		require.NoError(t, err)
+		_, err = enqueue("replace", 2, queue.WithUniqueConflictMode(queue.UniqueConflictReplace))
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{"n":2}`, string(claim(t, "replace").Payload))
+		assertNothingToClaim(t, "replace")
+	})
+
+	t.Run("replace cannot swap a processing task", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := enqueue("processing", 1)
+		require.NoError(t, err)
+		claim(t, "processing")
+
+		_, err = enqueue("processing", 2, queue.WithUniqueConflictMode(queue.UniqueConflictReplace))
+		assert.ErrorIs(t, err, queue.ErrDuplicateTask)
+	})
+
+	t.Run("concurrent enqueues across storages", func(t *testing.T) {
+		t.Parallel()
+
+		other, err := queue.NewEnqueuer(newTestStorage(t, schema))
+		require.NoError(t, err)
+		enqueuers := []*queue.Enqueuer{enqueuer, other}
+
+		var (
+			created atomic.Int32
+			wg      sync.WaitGroup
+		)
+		for i := range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := enqueuers[i%len(enqueuers)].Enqueue(ctx, reportPayload{N: i},
+					queue.WithQueue("concurrent"), queue.WithUniqueKey("concurrent", time.Minute))
+				if err == nil {
+					created.Add(1)
+					return
+				}
+				assert.ErrorIs(t, err, queue.ErrDuplicateTask)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), created.Load(), "one enqueue holds the key")
+		claim(t, "concurrent")
+		assertNothingToClaim(t, "concurrent")
+	})
+}
+
+func TestStorage_Workflows(t *testing.T) {
+	t.Parallel()
+
+	t.Run("completion", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		storage := newTestStorage(t, newTestSchema(t))
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+
+		done := make(chan []int, 1)
+		runTestWorker(t, storage,
+			queue.NewTaskHandler(func(ctx context.Context, _ exportStep) error {
+				return queue.SetResult(ctx, 10)
+			}),
+			queue.NewTaskHandler(func(ctx context.Context, p shardStep) error {
+				var base int
+				if err := queue.ParentResult(ctx, &base); err != nil {
+					return err
+				}
+				return queue.SetResult(ctx, base+p.N)
+			}),
+			queue.NewTaskHandler(func(ctx context.Context, _ notifyStep) error {
+				var results []int
+				if err := queue.ParentResult(ctx, &results); err != nil {
+					return err
+				}
+				done <- results
+				return nil
+			}),
+			queue.NewTaskHandler(func(ctx context.Context, _ failureStep) error {
+				t.Error("error callback ran for a successful workflow")
+				return nil
+			}),
+		)
+
+		_, err = enqueuer.EnqueueWorkflow(ctx, queue.Chain(
+			queue.Step(exportStep{}),
+			queue.Group(queue.Step(shardStep{N: 1}), queue.Step(shardStep{N: 2})),
+			queue.Step(notifyStep{}),
+		).OnError(queue.Step(failureStep{})))
+		require.NoError(t, err)
+
+		select {
+		case results := <-done:
+			assert.ElementsMatch(t, []int{11, 12}, results)
+		case <-time.After(5 * time.Second):
+			t.Fatal("workflow did not finish")
+		}
+
+		// The error callback is dropped once the last step completes
+		require.Eventually(t, func() bool {
+			counts, err := storage.CountTasks(ctx)
+			return err == nil && len(counts) == 1 && counts[0].Status == queue.TaskStatusCompleted
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		storage := newTestStorage(t, newTestSchema(t))
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+
+		failures := make(chan queue.WorkflowFailure, 1)
+		runTestWorker(t, storage,
+			queue.NewTaskHandler(func(ctx context.Context, _ exportStep) error {
+				return errors.New("bucket not found")
+			}, queue.WithHandlerRetryPolicy(queue.FixedBackoff(time.Millisecond))),
+			queue.NewTaskHandler(func(ctx context.Context, _ notifyStep) error {
+				t.Error("step ran after the workflow failed")
+				return nil
+			}),
+			queue.NewTaskHandler(func(ctx context.Context, _ failureStep) error {
+				var failure queue.WorkflowFailure
+				if err := queue.ParentResult(ctx, &failure); err != nil {
+					return err
+				}
+				failures <- failure
+				return nil
+			}),
+		)
+
+		workflowID, err := enqueuer.EnqueueWorkflow(ctx, queue.Chain(
+			queue.Step(exportStep{}),
+			queue.Step(notifyStep{}),
+		).OnError(queue.Step(failureStep{})))
+		require.NoError(t, err)
+
+		select {
+		case failure := <-failures:
+			assert.Equal(t, workflowID, failure.WorkflowID)
+			assert.Equal(t, "pgstorage_test.exportStep", failure.TaskName)
+			assert.Equal(t, "bucket not found", failure.Error)
+		case <-time.After(5 * time.Second):
+			t.Fatal("error callback did not run")
+		}
+
+		entries, err := storage.ListDLQ(ctx, queue.DLQFilter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "pgstorage_test.exportStep", entries[0].TaskName)
+
+		// The notify step is dropped, leaving only the error callback
+		require.Eventually(t, func() bool {
+			counts, err := storage.CountTasks(ctx)
+			return err == nil && len(counts) == 1 && counts[0].Status == queue.TaskStatusCompleted
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestLeaderElector(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	schema := newTestSchema(t)
+
+	// Advisory locks are database-wide, so the lease name keeps this test to itself
+	name := "leader_test_" + uuid.NewString()
+	newElector := func() *pgstorage.LeaderElector {
+		elector, err := pgstorage.NewLeaderElector(newTestPool(t, schema), name)
+		require.NoError(t, err)
+		return elector
+	}
+	leader, standby := newElector(), newElector()
+
+	acquired, err := leader.TryAcquire(ctx, time.Minute)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	acquired, err = standby.TryAcquire(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired, "the lock is held by the leader")
+
+	acquired, err = leader.TryAcquire(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired, "the leader keeps its lock")
+
+	// A unique key equal to the lease name must not wait on the leader's lock
+	enqueuer, err := queue.NewEnqueuer(newTestStorage(t, schema))
+	require.NoError(t, err)
+	enqueueCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	_, err = enqueuer.Enqueue(enqueueCtx, reportPayload{N: 1}, queue.WithUniqueKey(name, time.Minute))
+	require.NoError(t, err)
+
+	require.NoError(t, leader.Release(ctx))
+
+	acquired, err = standby.TryAcquire(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired, "the standby takes over after release")
+	require.NoError(t, standby.Release(ctx))
+}
@@ -0,0 +1,119 @@
+package pgstorage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+// DefaultLeaderName is the lease name used by NewLeaderElector when none is given.
+const DefaultLeaderName = "queue:scheduler"
+
+// Compile-time check that LeaderElector implements queue.LeaderElector
+var _ queue.LeaderElector = (*LeaderElector)(nil)
+
+// LeaderElector implements queue.LeaderElector with a session-level PostgreSQL advisory lock.
+//
+// The leader keeps the lock on a dedicated connection taken out of the pool.
+// PostgreSQL releases the lock as soon as that session ends, so a standby takes
+// over on its next attempt after the leader crashes or loses its connection. A
+// leader that finds its session gone reports queue.ErrLeadershipLost, so its
+// Scheduler steps down at once instead of waiting out the lease TTL.
+// Session locks do not work through poolers in transaction mode (e.g. PgBouncer),
+// so connect the pool directly to PostgreSQL.
+type LeaderElector struct {
+	pool *pgxpool.Pool
+	key  string
+
+	mu   sync.Mutex
+	conn *pgx.Conn // Holds the advisory lock while the candidate leads
+}
+
+// NewLeaderElector creates a candidate for the lease with the given name.
+// Candidates sharing a name compete for the same advisory lock.
+func NewLeaderElector(pool *pgxpool.Pool, name string) (*LeaderElector, error) {
+	if pool == nil {
+		return nil, ErrDBNil
+	}
+	if name == "" {
+		name = DefaultLeaderName
+	}
+
+	return &LeaderElector{
+		pool: pool,
+		key:  leaderLockKey(name),
+	}, nil
+}
+
+// leaderLockKey namespaces the lease name so it cannot collide with unique task keys,
+// which share the hashtextextended advisory lock space.
+func leaderLockKey(name string) string {
+	return "queue_leader:" + name
+}
+
+// TryAcquire takes the advisory lock, or checks that the session holding it is alive.
+// The lock lives as long as its session, so ttl is not used.
+func (e *LeaderElector) TryAcquire(ctx context.Context, _ time.Duration) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn != nil {
+		if err := e.conn.Ping(ctx); err != nil {
+			// PostgreSQL released the lock with the session; a standby may hold it already
+			e.closeConn()
+			return false, fmt.Errorf("%w: leader lock session lost: %w", queue.ErrLeadershipLost, err)
+		}
+		return true, nil
+	}
+
+	conn, err := e.pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	var locked bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtextextended($1, 0))`, e.key).Scan(&locked); err != nil {
+		conn.Release()
+		return false, fmt.Errorf("failed to take leader lock: %w", err)
+	}
+	if !locked {
+		conn.Release()
+		return false, nil
+	}
+
+	// Detach the connection from the pool so the lock outlives pool housekeeping
+	e.conn = conn.Hijack()
+	return true, nil
+}
+
+// Release closes the session holding the advisory lock, which releases the lock.
+func (e *LeaderElector) Release(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		return nil
+	}
+
+	conn := e.conn
+	e.conn = nil
+	if err := conn.Close(ctx); err != nil {
+		return fmt.Errorf("failed to close leader lock session: %w", err)
+	}
+	return nil
+}
+
+// closeConn drops a broken leader session.
+func (e *LeaderElector) closeConn() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_ = e.conn.Close(ctx)
+	e.conn = nil
+}
@@ -0,0 +1,44 @@
+package pgstorage
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"io/fs"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/dmitrymomot/foundation/integration/database/pg"
+)
+
+// DefaultMigrationsTable is the goose version table of the queue schema.
+const DefaultMigrationsTable = "queue_schema_migrations"
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// Migrations returns the embedded goose migrations for the tasks and tasks_dlq tables.
+// Use it to apply the schema with your own tooling instead of Migrate.
+func Migrations() fs.FS {
+	sub, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		// Unreachable: the directory is embedded at compile time
+		panic(err)
+	}
+	return sub
+}
+
+// Migrate applies the embedded queue migrations with pg.MigrateFS, tracking
+// versions in DefaultMigrationsTable.
+func Migrate(ctx context.Context, pool *pgxpool.Pool, log *slog.Logger) error {
+	if pool == nil {
+		return errors.Join(ErrFailedToApplyMigrations, ErrDBNil)
+	}
+
+	if err := pg.MigrateFS(ctx, pool, Migrations(), DefaultMigrationsTable, log); err != nil {
+		return errors.Join(ErrFailedToApplyMigrations, err)
+	}
+
+	return nil
+}
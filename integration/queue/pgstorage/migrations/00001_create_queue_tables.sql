@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tasks (
+    id UUID PRIMARY KEY,
+    queue VARCHAR(255) NOT NULL DEFAULT 'default',
+    task_type VARCHAR(32) NOT NULL,
+    task_name VARCHAR(255) NOT NULL,
+    payload JSONB,
+    status VARCHAR(32) NOT NULL DEFAULT 'pending',
+    priority SMALLINT NOT NULL DEFAULT 50 CHECK (priority BETWEEN 0 AND 100),
+    retry_count SMALLINT NOT NULL DEFAULT 0,
+    max_retries SMALLINT NOT NULL DEFAULT 3,
+    scheduled_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMPTZ,
+    locked_by UUID,
+    processed_at TIMESTAMPTZ,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- Claim path: pending tasks ordered by priority, then by schedule time
+CREATE INDEX IF NOT EXISTS idx_tasks_claim
+    ON tasks (queue, priority DESC, scheduled_at ASC)
+    WHERE status = 'pending';
+
+-- Lock expiration path: processing tasks with an expired lock
+CREATE INDEX IF NOT EXISTS idx_tasks_locked_until
+    ON tasks (locked_until)
+    WHERE status = 'processing';
+
+-- Scheduler idempotency path: pending periodic tasks by name
+CREATE INDEX IF NOT EXISTS idx_tasks_pending_name
+    ON tasks (task_name)
+    WHERE status = 'pending';
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS tasks_dlq (
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL,
+    queue VARCHAR(255) NOT NULL,
+    task_type VARCHAR(32) NOT NULL,
+    task_name VARCHAR(255) NOT NULL,
+    payload JSONB,
+    priority SMALLINT NOT NULL DEFAULT 50,
+    error TEXT NOT NULL DEFAULT '',
+    retry_count SMALLINT NOT NULL DEFAULT 0,
+    failed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+CREATE INDEX IF NOT EXISTS idx_tasks_dlq_queue_failed_at ON tasks_dlq (queue, failed_at DESC);
+CREATE INDEX IF NOT EXISTS idx_tasks_dlq_task_name ON tasks_dlq (task_name);
+
+-- +goose Down
+DROP TABLE IF EXISTS tasks_dlq;
+DROP TABLE IF EXISTS tasks;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS retry_policy JSONB;
+
+-- +goose Down
+ALTER TABLE tasks DROP COLUMN IF EXISTS retry_policy;
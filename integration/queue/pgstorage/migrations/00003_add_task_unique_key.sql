@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS unique_key TEXT;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS unique_until TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_unique_key ON tasks (unique_key) WHERE unique_key IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_tasks_unique_key;
+ALTER TABLE tasks DROP COLUMN IF EXISTS unique_until;
+ALTER TABLE tasks DROP COLUMN IF EXISTS unique_key;
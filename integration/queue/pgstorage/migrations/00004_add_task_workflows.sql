@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS workflow_id UUID;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id UUID;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS group_id UUID;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS result JSONB;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_result JSONB;
+
+-- Activation path: waiting tasks by the task, group or workflow they wait for
+CREATE INDEX IF NOT EXISTS idx_tasks_waiting_parent ON tasks (parent_id) WHERE status = 'waiting';
+CREATE INDEX IF NOT EXISTS idx_tasks_group ON tasks (group_id) WHERE group_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_workflow ON tasks (workflow_id) WHERE workflow_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_tasks_workflow;
+DROP INDEX IF EXISTS idx_tasks_group;
+DROP INDEX IF EXISTS idx_tasks_waiting_parent;
+ALTER TABLE tasks DROP COLUMN IF EXISTS parent_result;
+ALTER TABLE tasks DROP COLUMN IF EXISTS result;
+ALTER TABLE tasks DROP COLUMN IF EXISTS group_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS parent_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS workflow_id;
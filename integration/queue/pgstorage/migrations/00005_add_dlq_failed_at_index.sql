@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE INDEX IF NOT EXISTS idx_tasks_dlq_failed_at ON tasks_dlq (failed_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_tasks_dlq_failed_at;
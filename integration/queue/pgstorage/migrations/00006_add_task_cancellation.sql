@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS timeout_ms BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS cancel_requested BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE tasks DROP COLUMN IF EXISTS cancel_requested;
+ALTER TABLE tasks DROP COLUMN IF EXISTS timeout_ms;
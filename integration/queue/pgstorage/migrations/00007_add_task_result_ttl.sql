@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS result_ttl_ms BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_tasks_result_expiry ON tasks (processed_at) WHERE status = 'completed' AND result_ttl_ms > 0;
+CREATE INDEX IF NOT EXISTS idx_tasks_dlq_task_id ON tasks_dlq (task_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_tasks_dlq_task_id;
+DROP INDEX IF EXISTS idx_tasks_result_expiry;
+ALTER TABLE tasks DROP COLUMN IF EXISTS result_ttl_ms;
@@ -0,0 +1,38 @@
+package pgstorage
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Option configures a Storage.
+type Option func(*Storage)
+
+// WithLockCheckInterval sets the interval for releasing expired task locks.
+// Shorter intervals recover tasks from crashed workers faster at the cost of extra queries.
+func WithLockCheckInterval(interval time.Duration) Option {
+	return func(s *Storage) {
+		if interval > 0 {
+			s.lockCheckInterval = interval
+		}
+	}
+}
+
+// WithShutdownTimeout sets the graceful shutdown timeout for the lock expiration manager.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Storage) {
+		if timeout > 0 {
+			s.shutdownTimeout = timeout
+		}
+	}
+}
+
+// WithLogger sets the logger for internal operations.
+// Use slog.New(slog.NewTextHandler(io.Discard, nil)) to disable logging.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Storage) {
+		if logger != nil {
+			s.logger = logger
+		}
+	}
+}
@@ -0,0 +1,1117 @@
+package pgstorage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+	"github.com/dmitrymomot/foundation/integration/database/pg"
+)
+
+// Compile-time checks that Storage implements all queue repository interfaces
+var (
+	_ queue.EnqueuerRepository   = (*Storage)(nil)
+	_ queue.WorkerRepository     = (*Storage)(nil)
+	_ queue.SchedulerRepository  = (*Storage)(nil)
+	_ queue.UniqueTaskRepository = (*Storage)(nil)
+	_ queue.WorkflowRepository   = (*Storage)(nil)
+	_ queue.DLQRepository        = (*Storage)(nil)
+	_ queue.CancelRepository     = (*Storage)(nil)
+	_ queue.ResultRepository     = (*Storage)(nil)
+	_ queue.StatsRepository      = (*Storage)(nil)
+)
+
+// DB defines the subset of pgx operations used by Storage.
+// Satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx.
+type DB interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Stats provides observability metrics for monitoring and debugging
+type Stats struct {
+	ActiveTasks       int       // Pending and processing tasks, refreshed on each lock check
+	ExpiredLocksFreed int64     // Total number of expired locks freed
+	IsRunning         bool      // Whether the lock expiration manager is running
+	LastActivityAt    time.Time // Timestamp of last lock expiration (zero if never)
+}
+
+// Storage implements all queue repository interfaces on top of PostgreSQL.
+// Tasks are claimed with SELECT ... FOR UPDATE SKIP LOCKED, so any number of
+// worker processes can safely share the same tables.
+type Storage struct {
+	db DB
+
+	// Configuration
+	lockCheckInterval time.Duration
+	shutdownTimeout   time.Duration
+	logger            *slog.Logger
+
+	// State management
+	mu      sync.RWMutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running atomic.Bool
+	wg      sync.WaitGroup
+
+	// Observability metrics
+	activeTasks       atomic.Int64
+	expiredLocksFreed atomic.Int64
+	lastActivityAt    atomic.Int64 // Unix timestamp of last lock expiration
+}
+
+// New creates a PostgreSQL-backed queue storage.
+// Apply the schema with Migrate before use and call Start() to begin the lock expiration manager.
+func New(db DB, opts ...Option) (*Storage, error) {
+	if db == nil {
+		return nil, ErrDBNil
+	}
+
+	s := &Storage{
+		db:                db,
+		lockCheckInterval: time.Second,
+		shutdownTimeout:   30 * time.Second,
+		logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+// taskColumns lists task columns in the order expected by scanTask.
+const taskColumns = `id, queue, task_type, task_name, payload, status, priority,
+	retry_count, max_retries, scheduled_at, locked_until, locked_by, processed_at, error, created_at,
+	retry_policy, unique_key, unique_until, workflow_id, parent_id, group_id, result, parent_result,
+	timeout_ms, cancel_requested, result_ttl_ms`
+
+// CreateTask stores a new task.
+func (s *Storage) CreateTask(ctx context.Context, task *queue.Task) error {
+	if task == nil {
+		return ErrTaskNil
+	}
+	return insertTask(ctx, s.conn(ctx), task)
+}
+
+// CreateUniqueTask stores a new task unless its unique key is held by another task.
+// Enqueues of the same key are serialized with a transaction-scoped advisory lock,
+// so the holder lookup and the insert cannot interleave.
+func (s *Storage) CreateUniqueTask(ctx context.Context, task *queue.Task, mode queue.UniqueConflictMode) (uuid.UUID, error) {
+	if task == nil {
+		return uuid.Nil, ErrTaskNil
+	}
+	if task.UniqueKey == nil {
+		return task.ID, s.CreateTask(ctx, task)
+	}
+	key := *task.UniqueKey
+
+	holderID := task.ID
+	err := s.inTx(ctx, func(tx pgx.Tx) error {
+		if err := advisoryLock(ctx, tx, key); err != nil {
+			return fmt.Errorf("failed to lock unique key %q: %w", key, err)
+		}
+
+		const holderQuery = `SELECT id, status FROM tasks
+			WHERE unique_key = $1
+				AND (status IN ('pending', 'processing') OR unique_until > NOW())
+			ORDER BY created_at DESC
+			LIMIT 1`
+
+		var (
+			existingID uuid.UUID
+			status     string
+		)
+		err := tx.QueryRow(ctx, holderQuery, key).Scan(&existingID, &status)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			// Key is free
+		case err != nil:
+			return fmt.Errorf("failed to look up unique key %q: %w", key, err)
+		case mode == queue.UniqueConflictKeepExisting:
+			holderID = existingID
+			return nil
+		case mode != queue.UniqueConflictReplace || queue.TaskStatus(status) == queue.TaskStatusProcessing:
+			return fmt.Errorf("%w: %q", queue.ErrDuplicateTask, key)
+		case queue.TaskStatus(status) == queue.TaskStatusPending:
+			if _, err := tx.Exec(ctx, `DELETE FROM tasks WHERE id = $1`, existingID); err != nil {
+				return fmt.Errorf("failed to replace task %s: %w", existingID, err)
+			}
+		default:
+			// Finished holders are kept for inspection but give up the key
+			if _, err := tx.Exec(ctx, `UPDATE tasks SET unique_key = NULL WHERE id = $1`, existingID); err != nil {
+				return fmt.Errorf("failed to release unique key of task %s: %w", existingID, err)
+			}
+		}
+
+		return insertTask(ctx, tx, task)
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return holderID, nil
+}
+
+// CreateWorkflow stores all tasks of a workflow in one transaction.
+func (s *Storage) CreateWorkflow(ctx context.Context, tasks []*queue.Task) error {
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		for _, task := range tasks {
+			if task == nil {
+				return ErrTaskNil
+			}
+			if err := insertTask(ctx, tx, task); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// insertTask writes a task row using db, which may be the pool or an open transaction.
+func insertTask(ctx context.Context, db DB, task *queue.Task) error {
+	var retryPolicy []byte
+	if task.RetryPolicy != nil {
+		data, err := json.Marshal(task.RetryPolicy)
+		if err != nil {
+			return fmt.Errorf("failed to marshal retry policy of task %s: %w", task.ID, err)
+		}
+		retryPolicy = data
+	}
+
+	const q = `INSERT INTO tasks (` + taskColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18,
+			$19, $20, $21, $22, $23, $24, $25, $26)`
+
+	_, err := db.Exec(ctx, q,
+		task.ID, task.Queue, string(task.TaskType), task.TaskName, nullableJSON(task.Payload),
+		string(task.Status), int16(task.Priority), int16(task.RetryCount), int16(task.MaxRetries),
+		task.ScheduledAt, task.LockedUntil, task.LockedBy, task.ProcessedAt, task.Error, task.CreatedAt,
+		retryPolicy, task.UniqueKey, task.UniqueUntil,
+		task.WorkflowID, task.ParentID, task.GroupID, nullableJSON(task.Result), nullableJSON(task.ParentResult),
+		task.Timeout.Milliseconds(), task.CancelRequested, task.ResultTTL.Milliseconds(),
+	)
+	if err != nil {
+		if pg.IsDuplicateKeyError(err) {
+			return fmt.Errorf("%w: %s", ErrTaskAlreadyExists, task.ID)
+		}
+		return fmt.Errorf("failed to insert task %s: %w", task.ID, err)
+	}
+
+	return nil
+}
+
+// ClaimTask atomically claims the next highest-priority eligible task.
+func (s *Storage) ClaimTask(ctx context.Context, workerID uuid.UUID, queues []string, lockDuration time.Duration) (*queue.Task, error) {
+	if len(queues) == 0 {
+		return nil, queue.ErrNoTaskToClaim
+	}
+
+	// Task selection mirrors MemoryStorage: priority first, then earliest scheduled.
+	// SKIP LOCKED lets concurrent workers pass over rows another transaction is claiming
+	// instead of blocking on them, so each task is handed to exactly one worker.
+	const q = `UPDATE tasks
+		SET status = 'processing',
+			locked_until = NOW() + ($3 * INTERVAL '1 millisecond'),
+			locked_by = $2
+		WHERE id = (
+			SELECT id FROM tasks
+			WHERE status = 'pending'
+				AND queue = ANY($1)
+				AND scheduled_at <= NOW()
+				AND (locked_until IS NULL OR locked_until <= NOW())
+			ORDER BY priority DESC, scheduled_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + taskColumns
+
+	task, err := scanTask(s.conn(ctx).QueryRow(ctx, q, queues, workerID, lockDuration.Milliseconds()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, queue.ErrNoTaskToClaim
+		}
+		return nil, fmt.Errorf("failed to claim task: %w", err)
+	}
+
+	return task, nil
+}
+
+// CompleteTask marks a task as successfully completed and activates the
+// workflow tasks waiting for it.
+func (s *Storage) CompleteTask(ctx context.Context, taskID uuid.UUID, result []byte) error {
+	const q = `UPDATE tasks
+		SET status = 'completed', processed_at = NOW(), locked_until = NULL, locked_by = NULL, result = $2
+		WHERE id = $1 AND status = 'processing'
+		RETURNING group_id, workflow_id`
+
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		var groupID, workflowID *uuid.UUID
+		err := tx.QueryRow(ctx, q, taskID, nullableJSON(result)).Scan(&groupID, &workflowID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return s.processingStateError(ctx, taskID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to complete task %s: %w", taskID, err)
+		}
+
+		if err := activateChildren(ctx, tx, taskID, result); err != nil {
+			return err
+		}
+		if groupID != nil {
+			if err := completeGroup(ctx, tx, *groupID); err != nil {
+				return err
+			}
+		}
+		if workflowID != nil {
+			if err := finishWorkflow(ctx, tx, *workflowID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// FailTask records a task failure and reschedules it after retryDelay if retries remain.
+func (s *Storage) FailTask(ctx context.Context, taskID uuid.UUID, errorMsg string, retryDelay time.Duration) error {
+	// Column references on the right-hand side see the pre-update row,
+	// so retry_count + 1 is the new retry count.
+	const q = `UPDATE tasks
+		SET retry_count = retry_count + 1,
+			error = $2,
+			locked_until = NULL,
+			locked_by = NULL,
+			status = CASE WHEN retry_count + 1 >= max_retries THEN 'failed' ELSE 'pending' END,
+			scheduled_at = CASE WHEN retry_count + 1 >= max_retries THEN scheduled_at
+				ELSE NOW() + ($3 * INTERVAL '1 millisecond') END
+		WHERE id = $1 AND status = 'processing'`
+
+	tag, err := s.conn(ctx).Exec(ctx, q, taskID, errorMsg, retryDelay.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to fail task %s: %w", taskID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return s.processingStateError(ctx, taskID)
+	}
+
+	return nil
+}
+
+// MoveToDLQ moves a failed task to the dead letter queue for manual inspection.
+// The delete and insert run in a single statement, so a task is never in both tables.
+// If the task belongs to a workflow, the waiting rest of the workflow is dropped
+// and its error callbacks are activated in the same transaction.
+func (s *Storage) MoveToDLQ(ctx context.Context, taskID uuid.UUID) error {
+	const q = `WITH moved AS (
+			DELETE FROM tasks WHERE id = $1
+			RETURNING id, queue, task_type, task_name, payload, priority, error, retry_count, workflow_id
+		), dlq AS (
+			INSERT INTO tasks_dlq (id, task_id, queue, task_type, task_name, payload, priority, error, retry_count, failed_at, created_at)
+			SELECT $2, id, queue, task_type, task_name, payload, priority, COALESCE(error, ''), retry_count, NOW(), NOW()
+			FROM moved
+		)
+		SELECT task_name, COALESCE(error, ''), workflow_id FROM moved`
+
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		var (
+			taskName, errorMsg string
+			workflowID         *uuid.UUID
+		)
+		err := tx.QueryRow(ctx, q, taskID, uuid.New()).Scan(&taskName, &errorMsg, &workflowID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s", ErrTaskNotFound, taskID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to move task %s to DLQ: %w", taskID, err)
+		}
+
+		if workflowID == nil {
+			return nil
+		}
+		return failWorkflow(ctx, tx, queue.WorkflowFailure{
+			WorkflowID: *workflowID,
+			TaskID:     taskID,
+			TaskName:   taskName,
+			Error:      errorMsg,
+		})
+	})
+}
+
+const dlqColumns = `id, task_id, queue, task_type, task_name, payload, priority, error, retry_count, failed_at, created_at`
+
+// ListDLQ returns the dead letter queue entries matching filter, most recently failed first.
+func (s *Storage) ListDLQ(ctx context.Context, filter queue.DLQFilter) ([]*queue.TasksDlq, error) {
+	var (
+		where []string
+		args  []any
+	)
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.Queue != "" {
+		add("queue = $%d", filter.Queue)
+	}
+	if filter.TaskName != "" {
+		add("task_name = $%d", filter.TaskName)
+	}
+	if !filter.FailedAfter.IsZero() {
+		add("failed_at >= $%d", filter.FailedAfter)
+	}
+	if !filter.FailedBefore.IsZero() {
+		add("failed_at < $%d", filter.FailedBefore)
+	}
+
+	q := `SELECT ` + dlqColumns + ` FROM tasks_dlq`
+	if len(where) > 0 {
+		q += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	q += ` ORDER BY failed_at DESC, id`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		q += fmt.Sprintf(` LIMIT $%d`, len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		q += fmt.Sprintf(` OFFSET $%d`, len(args))
+	}
+
+	rows, err := s.conn(ctx).Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list DLQ: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*queue.TasksDlq
+	for rows.Next() {
+		entry, err := scanDLQ(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan DLQ entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list DLQ: %w", err)
+	}
+
+	return entries, nil
+}
+
+// GetDLQ returns a single dead letter queue entry.
+func (s *Storage) GetDLQ(ctx context.Context, id uuid.UUID) (*queue.TasksDlq, error) {
+	const q = `SELECT ` + dlqColumns + ` FROM tasks_dlq WHERE id = $1`
+
+	entry, err := scanDLQ(s.conn(ctx).QueryRow(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", queue.ErrDLQEntryNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to get DLQ entry %s: %w", id, err)
+	}
+
+	return entry, nil
+}
+
+// RequeueDLQ removes the dead letter queue entry and stores task in its place in one transaction.
+func (s *Storage) RequeueDLQ(ctx context.Context, id uuid.UUID, task *queue.Task) error {
+	if task == nil {
+		return ErrTaskNil
+	}
+
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `DELETE FROM tasks_dlq WHERE id = $1`, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete DLQ entry %s: %w", id, err)
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("%w: %s", queue.ErrDLQEntryNotFound, id)
+		}
+		return insertTask(ctx, tx, task)
+	})
+}
+
+// PurgeDLQ deletes dead letter queue entries that failed before the given time.
+func (s *Storage) PurgeDLQ(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := s.conn(ctx).Exec(ctx, `DELETE FROM tasks_dlq WHERE failed_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge DLQ: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// DeleteDLQ deletes a single dead letter queue entry.
+func (s *Storage) DeleteDLQ(ctx context.Context, id uuid.UUID) error {
+	tag, err := s.conn(ctx).Exec(ctx, `DELETE FROM tasks_dlq WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete DLQ entry %s: %w", id, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", queue.ErrDLQEntryNotFound, id)
+	}
+	return nil
+}
+
+// AddDLQ stores a dead letter queue entry for a failure outside the worker.
+func (s *Storage) AddDLQ(ctx context.Context, entry *queue.TasksDlq) error {
+	_, err := s.conn(ctx).Exec(ctx, `INSERT INTO tasks_dlq (`+dlqColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		entry.ID, entry.TaskID, entry.Queue, entry.TaskType, entry.TaskName, entry.Payload,
+		entry.Priority, entry.Error, entry.RetryCount, entry.FailedAt, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add DLQ entry %s: %w", entry.ID, err)
+	}
+	return nil
+}
+
+// CountTasks returns the number of stored tasks by queue and status.
+func (s *Storage) CountTasks(ctx context.Context) ([]queue.TaskCount, error) {
+	rows, err := s.conn(ctx).Query(ctx, `SELECT queue, status, count(*) FROM tasks
+		GROUP BY queue, status
+		ORDER BY queue, status`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []queue.TaskCount
+	for rows.Next() {
+		var (
+			c      queue.TaskCount
+			status string
+		)
+		if err := rows.Scan(&c.Queue, &status, &c.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan task count: %w", err)
+		}
+		c.Status = queue.TaskStatus(status)
+		counts = append(counts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	return counts, nil
+}
+
+// ListRetryingTasks returns pending tasks whose last attempt failed, soonest retry first.
+func (s *Storage) ListRetryingTasks(ctx context.Context, limit int) ([]*queue.Task, error) {
+	q := `SELECT ` + taskColumns + ` FROM tasks
+		WHERE status = 'pending' AND error IS NOT NULL
+		ORDER BY scheduled_at ASC`
+	var args []any
+	if limit > 0 {
+		q += ` LIMIT $1`
+		args = append(args, limit)
+	}
+
+	rows, err := s.conn(ctx).Query(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retrying tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []*queue.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list retrying tasks: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// CancelTask cancels a pending or waiting task, or flags a processing one for its worker.
+func (s *Storage) CancelTask(ctx context.Context, taskID uuid.UUID) error {
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		var (
+			status     string
+			workflowID *uuid.UUID
+		)
+		err := tx.QueryRow(ctx, `SELECT status, workflow_id FROM tasks WHERE id = $1 FOR UPDATE`, taskID).
+			Scan(&status, &workflowID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s", queue.ErrTaskNotFound, taskID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get task %s: %w", taskID, err)
+		}
+
+		switch queue.TaskStatus(status) {
+		case queue.TaskStatusPending, queue.TaskStatusWaiting:
+			return cancelTask(ctx, tx, taskID, workflowID)
+		case queue.TaskStatusProcessing:
+			if _, err := tx.Exec(ctx, `UPDATE tasks SET cancel_requested = TRUE WHERE id = $1`, taskID); err != nil {
+				return fmt.Errorf("failed to request cancellation of task %s: %w", taskID, err)
+			}
+			return nil
+		default:
+			return fmt.Errorf("%w: %s is %s", queue.ErrTaskNotCancellable, taskID, status)
+		}
+	})
+}
+
+// IsCancelRequested reports whether the task was cancelled or flagged for cancellation.
+func (s *Storage) IsCancelRequested(ctx context.Context, taskID uuid.UUID) (bool, error) {
+	const q = `SELECT cancel_requested OR status = 'cancelled' FROM tasks WHERE id = $1`
+
+	var requested bool
+	if err := s.conn(ctx).QueryRow(ctx, q, taskID).Scan(&requested); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, fmt.Errorf("%w: %s", queue.ErrTaskNotFound, taskID)
+		}
+		return false, fmt.Errorf("failed to check cancellation of task %s: %w", taskID, err)
+	}
+
+	return requested, nil
+}
+
+// MarkCancelled finishes a processing task as cancelled.
+func (s *Storage) MarkCancelled(ctx context.Context, taskID uuid.UUID) error {
+	return s.inTx(ctx, func(tx pgx.Tx) error {
+		var workflowID *uuid.UUID
+		err := tx.QueryRow(ctx, `SELECT workflow_id FROM tasks WHERE id = $1 AND status = 'processing' FOR UPDATE`, taskID).
+			Scan(&workflowID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return s.processingStateError(ctx, taskID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get task %s: %w", taskID, err)
+		}
+
+		return cancelTask(ctx, tx, taskID, workflowID)
+	})
+}
+
+// GetTaskResult returns the status and result of a task, falling back to the
+// dead letter queue for tasks that exhausted their retries.
+func (s *Storage) GetTaskResult(ctx context.Context, taskID uuid.UUID) (*queue.TaskResult, error) {
+	const q = `SELECT status, result, error, processed_at
+		FROM tasks
+		WHERE id = $1
+			AND NOT (status = 'completed' AND result_ttl_ms > 0
+				AND processed_at + (result_ttl_ms * INTERVAL '1 millisecond') < NOW())`
+
+	var (
+		status string
+		errMsg *string
+		result = &queue.TaskResult{TaskID: taskID}
+	)
+	err := s.conn(ctx).QueryRow(ctx, q, taskID).Scan(&status, &result.Result, &errMsg, &result.ProcessedAt)
+	if err == nil {
+		result.Status = queue.TaskStatus(status)
+		if errMsg != nil {
+			result.Error = *errMsg
+		}
+		return result, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get task %s: %w", taskID, err)
+	}
+
+	const dlqQ = `SELECT error, failed_at FROM tasks_dlq WHERE task_id = $1 ORDER BY failed_at DESC LIMIT 1`
+
+	var failedAt time.Time
+	err = s.conn(ctx).QueryRow(ctx, dlqQ, taskID).Scan(&result.Error, &failedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", queue.ErrTaskNotFound, taskID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dead letter queue entry of task %s: %w", taskID, err)
+	}
+
+	result.Status = queue.TaskStatusFailed
+	result.ProcessedAt = &failedAt
+	return result, nil
+}
+
+// ExtendLock extends the lock of a processing task held by workerID.
+func (s *Storage) ExtendLock(ctx context.Context, taskID, workerID uuid.UUID, duration time.Duration) error {
+	const q = `UPDATE tasks
+		SET locked_until = NOW() + ($3 * INTERVAL '1 millisecond')
+		WHERE id = $1 AND status = 'processing' AND locked_by = $2`
+
+	tag, err := s.conn(ctx).Exec(ctx, q, taskID, workerID, duration.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("failed to extend lock for task %s: %w", taskID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", queue.ErrLockLost, taskID)
+	}
+
+	return nil
+}
+
+// GetPendingTaskByName finds a pending task by name for scheduler idempotency checks.
+func (s *Storage) GetPendingTaskByName(ctx context.Context, taskName string) (*queue.Task, error) {
+	const q = `SELECT ` + taskColumns + ` FROM tasks
+		WHERE task_name = $1 AND status = 'pending'
+		ORDER BY scheduled_at ASC
+		LIMIT 1`
+
+	task, err := scanTask(s.conn(ctx).QueryRow(ctx, q, taskName))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pending task %q: %w", taskName, err)
+	}
+
+	return task, nil
+}
+
+// conn returns the transaction carried by ctx (see pg.WithTx), or the storage database.
+// Joining the caller's transaction makes an enqueue atomic with the caller's writes:
+// the task becomes visible to workers only once that transaction commits, and is
+// never created if it rolls back.
+func (s *Storage) conn(ctx context.Context) DB {
+	if tx, ok := pg.TxFromContext(ctx); ok {
+		return tx
+	}
+	return s.db
+}
+
+// inTx runs fn in a transaction, committing if it returns nil. Inside a caller's
+// transaction it runs in a savepoint, so a failed operation does not abort it.
+func (s *Storage) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := s.conn(ctx).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// advisoryLock takes a transaction-scoped advisory lock on key.
+func advisoryLock(ctx context.Context, tx pgx.Tx, key string) error {
+	_, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1, 0))`, key)
+	return err
+}
+
+// activateChildren makes the tasks waiting for parentID pending, handing over parentResult.
+// Step delays count from activation.
+func activateChildren(ctx context.Context, tx pgx.Tx, parentID uuid.UUID, parentResult []byte) error {
+	const q = `UPDATE tasks
+		SET status = 'pending', parent_result = $2, scheduled_at = NOW() + (scheduled_at - created_at)
+		WHERE parent_id = $1 AND status = 'waiting'`
+
+	if _, err := tx.Exec(ctx, q, parentID, nullableJSON(parentResult)); err != nil {
+		return fmt.Errorf("failed to activate children of %s: %w", parentID, err)
+	}
+	return nil
+}
+
+// completeGroup activates the tasks waiting for the group once every member has completed.
+// Members completing concurrently serialize on the group lock, and each one checks after
+// its own update, so exactly the last of them sees the whole group completed.
+func completeGroup(ctx context.Context, tx pgx.Tx, groupID uuid.UUID) error {
+	if err := advisoryLock(ctx, tx, groupID.String()); err != nil {
+		return fmt.Errorf("failed to lock group %s: %w", groupID, err)
+	}
+
+	const q = `SELECT bool_and(status = 'completed'), jsonb_agg(result ORDER BY processed_at)
+		FROM tasks WHERE group_id = $1`
+
+	var (
+		done    *bool
+		results []byte
+	)
+	if err := tx.QueryRow(ctx, q, groupID).Scan(&done, &results); err != nil {
+		return fmt.Errorf("failed to check group %s: %w", groupID, err)
+	}
+	if done == nil || !*done {
+		return nil
+	}
+
+	return activateChildren(ctx, tx, groupID, results)
+}
+
+// finishWorkflow drops the error callbacks of a workflow once none of its tasks is left to run.
+func finishWorkflow(ctx context.Context, tx pgx.Tx, workflowID uuid.UUID) error {
+	if err := advisoryLock(ctx, tx, workflowID.String()); err != nil {
+		return fmt.Errorf("failed to lock workflow %s: %w", workflowID, err)
+	}
+
+	const q = `DELETE FROM tasks
+		WHERE parent_id = $1 AND status = 'waiting'
+			AND NOT EXISTS (
+				SELECT 1 FROM tasks
+				WHERE workflow_id = $1 AND status IN ('waiting', 'pending', 'processing')
+			)`
+
+	if _, err := tx.Exec(ctx, q, workflowID); err != nil {
+		return fmt.Errorf("failed to finish workflow %s: %w", workflowID, err)
+	}
+	return nil
+}
+
+// failWorkflow drops the waiting tasks of a workflow and activates its error callbacks.
+func failWorkflow(ctx context.Context, tx pgx.Tx, failure queue.WorkflowFailure) error {
+	if err := advisoryLock(ctx, tx, failure.WorkflowID.String()); err != nil {
+		return fmt.Errorf("failed to lock workflow %s: %w", failure.WorkflowID, err)
+	}
+
+	const q = `DELETE FROM tasks WHERE workflow_id = $1 AND status = 'waiting'`
+	if _, err := tx.Exec(ctx, q, failure.WorkflowID); err != nil {
+		return fmt.Errorf("failed to drop workflow %s: %w", failure.WorkflowID, err)
+	}
+
+	data, err := json.Marshal(failure)
+	if err != nil {
+		return fmt.Errorf("failed to marshal workflow failure: %w", err)
+	}
+	return activateChildren(ctx, tx, failure.WorkflowID, data)
+}
+
+// cancelTask finishes a task as cancelled, releases its unique key and drops the
+// waiting rest of its workflow.
+func cancelTask(ctx context.Context, tx pgx.Tx, taskID uuid.UUID, workflowID *uuid.UUID) error {
+	const q = `UPDATE tasks
+		SET status = 'cancelled', processed_at = NOW(), locked_until = NULL, locked_by = NULL,
+			unique_until = NULL, cancel_requested = FALSE
+		WHERE id = $1`
+
+	if _, err := tx.Exec(ctx, q, taskID); err != nil {
+		return fmt.Errorf("failed to cancel task %s: %w", taskID, err)
+	}
+	if workflowID == nil {
+		return nil
+	}
+
+	if err := advisoryLock(ctx, tx, workflowID.String()); err != nil {
+		return fmt.Errorf("failed to lock workflow %s: %w", workflowID, err)
+	}
+	const dropQ = `DELETE FROM tasks WHERE (workflow_id = $1 OR parent_id = $1) AND status = 'waiting'`
+	if _, err := tx.Exec(ctx, dropQ, workflowID); err != nil {
+		return fmt.Errorf("failed to drop workflow %s: %w", workflowID, err)
+	}
+	return nil
+}
+
+// processingStateError explains why an update guarded by status = 'processing' matched no rows.
+func (s *Storage) processingStateError(ctx context.Context, taskID uuid.UUID) error {
+	var exists bool
+	if err := s.conn(ctx).QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1)`, taskID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check task %s: %w", taskID, err)
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, taskID)
+	}
+	return fmt.Errorf("%w: %s", ErrTaskNotProcessing, taskID)
+}
+
+// Start begins the lock expiration manager. This is a blocking operation
+// that runs until the context is cancelled. Use Run() for errgroup pattern or call this in a goroutine.
+func (s *Storage) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.mu.Unlock()
+		return ErrStorageAlreadyStarted
+	}
+
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.mu.Unlock()
+
+	s.running.Store(true)
+	defer s.running.Store(false)
+
+	s.logger.InfoContext(s.ctx, "postgres storage lock expiration manager started",
+		slog.Duration("check_interval", s.lockCheckInterval))
+
+	ticker := time.NewTicker(s.lockCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			s.logger.Info("postgres storage stopping")
+			return s.ctx.Err()
+		case <-ticker.C:
+			select {
+			case <-s.ctx.Done():
+				return s.ctx.Err()
+			default:
+				s.expireLocksWithWait()
+			}
+		}
+	}
+}
+
+// Stop gracefully shuts down the lock expiration manager with a timeout.
+// Returns an error if the shutdown timeout is exceeded.
+func (s *Storage) Stop() error {
+	s.mu.Lock()
+	if s.cancel == nil {
+		s.mu.Unlock()
+		return ErrStorageNotStarted
+	}
+
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+
+	cancel()
+
+	s.logger.Info("postgres storage stopping, waiting for lock expiration to complete",
+		slog.Duration("timeout", s.shutdownTimeout))
+
+	ctx, ctxCancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer ctxCancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.logger.Info("postgres storage stopped cleanly")
+		return nil
+	case <-ctx.Done():
+		s.logger.Warn("postgres storage shutdown timeout exceeded",
+			slog.Duration("timeout", s.shutdownTimeout))
+		return fmt.Errorf("shutdown timeout exceeded after %s", s.shutdownTimeout)
+	}
+}
+
+// Run provides errgroup compatibility for coordinated lifecycle management.
+// Returns a function that starts the lock expiration manager, monitors context cancellation,
+// and performs graceful shutdown when the context is cancelled.
+func (s *Storage) Run(ctx context.Context) func() error {
+	return func() error {
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- s.Start(ctx)
+		}()
+
+		select {
+		case <-ctx.Done():
+			// Context cancelled - perform graceful shutdown
+			_ = s.Stop() // Ignore stop error in normal shutdown
+			<-errCh      // Wait for Start() to exit
+			return nil
+		case err := <-errCh:
+			// Start() returned - check if it's a normal shutdown
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// expireLocksWithWait wraps expireLocks with WaitGroup tracking for graceful shutdown.
+func (s *Storage) expireLocksWithWait() {
+	s.mu.RLock()
+	if s.cancel == nil {
+		s.mu.RUnlock()
+		return
+	}
+	ctx := s.ctx
+	s.wg.Add(1)
+	s.mu.RUnlock()
+
+	defer s.wg.Done()
+	s.expireLocks(ctx)
+}
+
+// expireLocks releases locks of processing tasks whose lock has expired.
+// This allows tasks to be retried if a worker crashes or becomes unresponsive.
+// Retry count is left untouched, matching MemoryStorage semantics.
+func (s *Storage) expireLocks(ctx context.Context) {
+	// The worker is gone, so nobody else will finish a cancellation request
+	const cancelQ = `WITH cancelled AS (
+			UPDATE tasks
+			SET status = 'cancelled', processed_at = NOW(), locked_until = NULL, locked_by = NULL,
+				unique_until = NULL, cancel_requested = FALSE
+			WHERE status = 'processing' AND locked_until < NOW() AND cancel_requested
+			RETURNING workflow_id
+		)
+		DELETE FROM tasks
+		WHERE status = 'waiting'
+			AND (workflow_id IN (SELECT workflow_id FROM cancelled) OR parent_id IN (SELECT workflow_id FROM cancelled))`
+
+	if _, err := s.db.Exec(ctx, cancelQ); err != nil {
+		if ctx.Err() == nil {
+			s.logger.ErrorContext(ctx, "failed to cancel tasks with expired locks",
+				slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	const q = `UPDATE tasks
+		SET status = 'pending', locked_until = NULL, locked_by = NULL
+		WHERE status = 'processing' AND locked_until < NOW()`
+
+	tag, err := s.db.Exec(ctx, q)
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.ErrorContext(ctx, "failed to release expired task locks",
+				slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	if freed := tag.RowsAffected(); freed > 0 {
+		s.expiredLocksFreed.Add(freed)
+		s.lastActivityAt.Store(time.Now().Unix())
+	}
+
+	// Completed tasks outlive their result TTL only while they hold a unique key
+	// or their workflow may still need them
+	const purgeQ = `DELETE FROM tasks t
+		WHERE t.status = 'completed' AND t.result_ttl_ms > 0
+			AND t.processed_at + (t.result_ttl_ms * INTERVAL '1 millisecond') < NOW()
+			AND (t.unique_until IS NULL OR t.unique_until < NOW())
+			AND (t.workflow_id IS NULL OR NOT EXISTS (
+				SELECT 1 FROM tasks w
+				WHERE w.workflow_id = t.workflow_id AND w.status IN ('waiting', 'pending', 'processing')
+			))`
+
+	if _, err := s.db.Exec(ctx, purgeQ); err != nil && ctx.Err() == nil {
+		s.logger.ErrorContext(ctx, "failed to purge expired task results",
+			slog.String("error", err.Error()))
+	}
+
+	var active int64
+	const countQ = `SELECT COUNT(*) FROM tasks WHERE status IN ('pending', 'processing')`
+	if err := s.db.QueryRow(ctx, countQ).Scan(&active); err == nil {
+		s.activeTasks.Store(active)
+	}
+}
+
+// Stats returns current storage statistics for observability and monitoring.
+// This method is thread-safe and can be called at any time.
+func (s *Storage) Stats() Stats {
+	s.mu.RLock()
+	isRunning := s.cancel != nil
+	s.mu.RUnlock()
+
+	lastActivity := s.lastActivityAt.Load()
+	var lastActivityTime time.Time
+	if lastActivity > 0 {
+		lastActivityTime = time.Unix(lastActivity, 0)
+	}
+
+	return Stats{
+		ActiveTasks:       int(s.activeTasks.Load()),
+		ExpiredLocksFreed: s.expiredLocksFreed.Load(),
+		IsRunning:         isRunning,
+		LastActivityAt:    lastActivityTime,
+	}
+}
+
+// Healthcheck validates that the lock expiration manager is running and the database is reachable.
+// Returns nil if healthy, or an error describing the health issue.
+// This method is thread-safe and suitable for use in health check endpoints.
+func (s *Storage) Healthcheck(ctx context.Context) error {
+	if !s.Stats().IsRunning {
+		return fmt.Errorf("lock expiration manager is not running")
+	}
+
+	if _, err := s.db.Exec(ctx, "SELECT 1"); err != nil {
+		return errors.Join(pg.ErrHealthcheckFailed, err)
+	}
+
+	return nil
+}
+
+// scanTask reads a task row selected with taskColumns.
+// Enum-like columns are scanned into primitives to keep conversions explicit.
+func scanTask(row pgx.Row) (*queue.Task, error) {
+	var (
+		task                             queue.Task
+		taskType, status                 string
+		priority, retryCount, maxRetries int16
+		retryPolicy                      []byte
+		timeoutMs, resultTTLMs           int64
+	)
+
+	err := row.Scan(
+		&task.ID, &task.Queue, &taskType, &task.TaskName, &task.Payload, &status, &priority,
+		&retryCount, &maxRetries, &task.ScheduledAt, &task.LockedUntil, &task.LockedBy,
+		&task.ProcessedAt, &task.Error, &task.CreatedAt, &retryPolicy,
+		&task.UniqueKey, &task.UniqueUntil,
+		&task.WorkflowID, &task.ParentID, &task.GroupID, &task.Result, &task.ParentResult,
+		&timeoutMs, &task.CancelRequested, &resultTTLMs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if retryPolicy != nil {
+		task.RetryPolicy = &queue.RetryPolicy{}
+		if err := json.Unmarshal(retryPolicy, task.RetryPolicy); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal retry policy of task %s: %w", task.ID, err)
+		}
+	}
+
+	task.TaskType = queue.TaskType(taskType)
+	task.Status = queue.TaskStatus(status)
+	task.Priority = queue.Priority(priority)
+	task.RetryCount = int8(retryCount)
+	task.MaxRetries = int8(maxRetries)
+	task.Timeout = time.Duration(timeoutMs) * time.Millisecond
+	task.ResultTTL = time.Duration(resultTTLMs) * time.Millisecond
+
+	return &task, nil
+}
+
+// scanDLQ reads a dead letter queue row selected with dlqColumns.
+func scanDLQ(row pgx.Row) (*queue.TasksDlq, error) {
+	var (
+		entry                queue.TasksDlq
+		taskType             string
+		priority, retryCount int16
+	)
+
+	err := row.Scan(
+		&entry.ID, &entry.TaskID, &entry.Queue, &taskType, &entry.TaskName, &entry.Payload,
+		&priority, &entry.Error, &retryCount, &entry.FailedAt, &entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.TaskType = queue.TaskType(taskType)
+	entry.Priority = queue.Priority(priority)
+	entry.RetryCount = int8(retryCount)
+
+	return &entry, nil
+}
+
+// nullableJSON maps empty payloads to NULL, since an empty byte slice is not valid JSONB.
+func nullableJSON(data []byte) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,103 @@
+package pgstorage_test
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+	"github.com/dmitrymomot/foundation/integration/database/pg"
+	"github.com/dmitrymomot/foundation/integration/queue/pgstorage"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejects nil database", func(t *testing.T) {
+		t.Parallel()
+
+		storage, err := pgstorage.New(nil)
+		require.ErrorIs(t, err, pgstorage.ErrDBNil)
+		assert.Nil(t, storage)
+	})
+}
+
+func TestNewLeaderElector(t *testing.T) {
+	t.Parallel()
+
+	elector, err := pgstorage.NewLeaderElector(nil, "")
+	require.ErrorIs(t, err, pgstorage.ErrDBNil)
+	assert.Nil(t, elector)
+}
+
+func TestMigrations(t *testing.T) {
+	t.Parallel()
+
+	files, err := fs.Glob(pgstorage.Migrations(), "*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	data, err := fs.ReadFile(pgstorage.Migrations(), files[0])
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), "-- +goose Up")
+	assert.Contains(t, string(data), "CREATE TABLE IF NOT EXISTS tasks (")
+	assert.Contains(t, string(data), "CREATE TABLE IF NOT EXISTS tasks_dlq (")
+}
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejects nil pool", func(t *testing.T) {
+		t.Parallel()
+
+		err := pgstorage.Migrate(context.Background(), nil, nil)
+		require.ErrorIs(t, err, pgstorage.ErrFailedToApplyMigrations)
+		assert.ErrorIs(t, err, pgstorage.ErrDBNil)
+	})
+}
+
+// recordingConn records which connection executed a statement.
+// Only Exec is implemented; the embedded nil pgx.Tx panics on anything else.
+type recordingConn struct {
+	pgx.Tx
+	name string
+	log  *[]string
+}
+
+func (c recordingConn) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	*c.log = append(*c.log, c.name)
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func TestStorage_CreateTaskInContextTx(t *testing.T) {
+	t.Parallel()
+
+	var log []string
+	storage, err := pgstorage.New(recordingConn{name: "pool", log: &log})
+	require.NoError(t, err)
+
+	enqueuer, err := queue.NewEnqueuer(storage)
+	require.NoError(t, err)
+
+	type welcomeEmail struct {
+		UserID uuid.UUID `json:"user_id"`
+	}
+
+	ctx := context.Background()
+	_, err = enqueuer.Enqueue(ctx, welcomeEmail{UserID: uuid.New()})
+	require.NoError(t, err)
+
+	txCtx := pg.WithTx(ctx, recordingConn{name: "tx", log: &log})
+	_, err = enqueuer.Enqueue(txCtx, welcomeEmail{UserID: uuid.New()}, queue.WithDelay(time.Minute))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"pool", "tx"}, log)
+}
@@ -0,0 +1,84 @@
+// Package redisstorage provides a Redis-backed storage for the core/queue job system.
+//
+// Storage implements queue.EnqueuerRepository, queue.WorkerRepository and
+// queue.SchedulerRepository on top of a redis.UniversalClient, so it works with
+// standalone, sentinel and cluster deployments created by integration/database/redis.
+//
+// # Key Features
+//
+//   - Atomic task claiming, lock extension and failure handling via Lua scripts
+//   - Sorted sets for scheduled tasks and priority-ordered ready tasks
+//   - Priority-first, FIFO-within-priority task selection identical to queue.MemoryStorage
+//   - Lock expiration manager that returns tasks of crashed workers to the queue
+//   - Per-task retry policies and dead letter queue semantics matching queue.MemoryStorage
+//   - Atomic unique key enforcement (queue.UniqueTaskRepository)
+//   - Workflow chains, groups and error callbacks (queue.WorkflowRepository)
+//   - Dead letter queue listing, requeueing, deleting and purging (queue.DLQRepository)
+//   - Task cancellation and per-task timeouts (queue.CancelRepository)
+//   - Task results with deletion of completed tasks past their result TTL (queue.ResultRepository)
+//   - Task counts by queue and status and retrying tasks for dashboards (queue.StatsRepository)
+//   - Scheduler leader election with SET NX PX leases (LeaderElector)
+//   - Server-side clock for all due times and locks, so worker clock skew does not matter
+//
+// # Usage
+//
+//	client, err := redis.Connect(ctx, redisCfg)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	storage, err := redisstorage.New(client,
+//		redisstorage.WithKeyPrefix("{myapp-queue}"),
+//		redisstorage.WithLogger(logger),
+//	)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	enqueuer, _ := queue.NewEnqueuer(storage)
+//	worker, _ := queue.NewWorker(storage, queue.WithQueues("default", "email"))
+//	scheduler, _ := queue.NewScheduler(storage)
+//
+//	g, ctx := errgroup.WithContext(ctx)
+//	g.Go(storage.Run(ctx))
+//	g.Go(worker.Run(ctx))
+//	g.Go(scheduler.Run(ctx))
+//
+// # Key Layout
+//
+// All keys share a prefix (DefaultKeyPrefix unless WithKeyPrefix is used).
+// Each task is a hash; pending tasks are indexed in a per-queue sorted set
+// scored by scheduled time and promoted into a per-queue ready set scored by
+// priority once due. Processing tasks are indexed by lock deadline. On Redis
+// Cluster the prefix must be a hash tag so every key lives in the same slot.
+//
+// # Leader Election
+//
+// LeaderElector lets one queue.Scheduler per cluster create periodic tasks. The
+// lease is a key taken with SET NX PX and renewed or deleted only by its holder,
+// so a standby takes over once a crashed leader's key expires:
+//
+//	elector, err := redisstorage.NewLeaderElector(client, redisstorage.DefaultLeaderName)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	scheduler, _ := queue.NewScheduler(storage,
+//		queue.WithLeaderElector(elector),
+//		queue.WithLeaseTTL(15*time.Second),
+//	)
+//
+// # Lock Expiration
+//
+// Workers lock claimed tasks for the worker's lock timeout. If a worker crashes,
+// the lock expiration manager started by Start/Run moves its processing tasks
+// back to pending once the lock has expired, without touching the retry count.
+// The same pass deletes completed tasks whose result TTL has expired.
+// Running the manager in every process is safe because each release is atomic.
+//
+// # Health Checking
+//
+//	healthSrv.AddCheck("queue-storage", storage.Healthcheck)
+//
+// Healthcheck reports an error when the lock expiration manager is not running
+// or Redis does not respond.
+package redisstorage
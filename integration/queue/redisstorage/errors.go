@@ -0,0 +1,14 @@
+package redisstorage
+
+import "errors"
+
+var (
+	ErrClientNil             = errors.New("redis client cannot be nil")
+	ErrTaskNil               = errors.New("task cannot be nil")
+	ErrTaskNotFound          = errors.New("task not found")
+	ErrTaskNotProcessing     = errors.New("task is not in processing state")
+	ErrTaskAlreadyExists     = errors.New("task already exists")
+	ErrStorageAlreadyStarted = errors.New("redis storage already started")
+	ErrStorageNotStarted     = errors.New("redis storage not started")
+	ErrInvalidTaskData       = errors.New("invalid task data in redis")
+)
@@ -0,0 +1,483 @@
+package redisstorage_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/redis/go-redis/v9/maintnotifications"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+	"github.com/dmitrymomot/foundation/integration/queue/redisstorage"
+)
+
+// The tests below run the storage scripts on miniredis. Each test gets its own
+// server, so they run in parallel without seeing each other's tasks.
+
+type (
+	reportPayload struct {
+		N int `json:"n"`
+	}
+	exportStep struct{}
+	shardStep  struct {
+		N int `json:"n"`
+	}
+	notifyStep  struct{}
+	failureStep struct{}
+)
+
+// newTestServer starts a Redis server that is shut down after the test.
+func newTestServer(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+	return miniredis.RunT(t)
+}
+
+// newTestStorage opens a storage on its own client, as a separate process would.
+func newTestStorage(t *testing.T, server *miniredis.Miniredis) *redisstorage.Storage {
+	t.Helper()
+
+	client := redis.NewClient(&redis.Options{
+		Addr: server.Addr(),
+		// miniredis does not know the handshake command and go-redis would log every fallback
+		MaintNotificationsConfig: &maintnotifications.Config{Mode: maintnotifications.ModeDisabled},
+	})
+	t.Cleanup(func() { _ = client.Close() })
+
+	storage, err := redisstorage.New(client)
+	require.NoError(t, err)
+	return storage
+}
+
+// runTestWorker runs a worker with the handlers until the test ends.
+func runTestWorker(t *testing.T, storage *redisstorage.Storage, handlers ...queue.Handler) {
+	t.Helper()
+
+	worker, err := queue.NewWorker(storage,
+		queue.WithPullInterval(5*time.Millisecond),
+		queue.WithMaxConcurrentTasks(2),
+		queue.WithWorkerLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	require.NoError(t, err)
+	require.NoError(t, worker.RegisterHandlers(handlers...))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- worker.Run(ctx)() }()
+
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-errCh)
+	})
+}
+
+// countTasks returns the number of stored tasks by status.
+func countTasks(t *testing.T, storage *redisstorage.Storage) map[queue.TaskStatus]int64 {
+	t.Helper()
+
+	counts, err := storage.CountTasks(context.Background())
+	require.NoError(t, err)
+
+	byStatus := make(map[queue.TaskStatus]int64)
+	for _, c := range counts {
+		byStatus[c.Status] += c.Count
+	}
+	return byStatus
+}
+
+func TestStorage_ConcurrentClaims(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	queues := []string{queue.DefaultQueueName}
+	server := newTestServer(t)
+	storages := []*redisstorage.Storage{newTestStorage(t, server), newTestStorage(t, server)}
+
+	enqueuer, err := queue.NewEnqueuer(storages[0])
+	require.NoError(t, err)
+	const tasks = 50
+	for i := range tasks {
+		_, err := enqueuer.Enqueue(ctx, reportPayload{N: i})
+		require.NoError(t, err)
+	}
+
+	var (
+		mu      sync.Mutex
+		claimed = make(map[uuid.UUID]int)
+		wg      sync.WaitGroup
+	)
+	for i := range 8 {
+		storage := storages[i%len(storages)]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workerID := uuid.New()
+			for {
+				task, err := storage.ClaimTask(ctx, workerID, queues, time.Minute)
+				if errors.Is(err, queue.ErrNoTaskToClaim) {
+					return
+				}
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Equal(t, workerID, *task.LockedBy)
+
+				mu.Lock()
+				claimed[task.ID]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, claimed, tasks, "every task is claimed")
+	for id, n := range claimed {
+		assert.Equal(t, 1, n, "task %s is claimed once", id)
+	}
+}
+
+func TestStorage_RetryThenDLQ(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage := newTestStorage(t, newTestServer(t))
+
+	enqueuer, err := queue.NewEnqueuer(storage)
+	require.NoError(t, err)
+	dlq, err := queue.NewDeadLetterQueue(storage)
+	require.NoError(t, err)
+
+	var attempts atomic.Int32
+	runTestWorker(t, storage, queue.NewTaskHandler(func(ctx context.Context, p reportPayload) error {
+		attempts.Add(1)
+		return errors.New("smtp down")
+	}, queue.WithHandlerRetryPolicy(queue.FixedBackoff(time.Millisecond))))
+
+	// Default MaxRetries: the task is retried, then moved to the DLQ by the worker
+	taskID, err := enqueuer.Enqueue(ctx, reportPayload{N: 1})
+	require.NoError(t, err)
+
+	var entries []*queue.TasksDlq
+	require.Eventually(t, func() bool {
+		entries, err = dlq.List(ctx, queue.DLQFilter{})
+		return err == nil && len(entries) == 1
+	}, 5*time.Second, 10*time.Millisecond, "exhausted task reaches the DLQ")
+
+	assert.Equal(t, taskID, entries[0].TaskID)
+	assert.Equal(t, "smtp down", entries[0].Error)
+	assert.Equal(t, int8(3), entries[0].RetryCount)
+	assert.JSONEq(t, `{"n":1}`, string(entries[0].Payload))
+	assert.Equal(t, int32(3), attempts.Load())
+
+	result, err := storage.GetTaskResult(ctx, taskID)
+	require.NoError(t, err)
+	assert.Equal(t, queue.TaskStatusFailed, result.Status)
+	assert.Equal(t, "smtp down", result.Error)
+
+	// Requeue into a queue the worker does not pull, so the task stays put
+	requeuedID, err := dlq.Requeue(ctx, entries[0].ID, queue.WithRequeueQueue("manual"))
+	require.NoError(t, err)
+	assert.Equal(t, taskID, requeuedID)
+
+	task, err := storage.ClaimTask(ctx, uuid.New(), []string{"manual"}, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, taskID, task.ID)
+	assert.Equal(t, int8(0), task.RetryCount)
+
+	entries, err = dlq.List(ctx, queue.DLQFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestStorage_UniqueKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server := newTestServer(t)
+	storage := newTestStorage(t, server)
+
+	enqueuer, err := queue.NewEnqueuer(storage)
+	require.NoError(t, err)
+
+	// Subtests use their own queue and key, so their tasks never mix
+	enqueue := func(name string, n int, opts ...queue.EnqueueOption) (uuid.UUID, error) {
+		opts = append([]queue.EnqueueOption{queue.WithQueue(name), queue.WithUniqueKey(name, time.Minute)}, opts...)
+		return enqueuer.Enqueue(ctx, reportPayload{N: n}, opts...)
+	}
+	claim := func(t *testing.T, name string) *queue.Task {
+		t.Helper()
+		task, err := storage.ClaimTask(ctx, uuid.New(), []string{name}, time.Minute)
+		require.NoError(t, err)
+		return task
+	}
+	assertNothingToClaim := func(t *testing.T, name string) {
+		t.Helper()
+		_, err := storage.ClaimTask(ctx, uuid.New(), []string{name}, time.Minute)
+		assert.ErrorIs(t, err, queue.ErrNoTaskToClaim)
+	}
+
+	t.Run("reject", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := enqueue("reject", 1)
+		require.NoError(t, err)
+		_, err = enqueue("reject", 2)
+		assert.ErrorIs(t, err, queue.ErrDuplicateTask)
+	})
+
+	t.Run("keep existing", func(t *testing.T) {
+		t.Parallel()
+
+		firstID, err := enqueue("keep", 1)
+		require.NoError(t, err)
+		keptID, err := enqueue("keep", 2, queue.WithUniqueConflictMode(queue.UniqueConflictKeepExisting))
+		require.NoError(t, err)
+		assert.Equal(t, firstID, keptID)
+
+		assert.JSONEq(t, `{"n":1}`, string(claim(t, "keep").Payload))
+		assertNothingToClaim(t, "keep")
+	})
+
+	t.Run("replace pending", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := enqueue("replace", 1)
+		require.NoError(t, err)
+		_, err = enqueue("replace", 2, queue.WithUniqueConflictMode(queue.UniqueConflictReplace))
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{"n":2}`, string(claim(t, "replace").Payload))
+		assertNothingToClaim(t, "replace")
+	})
+
+	t.Run("replace cannot swap a processing task", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := enqueue("processing", 1)
+		require.NoError(t, err)
+		claim(t, "processing")
+
+		_, err = enqueue("processing", 2, queue.WithUniqueConflictMode(queue.UniqueConflictReplace))
+		assert.ErrorIs(t, err, queue.ErrDuplicateTask)
+	})
+
+	t.Run("concurrent enqueues across storages", func(t *testing.T) {
+		t.Parallel()
+
+		other, err := queue.NewEnqueuer(newTestStorage(t, server))
+		require.NoError(t, err)
+		enqueuers := []*queue.Enqueuer{enqueuer, other}
+
+		var (
+			created atomic.Int32
+			wg      sync.WaitGroup
+		)
+		for i := range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := enqueuers[i%len(enqueuers)].Enqueue(ctx, reportPayload{N: i},
+					queue.WithQueue("concurrent"), queue.WithUniqueKey("concurrent", time.Minute))
+				if err == nil {
+					created.Add(1)
+					return
+				}
+				assert.ErrorIs(t, err, queue.ErrDuplicateTask)
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), created.Load(), "one enqueue holds the key")
+		claim(t, "concurrent")
+		assertNothingToClaim(t, "concurrent")
+	})
+}
+
+func TestStorage_Workflows(t *testing.T) {
+	t.Parallel()
+
+	t.Run("completion", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		storage := newTestStorage(t, newTestServer(t))
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+
+		done := make(chan []int, 1)
+		runTestWorker(t, storage,
+			queue.NewTaskHandler(func(ctx context.Context, _ exportStep) error {
+				return queue.SetResult(ctx, 10)
+			}),
+			queue.NewTaskHandler(func(ctx context.Context, p shardStep) error {
+				var base int
+				if err := queue.ParentResult(ctx, &base); err != nil {
+					return err
+				}
+				return queue.SetResult(ctx, base+p.N)
+			}),
+			queue.NewTaskHandler(func(ctx context.Context, _ notifyStep) error {
+				var results []int
+				if err := queue.ParentResult(ctx, &results); err != nil {
+					return err
+				}
+				done <- results
+				return nil
+			}),
+			queue.NewTaskHandler(func(ctx context.Context, _ failureStep) error {
+				t.Error("error callback ran for a successful workflow")
+				return nil
+			}),
+		)
+
+		_, err = enqueuer.EnqueueWorkflow(ctx, queue.Chain(
+			queue.Step(exportStep{}),
+			queue.Group(queue.Step(shardStep{N: 1}), queue.Step(shardStep{N: 2})),
+			queue.Step(notifyStep{}),
+		).OnError(queue.Step(failureStep{})))
+		require.NoError(t, err)
+
+		select {
+		case results := <-done:
+			assert.ElementsMatch(t, []int{11, 12}, results)
+		case <-time.After(5 * time.Second):
+			t.Fatal("workflow did not finish")
+		}
+
+		// The error callback is dropped once the last step completes
+		require.Eventually(t, func() bool {
+			counts := countTasks(t, storage)
+			return len(counts) == 1 && counts[queue.TaskStatusCompleted] == 4
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		storage := newTestStorage(t, newTestServer(t))
+		enqueuer, err := queue.NewEnqueuer(storage)
+		require.NoError(t, err)
+
+		failures := make(chan queue.WorkflowFailure, 1)
+		runTestWorker(t, storage,
+			queue.NewTaskHandler(func(ctx context.Context, _ exportStep) error {
+				return errors.New("bucket not found")
+			}, queue.WithHandlerRetryPolicy(queue.FixedBackoff(time.Millisecond))),
+			queue.NewTaskHandler(func(ctx context.Context, _ notifyStep) error {
+				t.Error("step ran after the workflow failed")
+				return nil
+			}),
+			queue.NewTaskHandler(func(ctx context.Context, _ failureStep) error {
+				var failure queue.WorkflowFailure
+				if err := queue.ParentResult(ctx, &failure); err != nil {
+					return err
+				}
+				failures <- failure
+				return nil
+			}),
+		)
+
+		workflowID, err := enqueuer.EnqueueWorkflow(ctx, queue.Chain(
+			queue.Step(exportStep{}),
+			queue.Step(notifyStep{}),
+		).OnError(queue.Step(failureStep{})))
+		require.NoError(t, err)
+
+		select {
+		case failure := <-failures:
+			assert.Equal(t, workflowID, failure.WorkflowID)
+			assert.Equal(t, "redisstorage_test.exportStep", failure.TaskName)
+			assert.Equal(t, "bucket not found", failure.Error)
+		case <-time.After(5 * time.Second):
+			t.Fatal("error callback did not run")
+		}
+
+		entries, err := storage.ListDLQ(ctx, queue.DLQFilter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "redisstorage_test.exportStep", entries[0].TaskName)
+
+		// The notify step is dropped, leaving only the error callback
+		require.Eventually(t, func() bool {
+			counts := countTasks(t, storage)
+			return len(counts) == 1 && counts[queue.TaskStatusCompleted] == 1
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("taken task id stores nothing", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		storage := newTestStorage(t, newTestServer(t))
+
+		newTask := func() *queue.Task {
+			return &queue.Task{
+				ID: uuid.New(), Queue: queue.DefaultQueueName, TaskType: queue.TaskTypeOneTime,
+				TaskName: "redisstorage_test.exportStep", Payload: []byte(`{}`), Status: queue.TaskStatusPending,
+				Priority: queue.PriorityMedium, MaxRetries: 3, ScheduledAt: time.Now(), CreatedAt: time.Now(),
+			}
+		}
+		existing := newTask()
+		require.NoError(t, storage.CreateTask(ctx, existing))
+
+		err := storage.CreateWorkflow(ctx, []*queue.Task{newTask(), existing})
+		require.ErrorIs(t, err, redisstorage.ErrTaskAlreadyExists)
+		assert.Equal(t, map[queue.TaskStatus]int64{queue.TaskStatusPending: 1}, countTasks(t, storage))
+	})
+}
+
+func TestStorage_Cancel(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	queues := []string{queue.DefaultQueueName}
+	storage := newTestStorage(t, newTestServer(t))
+	enqueuer, err := queue.NewEnqueuer(storage)
+	require.NoError(t, err)
+
+	t.Run("pending task drops the rest of its workflow", func(t *testing.T) {
+		_, err := enqueuer.EnqueueWorkflow(ctx, queue.Chain(
+			queue.Step(exportStep{}),
+			queue.Step(notifyStep{}),
+		).OnError(queue.Step(failureStep{})))
+		require.NoError(t, err)
+
+		pending, err := storage.GetPendingTaskByName(ctx, "redisstorage_test.exportStep")
+		require.NoError(t, err)
+		require.NoError(t, storage.CancelTask(ctx, pending.ID))
+
+		assert.Equal(t, map[queue.TaskStatus]int64{queue.TaskStatusCancelled: 1}, countTasks(t, storage))
+		assert.ErrorIs(t, storage.CancelTask(ctx, pending.ID), queue.ErrTaskNotCancellable)
+	})
+
+	t.Run("processing task is flagged for its worker", func(t *testing.T) {
+		_, err := enqueuer.Enqueue(ctx, reportPayload{N: 1})
+		require.NoError(t, err)
+		task, err := storage.ClaimTask(ctx, uuid.New(), queues, time.Minute)
+		require.NoError(t, err)
+
+		require.NoError(t, storage.CancelTask(ctx, task.ID))
+		requested, err := storage.IsCancelRequested(ctx, task.ID)
+		require.NoError(t, err)
+		assert.True(t, requested)
+
+		require.NoError(t, storage.MarkCancelled(ctx, task.ID))
+		result, err := storage.GetTaskResult(ctx, task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, queue.TaskStatusCancelled, result.Status)
+	})
+
+	t.Run("unknown task", func(t *testing.T) {
+		assert.ErrorIs(t, storage.CancelTask(ctx, uuid.New()), queue.ErrTaskNotFound)
+	})
+}
@@ -0,0 +1,89 @@
+package redisstorage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+)
+
+// DefaultLeaderName is the lease name used by NewLeaderElector when none is given.
+const DefaultLeaderName = "scheduler"
+
+// Compile-time check that LeaderElector implements queue.LeaderElector
+var _ queue.LeaderElector = (*LeaderElector)(nil)
+
+// renewLeaseScript extends the lease only if the candidate still holds it.
+// ARGV: candidate_id, ttl_ms
+var renewLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return 1
+`)
+
+// releaseLeaseScript deletes the lease only if the candidate still holds it.
+// ARGV: candidate_id
+var releaseLeaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('DEL', KEYS[1])
+end
+return 1
+`)
+
+// LeaderElector implements queue.LeaderElector with a Redis key holding the leader's ID.
+//
+// The lease is taken with SET NX PX and renewed or released only by its holder,
+// so a crashed leader is replaced once its key expires.
+type LeaderElector struct {
+	client redis.UniversalClient
+	key    string
+	id     string
+}
+
+// NewLeaderElector creates a candidate for the lease with the given name.
+// Candidates sharing a name compete for the same key under DefaultKeyPrefix.
+func NewLeaderElector(client redis.UniversalClient, name string) (*LeaderElector, error) {
+	if client == nil {
+		return nil, ErrClientNil
+	}
+	if name == "" {
+		name = DefaultLeaderName
+	}
+
+	return &LeaderElector{
+		client: client,
+		key:    DefaultKeyPrefix + ":leader:" + name,
+		id:     uuid.New().String(),
+	}, nil
+}
+
+// TryAcquire takes the lease if the key is free, or extends it if the candidate holds it.
+func (e *LeaderElector) TryAcquire(ctx context.Context, ttl time.Duration) (bool, error) {
+	acquired, err := e.client.SetNX(ctx, e.key, e.id, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire leader lease: %w", err)
+	}
+	if acquired {
+		return true, nil
+	}
+
+	renewed, err := renewLeaseScript.Run(ctx, e.client, []string{e.key}, e.id, ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to renew leader lease: %w", err)
+	}
+	return renewed == 1, nil
+}
+
+// Release deletes the lease key if the candidate holds it.
+func (e *LeaderElector) Release(ctx context.Context) error {
+	if err := releaseLeaseScript.Run(ctx, e.client, []string{e.key}, e.id).Err(); err != nil {
+		return fmt.Errorf("failed to release leader lease: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package redisstorage
+
+import (
+	"log/slog"
+	"time"
+)
+
+// DefaultKeyPrefix is the prefix for all keys written by Storage.
+// The braces form a Redis Cluster hash tag, keeping every key in one slot
+// so the Lua scripts can touch them atomically.
+const DefaultKeyPrefix = "{queue}"
+
+// Option configures a Storage.
+type Option func(*Storage)
+
+// WithKeyPrefix sets the prefix for all keys written by Storage.
+// Use distinct prefixes to run isolated queue systems on one Redis instance.
+// On Redis Cluster the prefix must contain a hash tag, e.g. "{billing-queue}".
+func WithKeyPrefix(prefix string) Option {
+	return func(s *Storage) {
+		if prefix != "" {
+			s.prefix = prefix
+		}
+	}
+}
+
+// WithLockCheckInterval sets the interval for releasing expired task locks.
+func WithLockCheckInterval(interval time.Duration) Option {
+	return func(s *Storage) {
+		if interval > 0 {
+			s.lockCheckInterval = interval
+		}
+	}
+}
+
+// WithShutdownTimeout sets the graceful shutdown timeout for the lock expiration manager.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(s *Storage) {
+		if timeout > 0 {
+			s.shutdownTimeout = timeout
+		}
+	}
+}
+
+// WithLogger sets the logger for internal operations.
+// Use slog.New(slog.NewTextHandler(io.Discard, nil)) to disable logging.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Storage) {
+		if logger != nil {
+			s.logger = logger
+		}
+	}
+}
@@ -0,0 +1,642 @@
+package redisstorage
+
+import "github.com/redis/go-redis/v9"
+
+// Key layout (P is the configured prefix):
+//
+//	P:task:<id>          hash    task fields, timestamps in unix milliseconds
+//	P:tasks              set     ids of all stored tasks (Stats.ActiveTasks)
+//	P:scheduled:<queue>  zset    pending tasks scored by scheduled_at
+//	P:ready:<queue>      zset    due pending tasks scored by priority, then scheduled_at
+//	P:pending:<name>     set     pending task ids by task name (scheduler idempotency)
+//	P:processing         zset    processing tasks scored by locked_until
+//	P:unique:<key>       string  id of the task holding a unique key
+//	P:children:<id>      set     waiting workflow tasks by the task, group or workflow they wait for
+//	P:group:<id>         set     workflow group members
+//	P:workflow:<id>      set     workflow tasks, excluding error callbacks
+//	P:dlq:<id>           hash    dead letter queue entry
+//	P:dlq                zset    dead letter queue entry ids scored by failed_at
+//	P:dlq:by-task        hash    latest dead letter queue entry id by task id
+//	P:results            zset    completed tasks with a result TTL scored by expiry
+//
+// Every script receives the tasks set key as KEYS[1] so Redis Cluster routes
+// it to the slot owning the prefix hash tag, and the prefix itself as ARGV[1].
+
+// luaPrelude holds helpers shared by all scripts.
+// Server time is used everywhere so that workers with skewed clocks agree on
+// due times and lock expiry.
+const luaPrelude = `
+local p = ARGV[1]
+
+local function now_ms()
+	local t = redis.call('TIME')
+	return tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+end
+
+-- Lua formats large numbers in scientific notation, which loses precision
+local function num(n)
+	return string.format('%.0f', n)
+end
+
+local function index_pending(id, queue, name, scheduled_at)
+	redis.call('ZADD', p .. ':scheduled:' .. queue, num(scheduled_at), id)
+	redis.call('SADD', p .. ':pending:' .. name, id)
+end
+
+local function create_task(id, queue, name, status, scheduled_at, locked_until, unique_key, fields)
+	local key = p .. ':task:' .. id
+	redis.call('HSET', key, unpack(fields))
+	redis.call('SADD', p .. ':tasks', id)
+
+	if status == 'pending' then
+		index_pending(id, queue, name, tonumber(scheduled_at))
+	elseif status == 'processing' and locked_until ~= '' then
+		redis.call('ZADD', p .. ':processing', locked_until, id)
+	end
+	if unique_key ~= '' then
+		redis.call('SET', p .. ':unique:' .. unique_key, id)
+	end
+
+	local links = redis.call('HMGET', key, 'parent_id', 'group_id', 'workflow_id')
+	if links[1] then
+		redis.call('SADD', p .. ':children:' .. links[1], id)
+	end
+	if links[2] then
+		redis.call('SADD', p .. ':group:' .. links[2], id)
+	end
+	if links[3] then
+		redis.call('SADD', p .. ':workflow:' .. links[3], id)
+	end
+end
+
+-- delete_task removes a task hash and every index entry pointing at it
+local function delete_task(id)
+	local key = p .. ':task:' .. id
+	local t = redis.call('HMGET', key, 'queue', 'task_name', 'unique_key', 'parent_id', 'group_id', 'workflow_id')
+	redis.call('ZREM', p .. ':scheduled:' .. t[1], id)
+	redis.call('ZREM', p .. ':ready:' .. t[1], id)
+	redis.call('ZREM', p .. ':processing', id)
+	redis.call('SREM', p .. ':pending:' .. t[2], id)
+	redis.call('SREM', p .. ':tasks', id)
+	if t[3] and redis.call('GET', p .. ':unique:' .. t[3]) == id then
+		redis.call('DEL', p .. ':unique:' .. t[3])
+	end
+	if t[4] then
+		redis.call('SREM', p .. ':children:' .. t[4], id)
+	end
+	if t[5] then
+		redis.call('SREM', p .. ':group:' .. t[5], id)
+	end
+	if t[6] then
+		redis.call('SREM', p .. ':workflow:' .. t[6], id)
+	end
+	redis.call('ZREM', p .. ':results', id)
+	redis.call('DEL', key)
+end
+
+-- workflow_finished reports whether no task of the workflow is left to run
+local function workflow_finished(workflow_id)
+	for _, member in ipairs(redis.call('SMEMBERS', p .. ':workflow:' .. workflow_id)) do
+		local status = redis.call('HGET', p .. ':task:' .. member, 'status')
+		if status == 'waiting' or status == 'pending' or status == 'processing' then
+			return false
+		end
+	end
+	return true
+end
+
+-- activate_children makes the tasks waiting for parent_id pending, handing over
+-- parent_result. Step delays count from activation.
+local function activate_children(parent_id, parent_result, now)
+	local children = p .. ':children:' .. parent_id
+	for _, id in ipairs(redis.call('SMEMBERS', children)) do
+		local key = p .. ':task:' .. id
+		local t = redis.call('HMGET', key, 'status', 'queue', 'task_name', 'scheduled_at', 'created_at')
+		if t[1] == 'waiting' then
+			local scheduled_at = now + tonumber(t[4]) - tonumber(t[5])
+			redis.call('HSET', key, 'status', 'pending', 'scheduled_at', num(scheduled_at))
+			if parent_result then
+				redis.call('HSET', key, 'parent_result', parent_result)
+			end
+			index_pending(id, t[2], t[3], scheduled_at)
+		end
+	end
+	redis.call('DEL', children)
+end
+
+-- drop_waiting deletes the waiting tasks in the given set
+local function drop_waiting(set)
+	for _, id in ipairs(redis.call('SMEMBERS', set)) do
+		if redis.call('HGET', p .. ':task:' .. id, 'status') == 'waiting' then
+			delete_task(id)
+		end
+	end
+end
+
+-- cancel_task finishes a task as cancelled, releases its unique key and drops the
+-- waiting rest of its workflow
+local function cancel_task(id, now)
+	local key = p .. ':task:' .. id
+	local t = redis.call('HMGET', key, 'queue', 'task_name', 'unique_key', 'workflow_id')
+	redis.call('ZREM', p .. ':scheduled:' .. t[1], id)
+	redis.call('ZREM', p .. ':ready:' .. t[1], id)
+	redis.call('ZREM', p .. ':processing', id)
+	redis.call('SREM', p .. ':pending:' .. t[2], id)
+	redis.call('HSET', key, 'status', 'cancelled', 'processed_at', num(now))
+	redis.call('HDEL', key, 'locked_until', 'locked_by', 'unique_until', 'cancel_requested')
+	if t[3] and redis.call('GET', p .. ':unique:' .. t[3]) == id then
+		redis.call('DEL', p .. ':unique:' .. t[3])
+	end
+	if t[4] then
+		drop_waiting(p .. ':workflow:' .. t[4])
+		drop_waiting(p .. ':children:' .. t[4])
+	end
+end
+
+local function check_processing(key)
+	local status = redis.call('HGET', key, 'status')
+	if not status then
+		return redis.error_reply('TASK_NOT_FOUND')
+	end
+	if status ~= 'processing' then
+		return redis.error_reply('TASK_NOT_PROCESSING')
+	end
+	return nil
+end
+`
+
+// createScript stores a task hash and indexes it.
+// With a conflict mode, the unique key is checked first: the holder's id is returned
+// for keep-existing, a pending holder is deleted for replace, and otherwise the
+// script fails with DUPLICATE_TASK.
+// ARGV: prefix, id, queue, task_name, status, scheduled_at, locked_until,
+// unique_key, conflict_mode, hash field/value pairs...
+var createScript = redis.NewScript(luaPrelude + `
+local id = ARGV[2]
+if redis.call('EXISTS', p .. ':task:' .. id) == 1 then
+	return redis.error_reply('TASK_EXISTS')
+end
+
+local unique_key, mode = ARGV[8], ARGV[9]
+if unique_key ~= '' and mode ~= '' then
+	local holder = redis.call('GET', p .. ':unique:' .. unique_key)
+	if holder then
+		local t = redis.call('HMGET', p .. ':task:' .. holder, 'status', 'unique_until')
+		local active = t[1] == 'pending' or t[1] == 'processing'
+		if active or (t[1] and tonumber(t[2] or '0') > now_ms()) then
+			if mode == 'keep-existing' then
+				return holder
+			end
+			if mode ~= 'replace' or t[1] == 'processing' then
+				return redis.error_reply('DUPLICATE_TASK')
+			end
+			if t[1] == 'pending' then
+				delete_task(holder)
+			end
+		end
+	end
+end
+
+local fields = {}
+for i = 10, #ARGV do
+	fields[#fields + 1] = ARGV[i]
+end
+create_task(id, ARGV[3], ARGV[4], ARGV[5], ARGV[6], ARGV[7], unique_key, fields)
+return id
+`)
+
+// createWorkflowScript stores all tasks of a workflow in one call. Every id is
+// checked before the first write, so the workflow is stored whole or not at all.
+// ARGV: prefix, then per task: id, queue, task_name, status, scheduled_at,
+// locked_until, unique_key, field count, hash field/value pairs...
+var createWorkflowScript = redis.NewScript(luaPrelude + `
+local tasks = {}
+local i = 2
+while i <= #ARGV do
+	local n = tonumber(ARGV[i + 7])
+	local fields = {}
+	for j = i + 8, i + 7 + n do
+		fields[#fields + 1] = ARGV[j]
+	end
+	tasks[#tasks + 1] = {ARGV[i], ARGV[i + 1], ARGV[i + 2], ARGV[i + 3], ARGV[i + 4], ARGV[i + 5], ARGV[i + 6], fields}
+	i = i + 8 + n
+end
+
+for _, t in ipairs(tasks) do
+	if redis.call('EXISTS', p .. ':task:' .. t[1]) == 1 then
+		return redis.error_reply('TASK_EXISTS ' .. t[1])
+	end
+end
+
+for _, t in ipairs(tasks) do
+	create_task(t[1], t[2], t[3], t[4], t[5], t[6], t[7], t[8])
+end
+return #tasks
+`)
+
+// claimScript promotes due tasks and claims the best one across the given queues.
+// Ready score = (100 - priority) * 1e13 + scheduled_at, so the lowest score is the
+// highest priority task, with the earliest scheduled task winning ties.
+// ARGV: prefix, worker_id, lock_ms, queues...
+var claimScript = redis.NewScript(luaPrelude + `
+local now = now_ms()
+local best_id, best_score, best_queue
+
+for i = 4, #ARGV do
+	local queue = ARGV[i]
+	local scheduled = p .. ':scheduled:' .. queue
+	local ready = p .. ':ready:' .. queue
+
+	-- Promotion is bounded per call to keep the script short; the remainder
+	-- is promoted by subsequent claims
+	local due = redis.call('ZRANGEBYSCORE', scheduled, '-inf', num(now), 'WITHSCORES', 'LIMIT', 0, 1000)
+	for j = 1, #due, 2 do
+		local id = due[j]
+		local priority = tonumber(redis.call('HGET', p .. ':task:' .. id, 'priority') or '50')
+		redis.call('ZADD', ready, num((100 - priority) * 1e13 + tonumber(due[j + 1])), id)
+		redis.call('ZREM', scheduled, id)
+	end
+
+	local top = redis.call('ZRANGE', ready, 0, 0, 'WITHSCORES')
+	if top[1] then
+		local score = tonumber(top[2])
+		if best_score == nil or score < best_score then
+			best_id, best_score, best_queue = top[1], score, queue
+		end
+	end
+end
+
+if not best_id then
+	return false
+end
+
+local key = p .. ':task:' .. best_id
+local locked_until = now + tonumber(ARGV[3])
+redis.call('ZREM', p .. ':ready:' .. best_queue, best_id)
+redis.call('SREM', p .. ':pending:' .. redis.call('HGET', key, 'task_name'), best_id)
+redis.call('HSET', key, 'status', 'processing', 'locked_until', num(locked_until), 'locked_by', ARGV[2])
+redis.call('ZADD', p .. ':processing', num(locked_until), best_id)
+
+return redis.call('HGETALL', key)
+`)
+
+// completeScript marks a processing task as completed and activates the workflow
+// tasks waiting for it, or for its group once every member has completed.
+// Tasks with a result TTL are indexed for the expired result purge.
+// ARGV: prefix, id, result
+var completeScript = redis.NewScript(luaPrelude + `
+local id = ARGV[2]
+local key = p .. ':task:' .. id
+local err = check_processing(key)
+if err then
+	return err
+end
+
+local now = now_ms()
+local result = ARGV[3]
+redis.call('HSET', key, 'status', 'completed', 'processed_at', num(now))
+redis.call('HDEL', key, 'locked_until', 'locked_by')
+redis.call('ZREM', p .. ':processing', id)
+if result ~= '' then
+	redis.call('HSET', key, 'result', result)
+else
+	result = nil
+end
+local ttl = redis.call('HGET', key, 'result_ttl_ms')
+if ttl then
+	redis.call('ZADD', p .. ':results', num(now + tonumber(ttl)), id)
+end
+
+activate_children(id, result, now)
+
+local links = redis.call('HMGET', key, 'group_id', 'workflow_id')
+if links[1] then
+	-- Group results are passed as a JSON array in completion order
+	local members = {}
+	local done = true
+	for _, member in ipairs(redis.call('SMEMBERS', p .. ':group:' .. links[1])) do
+		local t = redis.call('HMGET', p .. ':task:' .. member, 'status', 'processed_at', 'result')
+		if t[1] ~= 'completed' then
+			done = false
+			break
+		end
+		members[#members + 1] = { tonumber(t[2]), t[3] or 'null' }
+	end
+	if done then
+		table.sort(members, function(a, b) return a[1] < b[1] end)
+		local results = {}
+		for i, m in ipairs(members) do
+			results[i] = m[2]
+		end
+		activate_children(links[1], '[' .. table.concat(results, ',') .. ']', now)
+	end
+end
+
+if links[2] and workflow_finished(links[2]) then
+	-- Error callbacks wait for the workflow ID and are no longer needed
+	drop_waiting(p .. ':children:' .. links[2])
+end
+
+return 'OK'
+`)
+
+// failScript records a failure and reschedules the task after the given delay
+// while retries remain, matching queue.MemoryStorage.
+// ARGV: prefix, id, error_message, retry_delay_ms
+var failScript = redis.NewScript(luaPrelude + `
+local id = ARGV[2]
+local key = p .. ':task:' .. id
+local err = check_processing(key)
+if err then
+	return err
+end
+
+local t = redis.call('HMGET', key, 'retry_count', 'max_retries', 'queue', 'task_name')
+local retry_count = tonumber(t[1] or '0') + 1
+local max_retries = tonumber(t[2] or '0')
+
+redis.call('HSET', key, 'retry_count', retry_count, 'error', ARGV[3])
+redis.call('HDEL', key, 'locked_until', 'locked_by')
+redis.call('ZREM', p .. ':processing', id)
+
+if retry_count >= max_retries then
+	redis.call('HSET', key, 'status', 'failed')
+else
+	local scheduled_at = now_ms() + tonumber(ARGV[4])
+	redis.call('HSET', key, 'status', 'pending', 'scheduled_at', num(scheduled_at))
+	index_pending(id, t[3], t[4], scheduled_at)
+end
+return 'OK'
+`)
+
+// moveToDLQScript copies a task into the dead letter queue and removes it with all index entries.
+// The entry is indexed by task id so the task is still reported as failed.
+// If the task belongs to a workflow, the waiting rest of it is dropped and its error
+// callbacks are activated.
+// ARGV: prefix, id, dlq_id
+var moveToDLQScript = redis.NewScript(luaPrelude + `
+local id = ARGV[2]
+local key = p .. ':task:' .. id
+if redis.call('EXISTS', key) == 0 then
+	return redis.error_reply('TASK_NOT_FOUND')
+end
+
+local t = redis.call('HMGET', key, 'queue', 'task_type', 'task_name', 'payload', 'priority', 'error', 'retry_count', 'workflow_id')
+local now = num(now_ms())
+local dlq_id = ARGV[3]
+
+redis.call('HSET', p .. ':dlq:' .. dlq_id,
+	'id', dlq_id,
+	'task_id', id,
+	'queue', t[1],
+	'task_type', t[2],
+	'task_name', t[3],
+	'payload', t[4] or '',
+	'priority', t[5],
+	'error', t[6] or '',
+	'retry_count', t[7],
+	'failed_at', now,
+	'created_at', now)
+redis.call('ZADD', p .. ':dlq', now, dlq_id)
+redis.call('HSET', p .. ':dlq:by-task', id, dlq_id)
+
+delete_task(id)
+
+-- The rest of the workflow can never run: drop it and hand the failure to the error callbacks
+local workflow_id = t[8]
+if workflow_id then
+	drop_waiting(p .. ':workflow:' .. workflow_id)
+	local failure = cjson.encode({
+		workflow_id = workflow_id,
+		task_id = id,
+		task_name = t[3],
+		error = t[6] or '',
+	})
+	activate_children(workflow_id, failure, tonumber(now))
+end
+return 'OK'
+`)
+
+// requeueScript removes a dead letter queue entry and stores a task in its place.
+// ARGV: prefix, dlq_id, id, queue, task_name, status, scheduled_at, hash field/value pairs...
+var requeueScript = redis.NewScript(luaPrelude + `
+local dlq_key = p .. ':dlq:' .. ARGV[2]
+if redis.call('EXISTS', dlq_key) == 0 then
+	return redis.error_reply('DLQ_NOT_FOUND')
+end
+local id = ARGV[3]
+if redis.call('EXISTS', p .. ':task:' .. id) == 1 then
+	return redis.error_reply('TASK_EXISTS')
+end
+
+redis.call('DEL', dlq_key)
+redis.call('ZREM', p .. ':dlq', ARGV[2])
+if redis.call('HGET', p .. ':dlq:by-task', id) == ARGV[2] then
+	redis.call('HDEL', p .. ':dlq:by-task', id)
+end
+
+local fields = {}
+for i = 8, #ARGV do
+	fields[#fields + 1] = ARGV[i]
+end
+create_task(id, ARGV[4], ARGV[5], ARGV[6], ARGV[7], '', '', fields)
+return 'OK'
+`)
+
+// purgeDLQScript deletes dead letter queue entries that failed before the given time.
+// ARGV: prefix, before_ms
+var purgeDLQScript = redis.NewScript(luaPrelude + `
+local index = p .. ':dlq'
+local ids = redis.call('ZRANGEBYSCORE', index, '-inf', '(' .. ARGV[2])
+local by_task = p .. ':dlq:by-task'
+for _, id in ipairs(ids) do
+	local task_id = redis.call('HGET', p .. ':dlq:' .. id, 'task_id')
+	if task_id and redis.call('HGET', by_task, task_id) == id then
+		redis.call('HDEL', by_task, task_id)
+	end
+	redis.call('DEL', p .. ':dlq:' .. id)
+end
+redis.call('ZREMRANGEBYSCORE', index, '-inf', '(' .. ARGV[2])
+return #ids
+`)
+
+// deleteDLQScript deletes a single dead letter queue entry.
+// ARGV: prefix, dlq_id
+var deleteDLQScript = redis.NewScript(luaPrelude + `
+local id = ARGV[2]
+local key = p .. ':dlq:' .. id
+local task_id = redis.call('HGET', key, 'task_id')
+if not task_id then
+	return redis.error_reply('DLQ_NOT_FOUND')
+end
+
+if redis.call('HGET', p .. ':dlq:by-task', task_id) == id then
+	redis.call('HDEL', p .. ':dlq:by-task', task_id)
+end
+redis.call('DEL', key)
+redis.call('ZREM', p .. ':dlq', id)
+return 'OK'
+`)
+
+// addDLQScript stores a dead letter queue entry for a failure outside the worker.
+// ARGV: prefix, dlq_id, task_id, queue, task_type, task_name, payload, priority, error, retry_count, failed_at, created_at
+var addDLQScript = redis.NewScript(luaPrelude + `
+local id = ARGV[2]
+redis.call('HSET', p .. ':dlq:' .. id,
+	'id', id,
+	'task_id', ARGV[3],
+	'queue', ARGV[4],
+	'task_type', ARGV[5],
+	'task_name', ARGV[6],
+	'payload', ARGV[7],
+	'priority', ARGV[8],
+	'error', ARGV[9],
+	'retry_count', ARGV[10],
+	'failed_at', ARGV[11],
+	'created_at', ARGV[12])
+redis.call('ZADD', p .. ':dlq', ARGV[11], id)
+redis.call('HSET', p .. ':dlq:by-task', ARGV[3], id)
+return 'OK'
+`)
+
+// cancelScript cancels a pending or waiting task, or flags a processing one for its worker.
+// ARGV: prefix, id
+var cancelScript = redis.NewScript(luaPrelude + `
+local id = ARGV[2]
+local key = p .. ':task:' .. id
+local status = redis.call('HGET', key, 'status')
+if not status then
+	return redis.error_reply('TASK_NOT_FOUND')
+end
+
+if status == 'pending' or status == 'waiting' then
+	cancel_task(id, now_ms())
+elseif status == 'processing' then
+	redis.call('HSET', key, 'cancel_requested', '1')
+else
+	return redis.error_reply('TASK_NOT_CANCELLABLE ' .. status)
+end
+return 'OK'
+`)
+
+// markCancelledScript finishes a processing task as cancelled.
+// ARGV: prefix, id
+var markCancelledScript = redis.NewScript(luaPrelude + `
+local id = ARGV[2]
+local err = check_processing(p .. ':task:' .. id)
+if err then
+	return err
+end
+
+cancel_task(id, now_ms())
+return 'OK'
+`)
+
+// extendLockScript pushes the lock deadline of a processing task held by the worker.
+// ARGV: prefix, id, duration_ms, worker_id
+var extendLockScript = redis.NewScript(luaPrelude + `
+local id = ARGV[2]
+local key = p .. ':task:' .. id
+local t = redis.call('HMGET', key, 'status', 'locked_by')
+if t[1] ~= 'processing' or t[2] ~= ARGV[4] then
+	return redis.error_reply('LOCK_LOST')
+end
+
+local locked_until = num(now_ms() + tonumber(ARGV[3]))
+redis.call('HSET', key, 'locked_until', locked_until)
+redis.call('ZADD', p .. ':processing', locked_until, id)
+return 'OK'
+`)
+
+// pendingByNameScript returns the first pending task with the given name.
+// Stale index entries are pruned on the way.
+// ARGV: prefix, task_name
+var pendingByNameScript = redis.NewScript(luaPrelude + `
+local index = p .. ':pending:' .. ARGV[2]
+for _, id in ipairs(redis.call('SMEMBERS', index)) do
+	local key = p .. ':task:' .. id
+	if redis.call('HGET', key, 'status') == 'pending' then
+		return redis.call('HGETALL', key)
+	end
+	redis.call('SREM', index, id)
+end
+return false
+`)
+
+// expireLocksScript returns processing tasks with expired locks to pending
+// without touching their retry count, matching queue.MemoryStorage.
+// Tasks flagged for cancellation are cancelled instead, since their worker is gone.
+// ARGV: prefix
+var expireLocksScript = redis.NewScript(luaPrelude + `
+local processing = p .. ':processing'
+local now = now_ms()
+local expired = redis.call('ZRANGEBYSCORE', processing, '-inf', '(' .. num(now), 'LIMIT', 0, 1000)
+local freed = 0
+
+for _, id in ipairs(expired) do
+	local key = p .. ':task:' .. id
+	local t = redis.call('HMGET', key, 'status', 'queue', 'task_name', 'scheduled_at', 'cancel_requested')
+	if t[1] == 'processing' and t[5] then
+		cancel_task(id, now)
+	elseif t[1] == 'processing' then
+		redis.call('HSET', key, 'status', 'pending')
+		redis.call('HDEL', key, 'locked_until', 'locked_by')
+		index_pending(id, t[2], t[3], tonumber(t[4]))
+		freed = freed + 1
+	end
+	redis.call('ZREM', processing, id)
+end
+
+return freed
+`)
+
+// purgeResultsScript deletes completed tasks whose result TTL has expired.
+// Tasks still holding a unique key are rescored to its expiry, and tasks of an
+// unfinished workflow are checked again after another TTL, matching queue.MemoryStorage.
+// ARGV: prefix
+var purgeResultsScript = redis.NewScript(luaPrelude + `
+local index = p .. ':results'
+local now = now_ms()
+local expired = redis.call('ZRANGEBYSCORE', index, '-inf', '(' .. num(now), 'LIMIT', 0, 1000)
+local purged = 0
+
+for _, id in ipairs(expired) do
+	local t = redis.call('HMGET', p .. ':task:' .. id, 'status', 'unique_until', 'workflow_id', 'result_ttl_ms')
+	if t[1] ~= 'completed' then
+		redis.call('ZREM', index, id)
+	elseif t[2] and tonumber(t[2]) > now then
+		redis.call('ZADD', index, t[2], id)
+	elseif t[3] and not workflow_finished(t[3]) then
+		redis.call('ZADD', index, num(now + tonumber(t[4] or '0')), id)
+	else
+		delete_task(id)
+		purged = purged + 1
+	end
+end
+
+return purged
+`)
+
+// resultScript returns the status, result, error and processed_at of a task.
+// Completed tasks past their result TTL are not found; tasks moved to the dead
+// letter queue are reported as failed with the time they failed.
+// ARGV: prefix, id
+var resultScript = redis.NewScript(luaPrelude + `
+local id = ARGV[2]
+local t = redis.call('HMGET', p .. ':task:' .. id, 'status', 'result', 'error', 'processed_at', 'result_ttl_ms')
+if t[1] then
+	if t[1] == 'completed' and t[4] and t[5] and tonumber(t[4]) + tonumber(t[5]) < now_ms() then
+		return redis.error_reply('TASK_NOT_FOUND')
+	end
+	return { t[1], t[2] or false, t[3] or false, t[4] or false }
+end
+
+local dlq_id = redis.call('HGET', p .. ':dlq:by-task', id)
+if dlq_id then
+	local d = redis.call('HMGET', p .. ':dlq:' .. dlq_id, 'error', 'failed_at')
+	if d[2] then
+		return { 'failed', false, d[1] or false, d[2] }
+	end
+end
+return redis.error_reply('TASK_NOT_FOUND')
+`)
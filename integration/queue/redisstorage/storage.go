@@ -0,0 +1,1072 @@
+package redisstorage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+	redisdb "github.com/dmitrymomot/foundation/integration/database/redis"
+)
+
+// Compile-time checks that Storage implements all queue repository interfaces
+var (
+	_ queue.EnqueuerRepository   = (*Storage)(nil)
+	_ queue.WorkerRepository     = (*Storage)(nil)
+	_ queue.SchedulerRepository  = (*Storage)(nil)
+	_ queue.UniqueTaskRepository = (*Storage)(nil)
+	_ queue.WorkflowRepository   = (*Storage)(nil)
+	_ queue.DLQRepository        = (*Storage)(nil)
+	_ queue.CancelRepository     = (*Storage)(nil)
+	_ queue.ResultRepository     = (*Storage)(nil)
+	_ queue.StatsRepository      = (*Storage)(nil)
+)
+
+// Stats provides observability metrics for monitoring and debugging
+type Stats struct {
+	ActiveTasks       int       // Tasks stored in Redis, refreshed on each lock check
+	ExpiredLocksFreed int64     // Total number of expired locks freed
+	IsRunning         bool      // Whether the lock expiration manager is running
+	LastActivityAt    time.Time // Timestamp of last lock expiration (zero if never)
+}
+
+// Storage implements all queue repository interfaces on top of Redis.
+// Every state transition runs as a single Lua script, so any number of
+// worker processes can safely share the same keys.
+type Storage struct {
+	client redis.UniversalClient
+	prefix string
+
+	// Configuration
+	lockCheckInterval time.Duration
+	shutdownTimeout   time.Duration
+	logger            *slog.Logger
+
+	// State management
+	mu      sync.RWMutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	running atomic.Bool
+	wg      sync.WaitGroup
+
+	// Observability metrics
+	activeTasks       atomic.Int64
+	expiredLocksFreed atomic.Int64
+	lastActivityAt    atomic.Int64 // Unix timestamp of last lock expiration
+}
+
+// New creates a Redis-backed queue storage.
+// Call Start() to begin the lock expiration manager.
+func New(client redis.UniversalClient, opts ...Option) (*Storage, error) {
+	if client == nil {
+		return nil, ErrClientNil
+	}
+
+	s := &Storage{
+		client:            client,
+		prefix:            DefaultKeyPrefix,
+		lockCheckInterval: time.Second,
+		shutdownTimeout:   30 * time.Second,
+		logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+// CreateTask stores a new task.
+func (s *Storage) CreateTask(ctx context.Context, task *queue.Task) error {
+	if task == nil {
+		return ErrTaskNil
+	}
+
+	args, err := s.createArgs(task, "")
+	if err != nil {
+		return fmt.Errorf("failed to create task %s: %w", task.ID, err)
+	}
+	if err := s.run(ctx, createScript, task.ID, args...); err != nil {
+		return fmt.Errorf("failed to create task %s: %w", task.ID, err)
+	}
+
+	return nil
+}
+
+// CreateUniqueTask stores a new task unless its unique key is held by another task.
+// The key check and the write run in one script, so concurrent enqueues cannot both win.
+func (s *Storage) CreateUniqueTask(ctx context.Context, task *queue.Task, mode queue.UniqueConflictMode) (uuid.UUID, error) {
+	if task == nil {
+		return uuid.Nil, ErrTaskNil
+	}
+	if task.UniqueKey == nil {
+		return task.ID, s.CreateTask(ctx, task)
+	}
+
+	args, err := s.createArgs(task, mode)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create task %s: %w", task.ID, err)
+	}
+
+	id, err := createScript.Run(ctx, s.client, s.keys(), args...).Text()
+	if err != nil {
+		if strings.HasPrefix(errorReply(err), "DUPLICATE_TASK") {
+			return uuid.Nil, fmt.Errorf("%w: %q", queue.ErrDuplicateTask, *task.UniqueKey)
+		}
+		return uuid.Nil, fmt.Errorf("failed to create task %s: %w", task.ID, scriptError(err, task.ID))
+	}
+
+	holderID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: id: %w", ErrInvalidTaskData, err)
+	}
+	return holderID, nil
+}
+
+// CreateWorkflow stores all tasks of a workflow atomically, in one script call.
+// If any task id is taken, no task is stored.
+func (s *Storage) CreateWorkflow(ctx context.Context, tasks []*queue.Task) error {
+	args := []any{s.prefix}
+	for _, task := range tasks {
+		if task == nil {
+			return ErrTaskNil
+		}
+		a, err := s.createArgs(task, "")
+		if err != nil {
+			return fmt.Errorf("failed to create task %s: %w", task.ID, err)
+		}
+		// Drop the prefix and conflict mode; the field list is counted so the script can split tasks
+		fields := a[9:]
+		args = append(args, a[1:8]...)
+		args = append(args, len(fields))
+		args = append(args, fields...)
+	}
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	if err := createWorkflowScript.Run(ctx, s.client, s.keys(), args...).Err(); err != nil {
+		if id, ok := strings.CutPrefix(errorReply(err), "TASK_EXISTS "); ok {
+			return fmt.Errorf("failed to create workflow: %w: %s", ErrTaskAlreadyExists, id)
+		}
+		return fmt.Errorf("failed to create workflow: %w", err)
+	}
+
+	return nil
+}
+
+// createArgs builds the createScript arguments. An empty mode skips the unique key check.
+func (s *Storage) createArgs(task *queue.Task, mode queue.UniqueConflictMode) ([]any, error) {
+	var lockedUntil, uniqueKey string
+	if task.LockedUntil != nil {
+		lockedUntil = formatTime(*task.LockedUntil)
+	}
+	if task.UniqueKey != nil {
+		uniqueKey = *task.UniqueKey
+	}
+
+	args := []any{
+		s.prefix, task.ID.String(), task.Queue, task.TaskName, string(task.Status),
+		formatTime(task.ScheduledAt), lockedUntil, uniqueKey, string(mode),
+	}
+	fields, err := taskFields(task)
+	if err != nil {
+		return nil, err
+	}
+	return append(args, fields...), nil
+}
+
+// ClaimTask atomically claims the next highest-priority eligible task.
+func (s *Storage) ClaimTask(ctx context.Context, workerID uuid.UUID, queues []string, lockDuration time.Duration) (*queue.Task, error) {
+	if len(queues) == 0 {
+		return nil, queue.ErrNoTaskToClaim
+	}
+
+	args := make([]any, 0, len(queues)+3)
+	args = append(args, s.prefix, workerID.String(), lockDuration.Milliseconds())
+	for _, q := range queues {
+		args = append(args, q)
+	}
+
+	res, err := claimScript.Run(ctx, s.client, s.keys(), args...).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, queue.ErrNoTaskToClaim
+		}
+		return nil, fmt.Errorf("failed to claim task: %w", err)
+	}
+
+	return parseTask(res)
+}
+
+// CompleteTask marks a task as successfully completed and activates the
+// workflow tasks waiting for it.
+func (s *Storage) CompleteTask(ctx context.Context, taskID uuid.UUID, result []byte) error {
+	if err := s.run(ctx, completeScript, taskID, s.prefix, taskID.String(), string(result)); err != nil {
+		return fmt.Errorf("failed to complete task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// FailTask records a task failure and reschedules it after retryDelay if retries remain.
+func (s *Storage) FailTask(ctx context.Context, taskID uuid.UUID, errorMsg string, retryDelay time.Duration) error {
+	if err := s.run(ctx, failScript, taskID, s.prefix, taskID.String(), errorMsg, retryDelay.Milliseconds()); err != nil {
+		return fmt.Errorf("failed to fail task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// MoveToDLQ moves a failed task to the dead letter queue for manual inspection.
+func (s *Storage) MoveToDLQ(ctx context.Context, taskID uuid.UUID) error {
+	if err := s.run(ctx, moveToDLQScript, taskID, s.prefix, taskID.String(), uuid.New().String()); err != nil {
+		return fmt.Errorf("failed to move task %s to DLQ: %w", taskID, err)
+	}
+	return nil
+}
+
+// ListDLQ returns the dead letter queue entries matching filter, most recently failed first.
+// The failure time range is served by the DLQ index; queue and name filters are applied
+// while paging through it.
+func (s *Storage) ListDLQ(ctx context.Context, filter queue.DLQFilter) ([]*queue.TasksDlq, error) {
+	rangeBy := &redis.ZRangeBy{Min: "-inf", Max: "+inf"}
+	if !filter.FailedAfter.IsZero() {
+		rangeBy.Min = formatTime(filter.FailedAfter)
+	}
+	if !filter.FailedBefore.IsZero() {
+		rangeBy.Max = "(" + formatTime(filter.FailedBefore)
+	}
+
+	const batchSize = 500
+	var (
+		entries []*queue.TasksDlq
+		skipped int
+	)
+	for offset := int64(0); ; offset += batchSize {
+		rangeBy.Offset, rangeBy.Count = offset, batchSize
+		ids, err := s.client.ZRevRangeByScore(ctx, s.prefix+":dlq", rangeBy).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list DLQ: %w", err)
+		}
+
+		cmds, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, id := range ids {
+				pipe.HGetAll(ctx, s.prefix+":dlq:"+id)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list DLQ: %w", err)
+		}
+
+		for _, cmd := range cmds {
+			h := cmd.(*redis.MapStringStringCmd).Val()
+			if len(h) == 0 {
+				continue // Purged or requeued since the index was read
+			}
+			entry, err := parseDLQ(h)
+			if err != nil {
+				return nil, err
+			}
+			if !filter.Matches(entry) {
+				continue
+			}
+			if skipped < filter.Offset {
+				skipped++
+				continue
+			}
+			entries = append(entries, entry)
+			if filter.Limit > 0 && len(entries) == filter.Limit {
+				return entries, nil
+			}
+		}
+
+		if len(ids) < batchSize {
+			return entries, nil
+		}
+	}
+}
+
+// GetDLQ returns a single dead letter queue entry.
+func (s *Storage) GetDLQ(ctx context.Context, id uuid.UUID) (*queue.TasksDlq, error) {
+	h, err := s.client.HGetAll(ctx, s.prefix+":dlq:"+id.String()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DLQ entry %s: %w", id, err)
+	}
+	if len(h) == 0 {
+		return nil, fmt.Errorf("%w: %s", queue.ErrDLQEntryNotFound, id)
+	}
+	return parseDLQ(h)
+}
+
+// RequeueDLQ removes the dead letter queue entry and stores task in its place atomically.
+func (s *Storage) RequeueDLQ(ctx context.Context, id uuid.UUID, task *queue.Task) error {
+	if task == nil {
+		return ErrTaskNil
+	}
+
+	fields, err := taskFields(task)
+	if err != nil {
+		return fmt.Errorf("failed to requeue DLQ entry %s: %w", id, err)
+	}
+	args := append([]any{
+		s.prefix, id.String(), task.ID.String(), task.Queue, task.TaskName, string(task.Status),
+		formatTime(task.ScheduledAt),
+	}, fields...)
+
+	if err := requeueScript.Run(ctx, s.client, s.keys(), args...).Err(); err != nil {
+		if strings.HasPrefix(errorReply(err), "DLQ_NOT_FOUND") {
+			return fmt.Errorf("%w: %s", queue.ErrDLQEntryNotFound, id)
+		}
+		return fmt.Errorf("failed to requeue DLQ entry %s: %w", id, scriptError(err, task.ID))
+	}
+
+	return nil
+}
+
+// PurgeDLQ deletes dead letter queue entries that failed before the given time.
+func (s *Storage) PurgeDLQ(ctx context.Context, before time.Time) (int64, error) {
+	purged, err := purgeDLQScript.Run(ctx, s.client, s.keys(), s.prefix, formatTime(before)).Int64()
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge DLQ: %w", err)
+	}
+	return purged, nil
+}
+
+// DeleteDLQ deletes a single dead letter queue entry.
+func (s *Storage) DeleteDLQ(ctx context.Context, id uuid.UUID) error {
+	if err := deleteDLQScript.Run(ctx, s.client, s.keys(), s.prefix, id.String()).Err(); err != nil {
+		if strings.HasPrefix(errorReply(err), "DLQ_NOT_FOUND") {
+			return fmt.Errorf("%w: %s", queue.ErrDLQEntryNotFound, id)
+		}
+		return fmt.Errorf("failed to delete DLQ entry %s: %w", id, err)
+	}
+	return nil
+}
+
+// AddDLQ stores a dead letter queue entry for a failure outside the worker.
+func (s *Storage) AddDLQ(ctx context.Context, entry *queue.TasksDlq) error {
+	err := addDLQScript.Run(ctx, s.client, s.keys(), s.prefix,
+		entry.ID.String(),
+		entry.TaskID.String(),
+		entry.Queue,
+		string(entry.TaskType),
+		entry.TaskName,
+		string(entry.Payload),
+		strconv.Itoa(int(entry.Priority)),
+		entry.Error,
+		strconv.Itoa(int(entry.RetryCount)),
+		formatTime(entry.FailedAt),
+		formatTime(entry.CreatedAt),
+	).Err()
+	if err != nil {
+		return fmt.Errorf("failed to add DLQ entry %s: %w", entry.ID, err)
+	}
+	return nil
+}
+
+// CountTasks returns the number of stored tasks by queue and status.
+// Tasks are counted in batches with SSCAN, so large queues do not block Redis.
+func (s *Storage) CountTasks(ctx context.Context) ([]queue.TaskCount, error) {
+	byQueue := make(map[string]map[queue.TaskStatus]int64)
+	err := s.scanTasks(ctx, []string{"queue", "status"}, func(_ string, values []any) {
+		q, _ := values[0].(string)
+		status, _ := values[1].(string)
+		if status == "" {
+			return // Deleted since the scan read its id
+		}
+		if byQueue[q] == nil {
+			byQueue[q] = make(map[queue.TaskStatus]int64)
+		}
+		byQueue[q][queue.TaskStatus(status)]++
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	var counts []queue.TaskCount
+	for _, q := range slices.Sorted(maps.Keys(byQueue)) {
+		for _, status := range slices.Sorted(maps.Keys(byQueue[q])) {
+			counts = append(counts, queue.TaskCount{Queue: q, Status: status, Count: byQueue[q][status]})
+		}
+	}
+	return counts, nil
+}
+
+// ListRetryingTasks returns pending tasks whose last attempt failed, soonest retry first.
+// Like CountTasks it scans every task, so keep it for monitoring.
+func (s *Storage) ListRetryingTasks(ctx context.Context, limit int) ([]*queue.Task, error) {
+	var ids []string
+	err := s.scanTasks(ctx, []string{"status", "error"}, func(id string, values []any) {
+		if values[0] == string(queue.TaskStatusPending) && values[1] != nil {
+			ids = append(ids, id)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retrying tasks: %w", err)
+	}
+
+	cmds, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range ids {
+			pipe.HGetAll(ctx, s.prefix+":task:"+id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list retrying tasks: %w", err)
+	}
+
+	tasks := make([]*queue.Task, 0, len(cmds))
+	for _, cmd := range cmds {
+		h := cmd.(*redis.MapStringStringCmd).Val()
+		if h["status"] != string(queue.TaskStatusPending) {
+			continue // Claimed or deleted since the scan
+		}
+		task, err := parseTaskHash(h)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+
+	slices.SortFunc(tasks, func(a, b *queue.Task) int {
+		return a.ScheduledAt.Compare(b.ScheduledAt)
+	})
+	if limit > 0 && len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+	return tasks, nil
+}
+
+// scanTasks calls fn with the requested hash fields of every stored task,
+// reading the tasks set with SSCAN and the hashes in pipelined batches.
+func (s *Storage) scanTasks(ctx context.Context, fields []string, fn func(id string, values []any)) error {
+	iter := s.client.SScan(ctx, s.prefix+":tasks", 0, "", 500).Iterator()
+	batch := make([]string, 0, 500)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		cmds, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, id := range batch {
+				pipe.HMGet(ctx, s.prefix+":task:"+id, fields...)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for i, cmd := range cmds {
+			fn(batch[i], cmd.(*redis.SliceCmd).Val())
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for iter.Next(ctx) {
+		batch = append(batch, iter.Val())
+		if len(batch) == cap(batch) {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// CancelTask cancels a pending or waiting task, or flags a processing one for its worker.
+func (s *Storage) CancelTask(ctx context.Context, taskID uuid.UUID) error {
+	if err := cancelScript.Run(ctx, s.client, s.keys(), s.prefix, taskID.String()).Err(); err != nil {
+		msg := errorReply(err)
+		switch {
+		case strings.HasPrefix(msg, "TASK_NOT_FOUND"):
+			return fmt.Errorf("%w: %s", queue.ErrTaskNotFound, taskID)
+		case strings.HasPrefix(msg, "TASK_NOT_CANCELLABLE"):
+			return fmt.Errorf("%w: %s is %s", queue.ErrTaskNotCancellable, taskID, strings.TrimPrefix(msg, "TASK_NOT_CANCELLABLE "))
+		}
+		return fmt.Errorf("failed to cancel task %s: %w", taskID, err)
+	}
+
+	return nil
+}
+
+// IsCancelRequested reports whether the task was cancelled or flagged for cancellation.
+func (s *Storage) IsCancelRequested(ctx context.Context, taskID uuid.UUID) (bool, error) {
+	values, err := s.client.HMGet(ctx, s.prefix+":task:"+taskID.String(), "status", "cancel_requested").Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check cancellation of task %s: %w", taskID, err)
+	}
+	if values[0] == nil {
+		return false, fmt.Errorf("%w: %s", queue.ErrTaskNotFound, taskID)
+	}
+
+	return values[1] != nil || values[0] == string(queue.TaskStatusCancelled), nil
+}
+
+// MarkCancelled finishes a processing task as cancelled.
+func (s *Storage) MarkCancelled(ctx context.Context, taskID uuid.UUID) error {
+	if err := markCancelledScript.Run(ctx, s.client, s.keys(), s.prefix, taskID.String()).Err(); err != nil {
+		return fmt.Errorf("failed to mark task %s as cancelled: %w", taskID, scriptError(err, taskID))
+	}
+	return nil
+}
+
+// ExtendLock extends the lock of a processing task held by workerID.
+func (s *Storage) ExtendLock(ctx context.Context, taskID, workerID uuid.UUID, duration time.Duration) error {
+	err := extendLockScript.Run(ctx, s.client, s.keys(), s.prefix, taskID.String(), duration.Milliseconds(), workerID.String()).Err()
+	if err != nil {
+		if strings.HasPrefix(errorReply(err), "LOCK_LOST") {
+			return fmt.Errorf("%w: %s", queue.ErrLockLost, taskID)
+		}
+		return fmt.Errorf("failed to extend lock for task %s: %w", taskID, err)
+	}
+	return nil
+}
+
+// GetTaskResult returns the status and result of a task. A task moved to the dead
+// letter queue is reported as failed; completed tasks past their result TTL are not found.
+func (s *Storage) GetTaskResult(ctx context.Context, taskID uuid.UUID) (*queue.TaskResult, error) {
+	values, err := resultScript.Run(ctx, s.client, s.keys(), s.prefix, taskID.String()).Slice()
+	if err != nil {
+		if strings.HasPrefix(errorReply(err), "TASK_NOT_FOUND") {
+			return nil, fmt.Errorf("%w: %s", queue.ErrTaskNotFound, taskID)
+		}
+		return nil, fmt.Errorf("failed to get result of task %s: %w", taskID, err)
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("%w: unexpected result reply of %d values", ErrInvalidTaskData, len(values))
+	}
+
+	status, _ := values[0].(string)
+	result := &queue.TaskResult{
+		TaskID: taskID,
+		Status: queue.TaskStatus(status),
+	}
+	if v, ok := values[1].(string); ok {
+		result.Result = []byte(v)
+	}
+	if v, ok := values[2].(string); ok {
+		result.Error = v
+	}
+	if v, ok := values[3].(string); ok {
+		processedAt, err := parseTime(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: processed_at: %w", ErrInvalidTaskData, err)
+		}
+		result.ProcessedAt = &processedAt
+	}
+
+	return result, nil
+}
+
+// GetPendingTaskByName finds a pending task by name for scheduler idempotency checks.
+func (s *Storage) GetPendingTaskByName(ctx context.Context, taskName string) (*queue.Task, error) {
+	res, err := pendingByNameScript.Run(ctx, s.client, s.keys(), s.prefix, taskName).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pending task %q: %w", taskName, err)
+	}
+
+	return parseTask(res)
+}
+
+// run executes a script that replies with a status or one of the task error codes.
+func (s *Storage) run(ctx context.Context, script *redis.Script, taskID uuid.UUID, args ...any) error {
+	if err := script.Run(ctx, s.client, s.keys(), args...).Err(); err != nil {
+		return scriptError(err, taskID)
+	}
+	return nil
+}
+
+// scriptError converts script error replies into the package sentinel errors.
+func scriptError(err error, taskID uuid.UUID) error {
+	msg := errorReply(err)
+	switch {
+	case strings.HasPrefix(msg, "TASK_NOT_FOUND"):
+		return fmt.Errorf("%w: %s", ErrTaskNotFound, taskID)
+	case strings.HasPrefix(msg, "TASK_NOT_PROCESSING"):
+		return fmt.Errorf("%w: %s", ErrTaskNotProcessing, taskID)
+	case strings.HasPrefix(msg, "TASK_EXISTS"):
+		return fmt.Errorf("%w: %s", ErrTaskAlreadyExists, taskID)
+	}
+	return err
+}
+
+// errorReply returns the message of a script error reply. Some servers prefix
+// replies raised with redis.error_reply by the generic ERR code.
+func errorReply(err error) string {
+	return strings.TrimPrefix(err.Error(), "ERR ")
+}
+
+// keys returns the KEYS argument shared by all scripts.
+func (s *Storage) keys() []string {
+	return []string{s.prefix + ":tasks"}
+}
+
+// Start begins the lock expiration manager. This is a blocking operation
+// that runs until the context is cancelled. Use Run() for errgroup pattern or call this in a goroutine.
+func (s *Storage) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.mu.Unlock()
+		return ErrStorageAlreadyStarted
+	}
+
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.mu.Unlock()
+
+	s.running.Store(true)
+	defer s.running.Store(false)
+
+	s.logger.InfoContext(s.ctx, "redis storage lock expiration manager started",
+		slog.Duration("check_interval", s.lockCheckInterval))
+
+	ticker := time.NewTicker(s.lockCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			s.logger.Info("redis storage stopping")
+			return s.ctx.Err()
+		case <-ticker.C:
+			select {
+			case <-s.ctx.Done():
+				return s.ctx.Err()
+			default:
+				s.expireLocksWithWait()
+			}
+		}
+	}
+}
+
+// Stop gracefully shuts down the lock expiration manager with a timeout.
+// Returns an error if the shutdown timeout is exceeded.
+func (s *Storage) Stop() error {
+	s.mu.Lock()
+	if s.cancel == nil {
+		s.mu.Unlock()
+		return ErrStorageNotStarted
+	}
+
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+
+	cancel()
+
+	s.logger.Info("redis storage stopping, waiting for lock expiration to complete",
+		slog.Duration("timeout", s.shutdownTimeout))
+
+	ctx, ctxCancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer ctxCancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.logger.Info("redis storage stopped cleanly")
+		return nil
+	case <-ctx.Done():
+		s.logger.Warn("redis storage shutdown timeout exceeded",
+			slog.Duration("timeout", s.shutdownTimeout))
+		return fmt.Errorf("shutdown timeout exceeded after %s", s.shutdownTimeout)
+	}
+}
+
+// Run provides errgroup compatibility for coordinated lifecycle management.
+// Returns a function that starts the lock expiration manager, monitors context cancellation,
+// and performs graceful shutdown when the context is cancelled.
+func (s *Storage) Run(ctx context.Context) func() error {
+	return func() error {
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- s.Start(ctx)
+		}()
+
+		select {
+		case <-ctx.Done():
+			// Context cancelled - perform graceful shutdown
+			_ = s.Stop() // Ignore stop error in normal shutdown
+			<-errCh      // Wait for Start() to exit
+			return nil
+		case err := <-errCh:
+			// Start() returned - check if it's a normal shutdown
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// expireLocksWithWait wraps expireLocks with WaitGroup tracking for graceful shutdown.
+func (s *Storage) expireLocksWithWait() {
+	s.mu.RLock()
+	if s.cancel == nil {
+		s.mu.RUnlock()
+		return
+	}
+	ctx := s.ctx
+	s.wg.Add(1)
+	s.mu.RUnlock()
+
+	defer s.wg.Done()
+	s.expireLocks(ctx)
+}
+
+// expireLocks releases locks of processing tasks whose lock has expired.
+// This allows tasks to be retried if a worker crashes or becomes unresponsive.
+// Retry count is left untouched, matching MemoryStorage semantics.
+func (s *Storage) expireLocks(ctx context.Context) {
+	freed, err := expireLocksScript.Run(ctx, s.client, s.keys(), s.prefix).Int64()
+	if err != nil {
+		if ctx.Err() == nil {
+			s.logger.ErrorContext(ctx, "failed to release expired task locks",
+				slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	if freed > 0 {
+		s.expiredLocksFreed.Add(freed)
+		s.lastActivityAt.Store(time.Now().Unix())
+	}
+
+	if err := purgeResultsScript.Run(ctx, s.client, s.keys(), s.prefix).Err(); err != nil && ctx.Err() == nil {
+		s.logger.ErrorContext(ctx, "failed to purge expired task results",
+			slog.String("error", err.Error()))
+	}
+
+	if active, err := s.client.SCard(ctx, s.prefix+":tasks").Result(); err == nil {
+		s.activeTasks.Store(active)
+	}
+}
+
+// Stats returns current storage statistics for observability and monitoring.
+// This method is thread-safe and can be called at any time.
+func (s *Storage) Stats() Stats {
+	s.mu.RLock()
+	isRunning := s.cancel != nil
+	s.mu.RUnlock()
+
+	lastActivity := s.lastActivityAt.Load()
+	var lastActivityTime time.Time
+	if lastActivity > 0 {
+		lastActivityTime = time.Unix(lastActivity, 0)
+	}
+
+	return Stats{
+		ActiveTasks:       int(s.activeTasks.Load()),
+		ExpiredLocksFreed: s.expiredLocksFreed.Load(),
+		IsRunning:         isRunning,
+		LastActivityAt:    lastActivityTime,
+	}
+}
+
+// Healthcheck validates that the lock expiration manager is running and Redis is reachable.
+// Returns nil if healthy, or an error describing the health issue.
+// This method is thread-safe and suitable for use in health check endpoints.
+func (s *Storage) Healthcheck(ctx context.Context) error {
+	if !s.Stats().IsRunning {
+		return fmt.Errorf("lock expiration manager is not running")
+	}
+
+	return redisdb.Healthcheck(s.client)(ctx)
+}
+
+// taskFields flattens a task into hash field/value pairs.
+// Nil optional fields are omitted so they read back as nil.
+func taskFields(task *queue.Task) ([]any, error) {
+	var retryPolicy []byte
+	if task.RetryPolicy != nil {
+		data, err := json.Marshal(task.RetryPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal retry policy: %w", err)
+		}
+		retryPolicy = data
+	}
+
+	fields := []any{
+		"id", task.ID.String(),
+		"queue", task.Queue,
+		"task_type", string(task.TaskType),
+		"task_name", task.TaskName,
+		"payload", string(task.Payload),
+		"status", string(task.Status),
+		"priority", int(task.Priority),
+		"retry_count", int(task.RetryCount),
+		"max_retries", int(task.MaxRetries),
+		"scheduled_at", formatTime(task.ScheduledAt),
+		"created_at", formatTime(task.CreatedAt),
+	}
+
+	if task.LockedUntil != nil {
+		fields = append(fields, "locked_until", formatTime(*task.LockedUntil))
+	}
+	if task.LockedBy != nil {
+		fields = append(fields, "locked_by", task.LockedBy.String())
+	}
+	if task.ProcessedAt != nil {
+		fields = append(fields, "processed_at", formatTime(*task.ProcessedAt))
+	}
+	if task.Error != nil {
+		fields = append(fields, "error", *task.Error)
+	}
+	if task.RetryPolicy != nil {
+		fields = append(fields, "retry_policy", string(retryPolicy))
+	}
+	if task.UniqueKey != nil {
+		fields = append(fields, "unique_key", *task.UniqueKey)
+	}
+	if task.UniqueUntil != nil {
+		fields = append(fields, "unique_until", formatTime(*task.UniqueUntil))
+	}
+	if task.WorkflowID != nil {
+		fields = append(fields, "workflow_id", task.WorkflowID.String())
+	}
+	if task.ParentID != nil {
+		fields = append(fields, "parent_id", task.ParentID.String())
+	}
+	if task.GroupID != nil {
+		fields = append(fields, "group_id", task.GroupID.String())
+	}
+	if len(task.Result) > 0 {
+		fields = append(fields, "result", string(task.Result))
+	}
+	if len(task.ParentResult) > 0 {
+		fields = append(fields, "parent_result", string(task.ParentResult))
+	}
+	if task.Timeout > 0 {
+		fields = append(fields, "timeout_ms", task.Timeout.Milliseconds())
+	}
+	if task.CancelRequested {
+		fields = append(fields, "cancel_requested", "1")
+	}
+	if task.ResultTTL > 0 {
+		fields = append(fields, "result_ttl_ms", task.ResultTTL.Milliseconds())
+	}
+
+	return fields, nil
+}
+
+// parseTask converts a flat HGETALL reply into a task.
+func parseTask(res any) (*queue.Task, error) {
+	values, ok := res.([]any)
+	if !ok || len(values)%2 != 0 {
+		return nil, fmt.Errorf("%w: unexpected reply %T", ErrInvalidTaskData, res)
+	}
+
+	h := make(map[string]string, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		k, _ := values[i].(string)
+		v, _ := values[i+1].(string)
+		h[k] = v
+	}
+	return parseTaskHash(h)
+}
+
+// parseTaskHash converts task hash fields into a task.
+func parseTaskHash(h map[string]string) (*queue.Task, error) {
+	var (
+		task queue.Task
+		err  error
+	)
+
+	if task.ID, err = uuid.Parse(h["id"]); err != nil {
+		return nil, fmt.Errorf("%w: id: %w", ErrInvalidTaskData, err)
+	}
+
+	task.Queue = h["queue"]
+	task.TaskType = queue.TaskType(h["task_type"])
+	task.TaskName = h["task_name"]
+	task.Status = queue.TaskStatus(h["status"])
+	if p := h["payload"]; p != "" {
+		task.Payload = []byte(p)
+	}
+
+	ints := []struct {
+		field string
+		dst   *int8
+	}{
+		{"retry_count", &task.RetryCount},
+		{"max_retries", &task.MaxRetries},
+	}
+	for _, f := range ints {
+		n, err := strconv.ParseInt(h[f.field], 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidTaskData, f.field, err)
+		}
+		*f.dst = int8(n)
+	}
+
+	priority, err := strconv.ParseInt(h["priority"], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("%w: priority: %w", ErrInvalidTaskData, err)
+	}
+	task.Priority = queue.Priority(priority)
+
+	if task.ScheduledAt, err = parseTime(h["scheduled_at"]); err != nil {
+		return nil, fmt.Errorf("%w: scheduled_at: %w", ErrInvalidTaskData, err)
+	}
+	if task.CreatedAt, err = parseTime(h["created_at"]); err != nil {
+		return nil, fmt.Errorf("%w: created_at: %w", ErrInvalidTaskData, err)
+	}
+
+	if v, ok := h["locked_until"]; ok {
+		t, err := parseTime(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: locked_until: %w", ErrInvalidTaskData, err)
+		}
+		task.LockedUntil = &t
+	}
+	if v, ok := h["locked_by"]; ok {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: locked_by: %w", ErrInvalidTaskData, err)
+		}
+		task.LockedBy = &id
+	}
+	if v, ok := h["processed_at"]; ok {
+		t, err := parseTime(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: processed_at: %w", ErrInvalidTaskData, err)
+		}
+		task.ProcessedAt = &t
+	}
+	if v, ok := h["error"]; ok {
+		task.Error = &v
+	}
+	if v, ok := h["retry_policy"]; ok {
+		task.RetryPolicy = &queue.RetryPolicy{}
+		if err := json.Unmarshal([]byte(v), task.RetryPolicy); err != nil {
+			return nil, fmt.Errorf("%w: retry_policy: %w", ErrInvalidTaskData, err)
+		}
+	}
+	if v, ok := h["unique_key"]; ok {
+		task.UniqueKey = &v
+	}
+	if v, ok := h["unique_until"]; ok {
+		t, err := parseTime(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unique_until: %w", ErrInvalidTaskData, err)
+		}
+		task.UniqueUntil = &t
+	}
+	for field, dst := range map[string]**uuid.UUID{
+		"workflow_id": &task.WorkflowID,
+		"parent_id":   &task.ParentID,
+		"group_id":    &task.GroupID,
+	} {
+		if v, ok := h[field]; ok {
+			id, err := uuid.Parse(v)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %w", ErrInvalidTaskData, field, err)
+			}
+			*dst = &id
+		}
+	}
+	if v, ok := h["result"]; ok {
+		task.Result = []byte(v)
+	}
+	if v, ok := h["parent_result"]; ok {
+		task.ParentResult = []byte(v)
+	}
+	if v, ok := h["timeout_ms"]; ok {
+		ms, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: timeout_ms: %w", ErrInvalidTaskData, err)
+		}
+		task.Timeout = time.Duration(ms) * time.Millisecond
+	}
+	_, task.CancelRequested = h["cancel_requested"]
+	if v, ok := h["result_ttl_ms"]; ok {
+		ms, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: result_ttl_ms: %w", ErrInvalidTaskData, err)
+		}
+		task.ResultTTL = time.Duration(ms) * time.Millisecond
+	}
+
+	return &task, nil
+}
+
+// parseDLQ converts a dead letter queue entry hash into an entry.
+func parseDLQ(h map[string]string) (*queue.TasksDlq, error) {
+	var (
+		entry queue.TasksDlq
+		err   error
+	)
+
+	if entry.ID, err = uuid.Parse(h["id"]); err != nil {
+		return nil, fmt.Errorf("%w: id: %w", ErrInvalidTaskData, err)
+	}
+	if entry.TaskID, err = uuid.Parse(h["task_id"]); err != nil {
+		return nil, fmt.Errorf("%w: task_id: %w", ErrInvalidTaskData, err)
+	}
+
+	entry.Queue = h["queue"]
+	entry.TaskType = queue.TaskType(h["task_type"])
+	entry.TaskName = h["task_name"]
+	entry.Error = h["error"]
+	if p := h["payload"]; p != "" {
+		entry.Payload = []byte(p)
+	}
+
+	priority, err := strconv.ParseInt(h["priority"], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("%w: priority: %w", ErrInvalidTaskData, err)
+	}
+	entry.Priority = queue.Priority(priority)
+
+	retryCount, err := strconv.ParseInt(h["retry_count"], 10, 8)
+	if err != nil {
+		return nil, fmt.Errorf("%w: retry_count: %w", ErrInvalidTaskData, err)
+	}
+	entry.RetryCount = int8(retryCount)
+
+	if entry.FailedAt, err = parseTime(h["failed_at"]); err != nil {
+		return nil, fmt.Errorf("%w: failed_at: %w", ErrInvalidTaskData, err)
+	}
+	if entry.CreatedAt, err = parseTime(h["created_at"]); err != nil {
+		return nil, fmt.Errorf("%w: created_at: %w", ErrInvalidTaskData, err)
+	}
+
+	return &entry, nil
+}
+
+// formatTime encodes a time as unix milliseconds, the unit used for sorted set scores.
+func formatTime(t time.Time) string {
+	return strconv.FormatInt(t.UnixMilli(), 10)
+}
+
+func parseTime(s string) (time.Time, error) {
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(ms), nil
+}
@@ -0,0 +1,62 @@
+package redisstorage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dmitrymomot/foundation/core/queue"
+	"github.com/dmitrymomot/foundation/integration/queue/redisstorage"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejects nil client", func(t *testing.T) {
+		t.Parallel()
+
+		storage, err := redisstorage.New(nil)
+		require.ErrorIs(t, err, redisstorage.ErrClientNil)
+		assert.Nil(t, storage)
+	})
+
+	t.Run("is not running before start", func(t *testing.T) {
+		t.Parallel()
+
+		client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+		t.Cleanup(func() { _ = client.Close() })
+
+		storage, err := redisstorage.New(client)
+		require.NoError(t, err)
+
+		assert.False(t, storage.Stats().IsRunning)
+		assert.Error(t, storage.Healthcheck(context.Background()))
+		assert.ErrorIs(t, storage.Stop(), redisstorage.ErrStorageNotStarted)
+	})
+}
+
+func TestStorage_CreateWorkflowRejectsNilTask(t *testing.T) {
+	t.Parallel()
+
+	// Nothing listens here: the workflow is validated before anything is sent
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	t.Cleanup(func() { _ = client.Close() })
+
+	storage, err := redisstorage.New(client)
+	require.NoError(t, err)
+
+	err = storage.CreateWorkflow(context.Background(), []*queue.Task{{ID: uuid.New()}, nil})
+	require.ErrorIs(t, err, redisstorage.ErrTaskNil)
+}
+
+func TestNewLeaderElector(t *testing.T) {
+	t.Parallel()
+
+	elector, err := redisstorage.NewLeaderElector(nil, "")
+	require.ErrorIs(t, err, redisstorage.ErrClientNil)
+	assert.Nil(t, elector)
+}